@@ -34,6 +34,10 @@ type Actor interface {
 // actor type, network parameters, and other contextual information. Allows actors to use GetMethodName without an import cycle.
 type MethodNameFn func(ctx context.Context, methodNum abi.MethodNum, actorName string, height int64, network string, helper *helper.Helper, logger *logger.Logger) (string, error)
 
+// ConstructorParamsFn parses the constructor params of the actor with the given name.
+// Allows the init actor to decode the params of the actors it creates without an import cycle.
+type ConstructorParamsFn func(network string, height int64, actorName string, raw []byte) (map[string]interface{}, error)
+
 func ParseSend(msg *parser.LotusMessage) map[string]interface{} {
 	metadata := make(map[string]interface{})
 	metadata[parser.ParamsKey] = msg.Params
@@ -91,11 +91,17 @@ func SetupActorsCache(dataSource common.DataSource, logger *logger.Logger, metri
 
 	var offChainCache IActorsCache
 
-	var onChainCache impl.OnChain
+	var onChainCache IActorsCache
 
 	logger = logger2.GetSafeLogger(logger)
 	metrics := cacheMetrics.NewClient(metricsClient, "actorsCache")
 
+	// without a node every lookup is served by the off chain cache, seeded from the snapshot if any
+	if dataSource.Node == nil {
+		onChainCache = &impl.Offline{}
+	} else {
+		onChainCache = &impl.OnChain{}
+	}
 	err := onChainCache.NewImpl(dataSource, logger, metrics, backoff)
 	if err != nil {
 		return nil, err
@@ -109,21 +115,49 @@ func SetupActorsCache(dataSource common.DataSource, logger *logger.Logger, metri
 
 	offChainCache = &combinedCache
 
-	logger.Infof("[ActorsCache] - Actors cache initialized. Off chain cache implementation: %s", offChainCache.ImplementationType())
-
-	return &ActorsCache{
+	actorsCache := &ActorsCache{
 		offChainCache: offChainCache,
-		onChainCache:  &onChainCache,
-		badAddress:    cmap.New(),
+		onChainCache:  onChainCache,
+		badAddress:    newBadAddresses(),
 		logger:        logger,
 		httpClient:    resty.New().SetTimeout(30 * time.Second),
 		metrics:       metrics,
 		networkName:   dataSource.Config.NetworkName,
-	}, nil
+		actorNames:    cmap.New(),
+	}
+
+	if dataSource.Config.SnapshotPath != "" {
+		if err = actorsCache.loadSnapshot(context.Background(), dataSource.Config.SnapshotPath); err != nil {
+			logger.Errorf("[ActorsCache] - Unable to load snapshot: %s", err.Error())
+			return nil, err
+		}
+	}
+
+	logger.Infof("[ActorsCache] - Actors cache initialized. Off chain cache implementation: %s. On chain cache implementation: %s", offChainCache.ImplementationType(), onChainCache.ImplementationType())
+
+	return actorsCache, nil
+}
+
+// loadSnapshot stores every entry of the snapshot as canonical, as snapshots are only taken from confirmed data.
+func (a *ActorsCache) loadSnapshot(ctx context.Context, path string) error {
+	snapshot, err := impl.LoadSnapshot(path)
+	if err != nil {
+		return err
+	}
+	for _, info := range snapshot.Addresses {
+		info.IsCanonical = true
+		a.StoreAddressInfo(info)
+	}
+	for selectorID, sig := range snapshot.EVMSelectors {
+		if err := a.offChainCache.StoreEVMSelectorSig(ctx, selectorID, sig, true); err != nil {
+			return fmt.Errorf("could not store selector sig %s: %w", selectorID, err)
+		}
+	}
+	return nil
 }
 
 func (a *ActorsCache) ClearBadAddressCache() {
-	a.badAddress.Clear()
+	a.badAddress.clear()
 }
 
 func (a *ActorsCache) GetActorCode(add address.Address, key filTypes.TipSetKey, onChainOnly, canonical bool) (string, error) {
@@ -131,9 +165,14 @@ func (a *ActorsCache) GetActorCode(add address.Address, key filTypes.TipSetKey,
 
 	store, actorCode, err := a.getActorCode(add, key, onChainOnly, canonical)
 	if err != nil {
+		if errors.Is(err, common.ErrOffline) {
+			// expected when running offline, the caller decides how to degrade
+			a.logger.Debugf("[ActorsCache] - Unable to retrieve actor code: %s", err.Error())
+			return "", err
+		}
 		a.logger.Errorf("[ActorsCache] - Unable to retrieve actor code from node: %s", err.Error())
 		if strings.Contains(err.Error(), "actor not found") {
-			a.badAddress.Set(addStr, true)
+			a.badAddress.add(key, addStr)
 		}
 
 		return "", err
@@ -251,10 +290,6 @@ func (a *ActorsCache) getShortAddress(add address.Address, canonical bool) (stor
 	}
 	a.logger.Debugf("[ActorsCache] - Unable to retrieve short address from offchain cache for address %s. Trying onchain cache", addStr)
 	// Try onchain
-	// Check if this is a flagged address
-	if a.isBadAddress(add) {
-		return false, "", fmt.Errorf("address %s is flagged as bad", addStr)
-	}
 	short, err = a.onChainCache.GetShortAddress(add, canonical)
 	if err != nil {
 		a.logger.Debugf("[ActorsCache] - Unable to retrieve short address from onchain cache for address %s.", addStr)
@@ -287,9 +322,6 @@ func (a *ActorsCache) getRobustAddress(add address.Address, canonical bool) (sto
 	}
 	a.logger.Debugf("[ActorsCache] - Unable to retrieve robust address from offchain cache for address %s. Trying onchain cache", addStr)
 	// Try onchain
-	if a.isBadAddress(add) {
-		return false, "", fmt.Errorf("%w: address %s is flagged as bad", ErrBadAddress, addStr)
-	}
 	robust, err = a.onChainCache.GetRobustAddress(add, canonical)
 	if err != nil {
 		a.logger.Debugf("[ActorsCache] - Unable to retrieve robust address from onchain cache for address %s.", addStr)
@@ -307,8 +339,8 @@ func (a *ActorsCache) getActorCode(add address.Address, key filTypes.TipSetKey,
 	}
 	a.logger.Debugf("[ActorsCache] - Unable to retrieve actor code from offchain cache for address %s. Trying on-chain cache", addStr)
 	// Try onchain
-	if a.isBadAddress(add) {
-		return false, "", fmt.Errorf(" %w : %s is flagged as bad", ErrBadAddress, addStr)
+	if a.badAddress.has(key, addStr) {
+		return false, "", fmt.Errorf("%w: address %s is flagged as bad", ErrBadAddress, addStr)
 	}
 
 	actorCode, err = a.onChainCache.GetActorCode(add, key, onChainOnly, canonical)
@@ -369,11 +401,6 @@ func (a *ActorsCache) storeRobustAddress(add address.Address, info types.Address
 
 }
 
-func (a *ActorsCache) isBadAddress(add address.Address) bool {
-	_, bad := a.badAddress.Get(add.String())
-	return bad
-}
-
 func (a *ActorsCache) BackFill() error {
 	return a.offChainCache.BackFill()
 }
@@ -388,4 +415,23 @@ func (a *ActorsCache) NewImpl(dataSource common.DataSource, logger *logger.Logge
 
 func (a *ActorsCache) StoreAddressInfo(addInfo types.AddressInfo) {
 	a.offChainCache.StoreAddressInfo(addInfo)
+	if addInfo.ActorCid != "" && addInfo.ActorType != "" {
+		a.actorNames.Set(addInfo.ActorCid, addInfo.ActorType)
+	}
+}
+
+// GetActorNameFromCode returns the actor type of the code cid, as stored with the addresses of the snapshot or the
+// ones found while parsing. It allows resolving code cids offline, where the node cannot be asked for the actor codes.
+func (a *ActorsCache) GetActorNameFromCode(actorCode string) (string, bool) {
+	name, ok := a.actorNames.Get(actorCode)
+	if !ok {
+		return "", false
+	}
+	return name.(string), true
+}
+
+func (a *ActorsCache) RemoveAddressInfo(addInfo types.AddressInfo) {
+	a.offChainCache.RemoveAddressInfo(addInfo)
+	a.badAddress.remove(addInfo.Short)
+	a.badAddress.remove(addInfo.Robust)
 }
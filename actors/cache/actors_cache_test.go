@@ -1,7 +1,140 @@
 package cache
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/actors/cache/impl"
+	"github.com/zondax/fil-parser/actors/cache/impl/common"
+	"github.com/zondax/fil-parser/metrics"
+	"github.com/zondax/fil-parser/types"
+	"github.com/zondax/golem/pkg/logger"
+	golemBackoff "github.com/zondax/golem/pkg/zhttpclient/backoff"
+)
 
 func TestSetupActorsCache(t *testing.T) {
 
 }
+
+func TestSetupActorsCache_Offline(t *testing.T) {
+	const (
+		short    = "f01234"
+		robust   = "f1gjdwtnnwjx6p5jgr5dudeodzy5xguuhwvl7lmva"
+		actorCid = "bafk2bzacea6rabflc7kpwr6y4lzcqsnuahr4zblyq3rhzrrsfceeiw2lufrb4"
+	)
+
+	addresses := types.NewAddressInfoMap()
+	addresses.Set(short, &types.AddressInfo{Short: short, Robust: robust, ActorCid: actorCid, ActorType: "account"})
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, impl.NewSnapshot(addresses).Write(file))
+	require.NoError(t, file.Close())
+
+	source := common.DataSource{Config: common.DataSourceConfig{SnapshotPath: path}}
+	actorsCache, err := SetupActorsCache(source, logger.NewDevelopmentLogger(), metrics.NewNoopMetricsClient(), golemBackoff.New())
+	require.NoError(t, err)
+
+	shortAddr, err := address.NewFromString(short)
+	require.NoError(t, err)
+	robustAddr, err := address.NewFromString(robust)
+	require.NoError(t, err)
+
+	code, err := actorsCache.GetActorCode(shortAddr, filTypes.EmptyTSK, false, true)
+	require.NoError(t, err)
+	assert.Equal(t, actorCid, code)
+
+	gotRobust, err := actorsCache.GetRobustAddress(shortAddr, true)
+	require.NoError(t, err)
+	assert.Equal(t, robust, gotRobust)
+
+	gotShort, err := actorsCache.GetShortAddress(robustAddr, true)
+	require.NoError(t, err)
+	assert.Equal(t, short, gotShort)
+
+	// code cids unknown to rosetta offline are resolved from the snapshot
+	name, ok := actorsCache.GetActorNameFromCode(actorCid)
+	require.True(t, ok)
+	assert.Equal(t, "account", name)
+
+	unknown, err := address.NewFromString("f05678")
+	require.NoError(t, err)
+	_, err = actorsCache.GetActorCode(unknown, filTypes.EmptyTSK, false, true)
+	assert.True(t, errors.Is(err, common.ErrOffline))
+}
+
+func TestActorsCache_RemoveAddressInfo(t *testing.T) {
+	const (
+		short    = "f01234"
+		robust   = "f1gjdwtnnwjx6p5jgr5dudeodzy5xguuhwvl7lmva"
+		actorCid = "bafk2bzacea6rabflc7kpwr6y4lzcqsnuahr4zblyq3rhzrrsfceeiw2lufrb4"
+	)
+
+	actorsCache, err := SetupActorsCache(common.DataSource{}, logger.NewDevelopmentLogger(), metrics.NewNoopMetricsClient(), golemBackoff.New())
+	require.NoError(t, err)
+
+	info := types.AddressInfo{Short: short, Robust: robust, ActorCid: actorCid, ActorType: "account"}
+	actorsCache.StoreAddressInfo(info)
+
+	shortAddr, err := address.NewFromString(short)
+	require.NoError(t, err)
+
+	code, err := actorsCache.GetActorCode(shortAddr, filTypes.EmptyTSK, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, actorCid, code)
+
+	actorsCache.RemoveAddressInfo(info)
+
+	_, err = actorsCache.GetActorCode(shortAddr, filTypes.EmptyTSK, false, false)
+	assert.True(t, errors.Is(err, common.ErrOffline))
+	_, err = actorsCache.GetRobustAddress(shortAddr, false)
+	assert.Error(t, err)
+}
+
+func TestActorsCache_BadAddress(t *testing.T) {
+	actorsCache, err := SetupActorsCache(common.DataSource{}, logger.NewDevelopmentLogger(), metrics.NewNoopMetricsClient(), golemBackoff.New())
+	require.NoError(t, err)
+
+	addr, err := address.NewFromString("f1gjdwtnnwjx6p5jgr5dudeodzy5xguuhwvl7lmva")
+	require.NoError(t, err)
+	tipset := func(seed string) filTypes.TipSetKey {
+		c, err := abi.CidBuilder.Sum([]byte(seed))
+		require.NoError(t, err)
+		return filTypes.NewTipSetKey(c)
+	}
+	first, second := tipset("first"), tipset("second")
+
+	// the address was not found by the parse of the first tipset
+	actorsCache.badAddress.add(first, addr.String())
+	_, err = actorsCache.GetActorCode(addr, first, false, false)
+	assert.ErrorIs(t, err, ErrBadAddress)
+
+	// the next tipset may create it implicitly, so it is looked up again
+	_, err = actorsCache.GetActorCode(addr, second, false, false)
+	assert.ErrorIs(t, err, common.ErrOffline)
+	_, err = actorsCache.GetShortAddress(addr, false)
+	assert.NotErrorIs(t, err, ErrBadAddress)
+
+	// lookups without a tipset are never flagged
+	actorsCache.badAddress.add(filTypes.EmptyTSK, addr.String())
+	assert.False(t, actorsCache.badAddress.has(filTypes.EmptyTSK, addr.String()))
+
+	// only the latest tipsets are kept
+	for i := 0; i < maxBadAddressTipsets; i++ {
+		actorsCache.badAddress.add(tipset(fmt.Sprint(i)), addr.String())
+	}
+	assert.False(t, actorsCache.badAddress.has(first, addr.String()))
+	assert.True(t, actorsCache.badAddress.has(tipset("0"), addr.String()))
+
+	actorsCache.RemoveAddressInfo(types.AddressInfo{Robust: addr.String()})
+	assert.False(t, actorsCache.badAddress.has(tipset("0"), addr.String()))
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"sync"
+
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+)
+
+// maxBadAddressTipsets bounds the tipsets whose bad addresses are kept, the oldest tipset is dropped first.
+const maxBadAddressTipsets = 64
+
+// badAddresses keeps the addresses the node could not find, scoped to the tipset they were looked up at.
+// An address missing at a tipset might be created implicitly by a later one, such as the first send to a new
+// f1 or f4 address, so a flag never short-circuits the lookups of another tipset, even while parses overlap.
+type badAddresses struct {
+	mu      sync.Mutex
+	tipsets map[filTypes.TipSetKey]map[string]struct{}
+	// order holds the tipsets in the order they flagged their first address
+	order []filTypes.TipSetKey
+}
+
+func newBadAddresses() *badAddresses {
+	return &badAddresses{tipsets: make(map[filTypes.TipSetKey]map[string]struct{})}
+}
+
+// add flags addr as bad for the lookups at key. Lookups without a tipset are not scoped to a parse and never flagged.
+func (b *badAddresses) add(key filTypes.TipSetKey, addr string) {
+	if key.IsEmpty() {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	addrs, ok := b.tipsets[key]
+	if !ok {
+		if len(b.order) == maxBadAddressTipsets {
+			delete(b.tipsets, b.order[0])
+			b.order = b.order[1:]
+		}
+		addrs = make(map[string]struct{})
+		b.tipsets[key] = addrs
+		b.order = append(b.order, key)
+	}
+	addrs[addr] = struct{}{}
+}
+
+// has returns true if addr was flagged as bad for the lookups at key.
+func (b *badAddresses) has(key filTypes.TipSetKey, addr string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, ok := b.tipsets[key][addr]
+	return ok
+}
+
+// remove drops the flags of addr at every tipset.
+func (b *badAddresses) remove(addr string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, addrs := range b.tipsets {
+		delete(addrs, addr)
+	}
+}
+
+func (b *badAddresses) clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tipsets = make(map[filTypes.TipSetKey]map[string]struct{})
+	b.order = nil
+}
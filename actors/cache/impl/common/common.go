@@ -9,6 +9,7 @@ var (
 	ErrKeyNotFound       = errors.New("key not found")
 	ErrUnkownAddressType = errors.New("unknown address type")
 	ErrEmptyValue        = errors.New("empty value")
+	ErrOffline           = errors.New("node not available in offline mode")
 )
 
 func IsRobustAddress(add address.Address) (bool, error) {
@@ -10,6 +10,8 @@ import (
 type DataSourceConfig struct {
 	Cache       *CacheConfig
 	NetworkName string
+	// SnapshotPath points to an address snapshot used to seed the cache when running without a node
+	SnapshotPath string
 }
 
 type DataSource struct {
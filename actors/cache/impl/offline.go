@@ -0,0 +1,78 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/filecoin-project/go-address"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/zondax/fil-parser/actors/cache/impl/common"
+	cacheMetrics "github.com/zondax/fil-parser/actors/cache/metrics"
+	"github.com/zondax/fil-parser/types"
+	"github.com/zondax/golem/pkg/logger"
+	golemBackoff "github.com/zondax/golem/pkg/zhttpclient/backoff"
+)
+
+const OfflineImpl = "offline"
+
+// Offline replaces the OnChain implementation when there is no node available.
+// Every lookup fails right away with common.ErrOffline instead of reaching the network.
+type Offline struct{}
+
+func (m *Offline) NewImpl(_ common.DataSource, _ *logger.Logger, _ *cacheMetrics.ActorsCacheMetricsClient, _ *golemBackoff.BackOff) error {
+	return nil
+}
+
+func (m *Offline) ImplementationType() string {
+	return OfflineImpl
+}
+
+func (m *Offline) GetActorCode(address address.Address, _ filTypes.TipSetKey, _, _ bool) (string, error) {
+	return "", fmt.Errorf("%w: could not get actor code for address %s", common.ErrOffline, address.String())
+}
+
+func (m *Offline) GetRobustAddress(address address.Address, _ bool) (string, error) {
+	return "", fmt.Errorf("%w: could not get robust address for address %s", common.ErrOffline, address.String())
+}
+
+func (m *Offline) GetShortAddress(address address.Address, _ bool) (string, error) {
+	return "", fmt.Errorf("%w: could not get short address for address %s", common.ErrOffline, address.String())
+}
+
+func (m *Offline) GetEVMSelectorSig(_ context.Context, selectorID string, _ bool) (string, error) {
+	return "", fmt.Errorf("%w: could not get selector sig for selector %s", common.ErrOffline, selectorID)
+}
+
+func (m *Offline) StoreEVMSelectorSig(_ context.Context, _, _ string, _ bool) error {
+	// Not implemented
+	return nil
+}
+
+func (m *Offline) StoreAddressInfo(_ types.AddressInfo) {
+	// Not implemented
+}
+
+func (m *Offline) RemoveAddressInfo(_ types.AddressInfo) {
+	// Not implemented
+}
+
+// IsSystemActor returns false for all Offline implementations as the system actors list is maintained by the helper.
+// Only required to satisfy IActorsCache.
+func (m *Offline) IsSystemActor(_ string) bool {
+	return false
+}
+
+// IsGenesisActor returns false for all Offline implementations as the genesis actors list is maintained by the helper.
+// Only required to satisfy IActorsCache.
+func (m *Offline) IsGenesisActor(_ string) bool {
+	return false
+}
+
+func (m *Offline) BackFill() error {
+	// Nothing to do
+	return nil
+}
+
+func (m *Offline) ClearBadAddressCache() {
+	// Nothing to do
+}
@@ -38,6 +38,10 @@ func (m *OnChain) StoreAddressInfo(info types.AddressInfo) {
 	// Not implemented
 }
 
+func (m *OnChain) RemoveAddressInfo(info types.AddressInfo) {
+	// Not implemented
+}
+
 func (m *OnChain) BackFill() error {
 	// Nothing to do
 	return nil
@@ -0,0 +1,57 @@
+package impl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/zondax/fil-parser/types"
+)
+
+// Snapshot is a local copy of the address and actor code information usually retrieved from the node.
+// It is used to seed the cache when running offline.
+type Snapshot struct {
+	Addresses    []types.AddressInfo `json:"addresses"`
+	EVMSelectors map[string]string   `json:"evm_selectors,omitempty"`
+}
+
+// NewSnapshot builds a snapshot out of the addresses found while parsing, e.g. TxsParsedResult.Addresses.
+func NewSnapshot(addresses *types.AddressInfoMap) *Snapshot {
+	snapshot := &Snapshot{}
+	if addresses == nil {
+		return snapshot
+	}
+	addresses.Range(func(_ string, info *types.AddressInfo) bool {
+		snapshot.Addresses = append(snapshot.Addresses, *info)
+		return true
+	})
+	// keep the output stable between runs
+	sort.Slice(snapshot.Addresses, func(i, j int) bool {
+		return snapshot.Addresses[i].Short < snapshot.Addresses[j].Short
+	})
+	return snapshot
+}
+
+// LoadSnapshot reads a snapshot previously written with Snapshot.Write.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open snapshot: %w", err)
+	}
+	defer file.Close()
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(file).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("could not decode snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+func (s *Snapshot) Write(w io.Writer) error {
+	if err := json.NewEncoder(w).Encode(s); err != nil {
+		return fmt.Errorf("could not encode snapshot: %w", err)
+	}
+	return nil
+}
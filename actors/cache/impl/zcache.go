@@ -311,6 +311,18 @@ func (m *ZCache) StoreAddressInfo(info types.AddressInfo) {
 	}
 }
 
+// RemoveAddressInfo deletes every mapping StoreAddressInfo may have created for info.
+func (m *ZCache) RemoveAddressInfo(info types.AddressInfo) {
+	ctx := context.Background()
+	if info.Robust != "" {
+		_ = m.robustShortMap.Delete(ctx, info.Robust)
+	}
+	if info.Short != "" {
+		_ = m.shortCidMap.Delete(ctx, info.Short)
+		_ = m.shortRobustMap.Delete(ctx, info.Short)
+	}
+}
+
 func (m *ZCache) storeActorCode(shortAddress string, cidStr string) {
 	if shortAddress == "" || cidStr == "" || cidStr == cid.Undef.String() {
 		m.logger.Debugf("[ActorsCache] - Trying to store empty cid or short address")
@@ -25,12 +25,13 @@ func (m *ZCacheBlockConfirmation) NewImpl(source common.DataSource, logger *logg
 	}
 	latestSource := common.DataSource{
 		Node: source.Node,
-		Config: common.DataSourceConfig{
-			Cache: source.Config.Cache.Copy(),
-		},
 	}
-	latestSource.Config.Cache.Ttl = source.Config.Cache.LatestCacheTTL
-	latestSource.Config.Cache.GlobalPrefix = fmt.Sprintf("%s-%s", "latest", source.Config.Cache.GlobalPrefix)
+	// without a cache config both caches are local only
+	if source.Config.Cache != nil {
+		latestSource.Config.Cache = source.Config.Cache.Copy()
+		latestSource.Config.Cache.Ttl = source.Config.Cache.LatestCacheTTL
+		latestSource.Config.Cache.GlobalPrefix = fmt.Sprintf("%s-%s", "latest", source.Config.Cache.GlobalPrefix)
+	}
 	m.offChainLatest = &ZCache{}
 	if err := m.offChainLatest.NewImpl(latestSource, logger, metrics); err != nil {
 		return err
@@ -66,6 +67,14 @@ func (m *ZCacheBlockConfirmation) StoreAddressInfo(info types.AddressInfo) {
 	}
 }
 
+func (m *ZCacheBlockConfirmation) RemoveAddressInfo(info types.AddressInfo) {
+	if info.IsCanonical {
+		m.offChainCanonical.RemoveAddressInfo(info)
+	} else {
+		m.offChainLatest.RemoveAddressInfo(info)
+	}
+}
+
 func (m *ZCacheBlockConfirmation) GetActorCode(address address.Address, key filTypes.TipSetKey, _, canonical bool) (string, error) {
 	// try canonical first
 	code, err := m.offChainCanonical.GetActorCode(address, key)
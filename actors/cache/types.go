@@ -20,6 +20,7 @@ type IActorsCache interface {
 	GetRobustAddress(add address.Address, canonical bool) (string, error)
 	GetShortAddress(add address.Address, canonical bool) (string, error)
 	StoreAddressInfo(info types.AddressInfo)
+	RemoveAddressInfo(info types.AddressInfo)
 	GetEVMSelectorSig(ctx context.Context, selectorHash string, canonical bool) (string, error)
 	StoreEVMSelectorSig(ctx context.Context, selectorHash, selectorSig string, canonical bool) error
 	IsSystemActor(addr string) bool
@@ -29,12 +30,19 @@ type IActorsCache interface {
 	ImplementationType() string
 }
 
+// IActorNames is implemented by the caches that resolve code cids to actor names from the stored addresses, like ActorsCache.
+type IActorNames interface {
+	GetActorNameFromCode(actorCode string) (string, bool)
+}
+
 type ActorsCache struct {
 	offChainCache IActorsCache
 	onChainCache  IActorsCache
-	badAddress    cmap.ConcurrentMap
+	badAddress    *badAddresses
 	logger        *logger.Logger
 	httpClient    *resty.Client
 	networkName   string
 	metrics       *cacheMetrics.ActorsCacheMetricsClient
+	// actorNames maps the code cids of the stored addresses, the snapshot ones included, to their actor type
+	actorNames cmap.ConcurrentMap
 }
@@ -72,8 +72,19 @@ func (p *ActorParser) LatestSupportedVersion(actor string) (uint64, error) {
 
 func (p *ActorParser) GetActor(actor string) (Actor, error) {
 	if strings.Contains(actor, manifest.MultisigKey) {
-		return multisig.New(p.helper, p.logger, p.metrics, GetMethodName), nil
+		return multisig.New(p.helper, p.logger, p.metrics, GetMethodName, p.ParseConstructorParams), nil
 	}
 
-	return internal.GetActor(actor, p.logger, p.helper, p.metrics)
+	return internal.GetActor(actor, p.logger, p.helper, p.metrics, p.ParseConstructorParams)
+}
+
+// ParseConstructorParams parses raw with the Constructor method of the actor with the given name.
+func (p *ActorParser) ParseConstructorParams(network string, height int64, actorName string, raw []byte) (map[string]interface{}, error) {
+	actor, err := p.GetActor(actorName)
+	if err != nil {
+		return nil, err
+	}
+	metadata, _, err := actor.Parse(context.Background(), network, height, parser.MethodConstructor, &parser.LotusMessage{Params: raw},
+		&parser.LotusMessageReceipt{}, cid.Undef, filTypes.EmptyTSK, true)
+	return metadata, err
 }
@@ -23,14 +23,14 @@ func initConstructor[T typegen.CBORUnmarshaler](raw []byte, constructor T) (map[
 	return metadata, nil
 }
 
-func parseExec[T typegen.CBORUnmarshaler, R typegen.CBORUnmarshaler](msg *parser.LotusMessage, rawReturn []byte, params T, r R, h *helper.Helper) (map[string]interface{}, *types.AddressInfo, error) {
+func parseExec[T typegen.CBORUnmarshaler, R typegen.CBORUnmarshaler](msg *parser.LotusMessage, rawReturn []byte, params T, r R, h *helper.Helper, decode constructorParamsDecoder) (map[string]interface{}, *types.AddressInfo, error) {
 	metadata := make(map[string]interface{})
 	reader := bytes.NewReader(msg.Params)
 	err := params.UnmarshalCBOR(reader)
 	if err != nil {
 		return metadata, nil, fmt.Errorf("error unmarshaling exec params: %w", err)
 	}
-	codeCid, tmp, err := setExecParams(params)
+	codeCid, tmp, err := setExecParams(params, decode)
 	if err != nil {
 		return metadata, nil, fmt.Errorf("error parsing exec params: %w", err)
 	}
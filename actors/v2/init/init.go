@@ -28,20 +28,25 @@ import (
 	builtinInitv9 "github.com/filecoin-project/go-state-types/builtin/v9/init"
 
 	"github.com/zondax/fil-parser/actors"
+	"github.com/zondax/fil-parser/actors/metrics"
 	"github.com/zondax/fil-parser/parser"
 	"github.com/zondax/fil-parser/tools"
 	"github.com/zondax/fil-parser/types"
 )
 
 type Init struct {
-	helper *helper.Helper
-	logger *logger.Logger
+	helper              *helper.Helper
+	logger              *logger.Logger
+	metrics             *metrics.ActorsMetricsClient
+	constructorParamsFn actors.ConstructorParamsFn
 }
 
-func New(helper *helper.Helper, logger *logger.Logger) *Init {
+func New(helper *helper.Helper, logger *logger.Logger, metrics *metrics.ActorsMetricsClient, constructorParamsFn actors.ConstructorParamsFn) *Init {
 	return &Init{
-		helper: helper,
-		logger: logger,
+		helper:              helper,
+		logger:              logger,
+		metrics:             metrics,
+		constructorParamsFn: constructorParamsFn,
 	}
 }
 
@@ -118,7 +123,7 @@ func (i *Init) Exec(network string, height int64, msg *parser.LotusMessage, raw
 		return nil, nil, fmt.Errorf("%w: %d", actors.ErrUnsupportedHeight, height)
 	}
 
-	metadata, addressInfo, err := parseExec(msg, raw, params(), returnValue(), i.helper)
+	metadata, addressInfo, err := parseExec(msg, raw, params(), returnValue(), i.helper, i.newConstructorParamsDecoder(network, height, parser.MethodExec))
 	if addressInfo != nil {
 		createdActorCid, createdActorName, err := i.getActorDetailsFromAddress(height, version.FilNetworkVersion(), addressInfo, canonical)
 		if err == nil {
@@ -145,7 +150,7 @@ func (i *Init) Exec4(network string, height int64, msg *parser.LotusMessage, raw
 		return nil, nil, fmt.Errorf("%w: %d", actors.ErrUnsupportedHeight, height)
 	}
 
-	metadata, addressInfo, err := parseExec(msg, raw, params(), returnValue(), i.helper)
+	metadata, addressInfo, err := parseExec(msg, raw, params(), returnValue(), i.helper, i.newConstructorParamsDecoder(network, height, parser.MethodExec4))
 	if addressInfo != nil {
 		createdActorCid, createdActorName, err := i.getActorDetailsFromAddress(height, version.FilNetworkVersion(), addressInfo, canonical)
 		if err == nil {
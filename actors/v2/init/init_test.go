@@ -0,0 +1,178 @@
+package init_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	initv15 "github.com/filecoin-project/go-state-types/builtin/v15/init"
+	multisig15 "github.com/filecoin-project/go-state-types/builtin/v15/multisig"
+	paych15 "github.com/filecoin-project/go-state-types/builtin/v15/paych"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/manifest"
+	filApiTypes "github.com/filecoin-project/lotus/api/types"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	actormetrics "github.com/zondax/fil-parser/actors/metrics"
+	actorsV2 "github.com/zondax/fil-parser/actors/v2"
+	initActor "github.com/zondax/fil-parser/actors/v2/init"
+	filMetrics "github.com/zondax/fil-parser/metrics"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/parser/helper"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/mocks"
+	"github.com/zondax/golem/pkg/logger"
+	metrics2 "github.com/zondax/golem/pkg/metrics"
+	rosettaFilecoinLib "github.com/zondax/rosetta-filecoin-lib"
+)
+
+const (
+	msigCidStr  = "bafk2bzacect2p7urje3pylrrrjy3tngn6yaih4gtzauuatf2jllasuxd3tyxu"
+	paychCidStr = "bafk2bzacea6rabflc7kpwr6y4lzcqsnuahr4zblyq3rhzrrsfceeiw2lufrb4"
+)
+
+func newHelper(t *testing.T, codeCid cid.Cid) *helper.Helper {
+	return newHelperWithCodes(t, map[string]cid.Cid{manifest.MultisigKey: codeCid})
+}
+
+func newHelperWithCodes(t *testing.T, codeCids map[string]cid.Cid) *helper.Helper {
+	logger := logger.NewDevelopmentLogger()
+	metrics := filMetrics.NewMetricsClient(metrics2.NewNoopMetrics())
+
+	node := &mocks.FullNode{}
+	node.On("StateNetworkName", mock.Anything).Return(dtypes.NetworkName(tools.MainnetNetwork), nil)
+	node.On("StateNetworkVersion", mock.Anything, mock.Anything).Return(filApiTypes.NetworkVersion(16), nil)
+	node.On("StateActorCodeCIDs", mock.Anything, mock.Anything).Return(codeCids, nil)
+
+	lib := rosettaFilecoinLib.NewRosettaConstructionFilecoin(node)
+	return helper.NewHelper(lib, &mocks.IActorsCache{}, node, logger, metrics)
+}
+
+func newMetrics() *actormetrics.ActorsMetricsClient {
+	return actormetrics.NewClient(filMetrics.NewMetricsClient(metrics2.NewNoopMetrics()), "test")
+}
+
+func TestExec_ConstructorParams(t *testing.T) {
+	codeCid, err := cid.Parse(msigCidStr)
+	require.NoError(t, err)
+	constructorParams := []byte{0x84, 0x81, 0x42, 0x00, 0x01}
+
+	var buf bytes.Buffer
+	require.NoError(t, (&initv15.ExecParams{CodeCID: codeCid, ConstructorParams: constructorParams}).MarshalCBOR(&buf))
+	to, err := address.NewIDAddress(1)
+	require.NoError(t, err)
+	msg := &parser.LotusMessage{To: to, From: to, Params: buf.Bytes()}
+	height := tools.V24.Height()
+
+	tests := []struct {
+		name                string
+		constructorParamsFn func(network string, height int64, actorName string, raw []byte) (map[string]interface{}, error)
+		want                any
+	}{
+		{
+			name: "decoded by the created actor",
+			constructorParamsFn: func(_ string, _ int64, actorName string, raw []byte) (map[string]interface{}, error) {
+				assert.Equal(t, manifest.MultisigKey, actorName)
+				assert.Equal(t, constructorParams, raw)
+				return map[string]interface{}{parser.ParamsKey: "decoded"}, nil
+			},
+			want: "decoded",
+		},
+		{
+			name: "raw params are kept when decoding fails",
+			constructorParamsFn: func(_ string, _ int64, _ string, _ []byte) (map[string]interface{}, error) {
+				return nil, assert.AnError
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := initActor.New(newHelper(t, codeCid), logger.NewDevelopmentLogger(), newMetrics(), tt.constructorParamsFn)
+			metadata, _, err := i.Exec(tools.MainnetNetwork, height, msg, nil, exitcode.Ok, true)
+			require.NoError(t, err)
+
+			params, ok := metadata[parser.ParamsKey].(parser.ExecParams)
+			require.True(t, ok)
+			assert.Equal(t, msigCidStr, params.CodeCid)
+			assert.Equal(t, "hIFCAAE=", params.ConstructorParams)
+			assert.Equal(t, tt.want, params.DecodedConstructorParams)
+		})
+	}
+}
+
+// TestExec_ConstructorParamsFromActorParser decodes the constructor params through the parser of the created actor.
+func TestExec_ConstructorParamsFromActorParser(t *testing.T) {
+	msigCid, err := cid.Parse(msigCidStr)
+	require.NoError(t, err)
+	paychCid, err := cid.Parse(paychCidStr)
+	require.NoError(t, err)
+	h := newHelperWithCodes(t, map[string]cid.Cid{manifest.MultisigKey: msigCid, manifest.PaychKey: paychCid})
+	actorParser := actorsV2.NewActorParser(tools.MainnetNetwork, h, logger.NewDevelopmentLogger(), filMetrics.NewMetricsClient(metrics2.NewNoopMetrics()))
+
+	signer, err := address.NewIDAddress(1200)
+	require.NoError(t, err)
+	from, err := address.NewIDAddress(1300)
+	require.NoError(t, err)
+	to, err := address.NewIDAddress(1400)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		codeCid           cid.Cid
+		constructorParams cbg.CBORMarshaler
+		want              map[string]interface{}
+	}{
+		{
+			name:    "multisig signers and threshold",
+			codeCid: msigCid,
+			constructorParams: &multisig15.ConstructorParams{
+				Signers:               []address.Address{signer},
+				NumApprovalsThreshold: 1,
+				UnlockDuration:        100,
+			},
+			want: map[string]interface{}{"Signers": []interface{}{signer.String()}, "NumApprovalsThreshold": float64(1)},
+		},
+		{
+			name:              "payment channel from and to",
+			codeCid:           paychCid,
+			constructorParams: &paych15.ConstructorParams{From: from, To: to},
+			want:              map[string]interface{}{"From": from.String(), "To": to.String()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var constructorParams bytes.Buffer
+			require.NoError(t, tt.constructorParams.MarshalCBOR(&constructorParams))
+			var buf bytes.Buffer
+			require.NoError(t, (&initv15.ExecParams{CodeCID: tt.codeCid, ConstructorParams: constructorParams.Bytes()}).MarshalCBOR(&buf))
+			initAddr, err := address.NewIDAddress(1)
+			require.NoError(t, err)
+			msg := &parser.LotusMessage{To: initAddr, From: initAddr, Params: buf.Bytes()}
+
+			_, metadata, _, err := actorParser.GetMetadata(context.Background(), manifest.InitKey, parser.MethodExec, msg, cid.Undef,
+				&parser.LotusMessageReceipt{}, tools.V24.Height(), filTypes.EmptyTSK, true)
+			require.NoError(t, err)
+			params, ok := metadata[parser.ParamsKey].(parser.ExecParams)
+			require.True(t, ok)
+			require.NotNil(t, params.DecodedConstructorParams)
+
+			// compare the decoded params as they are written to the tx metadata
+			encoded, err := json.Marshal(params.DecodedConstructorParams)
+			require.NoError(t, err)
+			var decoded map[string]interface{}
+			require.NoError(t, json.Unmarshal(encoded, &decoded))
+			for key, want := range tt.want {
+				assert.Equal(t, want, decoded[key], key)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	builtinInitv17 "github.com/filecoin-project/go-state-types/builtin/v17/init"
 	builtinInitv8 "github.com/filecoin-project/go-state-types/builtin/v8/init"
 	builtinInitv9 "github.com/filecoin-project/go-state-types/builtin/v9/init"
+	"github.com/filecoin-project/go-state-types/manifest"
 	"github.com/filecoin-project/lotus/chain/types/ethtypes"
 
 	legacyInitv7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/init"
@@ -23,14 +24,43 @@ import (
 	typegen "github.com/whyrusleeping/cbor-gen"
 	"github.com/zondax/fil-parser/actors"
 	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
 	"github.com/zondax/fil-parser/types"
 )
 
-func setExecParams(params typegen.CBORUnmarshaler) (cid.Cid, any, error) {
+// constructorParamsDecoder parses the constructor params of the actor created with the given code cid.
+type constructorParamsDecoder func(codeCid cid.Cid, constructorParams []byte) any
+
+// newConstructorParamsDecoder resolves the code cid to the actor name and parses the constructor params with the Constructor
+// method of that actor. Params that cannot be parsed are logged and counted in the method error metric of method,
+// but do not fail the call, as the raw params are always kept.
+func (i *Init) newConstructorParamsDecoder(network string, height int64, method string) constructorParamsDecoder {
+	return func(codeCid cid.Cid, constructorParams []byte) any {
+		if i.constructorParamsFn == nil || len(constructorParams) == 0 {
+			return nil
+		}
+		actorName, err := i.helper.GetActorNameFromCid(codeCid, height)
+		if err != nil {
+			_ = i.metrics.UpdateActorMethodErrorMetric(manifest.InitKey, method)
+			i.logger.Warnf("error resolving actor name of code cid %s: %s", codeCid, err)
+			return nil
+		}
+		metadata, err := i.constructorParamsFn(network, height, tools.ParseActorName(actorName), constructorParams)
+		if err != nil {
+			_ = i.metrics.UpdateActorMethodErrorMetric(manifest.InitKey, method)
+			i.logger.Warnf("error parsing %s constructor params: %s", actorName, err)
+			return nil
+		}
+		return metadata[parser.ParamsKey]
+	}
+}
+
+func setExecParams(params typegen.CBORUnmarshaler, decode constructorParamsDecoder) (cid.Cid, any, error) {
 	setParams := func(codeCid cid.Cid, constructorParams []byte) (cid.Cid, any, error) {
 		return codeCid, parser.ExecParams{
-			CodeCid:           codeCid.String(),
-			ConstructorParams: base64.StdEncoding.EncodeToString(constructorParams),
+			CodeCid:                  codeCid.String(),
+			ConstructorParams:        base64.StdEncoding.EncodeToString(constructorParams),
+			DecodedConstructorParams: decode(codeCid, constructorParams),
 		}, nil
 	}
 
@@ -50,9 +80,10 @@ func setExecParams(params typegen.CBORUnmarshaler) (cid.Cid, any, error) {
 			subAddressStr = subAddress.String()
 		}
 		return codeCid, parser.Exec4Params{
-			CodeCid:           codeCid.String(),
-			ConstructorParams: base64.StdEncoding.EncodeToString(constructorParams),
-			SubAddress:        subAddressStr,
+			CodeCid:                  codeCid.String(),
+			ConstructorParams:        base64.StdEncoding.EncodeToString(constructorParams),
+			DecodedConstructorParams: decode(codeCid, constructorParams),
+			SubAddress:               subAddressStr,
 		}, nil
 	}
 
@@ -28,7 +28,7 @@ import (
 
 // GetActor returns a new instance of the specified actor type. It does not return multisig actors to avoid
 // circular dependencies, as multisig also needs all actors to parse 'propose'.
-func GetActor(actor string, logger *logger.Logger, helper *helper.Helper, metrics *actormetrics.ActorsMetricsClient) (actors.Actor, error) {
+func GetActor(actor string, logger *logger.Logger, helper *helper.Helper, metrics *actormetrics.ActorsMetricsClient, constructorParamsFn actors.ConstructorParamsFn) (actors.Actor, error) {
 	actorName := actor
 	if strings.Contains(actor, "/") {
 		parts := strings.Split(actor, "/")
@@ -48,7 +48,7 @@ func GetActor(actor string, logger *logger.Logger, helper *helper.Helper, metric
 	case manifest.EvmKey:
 		return evm.New(logger, metrics), nil
 	case manifest.InitKey:
-		return initActor.New(helper, logger), nil
+		return initActor.New(helper, logger, metrics, constructorParamsFn), nil
 	case manifest.MarketKey:
 		return market.New(logger), nil
 	case manifest.MinerKey:
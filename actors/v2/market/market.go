@@ -435,12 +435,13 @@ func (*Market) GetDealSectorExported(network string, height int64, rawParams, ra
 		return map[string]interface{}{}, fmt.Errorf("%w: %d", actors.ErrUnsupportedHeight, height)
 	}
 
-	err := extractedParams().UnmarshalCBOR(bytes.NewReader(rawParams))
+	params := extractedParams()
+	err := params.UnmarshalCBOR(bytes.NewReader(rawParams))
 	if err != nil {
 		return metadata, err
 	}
 
-	metadata[parser.ParamsKey] = extractedParams
+	metadata[parser.ParamsKey] = params
 
 	sectorNumber, err := abi.ParseUIntKey(string(rawReturn))
 	if err != nil {
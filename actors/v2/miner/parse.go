@@ -97,7 +97,7 @@ func (m *Miner) Parse(_ context.Context, network string, height int64, txType st
 		resp, err := m.ProveCommitAggregate(network, height, msg.Params)
 		return resp, nil, err
 	case parser.MethodProveReplicaUpdates:
-		resp, err := m.ProveReplicaUpdates(network, height, msg.Params)
+		resp, err := m.ProveReplicaUpdates(network, height, msg.Params, msgRct.Return)
 		return resp, nil, err
 	case parser.MethodPreCommitSectorBatch2:
 		resp, err := m.PreCommitSectorBatch2(network, height, msg.Params)
@@ -148,7 +148,7 @@ func (m *Miner) Parse(_ context.Context, network string, height int64, txType st
 		resp, err := m.ConfirmUpdateWorkerKey(network, height, msg.Params)
 		return resp, nil, err
 	case parser.MethodProveCommitSectorsNI:
-		resp, err := m.ProveCommitSectorsNI(network, height, msg.Params)
+		resp, err := m.ProveCommitSectorsNI(network, height, msg.Params, msgRct.Return)
 		return resp, nil, err
 	case parser.MethodInitialPledgeExported, parser.MethodInitialPledge:
 		resp, err := m.InitialPledgeExported(network, height, msgRct.Return)
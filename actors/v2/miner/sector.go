@@ -142,7 +142,7 @@ func (*Miner) GetSectorSize(network string, height int64, rawReturn []byte) (map
 	return parseGeneric(rawReturn, nil, false, &types.GetSectorSizeReturn{}, &abi.EmptyValue{}, parser.ParamsKey)
 }
 
-func (*Miner) ProveCommitSectorsNI(network string, height int64, rawParams []byte) (map[string]interface{}, error) {
+func (*Miner) ProveCommitSectorsNI(network string, height int64, rawParams, rawReturn []byte) (map[string]interface{}, error) {
 	version := tools.VersionFromHeight(network, height)
 	params, ok := proveCommitSectorsNIParams[version.String()]
 	if !ok {
@@ -152,5 +152,5 @@ func (*Miner) ProveCommitSectorsNI(network string, height int64, rawParams []byt
 	if !ok {
 		return nil, fmt.Errorf("%w: %d", actors.ErrUnsupportedHeight, height)
 	}
-	return parseGeneric(rawParams, nil, false, params(), returnValue(), parser.ParamsKey)
+	return parseGeneric(rawParams, rawReturn, true, params(), returnValue(), parser.ParamsKey)
 }
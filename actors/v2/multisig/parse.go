@@ -43,18 +43,20 @@ type Msig struct {
 	verifreg *verifiedRegistry.VerifiedRegistry
 	evm      *evm.Evm
 
-	methodNameFn actors.MethodNameFn
+	methodNameFn        actors.MethodNameFn
+	constructorParamsFn actors.ConstructorParamsFn
 }
 
-func New(helper *helper.Helper, logger *logger.Logger, metrics *metrics.ActorsMetricsClient, methodNameFn actors.MethodNameFn) *Msig {
+func New(helper *helper.Helper, logger *logger.Logger, metrics *metrics.ActorsMetricsClient, methodNameFn actors.MethodNameFn, constructorParamsFn actors.ConstructorParamsFn) *Msig {
 	return &Msig{
-		helper:       helper,
-		logger:       logger,
-		metrics:      metrics,
-		miner:        miner.New(logger),
-		verifreg:     verifiedRegistry.New(logger),
-		evm:          evm.New(logger, metrics),
-		methodNameFn: methodNameFn,
+		helper:              helper,
+		logger:              logger,
+		metrics:             metrics,
+		miner:               miner.New(logger),
+		verifreg:            verifiedRegistry.New(logger),
+		evm:                 evm.New(logger, metrics),
+		methodNameFn:        methodNameFn,
+		constructorParamsFn: constructorParamsFn,
 	}
 }
 
@@ -65,7 +65,7 @@ func (m *Msig) innerProposeMethod(
 	if strings.Contains(actorName, manifest.MultisigKey) {
 		actor = m
 	} else {
-		actor, err = internal.GetActor(actorName, m.logger, m.helper, m.metrics)
+		actor, err = internal.GetActor(actorName, m.logger, m.helper, m.metrics, m.constructorParamsFn)
 		if err != nil {
 			return nil, "", err
 		}
@@ -128,7 +128,8 @@ func (*Power) OnConsensusFault(network string, height int64, msg *parser.LotusMe
 	case tools.AnyIsSupported(network, height, tools.VersionsBefore(tools.V15)...):
 		return nil, fmt.Errorf("%w: %d", actors.ErrInvalidHeightForMethod, height)
 	case tools.AnyIsSupported(network, height, tools.VersionsAfter(tools.V16)...):
-		return map[string]interface{}{}, nil
+		// the params are the pledge the miner removes from the network total
+		data, err = parse(raw, nil, false, &abi.TokenAmount{}, &abi.TokenAmount{}, parser.ParamsKey)
 	default:
 		err = fmt.Errorf("%w: %d", actors.ErrUnsupportedHeight, height)
 	}
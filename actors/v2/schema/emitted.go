@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"reflect"
+
+	"github.com/filecoin-project/go-state-types/manifest"
+
+	marketTypes "github.com/zondax/fil-parser/actors/v2/market/types"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/types"
+)
+
+// emitted overrides the reflected types of a method the parser transforms, instead of writing the go-state-types values.
+// A nil params or return keeps the reflected type.
+type emitted struct {
+	params reflect.Type
+	ret    reflect.Type
+	// extra are metadata keys the parser writes next to the params and return
+	extra map[string]reflect.Type
+}
+
+var (
+	stringType      = reflect.TypeOf("")
+	addressInfoType = reflect.TypeOf(types.AddressInfo{})
+	eamReturnType   = reflect.TypeOf(parser.EamCreateReturn{})
+	ethHash         = map[string]reflect.Type{parser.EthHashKey: stringType}
+)
+
+// emittedTypes holds the overrides by actor and method. Hex and base64 encoded values are described as strings.
+var emittedTypes = map[string]map[string]emitted{
+	manifest.AccountKey: {
+		parser.MethodPubkeyAddress:         {params: stringType, ret: stringType},
+		parser.MethodUniversalReceiverHook: {params: stringType},
+	},
+	manifest.InitKey: {
+		parser.MethodExec:  {params: reflect.TypeOf(parser.ExecParams{}), ret: addressInfoType},
+		parser.MethodExec4: {params: reflect.TypeOf(parser.Exec4Params{}), ret: addressInfoType},
+	},
+	manifest.PowerKey: {
+		parser.MethodCreateMiner:         {ret: addressInfoType},
+		parser.MethodCreateMinerExported: {ret: addressInfoType},
+	},
+	manifest.EamKey: {
+		parser.MethodCreate:         {ret: eamReturnType, extra: ethHash},
+		parser.MethodCreate2:        {ret: eamReturnType, extra: ethHash},
+		parser.MethodCreateExternal: {params: stringType, ret: eamReturnType, extra: ethHash},
+	},
+	manifest.EvmKey: {
+		parser.MethodInvokeContract:         {params: stringType, ret: stringType},
+		parser.MethodInvokeContractReadOnly: {params: stringType, ret: stringType},
+	},
+	manifest.MultisigKey: {
+		parser.MethodPropose:            {params: reflect.TypeOf(parser.MultisigPropose{})},
+		parser.MethodProposeExported:    {params: reflect.TypeOf(parser.MultisigPropose{})},
+		parser.MethodInvokeContract:     {params: stringType, ret: stringType},
+		parser.MethodChangeOwnerAddress: {params: stringType},
+	},
+	manifest.MarketKey: {
+		parser.MethodWithdrawBalance:         {ret: reflect.TypeOf(marketTypes.WithdrawBalanceReturn{})},
+		parser.MethodWithdrawBalanceExported: {ret: reflect.TypeOf(marketTypes.WithdrawBalanceReturn{})},
+		parser.MethodGetDealSectorExported:   {ret: stringType},
+	},
+	manifest.MinerKey: {
+		parser.MethodControlAddresses: {params: stringType, ret: reflect.TypeOf(parser.ControlAddress{})},
+		parser.MethodGetBeneficiary:   {params: stringType, ret: reflect.TypeOf(parser.GetBeneficiaryReturn{})},
+	},
+}
+
+// emittedTypesOf returns the types the parser writes for the method, given the reflected ones.
+func emittedTypesOf(actorName, method string, params, ret reflect.Type) (reflect.Type, reflect.Type, map[string]reflect.Type) {
+	override, ok := emittedTypes[actorName][method]
+	if !ok {
+		return params, ret, nil
+	}
+	if override.params != nil {
+		params = override.params
+	}
+	if override.ret != nil {
+		ret = override.ret
+	}
+	return params, ret, override.extra
+}
@@ -0,0 +1,197 @@
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs/go-cid"
+)
+
+var (
+	addressType    = reflect.TypeOf(address.Address{})
+	bigIntType     = reflect.TypeOf(big.Int{})
+	cidType        = reflect.TypeOf(cid.Cid{})
+	bitfieldType   = reflect.TypeOf(bitfield.BitField{})
+	emptyValueType = reflect.TypeOf(abi.EmptyValue{})
+
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	invalidDefChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// reflector builds the JSON Schema of go types as encoding/json marshals them.
+// Named structs are added to defs and referenced, so recursive types are supported.
+type reflector struct {
+	defs  map[string]interface{}
+	names map[reflect.Type]string
+}
+
+func newReflector() *reflector {
+	return &reflector{
+		defs:  make(map[string]interface{}),
+		names: make(map[reflect.Type]string),
+	}
+}
+
+func (r *reflector) schemaOf(t reflect.Type) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		return nullable(r.schemaOf(t.Elem()))
+	}
+
+	switch t {
+	case addressType:
+		return map[string]interface{}{"type": "string", "description": "filecoin address"}
+	case bigIntType:
+		return map[string]interface{}{"type": "string", "pattern": "^-?[0-9]+$", "description": "arbitrary precision integer"}
+	case cidType:
+		return map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"/": map[string]interface{}{"type": "string"}},
+			"required":   []string{"/"},
+		}
+	case bitfieldType:
+		return map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "integer", "minimum": 0},
+			"description": "run-length encoded bitfield",
+		}
+	}
+
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return map[string]interface{}{"description": fmt.Sprintf("custom json encoding of %s", t)}
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return nullable(map[string]interface{}{"type": "string", "contentEncoding": "base64"})
+		}
+		return nullable(map[string]interface{}{"type": "array", "items": r.schemaOf(t.Elem())})
+	case reflect.Array:
+		return map[string]interface{}{
+			"type":     "array",
+			"items":    r.schemaOf(t.Elem()),
+			"minItems": t.Len(),
+			"maxItems": t.Len(),
+		}
+	case reflect.Map:
+		return nullable(map[string]interface{}{"type": "object", "additionalProperties": r.schemaOf(t.Elem())})
+	case reflect.Struct:
+		return r.structRef(t)
+	}
+	// interfaces, and anything else encoding/json can't describe ahead of time
+	return map[string]interface{}{}
+}
+
+// structRef adds the schema of t to the definitions and returns a reference to it.
+func (r *reflector) structRef(t reflect.Type) map[string]interface{} {
+	if t.Name() == "" {
+		return r.structSchema(t)
+	}
+	name, ok := r.names[t]
+	if !ok {
+		name = r.defName(t)
+		r.names[t] = name
+		// reserve the name before walking the fields, so recursive types reference it instead of looping
+		r.defs[name] = nil
+		r.defs[name] = r.structSchema(t)
+	}
+	return map[string]interface{}{"$ref": "#/$defs/" + name}
+}
+
+func (r *reflector) defName(t reflect.Type) string {
+	base := invalidDefChars.ReplaceAllString(t.String(), "_")
+	name := base
+	for i := 2; ; i++ {
+		if _, taken := r.defs[name]; !taken {
+			return name
+		}
+		// same name in a different package, e.g. the params of two actor versions
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+}
+
+func (r *reflector) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+	r.addFields(t, properties, &required)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addFields adds the fields of t following the encoding/json rules: unexported and "-" fields are skipped,
+// untagged embedded structs are flattened and fields without omitempty are always present.
+func (r *reflector) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct && !isEncodedAsValue(fieldType) {
+			r.addFields(fieldType, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = r.schemaOf(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// isEncodedAsValue returns true if t is not encoded as an object of its fields.
+func isEncodedAsValue(t reflect.Type) bool {
+	switch t {
+	case addressType, bigIntType, cidType, bitfieldType:
+		return true
+	}
+	return t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType)
+}
+
+func nullable(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}},
+	}
+}
@@ -0,0 +1,267 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	builtinActors "github.com/filecoin-project/go-state-types/actors"
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/ipfs/go-cid"
+	"github.com/zondax/golem/pkg/logger"
+
+	builtinv1 "github.com/filecoin-project/specs-actors/actors/builtin"
+	exportedv1 "github.com/filecoin-project/specs-actors/actors/builtin/exported"
+	builtinv2 "github.com/filecoin-project/specs-actors/v2/actors/builtin"
+	exportedv2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/exported"
+	builtinv3 "github.com/filecoin-project/specs-actors/v3/actors/builtin"
+	exportedv3 "github.com/filecoin-project/specs-actors/v3/actors/builtin/exported"
+	builtinv4 "github.com/filecoin-project/specs-actors/v4/actors/builtin"
+	exportedv4 "github.com/filecoin-project/specs-actors/v4/actors/builtin/exported"
+	builtinv5 "github.com/filecoin-project/specs-actors/v5/actors/builtin"
+	exportedv5 "github.com/filecoin-project/specs-actors/v5/actors/builtin/exported"
+	builtinv6 "github.com/filecoin-project/specs-actors/v6/actors/builtin"
+	exportedv6 "github.com/filecoin-project/specs-actors/v6/actors/builtin/exported"
+	builtinv7 "github.com/filecoin-project/specs-actors/v7/actors/builtin"
+	exportedv7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/exported"
+
+	v2 "github.com/zondax/fil-parser/actors/v2"
+	logger2 "github.com/zondax/fil-parser/logger"
+	"github.com/zondax/fil-parser/metrics"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+const draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Document is the JSON Schema of the metadata the parser produces for an actor method at a network version.
+type Document struct {
+	Network string
+	Actor   string
+	Method  string
+	Version string
+	// Typed is false when the param and return types of the method are unknown, so the document accepts any metadata.
+	Typed  bool
+	Schema map[string]interface{}
+}
+
+// Path returns the location of the document relative to the output directory.
+func (d *Document) Path() string {
+	return filepath.Join(d.Network, d.Actor, d.Version, d.Method+".json")
+}
+
+// legacyActor is implemented by the spec-actors of every legacy version.
+type legacyActor interface {
+	Code() cid.Cid
+	Exports() []interface{}
+}
+
+// legacyExports holds the exported methods of the spec-actors, by actor name, for each legacy actors version.
+// The index of a method in the exports is its method number.
+var legacyExports = map[builtinActors.Version]map[string][]interface{}{
+	builtinActors.Version0: exportsByName(exportedv1.BuiltinActors(), builtinv1.ActorNameByCode),
+	builtinActors.Version2: exportsByName(exportedv2.BuiltinActors(), builtinv2.ActorNameByCode),
+	builtinActors.Version3: exportsByName(exportedv3.BuiltinActors(), builtinv3.ActorNameByCode),
+	builtinActors.Version4: exportsByName(exportedv4.BuiltinActors(), builtinv4.ActorNameByCode),
+	builtinActors.Version5: exportsByName(exportedv5.BuiltinActors(), builtinv5.ActorNameByCode),
+	builtinActors.Version6: exportsByName(exportedv6.BuiltinActors(), builtinv6.ActorNameByCode),
+	builtinActors.Version7: exportsByName(exportedv7.BuiltinActors(), builtinv7.ActorNameByCode),
+}
+
+func exportsByName[T legacyActor](legacyActors []T, nameByCode func(cid.Cid) string) map[string][]interface{} {
+	exports := make(map[string][]interface{}, len(legacyActors))
+	for _, actor := range legacyActors {
+		exports[actorKey(nameByCode(actor.Code()))] = actor.Exports()
+	}
+	return exports
+}
+
+func actorKey(actorName string) string {
+	parts := strings.Split(actorName, "/")
+	return parts[len(parts)-1]
+}
+
+// Generator builds the JSON Schema documents of every actor method supported by the parser.
+type Generator struct {
+	network     string
+	logger      *logger.Logger
+	actorParser *v2.ActorParser
+}
+
+func NewGenerator(network string, logger *logger.Logger) *Generator {
+	logger = logger2.GetSafeLogger(logger)
+	return &Generator{
+		network:     network,
+		logger:      logger,
+		actorParser: v2.NewActorParser(network, nil, logger, metrics.NewNoopMetricsClient()).(*v2.ActorParser),
+	}
+}
+
+// Generate returns a document per actor, method and network version, sorted by path.
+// Only the methods an actor exposes at a version get a document, except for Send which every actor accepts.
+// Params and Return are described by the go-state-types or spec-actors types the parser decodes them into,
+// or by the types the parser writes instead for the methods it transforms (see emittedTypes).
+func (g *Generator) Generate(ctx context.Context) ([]*Document, error) {
+	versions := tools.GetSupportedVersions(g.network)
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no supported versions for network %s", g.network)
+	}
+	// the latest actors release includes every actor ever deployed
+	latestActorsVersion, err := builtinActors.VersionForNetwork(versions[len(versions)-1].FilNetworkVersion())
+	if err != nil {
+		return nil, fmt.Errorf("error getting actors version: %w", err)
+	}
+
+	var documents []*Document
+	for _, actorName := range manifest.GetBuiltinActorsKeys(latestActorsVersion) {
+		actor, err := g.actorParser.GetActor(actorName)
+		if err != nil {
+			return nil, fmt.Errorf("error getting actor %s: %w", actorName, err)
+		}
+		for _, version := range versions {
+			height := version.Height()
+			if actor.StartNetworkHeight() > height {
+				continue
+			}
+			methods, err := actor.Methods(ctx, g.network, height)
+			if err != nil {
+				g.logger.Debugf("skipping actor %s at version %s: %s", actorName, version, err)
+				continue
+			}
+			actorsVersion, err := builtinActors.VersionForNetwork(version.FilNetworkVersion())
+			if err != nil {
+				return nil, fmt.Errorf("error getting actors version of %s: %w", version, err)
+			}
+
+			methodTypes := make(map[string][2]reflect.Type, len(methods))
+			for methodNum, method := range methods {
+				params, ret, ok := g.methodTypes(actorName, actorsVersion, uint64(methodNum), method.Method)
+				if !ok {
+					// the method exists but its types are unknown, e.g. it is deprecated
+					methodTypes[method.Name] = [2]reflect.Type{}
+					continue
+				}
+				methodTypes[method.Name] = [2]reflect.Type{params, ret}
+			}
+
+			for txType := range actor.TransactionTypes() {
+				types, ok := methodTypes[txType]
+				if !ok && txType != parser.MethodSend {
+					continue
+				}
+				params, ret, extra := emittedTypesOf(actorName, txType, types[0], types[1])
+				documents = append(documents, newDocument(g.network, actorName, txType, version.String(), params, ret, extra))
+			}
+		}
+	}
+
+	sort.Slice(documents, func(i, j int) bool {
+		return documents[i].Path() < documents[j].Path()
+	})
+	return documents, nil
+}
+
+// methodTypes returns the param and return types of an actor method.
+// Builtin-actors methods are typed in go-state-types, while the legacy ones are looked up in the spec-actors exports.
+func (g *Generator) methodTypes(actorName string, actorsVersion builtinActors.Version, methodNum uint64, method interface{}) (reflect.Type, reflect.Type, bool) {
+	if exports, ok := legacyExports[actorsVersion]; ok {
+		actorExports := exports[actorName]
+		if methodNum >= uint64(len(actorExports)) || actorExports[methodNum] == nil {
+			return nil, nil, false
+		}
+		// spec-actors methods take the runtime as first argument: func(rt runtime.Runtime, params *T) *R
+		fn := reflect.TypeOf(actorExports[methodNum])
+		if fn.Kind() != reflect.Func || fn.NumIn() != 2 || fn.NumOut() != 1 {
+			return nil, nil, false
+		}
+		return fn.In(1), fn.Out(0), true
+	}
+
+	if method == nil {
+		return nil, nil, false
+	}
+	// go-state-types methods are typed as func(params *T) *R
+	fn := reflect.TypeOf(method)
+	if fn.Kind() != reflect.Func || fn.NumIn() != 1 || fn.NumOut() != 1 {
+		return nil, nil, false
+	}
+	return fn.In(0), fn.Out(0), true
+}
+
+func newDocument(network, actorName, method, version string, params, ret reflect.Type, extra map[string]reflect.Type) *Document {
+	r := newReflector()
+	properties := map[string]interface{}{
+		// added by the parser to the metadata of every method
+		parser.MethodNumKey: map[string]interface{}{"type": "string"},
+	}
+	for key, t := range extra {
+		properties[key] = r.schemaOf(t)
+	}
+	typed := params != nil && ret != nil
+	switch {
+	case method == parser.MethodSend:
+		// Send keeps the raw params
+		properties[parser.ParamsKey] = map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+		typed = true
+	case typed:
+		// the metadata holds the decoded values, never a null pointer
+		if params = elem(params); params != emptyValueType {
+			properties[parser.ParamsKey] = r.schemaOf(params)
+		}
+		if ret = elem(ret); ret != emptyValueType {
+			properties[parser.ReturnKey] = r.schemaOf(ret)
+		}
+	default:
+		properties[parser.ParamsKey] = map[string]interface{}{}
+		properties[parser.ReturnKey] = map[string]interface{}{}
+	}
+
+	schema := map[string]interface{}{
+		"$schema":    draft,
+		"title":      fmt.Sprintf("%s %s %s metadata", actorName, method, version),
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(r.defs) > 0 {
+		schema["$defs"] = r.defs
+	}
+
+	return &Document{
+		Network: network,
+		Actor:   actorName,
+		Method:  method,
+		Version: version,
+		Typed:   typed,
+		Schema:  schema,
+	}
+}
+
+func elem(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// Write stores every document under dir, at the document path.
+func Write(dir string, documents []*Document) error {
+	for _, document := range documents {
+		path := filepath.Join(dir, document.Path())
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return fmt.Errorf("error creating directory for %s: %w", path, err)
+		}
+		data, err := json.MarshalIndent(document.Schema, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshalling schema %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+			return fmt.Errorf("error writing schema %s: %w", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,389 @@
+package schema_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	stateBig "github.com/filecoin-project/go-state-types/big"
+	eam15 "github.com/filecoin-project/go-state-types/builtin/v15/eam"
+	init15 "github.com/filecoin-project/go-state-types/builtin/v15/init"
+	multisig15 "github.com/filecoin-project/go-state-types/builtin/v15/multisig"
+	power15 "github.com/filecoin-project/go-state-types/builtin/v15/power"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/manifest"
+	filApiTypes "github.com/filecoin-project/lotus/api/types"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	typegen "github.com/whyrusleeping/cbor-gen"
+	actorsV2 "github.com/zondax/fil-parser/actors/v2"
+	"github.com/zondax/fil-parser/actors/v2/schema"
+	filMetrics "github.com/zondax/fil-parser/metrics"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/parser/helper"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/mocks"
+	"github.com/zondax/golem/pkg/logger"
+	metrics2 "github.com/zondax/golem/pkg/metrics"
+	rosettaFilecoinLib "github.com/zondax/rosetta-filecoin-lib"
+)
+
+func findDocument(documents []*schema.Document, actor, method, version string) *schema.Document {
+	for _, document := range documents {
+		if document.Actor == actor && document.Method == method && document.Version == version {
+			return document
+		}
+	}
+	return nil
+}
+
+// definition resolves the $ref of a property to its definition.
+func definition(t *testing.T, document *schema.Document, key string) map[string]interface{} {
+	properties := document.Schema["properties"].(map[string]interface{})
+	require.Containsf(t, properties, key, "%s has no %s", document.Path(), key)
+	ref, ok := properties[key].(map[string]interface{})["$ref"].(string)
+	require.True(t, ok)
+	defs := document.Schema["$defs"].(map[string]interface{})
+	def, ok := defs[filepath.Base(ref)].(map[string]interface{})
+	require.Truef(t, ok, "missing definition %s", ref)
+	return def
+}
+
+// addressInfoKeys are the keys of the AddressInfo the parser writes as the return of the methods creating an actor.
+var addressInfoKeys = []string{"short", "robust", "eth_address", "actor_cid", "actor_type", "creation_tx_cid"}
+
+func TestGenerate(t *testing.T) {
+	documents, err := schema.NewGenerator(tools.MainnetNetwork, nil).Generate(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, documents)
+
+	tests := []struct {
+		name       string
+		actor      string
+		method     string
+		version    string
+		params     []string
+		returnKeys []string
+	}{
+		{
+			name:       "legacy power CreateMiner",
+			actor:      manifest.PowerKey,
+			method:     parser.MethodCreateMiner,
+			version:    tools.V4.String(),
+			params:     []string{"Owner", "Worker", "SealProofType", "Peer", "Multiaddrs"},
+			returnKeys: addressInfoKeys,
+		},
+		{
+			name:       "power CreateMiner",
+			actor:      manifest.PowerKey,
+			method:     parser.MethodCreateMiner,
+			version:    tools.V24.String(),
+			params:     []string{"Owner", "Worker", "WindowPoStProofType", "Peer", "Multiaddrs"},
+			returnKeys: addressInfoKeys,
+		},
+		{
+			name:       "init Exec",
+			actor:      manifest.InitKey,
+			method:     parser.MethodExec,
+			version:    tools.V24.String(),
+			params:     []string{"CodeCid", "constructorParams"},
+			returnKeys: addressInfoKeys,
+		},
+		{
+			name:       "miner TerminateSectors",
+			actor:      manifest.MinerKey,
+			method:     parser.MethodTerminateSectors,
+			version:    tools.V24.String(),
+			params:     []string{"Terminations"},
+			returnKeys: []string{"Done"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document := findDocument(documents, tt.actor, tt.method, tt.version)
+			require.NotNil(t, document)
+			assert.True(t, document.Typed)
+
+			params := definition(t, document, parser.ParamsKey)
+			assert.ElementsMatch(t, tt.params, params["required"])
+			ret := definition(t, document, parser.ReturnKey)
+			assert.ElementsMatch(t, tt.returnKeys, ret["required"])
+		})
+	}
+
+	t.Run("encoded types", func(t *testing.T) {
+		document := findDocument(documents, manifest.PowerKey, parser.MethodCreateMiner, tools.V24.String())
+		require.NotNil(t, document)
+		properties := definition(t, document, parser.ParamsKey)["properties"].(map[string]interface{})
+		assert.Equal(t, "string", properties["Owner"].(map[string]interface{})["type"])
+		assert.Equal(t, "integer", properties["WindowPoStProofType"].(map[string]interface{})["type"])
+	})
+
+	t.Run("empty params are omitted", func(t *testing.T) {
+		document := findDocument(documents, manifest.PowerKey, parser.MethodCurrentTotalPower, tools.V24.String())
+		require.NotNil(t, document)
+		properties := document.Schema["properties"].(map[string]interface{})
+		assert.NotContains(t, properties, parser.ParamsKey)
+		assert.Contains(t, properties, parser.ReturnKey)
+	})
+
+	t.Run("send on every actor", func(t *testing.T) {
+		for _, actor := range []string{manifest.AccountKey, manifest.MultisigKey, manifest.EvmKey} {
+			assert.NotNilf(t, findDocument(documents, actor, parser.MethodSend, tools.V24.String()), "missing Send for %s", actor)
+		}
+	})
+
+	t.Run("methods missing at a version", func(t *testing.T) {
+		assert.Nil(t, findDocument(documents, manifest.EvmKey, parser.MethodInvokeContract, tools.V10.String()))
+		assert.NotNil(t, findDocument(documents, manifest.EvmKey, parser.MethodInvokeContract, tools.V24.String()))
+	})
+}
+
+func TestWrite(t *testing.T) {
+	documents, err := schema.NewGenerator(tools.MainnetNetwork, nil).Generate(context.Background())
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, schema.Write(dir, documents))
+
+	document := findDocument(documents, manifest.MinerKey, parser.MethodPreCommitSectorBatch2, tools.V24.String())
+	require.NotNil(t, document)
+	data, err := os.ReadFile(filepath.Join(dir, tools.MainnetNetwork, manifest.MinerKey, tools.V24.String(), parser.MethodPreCommitSectorBatch2+".json"))
+	require.NoError(t, err)
+
+	var written map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", written["$schema"])
+	assert.Contains(t, written["$defs"], "miner.SectorPreCommitInfo")
+}
+
+const (
+	msgCidStr  = "bafy2bzacebbpdegvr3i4cosewthysg5xkxpqfn2wfcz6mv2hmoktwbdxkax4s"
+	msigCidStr = "bafk2bzacect2p7urje3pylrrrjy3tngn6yaih4gtzauuatf2jllasuxd3tyxu"
+)
+
+// parserMetadata runs params and ret through the actor parser and returns the metadata as the parser encodes it.
+func parserMetadata(t *testing.T, actorName, method string, height int64, params, ret typegen.CBORMarshaler) interface{} {
+	msgCid, err := cid.Parse(msgCidStr)
+	require.NoError(t, err)
+	msigCid, err := cid.Parse(msigCidStr)
+	require.NoError(t, err)
+
+	logger := logger.NewDevelopmentLogger()
+	metrics := filMetrics.NewMetricsClient(metrics2.NewNoopMetrics())
+	node := &mocks.FullNode{}
+	node.On("StateNetworkName", mock.Anything).Return(dtypes.NetworkName(tools.MainnetNetwork), nil)
+	node.On("StateNetworkVersion", mock.Anything, mock.Anything).Return(filApiTypes.NetworkVersion(16), nil)
+	node.On("StateActorCodeCIDs", mock.Anything, mock.Anything).Return(map[string]cid.Cid{manifest.MultisigKey: msigCid}, nil)
+	cache := &mocks.IActorsCache{}
+	cache.On("StoreAddressInfo", mock.Anything).Return(nil)
+	h := helper.NewHelper(rosettaFilecoinLib.NewRosettaConstructionFilecoin(node), cache, node, logger, metrics)
+
+	var rawParams, rawReturn bytes.Buffer
+	require.NoError(t, params.MarshalCBOR(&rawParams))
+	if ret != nil {
+		require.NoError(t, ret.MarshalCBOR(&rawReturn))
+	}
+	id, err := address.NewIDAddress(1500)
+	require.NoError(t, err)
+	msg := &parser.LotusMessage{To: id, From: id, Cid: msgCid, Params: rawParams.Bytes()}
+	_, metadata, _, err := actorsV2.NewActorParser(tools.MainnetNetwork, h, logger, metrics).GetMetadata(context.Background(), actorName, method, msg, msgCid,
+		&parser.LotusMessageReceipt{ExitCode: exitcode.Ok, Return: rawReturn.Bytes()}, height, filTypes.EmptyTSK, true)
+	require.NoError(t, err)
+	metadata[parser.MethodNumKey] = "2"
+
+	encoded, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	require.NoError(t, decoder.Decode(&decoded))
+	return decoded
+}
+
+// validate checks value against the subset of JSON Schema the generator produces.
+func validate(schemaDoc map[string]interface{}, node map[string]interface{}, value interface{}, path string) error {
+	if ref, ok := node["$ref"].(string); ok {
+		def, ok := schemaDoc["$defs"].(map[string]interface{})[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: missing definition %s", path, ref)
+		}
+		return validate(schemaDoc, def, value, path)
+	}
+	if anyOf, ok := node["anyOf"].([]interface{}); ok {
+		var errs []string
+		for _, option := range anyOf {
+			err := validate(schemaDoc, option.(map[string]interface{}), value, path)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err.Error())
+		}
+		return fmt.Errorf("%s: no option matches: %s", path, strings.Join(errs, "; "))
+	}
+
+	switch node["type"] {
+	case nil:
+		return nil
+	case "null":
+		if value != nil {
+			return fmt.Errorf("%s: expected null, got %T", path, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean, got %T", path, value)
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok || strings.ContainsAny(number.String(), ".eE") {
+			return fmt.Errorf("%s: expected integer, got %v", path, value)
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return fmt.Errorf("%s: expected number, got %T", path, value)
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected string, got %T", path, value)
+		}
+		if pattern, ok := node["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(str) {
+			return fmt.Errorf("%s: %q does not match %s", path, str, pattern)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected array, got %T", path, value)
+		}
+		for i, item := range items {
+			if err := validate(schemaDoc, node["items"].(map[string]interface{}), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected object, got %T", path, value)
+		}
+		var required []string
+		switch r := node["required"].(type) {
+		case []string:
+			required = r
+		case []interface{}:
+			for _, key := range r {
+				required = append(required, key.(string))
+			}
+		}
+		for _, key := range required {
+			if _, ok := object[key]; !ok {
+				return fmt.Errorf("%s: missing required key %s", path, key)
+			}
+		}
+		properties, _ := node["properties"].(map[string]interface{})
+		for key, item := range object {
+			property, ok := properties[key].(map[string]interface{})
+			if !ok {
+				if additional, ok := node["additionalProperties"].(map[string]interface{}); ok {
+					property = additional
+				} else {
+					continue
+				}
+			}
+			if err := validate(schemaDoc, property, item, path+"."+key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TestGenerate_ParserOutput validates the metadata the parser writes against the published schema,
+// for methods whose params or return are transformed by the parser.
+func TestGenerate_ParserOutput(t *testing.T) {
+	documents, err := schema.NewGenerator(tools.MainnetNetwork, nil).Generate(context.Background())
+	require.NoError(t, err)
+	height := tools.V24.Height()
+	msigCid, err := cid.Parse(msigCidStr)
+	require.NoError(t, err)
+	owner, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+	robust, err := address.NewFromString("f2ddsjma6hfwcqhdp4vv6z4t5fighlhrjrqyxcekq")
+	require.NoError(t, err)
+
+	var ctorParams bytes.Buffer
+	require.NoError(t, (&multisig15.ConstructorParams{Signers: []address.Address{owner}, NumApprovalsThreshold: 1}).MarshalCBOR(&ctorParams))
+	invokeParams, invokeReturn := abi.CborBytes{0xa9, 0x05}, abi.CborBytes{0x01}
+
+	tests := []struct {
+		actor  string
+		method string
+		params typegen.CBORMarshaler
+		ret    typegen.CBORMarshaler
+	}{
+		{
+			actor:  manifest.InitKey,
+			method: parser.MethodExec,
+			params: &init15.ExecParams{CodeCID: msigCid, ConstructorParams: ctorParams.Bytes()},
+			ret:    &init15.ExecReturn{IDAddress: owner, RobustAddress: robust},
+		},
+		{
+			actor:  manifest.PowerKey,
+			method: parser.MethodCreateMiner,
+			params: &power15.CreateMinerParams{Owner: owner, Worker: owner, WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1_1},
+			ret:    &power15.CreateMinerReturn{IDAddress: owner, RobustAddress: robust},
+		},
+		{
+			actor:  manifest.EamKey,
+			method: parser.MethodCreate,
+			params: &eam15.CreateParams{Initcode: []byte{0x60, 0x80}, Nonce: 3},
+			ret:    &eam15.CreateReturn{ActorID: 1600, RobustAddress: &robust, EthAddress: [20]byte{0xd4}},
+		},
+		{
+			actor:  manifest.EvmKey,
+			method: parser.MethodInvokeContract,
+			params: &invokeParams,
+			ret:    &invokeReturn,
+		},
+		{
+			actor:  manifest.MultisigKey,
+			method: parser.MethodAddSigner,
+			params: &multisig15.AddSignerParams{Signer: owner, Increase: true},
+		},
+		{
+			actor:  manifest.PowerKey,
+			method: parser.MethodUpdatePledgeTotal,
+			params: func() *abi.TokenAmount { amount := stateBig.NewInt(10); return &amount }(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.actor+" "+tt.method, func(t *testing.T) {
+			document := findDocument(documents, tt.actor, tt.method, tools.V24.String())
+			require.NotNil(t, document)
+			require.True(t, document.Typed)
+
+			metadata := parserMetadata(t, tt.actor, tt.method, height, tt.params, tt.ret)
+			assert.NoError(t, validate(document.Schema, document.Schema, metadata, "metadata"))
+		})
+	}
+
+	t.Run("reflected types do not match transformed output", func(t *testing.T) {
+		document := findDocument(documents, manifest.InitKey, parser.MethodExec, tools.V24.String())
+		require.NotNil(t, document)
+		metadata := map[string]interface{}{
+			parser.ParamsKey: map[string]interface{}{"CodeCID": map[string]interface{}{"/": msigCidStr}, "ConstructorParams": ""},
+		}
+		assert.Error(t, validate(document.Schema, document.Schema, metadata, "metadata"))
+	})
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zondax/fil-parser/actors/v2/schema"
+	logger2 "github.com/zondax/fil-parser/logger"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func main() {
+	cmd := &cobra.Command{
+		Use:   "schemagen",
+		Short: "Generate the JSON Schema of the params and return of every actor method",
+		RunE:  generate,
+	}
+	cmd.Flags().String("network", tools.MainnetNetwork, "--network calibration")
+	cmd.Flags().String("outPath", "schemas", "--outPath ../../schemas")
+
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func generate(cmd *cobra.Command, _ []string) error {
+	logger := logger2.GetSafeLogger(nil)
+
+	network, err := cmd.Flags().GetString("network")
+	if err != nil {
+		return err
+	}
+	outPath, err := cmd.Flags().GetString("outPath")
+	if err != nil {
+		return err
+	}
+
+	documents, err := schema.NewGenerator(network, logger).Generate(context.Background())
+	if err != nil {
+		return err
+	}
+	if err := schema.Write(outPath, documents); err != nil {
+		return err
+	}
+
+	untyped := 0
+	for _, document := range documents {
+		if !document.Typed {
+			untyped++
+		}
+	}
+	logger.Infof("wrote %d schemas to %s (%d without known types)", len(documents), outPath, untyped)
+	return nil
+}
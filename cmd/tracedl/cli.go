@@ -80,6 +80,8 @@ func get(c *cli.CLI, cmd *cobra.Command, _ []string) {
 		data, err = getNativeLogsByHeight(height, rpcClient.client)
 	case "metadata":
 		data, err = getMetadata(rpcClient)
+	case "rpc":
+		data, err = recordNodeCalls(height, config, rpcClient)
 	}
 
 	if err != nil {
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	lotusChainTypes "github.com/filecoin-project/lotus/chain/types"
+	filParser "github.com/zondax/fil-parser"
+	"github.com/zondax/fil-parser/actors/cache/impl/common"
+	"github.com/zondax/fil-parser/tools/recorder"
+	"github.com/zondax/fil-parser/types"
+	rosettaFilecoinLib "github.com/zondax/rosetta-filecoin-lib"
+)
+
+// recordNodeCalls parses the height while recording every call made to the node,
+// so the height can later be parsed offline with recorder.NewReplayer.
+func recordNodeCalls(height uint64, config *Config, rpcClient *RPCClient) (*recorder.Fixture, error) {
+	traces, err := getTraceFileByHeight(height, rpcClient.client)
+	if err != nil {
+		return nil, err
+	}
+	tipset, err := getTipsetFileByHeight(height, lotusChainTypes.EmptyTSK, rpcClient.client)
+	if err != nil {
+		return nil, err
+	}
+	if traces == nil || tipset == nil {
+		return nil, fmt.Errorf("no data found for height %d", height)
+	}
+	ethLogs, err := getEthLogsByHeight(height, rpcClient.client)
+	if err != nil {
+		return nil, err
+	}
+	nativeLogs, err := getNativeLogsByHeight(height, rpcClient.client)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := getMetadata(rpcClient)
+	if err != nil {
+		return nil, err
+	}
+	tracesJson, err := json.Marshal(traces)
+	if err != nil {
+		return nil, err
+	}
+
+	// everything the parser needs from the node goes through the recorder, including the library setup
+	node := recorder.NewRecorder(rpcClient.client)
+	lib := rosettaFilecoinLib.NewRosettaConstructionFilecoin(node)
+	if lib == nil {
+		return nil, fmt.Errorf("could not create instance of rosetta filecoin-lib")
+	}
+	source := common.DataSource{
+		Node:   node,
+		Config: common.DataSourceConfig{NetworkName: config.NetworkName},
+	}
+	parser, err := filParser.NewFilecoinParserWithActorV2(lib, source, l)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = parser.ParseTipset(context.Background(), &types.HeightData{
+		Height:    height,
+		Traces:    tracesJson,
+		Tipset:    tipset,
+		NativeLog: nativeLogs,
+		EthLogs:   ethLogs,
+		Metadata:  metadata,
+		Canonical: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not parse height %d: %w", height, err)
+	}
+
+	return node.Fixture(), nil
+}
@@ -33,13 +33,12 @@ import (
 )
 
 var (
-	errUnknownImpl    = errors.New("unknown implementation")
 	errUnknownVersion = errors.New("unknown trace version")
+	errNoNetworkName  = errors.New("network name is required when running without a node, use WithNetworkName")
 )
 
 type FilecoinParser struct {
-	parserV1 Parser
-	parserV2 Parser
+	registry *ParserRegistry
 	Helper   *helper2.Helper
 	logger   *logger.Logger
 	network  string
@@ -50,12 +49,17 @@ type Parser interface {
 	NodeVersionsSupported() []string
 	GetConfig() parser.Config
 	ParseTransactions(ctx context.Context, txsData types.TxsData) (*types.TxsParsedResult, error)
+	ParseTransactionsStream(ctx context.Context, txsData types.TxsData, handler types.TxsChunkHandler) error
 	ParseNativeEvents(ctx context.Context, eventsData types.EventsData) (*types.EventsParsedResult, error)
 	ParseMultisigEvents(ctx context.Context, multisigTxs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.MultisigEvents, error)
 	ParseMinerEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.MinerEvents, error)
 	ParseVerifregEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.VerifregEvents, error)
 	ParseDataCapEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.DataCapEvents, error)
 	ParseDealsEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.DealsEvents, error)
+	ParsePaymentChannelEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.PaymentChannelEvents, error)
+	ParsePowerEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.PowerEvents, error)
+	ParseBlockRewardEvents(ctx context.Context, txs []*types.Transaction, tipset *types.ExtendedTipSet) (*types.BlockRewardEvents, error)
+	ParseActorCreations(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.ActorCreationEvents, error)
 	ParseEthLogs(ctx context.Context, eventsData types.EventsData) (*types.EventsParsedResult, error)
 	GetBaseFee(traces []byte, tipset *types.ExtendedTipSet) (uint64, error)
 	IsNodeVersionSupported(ver string) bool
@@ -76,32 +80,37 @@ func NewFilecoinParser(lib *rosettaFilecoinLib.RosettaConstructionFilecoin, cach
 	}
 
 	logger = logger2.GetSafeLogger(logger)
-	actorsCache, err := cache.SetupActorsCache(cacheSource, logger, defaultOpts.metrics, defaultOpts.backoff)
+	networkName, err := getNetworkName(cacheSource, defaultOpts)
 	if err != nil {
-		logger.Errorf("could not setup actors cache: %v", err)
+		logger.Error(err.Error())
 		return nil, err
 	}
 
-	helper := helper2.NewHelper(lib, actorsCache, cacheSource.Node, logger, defaultOpts.metrics)
-	if helper == nil {
-		return nil, errors.New("helper is nil")
-	}
-
-	network, err := helper.GetFilecoinNodeClient().StateNetworkName(context.Background())
+	actorsCache, err := cache.SetupActorsCache(cacheSource, logger, defaultOpts.metrics, defaultOpts.backoff)
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Errorf("could not setup actors cache: %v", err)
 		return nil, err
 	}
 
+	helper := helper2.NewHelperWithNetwork(lib, actorsCache, cacheSource.Node, networkName, logger, defaultOpts.metrics)
+	if helper.IsOffline() {
+		logger.Infof("[parser] - running offline for network %s", networkName)
+	}
+
 	parserV1 := v1.NewParser(helper, logger, defaultOpts.metrics, defaultOpts.backoff, defaultOpts.config)
 	parserV2 := v2.NewParser(helper, logger, defaultOpts.metrics, defaultOpts.backoff, defaultOpts.config)
 
+	registry := NewParserRegistry(defaultOpts.versionFallback)
+	if err := registerDefaultParsers(registry, parserV1, TraceFormatV1, parserV2); err != nil {
+		logger.Errorf("could not register parsers: %v", err)
+		return nil, err
+	}
+
 	return &FilecoinParser{
-		parserV1: parserV1,
-		parserV2: parserV2,
+		registry: registry,
 		Helper:   helper,
 		logger:   logger,
-		network:  tools.ParseRawNetworkName(string(network)),
+		network:  networkName,
 	}, nil
 }
 
@@ -120,117 +129,160 @@ func NewFilecoinParserWithActorV2(lib *rosettaFilecoinLib.RosettaConstructionFil
 	}
 
 	logger = logger2.GetSafeLogger(logger)
-	actorsCache, err := cache.SetupActorsCache(cacheSource, logger, defaultOpts.metrics, defaultOpts.backoff)
+	networkName, err := getNetworkName(cacheSource, defaultOpts)
 	if err != nil {
-		logger.Errorf("could not setup actors cache: %v", err)
+		logger.Error(err.Error())
 		return nil, err
 	}
 
-	helper := helper2.NewHelper(lib, actorsCache, cacheSource.Node, logger, defaultOpts.metrics)
-	if helper == nil {
-		return nil, errors.New("helper is nil")
-	}
-	network, err := helper.GetFilecoinNodeClient().StateNetworkName(context.Background())
+	actorsCache, err := cache.SetupActorsCache(cacheSource, logger, defaultOpts.metrics, defaultOpts.backoff)
 	if err != nil {
-		logger.Error(err.Error())
+		logger.Errorf("could not setup actors cache: %v", err)
 		return nil, err
 	}
-	networkName := tools.ParseRawNetworkName(string(network))
+
+	helper := helper2.NewHelperWithNetwork(lib, actorsCache, cacheSource.Node, networkName, logger, defaultOpts.metrics)
+	if helper.IsOffline() {
+		logger.Infof("[parser] - running offline for network %s", networkName)
+	}
 
 	var parserV1 Parser
 	var parserV2 Parser
 
 	parserV1 = v1.NewActorsV2Parser(networkName, helper, logger, defaultOpts.metrics, defaultOpts.backoff, defaultOpts.config)
+	parserV1TraceFormat := TraceFormatV1
 	if networkName == tools.CalibrationNetwork {
 		// trace files already use executiontracev2 because of a resync and calibration resets
 		// so we need to use the new parser regardless of the height
 		parserV1 = v2.NewActorsV2Parser(networkName, helper, logger, defaultOpts.metrics, defaultOpts.backoff, defaultOpts.config)
+		parserV1TraceFormat = TraceFormatV2
 	}
 
 	parserV2 = v2.NewActorsV2Parser(networkName, helper, logger, defaultOpts.metrics, defaultOpts.backoff, defaultOpts.config)
 
+	registry := NewParserRegistry(defaultOpts.versionFallback)
+	if err := registerDefaultParsers(registry, parserV1, parserV1TraceFormat, parserV2); err != nil {
+		logger.Errorf("could not register parsers: %v", err)
+		return nil, err
+	}
+
 	return &FilecoinParser{
-		parserV1: parserV1,
-		parserV2: parserV2,
+		registry: registry,
 		Helper:   helper,
 		logger:   logger,
 		network:  networkName,
 	}, nil
 }
 
-func (p *FilecoinParser) ParseTransactions(ctx context.Context, txsData types.TxsData) (*types.TxsParsedResult, error) {
-	parserVersion, err := p.translateParserVersionFromMetadata(txsData.Metadata)
-	if err != nil {
-		return nil, errUnknownVersion
+// registerDefaultParsers registers the parsers handling the node versions supported by parser/v1 and parser/v2.
+func registerDefaultParsers(registry *ParserRegistry, parserV1 Parser, parserV1TraceFormat string, parserV2 Parser) error {
+	if err := registry.Register(parserV1, parserV1TraceFormat, NodeVersions(v1.NodeVersionsSupported...)); err != nil {
+		return fmt.Errorf("could not register parser %s: %w", v1.Version, err)
 	}
+	if err := registry.Register(parserV2, TraceFormatV2, NodeVersions(v2.NodeVersionsSupported...)); err != nil {
+		return fmt.Errorf("could not register parser %s: %w", v2.Version, err)
+	}
+	return nil
+}
+
+// Registry returns the registry used to pick the parser for each node version.
+// Parsers registered on it take precedence over the built-in ones for the node versions they handle.
+func (p *FilecoinParser) Registry() *ParserRegistry {
+	return p.registry
+}
 
-	var parsedResult *types.TxsParsedResult
+// getNetworkName returns the network name set through WithNetworkName, or asks the node for it otherwise.
+func getNetworkName(cacheSource common.DataSource, opts FilecoinParserOptions) (string, error) {
+	if opts.networkName != "" {
+		return tools.ParseRawNetworkName(opts.networkName), nil
+	}
+	if cacheSource.Node == nil {
+		return "", errNoNetworkName
+	}
+	network, err := cacheSource.Node.StateNetworkName(context.Background())
+	if err != nil {
+		return "", err
+	}
+	return tools.ParseRawNetworkName(string(network)), nil
+}
 
-	p.logger.Debugf("trace files node version: [%s] - parser to use: [%s]", txsData.Metadata.NodeMajorMinorVersion, parserVersion)
-	switch parserVersion {
-	case v1.Version:
-		parsedResult, err = p.parserV1.ParseTransactions(ctx, txsData)
-	case v2.Version:
-		parsedResult, err = p.parserV2.ParseTransactions(ctx, txsData)
-	default:
-		p.logger.Errorf("[parser] implementation not supported: %s", parserVersion)
-		return nil, errUnknownImpl
+func (p *FilecoinParser) ParseTransactions(ctx context.Context, txsData types.TxsData) (*types.TxsParsedResult, error) {
+	resolved, err := p.resolveParser(txsData.Metadata)
+	if err != nil {
+		return nil, err
 	}
 
+	parsedResult, err := resolved.Parser.ParseTransactions(ctx, txsData)
 	if err != nil {
 		return nil, err
 	}
 
 	parsedResult.Txs = p.FilterDuplicated(parsedResult.Txs)
+	parsedResult.VersionFallback = resolved.Fallback
 
 	return parsedResult, nil
 }
 
-func (p *FilecoinParser) ParseNativeEvents(ctx context.Context, eventsData types.EventsData) (*types.EventsParsedResult, error) {
-	parserVersion, err := p.translateParserVersionFromMetadata(eventsData.Metadata)
+// ParseTransactionsStream parses the traces one top-level trace at a time and hands every finished chunk to handler.
+// Transactions already emitted in a previous chunk of the same call are dropped, following FilterDuplicated.
+// The handler can return parser.ErrStreamStopped to stop consuming the traces without failing the call.
+func (p *FilecoinParser) ParseTransactionsStream(ctx context.Context, txsData types.TxsData, handler types.TxsChunkHandler) error {
+	resolved, err := p.resolveParser(txsData.Metadata)
 	if err != nil {
-		return nil, errUnknownVersion
+		return err
+	}
+
+	idsFound := make(map[string]bool)
+	filterDuplicated := func(chunk *types.TxsParsedChunk) error {
+		filteredTxs := make([]*types.Transaction, 0, len(chunk.Txs))
+		for _, tx := range chunk.Txs {
+			if !idsFound[tx.Id] {
+				idsFound[tx.Id] = true
+				filteredTxs = append(filteredTxs, tx)
+			}
+		}
+		chunk.Txs = filteredTxs
+		chunk.VersionFallback = resolved.Fallback
+		return handler(chunk)
 	}
 
-	var parsedResult *types.EventsParsedResult
+	err = resolved.Parser.ParseTransactionsStream(ctx, txsData, filterDuplicated)
+	if errors.Is(err, parser.ErrStreamStopped) {
+		return nil
+	}
+
+	return err
+}
 
-	p.logger.Debugf("trace files node version: [%s] - parser to use: [%s]", eventsData.Metadata.NodeMajorMinorVersion, parserVersion)
-	switch parserVersion {
-	case v1.Version, v2.Version:
-		parsedResult, err = p.parserV2.ParseNativeEvents(ctx, eventsData)
-	default:
-		p.logger.Errorf("[parser] implementation not supported: %s", parserVersion)
-		return nil, errUnknownImpl
+func (p *FilecoinParser) ParseNativeEvents(ctx context.Context, eventsData types.EventsData) (*types.EventsParsedResult, error) {
+	resolved, err := p.resolveEventsParser(eventsData.Metadata)
+	if err != nil {
+		return nil, err
 	}
 
+	parsedResult, err := resolved.Parser.ParseNativeEvents(ctx, eventsData)
 	if err != nil {
 		return nil, err
 	}
 
+	parsedResult.VersionFallback = resolved.Fallback
+
 	return parsedResult, nil
 }
 
 func (p *FilecoinParser) ParseEthLogs(ctx context.Context, eventsData types.EventsData) (*types.EventsParsedResult, error) {
-	parserVersion, err := p.translateParserVersionFromMetadata(eventsData.Metadata)
+	resolved, err := p.resolveEventsParser(eventsData.Metadata)
 	if err != nil {
-		return nil, errUnknownVersion
-	}
-
-	var parsedResult *types.EventsParsedResult
-
-	p.logger.Debugf("trace files node version: [%s] - parser to use: [%s]", eventsData.Metadata.NodeMajorMinorVersion, parserVersion)
-	switch parserVersion {
-	case v1.Version, v2.Version:
-		parsedResult, err = p.parserV2.ParseEthLogs(ctx, eventsData)
-	default:
-		p.logger.Errorf("[parser] implementation not supported: %s", parserVersion)
-		return nil, errUnknownImpl
+		return nil, err
 	}
 
+	parsedResult, err := resolved.Parser.ParseEthLogs(ctx, eventsData)
 	if err != nil {
 		return nil, err
 	}
 
+	parsedResult.VersionFallback = resolved.Fallback
+
 	return parsedResult, nil
 }
 
@@ -239,36 +291,117 @@ func (p *FilecoinParser) ParseMultisigEvents(ctx context.Context, txs []*types.T
 	if err != nil {
 		return nil, err
 	}
-	return p.parserV2.ParseMultisigEvents(ctx, multisigTxs, tipsetCid, tipsetKey)
+	events, err := p.txsEventsParser(txs)
+	if err != nil {
+		return nil, err
+	}
+	return events.ParseMultisigEvents(ctx, multisigTxs, tipsetCid, tipsetKey)
 }
 
 func (p *FilecoinParser) ParseMinerEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.MinerEvents, error) {
-	return p.parserV2.ParseMinerEvents(ctx, txs, tipsetCid, tipsetKey)
+	events, err := p.txsEventsParser(txs)
+	if err != nil {
+		return nil, err
+	}
+	return events.ParseMinerEvents(ctx, txs, tipsetCid, tipsetKey)
 }
 
 func (p *FilecoinParser) ParseVerifregEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.VerifregEvents, error) {
-	return p.parserV2.ParseVerifregEvents(ctx, txs, tipsetCid, tipsetKey)
+	events, err := p.txsEventsParser(txs)
+	if err != nil {
+		return nil, err
+	}
+	return events.ParseVerifregEvents(ctx, txs, tipsetCid, tipsetKey)
 }
 
 func (p *FilecoinParser) ParseDealsEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.DealsEvents, error) {
-	return p.parserV2.ParseDealsEvents(ctx, txs, tipsetCid, tipsetKey)
+	events, err := p.txsEventsParser(txs)
+	if err != nil {
+		return nil, err
+	}
+	return events.ParseDealsEvents(ctx, txs, tipsetCid, tipsetKey)
+}
+
+func (p *FilecoinParser) ParsePaymentChannelEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.PaymentChannelEvents, error) {
+	events, err := p.txsEventsParser(txs)
+	if err != nil {
+		return nil, err
+	}
+	return events.ParsePaymentChannelEvents(ctx, txs, tipsetCid, tipsetKey)
+}
+
+func (p *FilecoinParser) ParsePowerEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.PowerEvents, error) {
+	events, err := p.txsEventsParser(txs)
+	if err != nil {
+		return nil, err
+	}
+	return events.ParsePowerEvents(ctx, txs, tipsetCid, tipsetKey)
+}
+
+func (p *FilecoinParser) ParseBlockRewardEvents(ctx context.Context, txs []*types.Transaction, tipset *types.ExtendedTipSet) (*types.BlockRewardEvents, error) {
+	events, err := p.txsEventsParser(txs)
+	if err != nil {
+		return nil, err
+	}
+	return events.ParseBlockRewardEvents(ctx, txs, tipset)
+}
+
+func (p *FilecoinParser) ParseActorCreations(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.ActorCreationEvents, error) {
+	events, err := p.txsEventsParser(txs)
+	if err != nil {
+		return nil, err
+	}
+	return events.ParseActorCreations(ctx, txs, tipsetCid, tipsetKey)
 }
 
 func (p *FilecoinParser) ParseDataCapEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey types2.TipSetKey) (*types.DataCapEvents, error) {
-	return p.parserV2.ParseDataCapEvents(ctx, txs, tipsetCid, tipsetKey)
+	events, err := p.txsEventsParser(txs)
+	if err != nil {
+		return nil, err
+	}
+	return events.ParseDataCapEvents(ctx, txs, tipsetCid, tipsetKey)
 }
 
-func (p *FilecoinParser) translateParserVersionFromMetadata(metadata types.BlockMetadata) (string, error) {
-	switch {
-	// The empty string is for backwards compatibility with older traces versions
-	case p.parserV1.IsNodeVersionSupported(metadata.NodeMajorMinorVersion), metadata.NodeMajorMinorVersion == "":
-		return v1.Version, nil
-	case p.parserV2.IsNodeVersionSupported(metadata.NodeMajorMinorVersion):
-		return v2.Version, nil
-	default:
-		p.logger.Errorf("[parser] unsupported node version: %s", metadata.NodeFullVersion)
-		return "", fmt.Errorf("node version not supported %s", metadata.NodeFullVersion)
+// txsEventsParser returns the parser of the events of txs, resolved from the node version ParseTransactions stored in
+// them. Without txs, the events are parsed as those of legacy traces.
+func (p *FilecoinParser) txsEventsParser(txs []*types.Transaction) (Parser, error) {
+	var metadata types.BlockMetadata
+	if len(txs) > 0 {
+		metadata.NodeInfo = txs[0].NodeInfo
 	}
+	resolved, err := p.resolveEventsParser(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.Parser, nil
+}
+
+func (p *FilecoinParser) resolveEventsParser(metadata types.BlockMetadata) (*ParserResolution, error) {
+	resolved, err := p.registry.ResolveEvents(metadata)
+	if err != nil {
+		p.logger.Errorf("[parser] unsupported node version: %s. err: %s", metadata.NodeFullVersion, err)
+		return nil, fmt.Errorf("%w: %w", errUnknownVersion, err)
+	}
+
+	if resolved.Fallback {
+		p.logger.Warnf("[parser] unsupported node version: %s - falling back to events parser: [%s]", metadata.NodeFullVersion, resolved.Parser.Version())
+	}
+	return resolved, nil
+}
+
+func (p *FilecoinParser) resolveParser(metadata types.BlockMetadata) (*ParserResolution, error) {
+	resolved, err := p.registry.Resolve(metadata)
+	if err != nil {
+		p.logger.Errorf("[parser] unsupported node version: %s. err: %s", metadata.NodeFullVersion, err)
+		return nil, fmt.Errorf("%w: %w", errUnknownVersion, err)
+	}
+
+	if resolved.Fallback {
+		p.logger.Warnf("[parser] unsupported node version: %s - falling back to parser: [%s]", metadata.NodeFullVersion, resolved.Parser.Version())
+	} else {
+		p.logger.Debugf("trace files node version: [%s] - parser to use: [%s]", metadata.NodeMajorMinorVersion, resolved.Parser.Version())
+	}
+	return resolved, nil
 }
 
 func (p *FilecoinParser) FilterDuplicated(txs []*types.Transaction) []*types.Transaction {
@@ -286,20 +419,12 @@ func (p *FilecoinParser) FilterDuplicated(txs []*types.Transaction) []*types.Tra
 }
 
 func (p *FilecoinParser) GetBaseFee(traces []byte, metadata types.BlockMetadata, tipset *types.ExtendedTipSet) (uint64, error) {
-	parserVersion, err := p.translateParserVersionFromMetadata(metadata)
+	resolved, err := p.resolveParser(metadata)
 	if err != nil {
-		return 0, errUnknownVersion
+		return 0, err
 	}
 
-	p.logger.Debugf("trace files node version: [%s] - parser to use: [%s]", metadata.NodeMajorMinorVersion, parserVersion)
-	switch parserVersion {
-	case v1.Version:
-		return p.parserV1.GetBaseFee(traces, tipset)
-	case v2.Version:
-		return p.parserV2.GetBaseFee(traces, tipset)
-	}
-
-	return 0, errUnknownImpl
+	return resolved.Parser.GetBaseFee(traces, tipset)
 }
 
 func (p *FilecoinParser) ParseGenesis(genesis *types.GenesisBalances, genesisTipset *types.ExtendedTipSet) ([]*types.Transaction, *types.AddressInfoMap) {
@@ -396,6 +521,9 @@ func (p *FilecoinParser) ParseGenesisMultisig(ctx context.Context, genesis *type
 		}
 		addr, _ := address.NewFromString(actor.Key)
 
+		if p.Helper.IsOffline() {
+			return nil, fmt.Errorf("multisigTools.GenerateGenesisMultisigData(%s): %w", actor.Key, common.ErrOffline)
+		}
 		api := p.Helper.GetFilecoinNodeClient()
 		metadata, err := multisigTools.GenerateGenesisMultisigData(ctx, api, addr, genesisTipset)
 		if err != nil {
@@ -475,8 +603,9 @@ func (p *FilecoinParser) ParseBlocksInfo(ctx context.Context, height uint64, tra
 	}
 
 	blocksInfo := make([]types.BlockInfo, 0, len(tipset.Blocks()))
-	consolidateAddrs := p.parserV2.GetConfig().ConsolidateRobustAddress
-	bestEffort := p.parserV2.GetConfig().RobustAddressBestEffort
+	config := p.registry.Latest().GetConfig()
+	consolidateAddrs := config.ConsolidateRobustAddress
+	bestEffort := config.RobustAddressBestEffort
 
 	for _, block := range tipset.Blocks() {
 		minerAddr := block.Miner.String()
@@ -0,0 +1,112 @@
+package fil_parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/filecoin-project/go-address"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/actors/cache/impl/common"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/types"
+	rosettaFilecoinLib "github.com/zondax/rosetta-filecoin-lib"
+)
+
+const snapshotPrefix = "snapshot"
+
+// newOfflineParser builds a parser without a node, seeded with the address snapshot of the height.
+func newOfflineParser(t *testing.T, network, height string) *FilecoinParser {
+	snapshot, err := readGzFile(getFilename(snapshotPrefix, height))
+	require.NoError(t, err)
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, os.WriteFile(snapshotPath, snapshot, 0o600))
+
+	source := common.DataSource{
+		Config: common.DataSourceConfig{
+			NetworkName:  network,
+			SnapshotPath: snapshotPath,
+		},
+	}
+	p, err := NewFilecoinParser(rosettaFilecoinLib.NewRosettaConstructionFilecoin(nil), source, gLogger, WithNetworkName(network))
+	require.NoError(t, err)
+	require.True(t, p.Helper.IsOffline())
+	return p
+}
+
+func readTxsData(t *testing.T, height string) types.TxsData {
+	tipset, err := readTipset(height)
+	require.NoError(t, err)
+	ethlogs, err := readEthLogs(height)
+	require.NoError(t, err)
+	traces, err := readGzFile(tracesFilename(height))
+	require.NoError(t, err)
+	rawMetadata, err := readGzFile(getFilename("metadata", height))
+	require.NoError(t, err)
+	var metadata types.BlockMetadata
+	require.NoError(t, sonic.Unmarshal(rawMetadata, &metadata))
+
+	return types.TxsData{
+		EthLogs:   ethlogs,
+		Tipset:    tipset,
+		Traces:    traces,
+		Metadata:  metadata,
+		Canonical: true,
+	}
+}
+
+// TestParser_ParseTransactions_Offline parses a calibration height without a node. Its actor codes are not known to
+// rosetta offline, so every actor name is resolved from the codes stored in the snapshot.
+func TestParser_ParseTransactions_Offline(t *testing.T) {
+	const height = "1419335"
+	p := newOfflineParser(t, tools.CalibrationNetwork, height)
+
+	parsedResult, err := p.ParseTransactions(context.Background(), readTxsData(t, height))
+	require.NoError(t, err)
+	require.NotNil(t, parsedResult.Txs)
+	require.NotNil(t, parsedResult.Addresses)
+
+	// same totals as when parsing with a calibration node
+	assert.Equal(t, 37, len(parsedResult.Txs))
+	assert.Equal(t, 18, parsedResult.Addresses.Len())
+	assert.Equal(t, 5, len(parsedResult.TxCids))
+	for _, tx := range parsedResult.Txs {
+		assert.NotEqual(t, parser.UnknownStr, tx.TxType, tx.Id)
+		assert.NotEmpty(t, tx.TxType, tx.Id)
+	}
+}
+
+func TestHelper_GetActorAddressInfo_Offline(t *testing.T) {
+	const height = "1419335"
+	p := newOfflineParser(t, tools.CalibrationNetwork, height)
+	txsData := readTxsData(t, height)
+	parsedResult, err := p.ParseTransactions(context.Background(), txsData)
+	require.NoError(t, err)
+
+	// the addresses of the snapshot resolve without a node
+	var known *types.AddressInfo
+	parsedResult.Addresses.Range(func(_ string, info *types.AddressInfo) bool {
+		if info.Short != "" && info.Robust != "" && info.Short != info.Robust {
+			known = info
+			return false
+		}
+		return true
+	})
+	require.NotNil(t, known)
+	addr, err := address.NewFromString(known.Short)
+	require.NoError(t, err)
+	info := p.Helper.GetActorAddressInfo(addr, txsData.Tipset.Key(), txsData.Tipset.Height(), true)
+	assert.False(t, info.Degraded)
+	assert.Equal(t, known.Robust, info.Robust)
+
+	// the others would need the node
+	unknown, err := address.NewIDAddress(999_999_999)
+	require.NoError(t, err)
+	info = p.Helper.GetActorAddressInfo(unknown, txsData.Tipset.Key(), txsData.Tipset.Height(), true)
+	assert.True(t, info.Degraded)
+	assert.Empty(t, info.ActorType)
+}
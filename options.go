@@ -15,6 +15,10 @@ type FilecoinParserOptions struct {
 	metrics metrics2.MetricsClient
 	config  parser.Config
 	backoff *golemBackoff.BackOff
+	// networkName skips querying the node for the network name. Required when running without a node.
+	networkName string
+	// versionFallback picks the parser for node versions no registered parser supports.
+	versionFallback VersionFallback
 }
 
 // Option is a function type that modifies FilecoinParserOptions.
@@ -36,6 +40,23 @@ func WithConfig(config parser.Config) Option {
 	}
 }
 
+// WithNetworkName returns an Option that sets the network name instead of querying it from the node.
+// It is required to run the parser offline, that is, with a nil node in the cache data source.
+func WithNetworkName(networkName string) Option {
+	return func(o *FilecoinParserOptions) {
+		o.networkName = networkName
+	}
+}
+
+// WithVersionFallback returns an Option that sets the policy used for node versions no registered parser supports.
+// By default, they are rejected with FailOnUnknownVersion. Use FallbackToLatest to parse them with the parser
+// supporting the latest node version instead, flagging the output with VersionFallback.
+func WithVersionFallback(fallback VersionFallback) Option {
+	return func(o *FilecoinParserOptions) {
+		o.versionFallback = fallback
+	}
+}
+
 func WithBackoff(maxRetries int, maxWaitBeforeRetrySeconds int) Option {
 	return func(o *FilecoinParserOptions) {
 		b := golemBackoff.New().
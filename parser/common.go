@@ -8,6 +8,7 @@ import (
 
 	"github.com/zondax/golem/pkg/logger"
 
+	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/manifest"
 	"github.com/filecoin-project/lotus/api"
 	"github.com/filecoin-project/lotus/chain/types/ethtypes"
@@ -146,3 +147,19 @@ func TranslateTxCidToTxHash(nodeClient api.FullNode, mainMsgCid cid.Cid, metrics
 
 	return ethHash.String(), nil
 }
+
+// TranslateTxCidToTxHashOffline derives the tx hash of a message without querying the node.
+// The hash of a message sent by a delegated (f4) address depends on its eth signature, which is not part of the traces,
+// so the translation is flagged as Offline and left without a hash.
+func TranslateTxCidToTxHashOffline(from address.Address, mainMsgCid cid.Cid) (*types.TxCidTranslation, error) {
+	if from.Protocol() == address.Delegated {
+		return &types.TxCidTranslation{TxCid: mainMsgCid.String(), Offline: true}, nil
+	}
+
+	hash, err := ethtypes.EthHashFromCid(mainMsgCid)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TxCidTranslation{TxCid: mainMsgCid.String(), TxHash: hash.String()}, nil
+}
@@ -3,7 +3,13 @@ package parser
 import (
 	"testing"
 
+	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/builtin/v11/datacap"
+	"github.com/filecoin-project/lotus/chain/types/ethtypes"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/types"
 )
 
 func TestParseParams(t *testing.T) {
@@ -113,3 +119,37 @@ func TestParseReturn(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslateTxCidToTxHashOffline(t *testing.T) {
+	msgCid, err := cid.Parse("bafy2bzaceczpzd5k7u6hwaim7fdpwx2ujg7uhrdbpijf7q5ryvh7ogmawxupk")
+	require.NoError(t, err)
+	wantHash, err := ethtypes.EthHashFromCid(msgCid)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		from string
+		want *types.TxCidTranslation
+	}{
+		{
+			name: "secp256k1 sender",
+			from: "f1gjdwtnnwjx6p5jgr5dudeodzy5xguuhwvl7lmva",
+			want: &types.TxCidTranslation{TxCid: msgCid.String(), TxHash: wantHash.String()},
+		},
+		{
+			name: "delegated sender",
+			from: "f410faaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaonc6iji",
+			want: &types.TxCidTranslation{TxCid: msgCid.String(), Offline: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, err := address.NewFromString(tt.from)
+			require.NoError(t, err)
+
+			got, err := TranslateTxCidToTxHashOffline(from, msgCid)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
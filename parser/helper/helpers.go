@@ -43,6 +43,7 @@ import (
 	"github.com/zondax/rosetta-filecoin-lib/actors"
 
 	"github.com/zondax/fil-parser/actors/cache"
+	"github.com/zondax/fil-parser/actors/cache/impl/common"
 	logger2 "github.com/zondax/fil-parser/logger"
 	"github.com/zondax/fil-parser/parser"
 	parsermetrics "github.com/zondax/fil-parser/parser/metrics"
@@ -165,20 +166,30 @@ type Helper struct {
 }
 
 func NewHelper(lib *rosettaFilecoinLib.RosettaConstructionFilecoin, actorsCache cache.IActorsCache, node api.FullNode, logger *logger.Logger, metrics metrics.MetricsClient) *Helper {
-	h := &Helper{
+	logger = logger2.GetSafeLogger(logger)
+	if node == nil {
+		logger.Errorf("could not get network name: node is nil")
+		return nil
+	}
+	network, err := node.StateNetworkName(context.Background())
+	if err != nil {
+		logger.Errorf("could not get network name: %v", err)
+		return nil
+	}
+	return NewHelperWithNetwork(lib, actorsCache, node, string(network), logger, metrics)
+}
+
+// NewHelperWithNetwork creates a helper for the given network without querying the node for it.
+// node can be nil, in which case the helper runs offline and every node-only feature must be skipped (see IsOffline).
+func NewHelperWithNetwork(lib *rosettaFilecoinLib.RosettaConstructionFilecoin, actorsCache cache.IActorsCache, node api.FullNode, network string, logger *logger.Logger, metrics metrics.MetricsClient) *Helper {
+	return &Helper{
 		lib:        lib,
 		actorCache: actorsCache,
 		node:       node,
 		logger:     logger2.GetSafeLogger(logger),
 		metrics:    parsermetrics.NewClient(metrics, "helper"),
+		network:    tools.ParseRawNetworkName(network),
 	}
-	network, err := h.node.StateNetworkName(context.Background())
-	if err != nil {
-		h.logger.Errorf("could not get network name: %v", err)
-		return nil
-	}
-	h.network = tools.ParseRawNetworkName(string(network))
-	return h
 }
 
 func (h *Helper) GetActorsCache() cache.IActorsCache {
@@ -193,6 +204,15 @@ func (h *Helper) GetFilecoinNodeClient() api.FullNode {
 	return h.node
 }
 
+func (h *Helper) GetNetworkName() string {
+	return h.network
+}
+
+// IsOffline returns true when the helper has no node to query.
+func (h *Helper) IsOffline() bool {
+	return h.node == nil
+}
+
 // GetActorAddressInfo returns detailed actor address information:
 // - ActorCid
 // - ActorType
@@ -209,7 +229,12 @@ func (h *Helper) GetActorAddressInfo(add address.Address, key filTypes.TipSetKey
 
 	actorCid, actorName, err := h.GetActorInfoFromAddress(add, int64(height), key, canonical)
 	if err != nil {
-		h.logger.Errorf("could not get actor cid and name from address. Err: %s", err)
+		h.logAddressInfoError(addInfo, err, "could not get actor cid and name from address. Err: %s", err)
+		// the node already failed to find the actor for this tipset, so its addresses can't be resolved either
+		if errors.Is(err, cache.ErrBadAddress) {
+			addInfo.IsSystemActor = h.IsSystemActor(add) || h.IsGenesisActor(add)
+			return addInfo
+		}
 	} else {
 		addInfo.ActorCid = actorCid.String()
 		addInfo.ActorType = actorName
@@ -220,7 +245,7 @@ func (h *Helper) GetActorAddressInfo(add address.Address, key filTypes.TipSetKey
 		if ok, _, _ := h.IsZeroAddressAccountActor(add); ok {
 			addInfo.Short = ZeroAddressAccountActorShort
 		}
-		h.logger.Errorf("could not get short address for %s. Err: %v", add.String(), err)
+		h.logAddressInfoError(addInfo, err, "could not get short address for %s. Err: %v", add.String(), err)
 	}
 
 	addInfo.Robust, err = h.actorCache.GetRobustAddress(add, canonical)
@@ -228,7 +253,7 @@ func (h *Helper) GetActorAddressInfo(add address.Address, key filTypes.TipSetKey
 		if ok, _, _ := h.IsZeroAddressAccountActor(add); ok {
 			addInfo.Robust = ZeroAddressAccountActorRobust
 		}
-		h.logger.Errorf("could not get robust address for %s. Err: %v", add.String(), err)
+		h.logAddressInfoError(addInfo, err, "could not get robust address for %s. Err: %v", add.String(), err)
 	}
 
 	addInfo.IsSystemActor = h.IsSystemActor(add) || h.IsGenesisActor(add)
@@ -236,6 +261,17 @@ func (h *Helper) GetActorAddressInfo(add address.Address, key filTypes.TipSetKey
 	return addInfo
 }
 
+// logAddressInfoError logs a failed lookup of GetActorAddressInfo.
+// Lookups are expected to fail when running offline, so they are logged at debug level and addInfo is marked as degraded.
+func (h *Helper) logAddressInfoError(addInfo *types.AddressInfo, err error, format string, args ...interface{}) {
+	if errors.Is(err, common.ErrOffline) {
+		addInfo.Degraded = true
+		h.logger.Debugf(format, args...)
+		return
+	}
+	h.logger.Errorf(format, args...)
+}
+
 // GetActorNameFromAddress returns the actor name for the given address.
 func (h *Helper) GetActorNameFromAddress(add address.Address, height int64, key filTypes.TipSetKey, canonical bool) (string, error) {
 	_, actorName, err := h.GetActorInfoFromAddress(add, height, key, canonical)
@@ -332,7 +368,17 @@ func (h *Helper) isKeylessAccountActor(add address.Address, height int64) (bool,
 }
 
 // GetActorNameFromCid returns the actor name for the given cid and height from rosetta and fallsback to specialLegacyActors.
+// Offline, rosetta only knows the legacy and mainnet codes, so the cid is first looked up in the addresses of the actors
+// cache, seeded from the snapshot.
 func (h *Helper) GetActorNameFromCid(cid cid.Cid, height int64) (string, error) {
+	if h.IsOffline() {
+		if actorNames, ok := h.actorCache.(cache.IActorNames); ok {
+			if name, ok := actorNames.GetActorNameFromCode(cid.String()); ok {
+				return name, nil
+			}
+		}
+	}
+
 	version := tools.VersionFromHeight(h.network, height)
 	actorName, err := h.lib.BuiltinActors.GetActorNameFromCidByVersion(cid, version.FilNetworkVersion())
 	if err != nil {
@@ -412,7 +458,15 @@ func (h *Helper) GetEVMSelectorSig(ctx context.Context, selectorID string, canon
 	return s, err
 }
 
+// ActorNameResolver resolves the actor name of an address, such as with the actor names memoized for a tipset.
+type ActorNameResolver func(addr address.Address, height int64, tipsetKey filTypes.TipSetKey, canonical bool) (string, error)
+
 func (h *Helper) FilterTxsByActorType(ctx context.Context, txs []*types.Transaction, actorType string, tipsetKey filTypes.TipSetKey, canonical bool) ([]*types.Transaction, error) {
+	return h.FilterTxsByActorTypeWith(ctx, txs, actorType, tipsetKey, canonical, h.getActorName)
+}
+
+// FilterTxsByActorTypeWith behaves like FilterTxsByActorType, resolving the actor names of the tx addresses with resolveName.
+func (h *Helper) FilterTxsByActorTypeWith(ctx context.Context, txs []*types.Transaction, actorType string, tipsetKey filTypes.TipSetKey, canonical bool, resolveName ActorNameResolver) ([]*types.Transaction, error) {
 	var result []*types.Transaction
 	for _, tx := range txs {
 		addrTo, err := address.NewFromString(tx.TxTo)
@@ -429,7 +483,7 @@ func (h *Helper) FilterTxsByActorType(ctx context.Context, txs []*types.Transact
 		}
 
 		// #nosec G115
-		isType, err := h.isAnyAddressOfType(ctx, []address.Address{addrTo, addrFrom}, int64(tx.Height), tipsetKey, actorType, canonical)
+		isType, err := isAnyAddressOfType(ctx, []address.Address{addrTo, addrFrom}, int64(tx.Height), tipsetKey, actorType, canonical, resolveName)
 		if err != nil {
 			h.logger.Errorf("could not get actor type from address. Err: %s", err)
 			continue
@@ -459,12 +513,17 @@ func (h *Helper) IsCronActor(height int64, addr address.Address, tipsetKey filTy
 	return strings.Contains(actorName, manifest.CronKey)
 }
 
-func (h *Helper) isAnyAddressOfType(_ context.Context, addresses []address.Address, height int64, key filTypes.TipSetKey, actorType string, canonical bool) (bool, error) {
+func (h *Helper) getActorName(addr address.Address, height int64, key filTypes.TipSetKey, canonical bool) (string, error) {
+	_, actorName, err := h.GetActorInfoFromAddress(addr, height, key, canonical)
+	return actorName, err
+}
+
+func isAnyAddressOfType(_ context.Context, addresses []address.Address, height int64, key filTypes.TipSetKey, actorType string, canonical bool, resolveName ActorNameResolver) (bool, error) {
 	for _, addr := range addresses {
 		if addr == address.Undef {
 			continue
 		}
-		_, actorName, err := h.GetActorInfoFromAddress(addr, height, key, canonical)
+		actorName, err := resolveName(addr, height, key, canonical)
 		if err != nil {
 			return false, err
 		}
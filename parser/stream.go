@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+// computeStateTraceKey is the key holding the list of invocation results in a ComputeStateOutput json document.
+const computeStateTraceKey = "Trace"
+
+var ErrStreamStopped = errors.New("stream stopped by handler")
+
+// TracesReader returns a reader over the raw traces, preferring the streaming source when available.
+func TracesReader(traces []byte, reader io.Reader) io.Reader {
+	if reader != nil {
+		return reader
+	}
+	return bytes.NewReader(traces)
+}
+
+// DecodeComputeStateTraces incrementally decodes the "Trace" array of a ComputeStateOutput json document.
+// Every element is decoded into a fresh T and handed to fn before the next one is read, so only a single
+// invocation result is kept in memory at a time. The remaining keys of the document are skipped.
+// The document is walked with encoding/json, which can read it token by token, while every element is decoded
+// with sonic, like the parsers do when unmarshalling the whole document.
+func DecodeComputeStateTraces[T any](r io.Reader, fn func(trace T) error) error {
+	decoder := json.NewDecoder(r)
+
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("could not read compute state key: %w", err)
+		}
+		key, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("unexpected compute state token: %v", token)
+		}
+
+		// sonic matches keys case-insensitively when unmarshalling the whole document, keep the same behavior here
+		if !strings.EqualFold(key, computeStateTraceKey) {
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return fmt.Errorf("could not skip compute state key %s: %w", key, err)
+			}
+			continue
+		}
+
+		if err := decodeTraceArray(decoder, fn); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(decoder, '}')
+}
+
+func decodeTraceArray[T any](decoder *json.Decoder, fn func(trace T) error) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("could not read trace array: %w", err)
+	}
+	// a null trace list is valid and carries no invocations
+	if token == nil {
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("unexpected trace token: %v", token)
+	}
+
+	for decoder.More() {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return fmt.Errorf("could not read trace: %w", err)
+		}
+		var trace T
+		if err := sonic.Unmarshal(raw, &trace); err != nil {
+			return fmt.Errorf("could not decode trace: %w", err)
+		}
+		if err := fn(trace); err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(decoder, ']')
+}
+
+func expectDelim(decoder *json.Decoder, want json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("could not read token: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("unexpected token %v, expected %s", token, want)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type streamTrace struct {
+	MsgCid string
+	Value  int
+}
+
+func TestDecodeComputeStateTraces(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []streamTrace
+		wantErr bool
+	}{
+		{
+			name:  "traces after root",
+			input: `{"Root":{"/":"bafy"},"Trace":[{"MsgCid":"a","Value":1},{"MsgCid":"b","Value":2}]}`,
+			want:  []streamTrace{{MsgCid: "a", Value: 1}, {MsgCid: "b", Value: 2}},
+		},
+		{
+			name:  "traces before root and lowercase key",
+			input: `{"trace":[{"MsgCid":"a","Value":1}],"Root":null}`,
+			want:  []streamTrace{{MsgCid: "a", Value: 1}},
+		},
+		{
+			name:  "null traces",
+			input: `{"Root":null,"Trace":null}`,
+			want:  nil,
+		},
+		{
+			name:  "empty traces",
+			input: `{"Trace":[]}`,
+			want:  nil,
+		},
+		{
+			name:    "not an object",
+			input:   `[]`,
+			wantErr: true,
+		},
+		{
+			name:    "truncated document",
+			input:   `{"Trace":[{"MsgCid":"a","Value":1},`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []streamTrace
+			err := DecodeComputeStateTraces(strings.NewReader(tt.input), func(trace streamTrace) error {
+				got = append(got, trace)
+				return nil
+			})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecodeComputeStateTraces_HandlerError(t *testing.T) {
+	calls := 0
+	err := DecodeComputeStateTraces(strings.NewReader(`{"Trace":[{"MsgCid":"a"},{"MsgCid":"b"}]}`), func(trace streamTrace) error {
+		calls++
+		return ErrStreamStopped
+	})
+	require.True(t, errors.Is(err, ErrStreamStopped))
+	assert.Equal(t, 1, calls)
+}
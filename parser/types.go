@@ -20,12 +20,15 @@ type ControlAddress struct {
 type ExecParams struct {
 	CodeCid           string `json:"CodeCid"`
 	ConstructorParams string `json:"constructorParams"`
+	// DecodedConstructorParams are the constructor params parsed by the created actor, nil when they could not be parsed.
+	DecodedConstructorParams interface{} `json:"decodedConstructorParams,omitempty"`
 }
 
 type Exec4Params struct {
-	CodeCid           string `json:"CodeCid"`
-	ConstructorParams string `json:"constructorParams"`
-	SubAddress        string `json:"subAddress"`
+	CodeCid                  string      `json:"CodeCid"`
+	ConstructorParams        string      `json:"constructorParams"`
+	DecodedConstructorParams interface{} `json:"decodedConstructorParams,omitempty"`
+	SubAddress               string      `json:"subAddress"`
 }
 
 type BeneficiaryTerm struct {
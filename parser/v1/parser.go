@@ -41,8 +41,6 @@ var NodeVersionsSupported = []string{"v1.21", "v1.22"}
 
 type Parser struct {
 	actorParser            actors.ActorParserInterface
-	addresses              *types.AddressInfoMap
-	txCidEquivalents       []types.TxCidTranslation
 	helper                 *helper.Helper
 	logger                 *logger.Logger
 	multisigEventGenerator multisigTools.EventGenerator
@@ -56,16 +54,10 @@ type Parser struct {
 }
 
 func NewParser(helper *helper.Helper, logger *logger.Logger, metrics metrics.MetricsClient, backoff *golemBackoff.BackOff, config parser.Config) *Parser {
-	network, err := helper.GetFilecoinNodeClient().StateNetworkName(context.Background())
-	if err != nil {
-		logger.Fatal(err.Error())
-		return nil
-	}
-	networkName := tools.ParseRawNetworkName(string(network))
+	networkName := helper.GetNetworkName()
 	return &Parser{
 		network:                networkName,
 		actorParser:            actorsV1.NewActorParser(helper, logger, metrics),
-		addresses:              types.NewAddressInfoMap(),
 		helper:                 helper,
 		logger:                 logger2.GetSafeLogger(logger),
 		multisigEventGenerator: multisigTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, config),
@@ -81,7 +73,6 @@ func NewParser(helper *helper.Helper, logger *logger.Logger, metrics metrics.Met
 func NewActorsV2Parser(network string, helper *helper.Helper, logger *logger.Logger, metrics metrics.MetricsClient, backoff *golemBackoff.BackOff, config parser.Config) *Parser {
 	return &Parser{
 		actorParser:            actorsV2.NewActorParser(network, helper, logger, metrics),
-		addresses:              types.NewAddressInfoMap(),
 		helper:                 helper,
 		logger:                 logger2.GetSafeLogger(logger),
 		multisigEventGenerator: multisigTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, config),
@@ -125,98 +116,144 @@ func (p *Parser) ParseTransactions(ctx context.Context, txsData types.TxsData) (
 		return nil, errors.New("could not decode")
 	}
 
+	// addresses and txCidEquivalents are kept per call so concurrent calls on the same parser don't share state
 	var transactions []*types.Transaction
-	p.addresses = types.NewAddressInfoMap()
-	p.txCidEquivalents = make([]types.TxCidTranslation, 0)
+	addresses := types.NewAddressInfoMap()
+	txCidEquivalents := make([]types.TxCidTranslation, 0)
 
 	for _, trace := range computeState.Trace {
-		if !hasMessage(trace) {
-			p.logger.Errorf("Trace without message: %s", trace.MsgCid.String())
-			_ = p.metrics.UpdateTraceWithoutMessageMetric()
-			continue
+		txs, txCidEquivalent := p.parseInvocResult(ctx, trace, txsData, addresses)
+		transactions = append(transactions, txs...)
+		if txCidEquivalent != nil {
+			txCidEquivalents = append(txCidEquivalents, *txCidEquivalent)
 		}
+	}
 
-		// This remains unclear why we could have a trace without an execution trace. For historical reasons, there were a special case to handle them.
-		// However, the ExecutionTrace and the top-level tx info matches (from observing traces files).
-		// So in cases where we have a trace without an execution trace, we set the ExecutionTrace to the top-level tx info.
-		if ok := hasExecutionTrace(trace); !ok {
-			trace.ExecutionTrace.Msg = trace.Msg
-			trace.ExecutionTrace.MsgRct = trace.MsgRct
-			trace.ExecutionTrace.Error = trace.Error
-			trace.ExecutionTrace.Duration = trace.Duration
-			trace.ExecutionTrace.Subcalls = []typesV1.ExecutionTraceV1{}
-			p.logger.Warnf("Trace without execution trace for tx %s", trace.MsgCid.String())
-			_ = p.metrics.UpdateTraceWithoutExecutionTraceMetric()
-		} else if trace.ExecutionTrace.MsgRct.ExitCode != trace.MsgRct.ExitCode {
-			// Observed in the wild, the main tx data is not the same as the execution trace data.
-			// For those cases, we want to observe it, and use the top-level tx info as fallback.
-			// Ex: Height 501104
-			// Ex: https://filfox.info/en/message/bafy2bzacecgh3w4qk3t53kg6skweh4isekscy22s4nfxdyc7zcbfb4ez6li5m?t=2
-			p.logger.Errorf("ExecutionTrace.MsgRct.ExitCode != MsgRct.ExitCode for tx %s", trace.MsgCid.String())
-			_ = p.metrics.UpdateMismatchExitCodeMetric()
-
-			trace.ExecutionTrace.Msg = trace.Msg
-			trace.ExecutionTrace.MsgRct = trace.MsgRct
-			trace.ExecutionTrace.Error = trace.Error
-			trace.ExecutionTrace.Duration = trace.Duration
+	transactions = tools.SetNodeMetadata(transactions, txsData.Metadata, Version)
 
+	return &types.TxsParsedResult{
+		Txs:       transactions,
+		Addresses: addresses,
+		TxCids:    txCidEquivalents,
+	}, nil
+}
+
+// ParseTransactionsStream decodes the traces incrementally and calls handler once per top-level trace,
+// so neither the whole ComputeStateOutputV1 nor the whole list of transactions is kept in memory.
+func (p *Parser) ParseTransactionsStream(ctx context.Context, txsData types.TxsData, handler types.TxsChunkHandler) error {
+	return parser.DecodeComputeStateTraces(parser.TracesReader(txsData.Traces, txsData.TracesReader), func(trace *typesV1.InvocResultV1) error {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
 
-		systemExecution := p.helper.IsSystemActor(trace.ExecutionTrace.Msg.From) && p.helper.IsSystemActor(trace.ExecutionTrace.Msg.To)
+		addresses := types.NewAddressInfoMap()
+		txs, txCidEquivalent := p.parseInvocResult(ctx, trace, txsData, addresses)
+		if len(txs) == 0 {
+			return nil
+		}
 
-		// Main transaction
-		mainMsgCid := trace.MsgCid
-		mainExitCode := trace.MsgRct.ExitCode
-		transaction, err := p.parseTrace(ctx, trace.ExecutionTrace, mainMsgCid, txsData.Tipset, uuid.Nil.String(), systemExecution, mainExitCode, txsData.Canonical)
-		if err != nil {
-			p.logger.Errorf("Error parsing trace for tx %s: %v", mainMsgCid, err)
-			_ = p.metrics.UpdateParseTraceMetric()
-			continue
+		chunk := &types.TxsParsedChunk{
+			TxCid:     trace.MsgCid.String(),
+			Txs:       tools.SetNodeMetadata(txs, txsData.Metadata, Version),
+			Addresses: addresses,
+			TxCids:    []types.TxCidTranslation{},
+		}
+		if txCidEquivalent != nil {
+			chunk.TxCids = append(chunk.TxCids, *txCidEquivalent)
 		}
 
-		transaction.GasUsed = trace.GasCost.GasUsed.Uint64()
-		transactions = append(transactions, transaction)
+		return handler(chunk)
+	})
+}
 
-		subTxs := p.parseSubTxs(ctx, trace.ExecutionTrace.Subcalls, mainMsgCid, txsData.Tipset, txsData.EthLogs,
-			trace.Msg.Cid().String(), transaction.Id, 0, systemExecution, mainExitCode, txsData.Canonical)
-		if len(subTxs) > 0 {
-			transactions = append(transactions, subTxs...)
-		}
+// parseInvocResult parses a top-level trace into its main transaction, subcalls and fee transaction.
+// Addresses found along the way are appended to addresses.
+func (p *Parser) parseInvocResult(ctx context.Context, trace *typesV1.InvocResultV1, txsData types.TxsData, addresses *types.AddressInfoMap) ([]*types.Transaction, *types.TxCidTranslation) {
+	var transactions []*types.Transaction
+	var txCidEquivalent *types.TxCidTranslation
 
-		// Fees
-		if trace.GasCost.TotalCost.Uint64() > 0 {
-			feeTx := p.feesTransactions(trace, txsData.Tipset, transaction.TxType, transaction.Id, systemExecution, txsData.Canonical)
-			if p.config.FeesAsColumn {
-				transaction.FeeData = feeTx.TxMetadata
-			} else {
-				transactions = append(transactions, feeTx)
-			}
+	if !hasMessage(trace) {
+		p.logger.Errorf("Trace without message: %s", trace.MsgCid.String())
+		_ = p.metrics.UpdateTraceWithoutMessageMetric()
+		return nil, nil
+	}
+
+	// This remains unclear why we could have a trace without an execution trace. For historical reasons, there were a special case to handle them.
+	// However, the ExecutionTrace and the top-level tx info matches (from observing traces files).
+	// So in cases where we have a trace without an execution trace, we set the ExecutionTrace to the top-level tx info.
+	if ok := hasExecutionTrace(trace); !ok {
+		trace.ExecutionTrace.Msg = trace.Msg
+		trace.ExecutionTrace.MsgRct = trace.MsgRct
+		trace.ExecutionTrace.Error = trace.Error
+		trace.ExecutionTrace.Duration = trace.Duration
+		trace.ExecutionTrace.Subcalls = []typesV1.ExecutionTraceV1{}
+		p.logger.Warnf("Trace without execution trace for tx %s", trace.MsgCid.String())
+		_ = p.metrics.UpdateTraceWithoutExecutionTraceMetric()
+	} else if trace.ExecutionTrace.MsgRct.ExitCode != trace.MsgRct.ExitCode {
+		// Observed in the wild, the main tx data is not the same as the execution trace data.
+		// For those cases, we want to observe it, and use the top-level tx info as fallback.
+		// Ex: Height 501104
+		// Ex: https://filfox.info/en/message/bafy2bzacecgh3w4qk3t53kg6skweh4isekscy22s4nfxdyc7zcbfb4ez6li5m?t=2
+		p.logger.Errorf("ExecutionTrace.MsgRct.ExitCode != MsgRct.ExitCode for tx %s", trace.MsgCid.String())
+		_ = p.metrics.UpdateMismatchExitCodeMetric()
+
+		trace.ExecutionTrace.Msg = trace.Msg
+		trace.ExecutionTrace.MsgRct = trace.MsgRct
+		trace.ExecutionTrace.Error = trace.Error
+		trace.ExecutionTrace.Duration = trace.Duration
+
+	}
+
+	systemExecution := p.helper.IsSystemActor(trace.ExecutionTrace.Msg.From) && p.helper.IsSystemActor(trace.ExecutionTrace.Msg.To)
+
+	// Main transaction
+	mainMsgCid := trace.MsgCid
+	mainExitCode := trace.MsgRct.ExitCode
+	transaction, err := p.parseTrace(ctx, trace.ExecutionTrace, mainMsgCid, txsData.Tipset, uuid.Nil.String(), systemExecution, mainExitCode, txsData.Canonical, addresses)
+	if err != nil {
+		p.logger.Errorf("Error parsing trace for tx %s: %v", mainMsgCid, err)
+		_ = p.metrics.UpdateParseTraceMetric()
+		return nil, nil
+	}
+
+	transaction.GasUsed = trace.GasCost.GasUsed.Uint64()
+	transactions = append(transactions, transaction)
+
+	subTxs := p.parseSubTxs(ctx, trace.ExecutionTrace.Subcalls, mainMsgCid, txsData.Tipset, txsData.EthLogs,
+		trace.Msg.Cid().String(), transaction.Id, 0, systemExecution, mainExitCode, txsData.Canonical, addresses)
+	if len(subTxs) > 0 {
+		transactions = append(transactions, subTxs...)
+	}
+
+	// Fees
+	if trace.GasCost.TotalCost.Uint64() > 0 {
+		feeTx := p.feesTransactions(trace, txsData.Tipset, transaction.TxType, transaction.Id, systemExecution, txsData.Canonical)
+		if p.config.FeesAsColumn {
+			transaction.FeeData = feeTx.TxMetadata
+		} else {
+			transactions = append(transactions, feeTx)
 		}
+	}
 
-		// TxCid <-> TxHash
-		if int64(txsData.Tipset.Height()) >= p.config.TxCidTranslationStart {
-			txHash, err := parser.TranslateTxCidToTxHash(p.helper.GetFilecoinNodeClient(), trace.MsgCid, p.actorsCacheMetrics, p.backoff)
+	// TxCid <-> TxHash
+	if int64(txsData.Tipset.Height()) >= p.config.TxCidTranslationStart {
+		var err error
+		if p.helper.IsOffline() {
+			txCidEquivalent, err = parser.TranslateTxCidToTxHashOffline(trace.Msg.From, trace.MsgCid)
+		} else {
+			var txHash string
+			txHash, err = parser.TranslateTxCidToTxHash(p.helper.GetFilecoinNodeClient(), trace.MsgCid, p.actorsCacheMetrics, p.backoff)
 			if err == nil && txHash != "" {
-				p.txCidEquivalents = append(p.txCidEquivalents, types.TxCidTranslation{TxCid: trace.MsgCid.String(), TxHash: txHash})
-			}
-			if err != nil {
-				p.logger.Warnf("Error when trying to translate tx cid to tx hash: %v", err)
-				_ = p.metrics.UpdateTranslateTxCidToTxHashMetric()
+				txCidEquivalent = &types.TxCidTranslation{TxCid: trace.MsgCid.String(), TxHash: txHash}
 			}
 		}
+		if err != nil {
+			p.logger.Warnf("Error when trying to translate tx cid to tx hash: %v", err)
+			_ = p.metrics.UpdateTranslateTxCidToTxHashMetric()
+		}
 	}
 
-	transactions = tools.SetNodeMetadata(transactions, txsData.Metadata, Version)
-
-	// Clear this cache when we finish processing a tipset.
-	// Bad addresses in this tipset might be valid in the next one
-	p.helper.GetActorsCache().ClearBadAddressCache()
-
-	return &types.TxsParsedResult{
-		Txs:       transactions,
-		Addresses: p.addresses,
-		TxCids:    p.txCidEquivalents,
-	}, nil
+	return transactions, txCidEquivalent
 }
 
 func (p *Parser) ParseMultisigEvents(ctx context.Context, multisigTxs []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.MultisigEvents, error) {
@@ -238,6 +275,22 @@ func (p *Parser) ParseDataCapEvents(ctx context.Context, dataCapTxs []*types.Tra
 	return nil, errors.New("unimplimented")
 }
 
+func (p *Parser) ParsePaymentChannelEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.PaymentChannelEvents, error) {
+	return nil, errors.New("unimplimented")
+}
+
+func (p *Parser) ParsePowerEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.PowerEvents, error) {
+	return nil, errors.New("unimplimented")
+}
+
+func (p *Parser) ParseBlockRewardEvents(ctx context.Context, txs []*types.Transaction, tipset *types.ExtendedTipSet) (*types.BlockRewardEvents, error) {
+	return nil, errors.New("unimplimented")
+}
+
+func (p *Parser) ParseActorCreations(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.ActorCreationEvents, error) {
+	return nil, errors.New("unimplimented")
+}
+
 func (p *Parser) ParseNativeEvents(_ context.Context, _ types.EventsData) (*types.EventsParsedResult, error) {
 	return nil, errors.New("unimplimented")
 }
@@ -276,22 +329,22 @@ func (p *Parser) GetBaseFee(traces []byte, tipset *types.ExtendedTipSet) (uint64
 }
 
 func (p *Parser) parseSubTxs(ctx context.Context, subTxs []typesV1.ExecutionTraceV1, mainMsgCid cid.Cid, tipSet *types.ExtendedTipSet, ethLogs []types.EthLog, txHash string,
-	parentId string, level uint16, systemExecution bool, mainExitCode exitcode.ExitCode, canonical bool) (txs []*types.Transaction) {
+	parentId string, level uint16, systemExecution bool, mainExitCode exitcode.ExitCode, canonical bool, addresses *types.AddressInfoMap) (txs []*types.Transaction) {
 	level++
 	for _, subTx := range subTxs {
-		subTransaction, err := p.parseTrace(ctx, subTx, mainMsgCid, tipSet, parentId, systemExecution, mainExitCode, canonical)
+		subTransaction, err := p.parseTrace(ctx, subTx, mainMsgCid, tipSet, parentId, systemExecution, mainExitCode, canonical, addresses)
 		if err != nil {
 			continue
 		}
 
 		subTransaction.Level = level
 		txs = append(txs, subTransaction)
-		txs = append(txs, p.parseSubTxs(ctx, subTx.Subcalls, mainMsgCid, tipSet, ethLogs, txHash, subTransaction.Id, level, systemExecution, mainExitCode, canonical)...)
+		txs = append(txs, p.parseSubTxs(ctx, subTx.Subcalls, mainMsgCid, tipSet, ethLogs, txHash, subTransaction.Id, level, systemExecution, mainExitCode, canonical, addresses)...)
 	}
 	return
 }
 
-func (p *Parser) parseTrace(ctx context.Context, trace typesV1.ExecutionTraceV1, mainMsgCid cid.Cid, tipset *types.ExtendedTipSet, parentId string, systemExecution bool, mainExitCode exitcode.ExitCode, canonical bool) (*types.Transaction, error) {
+func (p *Parser) parseTrace(ctx context.Context, trace typesV1.ExecutionTraceV1, mainMsgCid cid.Cid, tipset *types.ExtendedTipSet, parentId string, systemExecution bool, mainExitCode exitcode.ExitCode, canonical bool, addresses *types.AddressInfoMap) (*types.Transaction, error) {
 	mainFailedTx := mainExitCode.IsError()
 	subcallFailedTx := trace.MsgRct.ExitCode.IsError()
 
@@ -326,7 +379,7 @@ func (p *Parser) parseTrace(ctx context.Context, trace typesV1.ExecutionTraceV1,
 
 	// If the tx failed, we don't want to add the address info to the addresses map, as we could be adding bad relationships between short and robust..
 	if !mainFailedTx && addressInfo != nil {
-		parser.AppendToAddressesMap(p.addresses, addressInfo)
+		parser.AppendToAddressesMap(addresses, addressInfo)
 	}
 
 	if len(metadata) == 0 {
@@ -349,7 +402,7 @@ func (p *Parser) parseTrace(ctx context.Context, trace typesV1.ExecutionTraceV1,
 		_ = p.metrics.UpdateJsonMarshalMetric(parsermetrics.MetadataValue, txType)
 	}
 
-	p.appendAddressInfo(trace.Msg, tipset.Key(), tipset.Height(), canonical)
+	p.appendAddressInfo(trace.Msg, tipset.Key(), tipset.Height(), canonical, addresses)
 
 	var blockCid string
 	if !systemExecution {
@@ -511,13 +564,13 @@ func hasExecutionTrace(trace *typesV1.InvocResultV1) bool {
 	return true
 }
 
-func (p *Parser) appendAddressInfo(msg *filTypes.Message, key filTypes.TipSetKey, height abi.ChainEpoch, canonical bool) {
+func (p *Parser) appendAddressInfo(msg *filTypes.Message, key filTypes.TipSetKey, height abi.ChainEpoch, canonical bool, addresses *types.AddressInfoMap) {
 	if msg == nil {
 		return
 	}
 	fromAdd := p.helper.GetActorAddressInfo(msg.From, key, height, canonical)
 	toAdd := p.helper.GetActorAddressInfo(msg.To, key, height, canonical)
-	parser.AppendToAddressesMap(p.addresses, fromAdd, toAdd)
+	parser.AppendToAddressesMap(addresses, fromAdd, toAdd)
 }
 
 func (p *Parser) getTxType(ctx context.Context, to, from address.Address, method abi.MethodNum, mainMsgCid cid.Cid, tipset *types.ExtendedTipSet, canonical bool) (actorName string, txType string, err error) {
@@ -28,11 +28,15 @@ import (
 	parsermetrics "github.com/zondax/fil-parser/parser/metrics"
 	typesV2 "github.com/zondax/fil-parser/parser/v2/types"
 	"github.com/zondax/fil-parser/tools"
+	creationTools "github.com/zondax/fil-parser/tools/actorcreation"
+	blockRewardTools "github.com/zondax/fil-parser/tools/blockreward"
 	dataCapTools "github.com/zondax/fil-parser/tools/datacap"
 	dealsTools "github.com/zondax/fil-parser/tools/deals"
 	eventTools "github.com/zondax/fil-parser/tools/events"
 	minerTools "github.com/zondax/fil-parser/tools/miner"
 	multisigTools "github.com/zondax/fil-parser/tools/multisig"
+	paychTools "github.com/zondax/fil-parser/tools/paymentchannel"
+	powerTools "github.com/zondax/fil-parser/tools/power"
 	verifregTools "github.com/zondax/fil-parser/tools/verifreg"
 	"github.com/zondax/fil-parser/types"
 	"github.com/zondax/golem/pkg/logger"
@@ -46,8 +50,6 @@ var NodeVersionsSupported = []string{"v1.23", "v1.24", "v1.25", "v1.26", "v1.27"
 type Parser struct {
 	network                string
 	actorParser            actors.ActorParserInterface
-	addresses              *types.AddressInfoMap
-	txCidEquivalents       []types.TxCidTranslation
 	helper                 *helper.Helper
 	logger                 *logger.Logger
 	multisigEventGenerator multisigTools.EventGenerator
@@ -55,6 +57,10 @@ type Parser struct {
 	verifregEventGenerator verifregTools.EventGenerator
 	dataCapEventGenerator  dataCapTools.EventGenerator
 	dealsEventGenerator    dealsTools.EventGenerator
+	paychEventGenerator    paychTools.EventGenerator
+	powerEventGenerator    powerTools.EventGenerator
+	rewardEventGenerator   blockRewardTools.EventGenerator
+	creationEventGenerator creationTools.EventGenerator
 	metrics                *parsermetrics.ParserMetricsClient
 	actorsCacheMetrics     *cacheMetrics.ActorsCacheMetricsClient
 	backoff                *golemBackoff.BackOff
@@ -62,16 +68,10 @@ type Parser struct {
 }
 
 func NewParser(helper *helper.Helper, logger *logger.Logger, metrics metrics.MetricsClient, backoff *golemBackoff.BackOff, config parser.Config) *Parser {
-	network, err := helper.GetFilecoinNodeClient().StateNetworkName(context.Background())
-	if err != nil {
-		logger.Fatal(err.Error())
-		return nil
-	}
-	networkName := tools.ParseRawNetworkName(string(network))
+	networkName := helper.GetNetworkName()
 	p := &Parser{
 		network:                networkName,
 		actorParser:            actorsV1.NewActorParser(helper, logger, metrics),
-		addresses:              types.NewAddressInfoMap(),
 		helper:                 helper,
 		logger:                 logger2.GetSafeLogger(logger),
 		multisigEventGenerator: multisigTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, config),
@@ -79,6 +79,10 @@ func NewParser(helper *helper.Helper, logger *logger.Logger, metrics metrics.Met
 		verifregEventGenerator: verifregTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, networkName, config),
 		dataCapEventGenerator:  dataCapTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, config),
 		dealsEventGenerator:    dealsTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, networkName, config),
+		paychEventGenerator:    paychTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, config),
+		powerEventGenerator:    powerTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, networkName, config),
+		rewardEventGenerator:   blockRewardTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, networkName, config),
+		creationEventGenerator: creationTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, config),
 		metrics:                parsermetrics.NewClient(metrics, "parserV2"),
 		actorsCacheMetrics:     cacheMetrics.NewClient(metrics, "actorsCache"),
 		config:                 config,
@@ -92,7 +96,6 @@ func NewActorsV2Parser(network string, helper *helper.Helper, logger *logger.Log
 	return &Parser{
 		network:                network,
 		actorParser:            actorsV2.NewActorParser(network, helper, logger, metrics),
-		addresses:              types.NewAddressInfoMap(),
 		helper:                 helper,
 		logger:                 logger2.GetSafeLogger(logger),
 		multisigEventGenerator: multisigTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, config),
@@ -100,6 +103,10 @@ func NewActorsV2Parser(network string, helper *helper.Helper, logger *logger.Log
 		verifregEventGenerator: verifregTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, network, config),
 		dataCapEventGenerator:  dataCapTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, config),
 		dealsEventGenerator:    dealsTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, network, config),
+		paychEventGenerator:    paychTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, config),
+		powerEventGenerator:    powerTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, network, config),
+		rewardEventGenerator:   blockRewardTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, network, config),
+		creationEventGenerator: creationTools.NewEventGenerator(helper, logger2.GetSafeLogger(logger), metrics, config),
 		metrics:                parsermetrics.NewClient(metrics, "parserV2"),
 		actorsCacheMetrics:     cacheMetrics.NewClient(metrics, "actorsCache"),
 		config:                 config,
@@ -138,90 +145,136 @@ func (p *Parser) ParseTransactions(ctx context.Context, txsData types.TxsData) (
 		return nil, errors.New("could not decode")
 	}
 
+	// addresses and txCidEquivalents are kept per call so concurrent calls on the same parser don't share state
 	var transactions []*types.Transaction
-	p.addresses = types.NewAddressInfoMap()
-	p.txCidEquivalents = make([]types.TxCidTranslation, 0)
+	addresses := types.NewAddressInfoMap()
+	txCidEquivalents := make([]types.TxCidTranslation, 0)
 
 	for _, trace := range computeState.Trace {
-		if trace.Msg == nil {
-			p.logger.Errorf("Trace without message: %s", trace.MsgCid.String())
-			_ = p.metrics.UpdateTraceWithoutMessageMetric()
-			continue
+		txs, txCidEquivalent := p.parseInvocResult(ctx, trace, txsData, addresses)
+		transactions = append(transactions, txs...)
+		if txCidEquivalent != nil {
+			txCidEquivalents = append(txCidEquivalents, *txCidEquivalent)
 		}
+	}
 
-		// Observed in the wild, the main tx data is not the same as the execution trace data.
-		// For those cases, we want to observe it, and use the top-level tx info as fallback.
-		// Ex: Height 501104
-		// Ex: https://filfox.info/en/message/bafy2bzacecgh3w4qk3t53kg6skweh4isekscy22s4nfxdyc7zcbfb4ez6li5m?t=2
-		if trace.ExecutionTrace.MsgRct.ExitCode != trace.MsgRct.ExitCode {
-			p.logger.Errorf("ExecutionTrace.MsgRct.ExitCode != MsgRct.ExitCode for tx %s", trace.MsgCid.String())
-			_ = p.metrics.UpdateMismatchExitCodeMetric()
-
-			trace.ExecutionTrace.Msg = *LotusMsgToExecutionTraceMsg(trace.Msg)
-			trace.ExecutionTrace.MsgRct = *LotusMsgRctToExecutionTraceMsgRct(trace.MsgRct)
-			trace.ExecutionTrace.Error = trace.Error
-			trace.ExecutionTrace.Duration = trace.Duration
+	transactions = tools.SetNodeMetadata(transactions, txsData.Metadata, Version)
+
+	return &types.TxsParsedResult{
+		Txs:       transactions,
+		Addresses: addresses,
+		TxCids:    txCidEquivalents,
+	}, nil
+}
+
+// ParseTransactionsStream decodes the traces incrementally and calls handler once per top-level trace,
+// so neither the whole ComputeStateOutputV2 nor the whole list of transactions is kept in memory.
+func (p *Parser) ParseTransactionsStream(ctx context.Context, txsData types.TxsData, handler types.TxsChunkHandler) error {
+	return parser.DecodeComputeStateTraces(parser.TracesReader(txsData.Traces, txsData.TracesReader), func(trace *typesV2.InvocResultV2) error {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
 
-		// check the 1st execution
-		systemExecution := p.helper.IsSystemActor(trace.ExecutionTrace.Msg.From) && p.helper.IsSystemActor(trace.ExecutionTrace.Msg.To)
+		addresses := types.NewAddressInfoMap()
+		txs, txCidEquivalent := p.parseInvocResult(ctx, trace, txsData, addresses)
+		if len(txs) == 0 {
+			return nil
+		}
 
-		mainMsgCid := trace.MsgCid
-		mainMsgExitCode := trace.MsgRct.ExitCode
-		transaction, err := p.parseTrace(ctx, trace.ExecutionTrace, mainMsgCid, txsData.Tipset, uuid.Nil.String(), systemExecution, mainMsgExitCode, txsData.Canonical)
-		if err != nil {
-			p.logger.Errorf("Error parsing trace for tx %s: %v", mainMsgCid, err)
-			_ = p.metrics.UpdateParseTraceMetric()
-			continue
+		chunk := &types.TxsParsedChunk{
+			TxCid:     trace.MsgCid.String(),
+			Txs:       tools.SetNodeMetadata(txs, txsData.Metadata, Version),
+			Addresses: addresses,
+			TxCids:    []types.TxCidTranslation{},
+		}
+		if txCidEquivalent != nil {
+			chunk.TxCids = append(chunk.TxCids, *txCidEquivalent)
 		}
 
-		// We only set the gas usage for the main transaction.
-		// If we need the gas usage of all sub-txs, we need to also parse GasCharges (today is very inefficient)
-		transaction.GasUsed = trace.GasCost.GasUsed.Uint64()
+		return handler(chunk)
+	})
+}
 
-		transactions = append(transactions, transaction)
+// parseInvocResult parses a top-level trace into its main transaction, subcalls and fee transaction.
+// Addresses found along the way are appended to addresses.
+func (p *Parser) parseInvocResult(ctx context.Context, trace *typesV2.InvocResultV2, txsData types.TxsData, addresses *types.AddressInfoMap) ([]*types.Transaction, *types.TxCidTranslation) {
+	var transactions []*types.Transaction
+	var txCidEquivalent *types.TxCidTranslation
 
-		// note: we are using the parent MsgRct.ExitCode not the ExecutionTrace.MsgRct.ExitCode
-		subTxs := p.parseSubTxs(ctx, trace.ExecutionTrace.Subcalls, mainMsgCid, txsData.Tipset, txsData.EthLogs,
-			trace.Msg.Cid().String(), transaction.Id, 0, systemExecution, mainMsgExitCode, txsData.Canonical)
-		if len(subTxs) > 0 {
-			transactions = append(transactions, subTxs...)
-		}
+	if trace.Msg == nil {
+		p.logger.Errorf("Trace without message: %s", trace.MsgCid.String())
+		_ = p.metrics.UpdateTraceWithoutMessageMetric()
+		return nil, nil
+	}
 
-		// Fees
-		if trace.GasCost.TotalCost.Uint64() > 0 {
-			feeTx := p.feesTransactions(trace, txsData.Tipset, transaction.TxType, transaction.Id, systemExecution, txsData.Canonical)
-			if p.config.FeesAsColumn {
-				transaction.FeeData = feeTx.TxMetadata
-			} else {
-				transactions = append(transactions, feeTx)
-			}
+	// Observed in the wild, the main tx data is not the same as the execution trace data.
+	// For those cases, we want to observe it, and use the top-level tx info as fallback.
+	// Ex: Height 501104
+	// Ex: https://filfox.info/en/message/bafy2bzacecgh3w4qk3t53kg6skweh4isekscy22s4nfxdyc7zcbfb4ez6li5m?t=2
+	if trace.ExecutionTrace.MsgRct.ExitCode != trace.MsgRct.ExitCode {
+		p.logger.Errorf("ExecutionTrace.MsgRct.ExitCode != MsgRct.ExitCode for tx %s", trace.MsgCid.String())
+		_ = p.metrics.UpdateMismatchExitCodeMetric()
+
+		trace.ExecutionTrace.Msg = *LotusMsgToExecutionTraceMsg(trace.Msg)
+		trace.ExecutionTrace.MsgRct = *LotusMsgRctToExecutionTraceMsgRct(trace.MsgRct)
+		trace.ExecutionTrace.Error = trace.Error
+		trace.ExecutionTrace.Duration = trace.Duration
+	}
+
+	// check the 1st execution
+	systemExecution := p.helper.IsSystemActor(trace.ExecutionTrace.Msg.From) && p.helper.IsSystemActor(trace.ExecutionTrace.Msg.To)
+
+	mainMsgCid := trace.MsgCid
+	mainMsgExitCode := trace.MsgRct.ExitCode
+	transaction, err := p.parseTrace(ctx, trace.ExecutionTrace, mainMsgCid, txsData.Tipset, uuid.Nil.String(), systemExecution, mainMsgExitCode, txsData.Canonical, addresses)
+	if err != nil {
+		p.logger.Errorf("Error parsing trace for tx %s: %v", mainMsgCid, err)
+		_ = p.metrics.UpdateParseTraceMetric()
+		return nil, nil
+	}
+
+	// We only set the gas usage for the main transaction.
+	// If we need the gas usage of all sub-txs, we need to also parse GasCharges (today is very inefficient)
+	transaction.GasUsed = trace.GasCost.GasUsed.Uint64()
+
+	transactions = append(transactions, transaction)
+
+	// note: we are using the parent MsgRct.ExitCode not the ExecutionTrace.MsgRct.ExitCode
+	subTxs := p.parseSubTxs(ctx, trace.ExecutionTrace.Subcalls, mainMsgCid, txsData.Tipset, txsData.EthLogs,
+		trace.Msg.Cid().String(), transaction.Id, 0, systemExecution, mainMsgExitCode, txsData.Canonical, addresses)
+	if len(subTxs) > 0 {
+		transactions = append(transactions, subTxs...)
+	}
+
+	// Fees
+	if trace.GasCost.TotalCost.Uint64() > 0 {
+		feeTx := p.feesTransactions(trace, txsData.Tipset, transaction.TxType, transaction.Id, systemExecution, txsData.Canonical)
+		if p.config.FeesAsColumn {
+			transaction.FeeData = feeTx.TxMetadata
+		} else {
+			transactions = append(transactions, feeTx)
 		}
+	}
 
-		// TxCid <-> TxHash
-		if int64(txsData.Tipset.Height()) >= p.config.TxCidTranslationStart {
-			txHash, err := parser.TranslateTxCidToTxHash(p.helper.GetFilecoinNodeClient(), trace.MsgCid, p.actorsCacheMetrics, p.backoff)
+	// TxCid <-> TxHash
+	if int64(txsData.Tipset.Height()) >= p.config.TxCidTranslationStart {
+		var err error
+		if p.helper.IsOffline() {
+			txCidEquivalent, err = parser.TranslateTxCidToTxHashOffline(trace.Msg.From, trace.MsgCid)
+		} else {
+			var txHash string
+			txHash, err = parser.TranslateTxCidToTxHash(p.helper.GetFilecoinNodeClient(), trace.MsgCid, p.actorsCacheMetrics, p.backoff)
 			if err == nil && txHash != "" {
-				p.txCidEquivalents = append(p.txCidEquivalents, types.TxCidTranslation{TxCid: trace.MsgCid.String(), TxHash: txHash})
-			}
-			if err != nil {
-				_ = p.metrics.UpdateTranslateTxCidToTxHashMetric()
-				p.logger.Warnf("Error when trying to translate tx cid to tx hash: %v", err)
+				txCidEquivalent = &types.TxCidTranslation{TxCid: trace.MsgCid.String(), TxHash: txHash}
 			}
 		}
+		if err != nil {
+			_ = p.metrics.UpdateTranslateTxCidToTxHashMetric()
+			p.logger.Warnf("Error when trying to translate tx cid to tx hash: %v", err)
+		}
 	}
 
-	transactions = tools.SetNodeMetadata(transactions, txsData.Metadata, Version)
-
-	// Clear this cache when we finish processing a tipset.
-	// Bad addresses in this tipset might be valid in the next one
-	p.helper.GetActorsCache().ClearBadAddressCache()
-
-	return &types.TxsParsedResult{
-		Txs:       transactions,
-		Addresses: p.addresses,
-		TxCids:    p.txCidEquivalents,
-	}, nil
+	return transactions, txCidEquivalent
 }
 
 func (p *Parser) ParseNativeEvents(_ context.Context, eventsData types.EventsData) (*types.EventsParsedResult, error) {
@@ -316,6 +369,22 @@ func (p *Parser) ParseDealsEvents(ctx context.Context, dealsTxs []*types.Transac
 	return p.dealsEventGenerator.GenerateDealsEvents(ctx, dealsTxs, tipsetCid, tipsetKey)
 }
 
+func (p *Parser) ParsePaymentChannelEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.PaymentChannelEvents, error) {
+	return p.paychEventGenerator.GeneratePaymentChannelEvents(ctx, txs, tipsetCid, tipsetKey)
+}
+
+func (p *Parser) ParsePowerEvents(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.PowerEvents, error) {
+	return p.powerEventGenerator.GeneratePowerEvents(ctx, txs, tipsetCid, tipsetKey)
+}
+
+func (p *Parser) ParseBlockRewardEvents(ctx context.Context, txs []*types.Transaction, tipset *types.ExtendedTipSet) (*types.BlockRewardEvents, error) {
+	return p.rewardEventGenerator.GenerateBlockRewardEvents(ctx, txs, tipset)
+}
+
+func (p *Parser) ParseActorCreations(ctx context.Context, txs []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.ActorCreationEvents, error) {
+	return p.creationEventGenerator.GenerateActorCreations(ctx, txs, tipsetCid, tipsetKey)
+}
+
 func (p *Parser) GetBaseFee(traces []byte, tipset *types.ExtendedTipSet) (uint64, error) {
 	// Unmarshal into vComputeState
 	computeState := &typesV2.ComputeStateOutputV2{}
@@ -346,22 +415,22 @@ func (p *Parser) GetBaseFee(traces []byte, tipset *types.ExtendedTipSet) (uint64
 }
 
 func (p *Parser) parseSubTxs(ctx context.Context, subTxs []typesV2.ExecutionTraceV2, mainMsgCid cid.Cid, tipSet *types.ExtendedTipSet, ethLogs []types.EthLog, txHash string,
-	parentId string, level uint16, systemExecution bool, mainExitCode exitcode.ExitCode, canonical bool) (txs []*types.Transaction) {
+	parentId string, level uint16, systemExecution bool, mainExitCode exitcode.ExitCode, canonical bool, addresses *types.AddressInfoMap) (txs []*types.Transaction) {
 	level++
 	for _, subTx := range subTxs {
-		subTransaction, err := p.parseTrace(ctx, subTx, mainMsgCid, tipSet, parentId, systemExecution, mainExitCode, canonical)
+		subTransaction, err := p.parseTrace(ctx, subTx, mainMsgCid, tipSet, parentId, systemExecution, mainExitCode, canonical, addresses)
 		if err != nil {
 			continue
 		}
 
 		subTransaction.Level = level
 		txs = append(txs, subTransaction)
-		txs = append(txs, p.parseSubTxs(ctx, subTx.Subcalls, mainMsgCid, tipSet, ethLogs, txHash, subTransaction.Id, level, systemExecution, mainExitCode, canonical)...)
+		txs = append(txs, p.parseSubTxs(ctx, subTx.Subcalls, mainMsgCid, tipSet, ethLogs, txHash, subTransaction.Id, level, systemExecution, mainExitCode, canonical, addresses)...)
 	}
 	return
 }
 
-func (p *Parser) parseTrace(ctx context.Context, trace typesV2.ExecutionTraceV2, mainMsgCid cid.Cid, tipset *types.ExtendedTipSet, parentId string, systemExecution bool, mainExitCode exitcode.ExitCode, canonical bool) (*types.Transaction, error) {
+func (p *Parser) parseTrace(ctx context.Context, trace typesV2.ExecutionTraceV2, mainMsgCid cid.Cid, tipset *types.ExtendedTipSet, parentId string, systemExecution bool, mainExitCode exitcode.ExitCode, canonical bool, addresses *types.AddressInfoMap) (*types.Transaction, error) {
 	mainFailedTx := mainExitCode.IsError()
 	subcallFailedTx := trace.MsgRct.ExitCode.IsError()
 	actorName, txType, err := p.getTxType(ctx, trace, mainMsgCid, tipset, canonical)
@@ -396,7 +465,7 @@ func (p *Parser) parseTrace(ctx context.Context, trace typesV2.ExecutionTraceV2,
 
 	// If the tx failed, we don't want to add the address info to the addresses map, as we could be adding bad relationships between short and robust..
 	if !mainFailedTx && addressInfo != nil {
-		parser.AppendToAddressesMap(p.addresses, addressInfo)
+		parser.AppendToAddressesMap(addresses, addressInfo)
 	}
 	if len(metadata) == 0 {
 		metadata = map[string]interface{}{
@@ -423,7 +492,7 @@ func (p *Parser) parseTrace(ctx context.Context, trace typesV2.ExecutionTraceV2,
 		Method: trace.Msg.Method,
 		Cid:    mainMsgCid,
 		Params: trace.Msg.Params,
-	}, tipset.Key(), tipset.Height(), canonical)
+	}, tipset.Key(), tipset.Height(), canonical, addresses)
 
 	var blockCid string
 	if !systemExecution {
@@ -581,17 +650,17 @@ func (p *Parser) getFromToRobustAddresses(from, to address.Address, canonical bo
 	return txFrom, txTo
 }
 
-func (p *Parser) appendAddressInfo(msg *parser.LotusMessage, key filTypes.TipSetKey, height abi.ChainEpoch, canonical bool) {
+func (p *Parser) appendAddressInfo(msg *parser.LotusMessage, key filTypes.TipSetKey, height abi.ChainEpoch, canonical bool, addresses *types.AddressInfoMap) {
 	if msg == nil {
 		return
 	}
 	if msg.From != address.Undef {
 		fromAdd := p.helper.GetActorAddressInfo(msg.From, key, height, canonical)
-		parser.AppendToAddressesMap(p.addresses, fromAdd)
+		parser.AppendToAddressesMap(addresses, fromAdd)
 	}
 	if msg.To != address.Undef {
 		toAdd := p.helper.GetActorAddressInfo(msg.To, key, height, canonical)
-		parser.AppendToAddressesMap(p.addresses, toAdd)
+		parser.AppendToAddressesMap(addresses, toAdd)
 	}
 }
 
@@ -0,0 +1,131 @@
+package fil_parser
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/zondax/fil-parser/types"
+)
+
+const defaultRangeWorkers = 1
+
+type rangeJob struct {
+	seq  uint64
+	data *types.HeightData
+}
+
+type rangeResult struct {
+	seq    uint64
+	result *types.HeightParsedResult
+}
+
+// ParseRange parses every height received from source using up to workers concurrent goroutines.
+// Results are emitted in the same order the heights were received, and at most workers heights are
+// in flight at any time, so a slow height only holds back its successors instead of buffering the whole range.
+// The returned channel is closed once source is closed and drained, or as soon as ctx is cancelled.
+// The bad address cache of the actors cache is shared by the heights in flight, and cleared once none of them uses it.
+func (p *FilecoinParser) ParseRange(ctx context.Context, source <-chan *types.HeightData, workers int) <-chan *types.HeightParsedResult {
+	if workers < 1 {
+		workers = defaultRangeWorkers
+	}
+
+	out := make(chan *types.HeightParsedResult)
+	jobs := make(chan rangeJob)
+	results := make(chan rangeResult, workers)
+	// inFlight bounds the number of heights dispatched but not yet emitted
+	inFlight := make(chan struct{}, workers)
+
+	// dispatcher
+	go func() {
+		defer close(jobs)
+		var seq uint64
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-source:
+				if !ok {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case inFlight <- struct{}{}:
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case jobs <- rangeJob{seq: seq, data: data}:
+				}
+				seq++
+			}
+		}
+	}()
+
+	// workers
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				result := p.parseHeight(ctx, job.data)
+				select {
+				case <-ctx.Done():
+					return
+				case results <- rangeResult{seq: job.seq, result: result}:
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// collector: reorders the results before emitting them
+	go func() {
+		defer close(out)
+		pending := make(map[uint64]*types.HeightParsedResult)
+		var next uint64
+		for res := range results {
+			pending[res.seq] = res.result
+			for {
+				result, ok := pending[next]
+				if !ok {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- result:
+				}
+				delete(pending, next)
+				next++
+				<-inFlight
+			}
+		}
+	}()
+
+	return out
+}
+
+// parseHeight runs the per-height parsers over data. Parsing stops at the first error.
+func (p *FilecoinParser) parseHeight(ctx context.Context, data *types.HeightData) *types.HeightParsedResult {
+	result := &types.HeightParsedResult{Height: data.Height}
+
+	bundle, err := p.ParseTipset(ctx, data, types.DatasetTransactions, types.DatasetNativeEvents, types.DatasetEthLogs, types.DatasetBlocksInfo)
+	if bundle != nil {
+		result.Txs = bundle.Txs
+		result.NativeEvents = bundle.NativeEvents
+		result.EthLogs = bundle.EthLogs
+		result.BlocksTimestamp = bundle.BlocksTimestamp
+		result.BlockAddresses = bundle.BlockAddresses
+	}
+	if err != nil {
+		result.Err = fmt.Errorf("height %d: %w", data.Height, err)
+	}
+
+	return result
+}
@@ -0,0 +1,156 @@
+package fil_parser
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/types"
+)
+
+const rangeHeight = "1419335"
+
+// rangeTestParser wraps a parser to track how many heights parse their transactions at once.
+type rangeTestParser struct {
+	Parser
+	mu         sync.Mutex
+	calls      int
+	running    int
+	maxRunning int
+	// delay runs before the traces of height are parsed
+	delay func(ctx context.Context, height uint64)
+}
+
+// rangeTestVersion is the full node version sendHeights sets on the metadata of height, so the wrapped parser can
+// tell the heights apart even if they share the traces and tipset of the fixture.
+func rangeTestVersion(height uint64) string {
+	return fmt.Sprintf("range-test-%d", height)
+}
+
+func (r *rangeTestParser) ParseTransactions(ctx context.Context, txsData types.TxsData) (*types.TxsParsedResult, error) {
+	var height uint64
+	if _, err := fmt.Sscanf(txsData.Metadata.NodeFullVersion, "range-test-%d", &height); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.calls++
+	r.running++
+	if r.running > r.maxRunning {
+		r.maxRunning = r.running
+	}
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		r.running--
+		r.mu.Unlock()
+	}()
+
+	r.delay(ctx, height)
+	return r.Parser.ParseTransactions(ctx, txsData)
+}
+
+// newRangeTestParser returns an offline parser whose traces are parsed by a rangeTestParser, along with the data of
+// the fixture height.
+func newRangeTestParser(t *testing.T, delay func(ctx context.Context, height uint64)) (*FilecoinParser, *rangeTestParser, types.TxsData) {
+	p := newOfflineParser(t, tools.CalibrationNetwork, rangeHeight)
+	txsData := readTxsData(t, rangeHeight)
+
+	resolved, err := p.Registry().Resolve(txsData.Metadata)
+	require.NoError(t, err)
+	wrapped := &rangeTestParser{Parser: resolved.Parser, delay: delay}
+	version := txsData.Metadata.NodeMajorMinorVersion
+	require.NoError(t, p.Registry().Register(wrapped, TraceFormatV2, NodeVersionRange{From: version, To: version}))
+	return p, wrapped, txsData
+}
+
+func sendHeights(ctx context.Context, txsData types.TxsData, heights []uint64) <-chan *types.HeightData {
+	source := make(chan *types.HeightData)
+	go func() {
+		defer close(source)
+		for _, height := range heights {
+			metadata := txsData.Metadata
+			metadata.NodeFullVersion = rangeTestVersion(height)
+			data := &types.HeightData{
+				Height:    height,
+				Traces:    txsData.Traces,
+				Tipset:    txsData.Tipset,
+				EthLogs:   txsData.EthLogs,
+				Metadata:  metadata,
+				Canonical: true,
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case source <- data:
+			}
+		}
+	}()
+	return source
+}
+
+func TestParser_ParseRange(t *testing.T) {
+	const workers = 3
+	heights := []uint64{10, 11, 12, 13, 14, 15, 16, 17}
+	// the first heights are the slowest, so they finish after the ones dispatched next
+	p, wrapped, txsData := newRangeTestParser(t, func(_ context.Context, height uint64) {
+		time.Sleep(time.Duration(heights[len(heights)-1]-height+1) * 10 * time.Millisecond)
+	})
+
+	ctx := context.Background()
+	var got []uint64
+	for result := range p.ParseRange(ctx, sendHeights(ctx, txsData, heights), workers) {
+		require.NoError(t, result.Err)
+		require.NotNil(t, result.Txs)
+		assert.Equal(t, 37, len(result.Txs.Txs))
+		got = append(got, result.Height)
+	}
+
+	assert.Equal(t, heights, got)
+	assert.Equal(t, len(heights), wrapped.calls)
+	assert.LessOrEqual(t, wrapped.maxRunning, workers)
+	assert.Greater(t, wrapped.maxRunning, 1)
+}
+
+func TestParser_ParseRange_Cancel(t *testing.T) {
+	const workers = 2
+	heights := []uint64{10, 11, 12, 13, 14, 15, 16, 17}
+	// every height but the first one blocks until the range is cancelled
+	p, wrapped, txsData := newRangeTestParser(t, func(ctx context.Context, height uint64) {
+		if height != heights[0] {
+			<-ctx.Done()
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := p.ParseRange(ctx, sendHeights(ctx, txsData, heights), workers)
+
+	first := <-out
+	require.NotNil(t, first)
+	assert.Equal(t, heights[0], first.Height)
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range out {
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ParseRange did not stop after the context was cancelled")
+	}
+
+	wrapped.mu.Lock()
+	defer wrapped.mu.Unlock()
+	// no more heights than workers are dispatched once the first one is emitted
+	assert.LessOrEqual(t, wrapped.calls, 1+workers)
+}
@@ -0,0 +1,286 @@
+package fil_parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/zondax/fil-parser/types"
+)
+
+// Trace formats understood by the built-in parsers.
+const (
+	TraceFormatV1 = "executiontracev1"
+	TraceFormatV2 = "executiontracev2"
+)
+
+var (
+	errNoParserRegistered = errors.New("no parser registered")
+	errInvalidNodeVersion = errors.New("invalid node version")
+)
+
+// NodeVersionRange is an inclusive range of node major.minor versions, such as v1.23 to v1.34.
+type NodeVersionRange struct {
+	From string
+	To   string
+}
+
+// NodeVersions returns a range covering exactly the given versions, which must be sorted.
+func NodeVersions(versions ...string) NodeVersionRange {
+	if len(versions) == 0 {
+		return NodeVersionRange{}
+	}
+	return NodeVersionRange{From: versions[0], To: versions[len(versions)-1]}
+}
+
+func (r NodeVersionRange) contains(ver nodeVersion) bool {
+	from, errFrom := parseNodeVersion(r.From)
+	to, errTo := parseNodeVersion(r.To)
+	if errFrom != nil || errTo != nil {
+		return false
+	}
+	return !ver.less(from) && !to.less(ver)
+}
+
+// RegisteredParser is a parser along with the trace format and node versions it handles.
+type RegisteredParser struct {
+	Parser          Parser
+	TraceFormat     string
+	NodeVersions    []NodeVersionRange
+	earliestVersion nodeVersion
+	latestVersion   nodeVersion
+	// order is the registration order, used to give precedence to the parsers registered last
+	order int
+}
+
+// VersionFallback picks the parser for node versions no registered parser handles.
+// registered is sorted from the oldest to the latest node version supported.
+// Returning an error makes the parse fail with errUnknownVersion.
+type VersionFallback func(metadata types.BlockMetadata, registered []*RegisteredParser) (*RegisteredParser, error)
+
+// FallbackToLatest parses unknown node versions with the parser supporting the latest node version.
+// The parsed output is flagged with VersionFallback so consumers can tell it apart.
+// Unparsable versions and versions older than every registered range are rejected, as the latest parser cannot
+// handle the traces of older nodes.
+func FallbackToLatest(metadata types.BlockMetadata, registered []*RegisteredParser) (*RegisteredParser, error) {
+	if len(registered) == 0 {
+		return nil, errNoParserRegistered
+	}
+	ver, err := parseNodeVersion(metadata.NodeMajorMinorVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, parser := range registered {
+		if !ver.less(parser.earliestVersion) {
+			return registered[len(registered)-1], nil
+		}
+	}
+	return nil, fmt.Errorf("node version not supported %s: older than every registered parser", metadata.NodeFullVersion)
+}
+
+// FailOnUnknownVersion rejects node versions no registered parser handles.
+func FailOnUnknownVersion(metadata types.BlockMetadata, _ []*RegisteredParser) (*RegisteredParser, error) {
+	return nil, fmt.Errorf("node version not supported %s", metadata.NodeFullVersion)
+}
+
+// ParserResolution is the parser picked for a given node version.
+type ParserResolution struct {
+	*RegisteredParser
+	// Fallback is true when the node version is not supported by any registered parser
+	// and the parser was picked by the VersionFallback policy instead.
+	Fallback bool
+}
+
+// ParserRegistry maps node versions to the parser implementation handling their traces.
+type ParserRegistry struct {
+	mu         sync.RWMutex
+	registered []*RegisteredParser
+	fallback   VersionFallback
+	registers  int
+}
+
+// NewParserRegistry returns an empty registry using fallback for unknown node versions.
+// A nil fallback defaults to FailOnUnknownVersion.
+func NewParserRegistry(fallback VersionFallback) *ParserRegistry {
+	if fallback == nil {
+		fallback = FailOnUnknownVersion
+	}
+	return &ParserRegistry{fallback: fallback}
+}
+
+// Register adds a parser handling the traces of the given node version ranges.
+// When ranges overlap, the parser registered last takes precedence.
+func (r *ParserRegistry) Register(parser Parser, traceFormat string, ranges ...NodeVersionRange) error {
+	if parser == nil {
+		return errors.New("parser cannot be nil")
+	}
+	if len(ranges) == 0 {
+		return fmt.Errorf("parser %s must handle at least one node version range", parser.Version())
+	}
+
+	entry := &RegisteredParser{Parser: parser, TraceFormat: traceFormat, NodeVersions: ranges}
+	for i, rng := range ranges {
+		from, err := parseNodeVersion(rng.From)
+		if err != nil {
+			return err
+		}
+		to, err := parseNodeVersion(rng.To)
+		if err != nil {
+			return err
+		}
+		if to.less(from) {
+			return fmt.Errorf("invalid node version range %s - %s", rng.From, rng.To)
+		}
+		if i == 0 || from.less(entry.earliestVersion) {
+			entry.earliestVersion = from
+		}
+		if entry.latestVersion.less(to) {
+			entry.latestVersion = to
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry.order = r.registers
+	r.registers++
+
+	// keep the parsers sorted by the latest node version they support, so the fallback can pick the latest one
+	idx := len(r.registered)
+	for i, registered := range r.registered {
+		if entry.latestVersion.less(registered.latestVersion) {
+			idx = i
+			break
+		}
+	}
+	r.registered = append(r.registered, nil)
+	copy(r.registered[idx+1:], r.registered[idx:])
+	r.registered[idx] = entry
+
+	return nil
+}
+
+// Registered returns the registered parsers, sorted from the oldest to the latest node version supported.
+func (r *ParserRegistry) Registered() []*RegisteredParser {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	registered := make([]*RegisteredParser, len(r.registered))
+	copy(registered, r.registered)
+	return registered
+}
+
+// Latest returns the parser supporting the latest node version, or nil if none was registered.
+func (r *ParserRegistry) Latest() Parser {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.registered) == 0 {
+		return nil
+	}
+	return r.registered[len(r.registered)-1].Parser
+}
+
+// Resolve returns the parser handling the traces of the node version in metadata.
+// The empty version is for backwards compatibility with older traces, which are handled by the oldest parser.
+func (r *ParserRegistry) Resolve(metadata types.BlockMetadata) (*ParserResolution, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.registered) == 0 {
+		return nil, errNoParserRegistered
+	}
+	if metadata.NodeMajorMinorVersion == "" {
+		return &ParserResolution{RegisteredParser: r.registered[0]}, nil
+	}
+
+	if ver, err := parseNodeVersion(metadata.NodeMajorMinorVersion); err == nil {
+		var match *RegisteredParser
+		for _, registered := range r.registered {
+			if !registered.handles(ver) {
+				continue
+			}
+			if match == nil || registered.order > match.order {
+				match = registered
+			}
+		}
+		if match != nil {
+			return &ParserResolution{RegisteredParser: match}, nil
+		}
+	}
+
+	registered := make([]*RegisteredParser, len(r.registered))
+	copy(registered, r.registered)
+	fallback, err := r.fallback(metadata, registered)
+	if err != nil {
+		return nil, err
+	}
+	if fallback == nil {
+		return nil, errNoParserRegistered
+	}
+	return &ParserResolution{RegisteredParser: fallback, Fallback: true}, nil
+}
+
+// ResolveEvents returns the parser handling the events of the node version in metadata.
+// Events are only parsed by parsers of the TraceFormatV2 trace format. Node versions resolved to a parser of another
+// trace format get the TraceFormatV2 parser supporting the oldest node versions, so a parser registered for newer
+// node versions does not change how the events of older heights are parsed.
+func (r *ParserRegistry) ResolveEvents(metadata types.BlockMetadata) (*ParserResolution, error) {
+	resolved, err := r.Resolve(metadata)
+	if err != nil {
+		return nil, err
+	}
+	if resolved.TraceFormat == TraceFormatV2 {
+		return resolved, nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, registered := range r.registered {
+		if registered.TraceFormat == TraceFormatV2 {
+			return &ParserResolution{RegisteredParser: registered, Fallback: resolved.Fallback}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w for the events of trace format %s", errNoParserRegistered, resolved.TraceFormat)
+}
+
+func (p *RegisteredParser) handles(ver nodeVersion) bool {
+	for _, rng := range p.NodeVersions {
+		if rng.contains(ver) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeVersion is a node major.minor version, such as v1.23.
+type nodeVersion struct {
+	major int
+	minor int
+}
+
+func (v nodeVersion) less(other nodeVersion) bool {
+	if v.major != other.major {
+		return v.major < other.major
+	}
+	return v.minor < other.minor
+}
+
+func parseNodeVersion(ver string) (nodeVersion, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(ver)), "v"), ".")
+	if len(parts) < 2 {
+		return nodeVersion{}, fmt.Errorf("%w: %s", errInvalidNodeVersion, ver)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nodeVersion{}, fmt.Errorf("%w: %s", errInvalidNodeVersion, ver)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nodeVersion{}, fmt.Errorf("%w: %s", errInvalidNodeVersion, ver)
+	}
+	return nodeVersion{major: major, minor: minor}, nil
+}
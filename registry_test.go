@@ -0,0 +1,137 @@
+package fil_parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "github.com/zondax/fil-parser/parser/v1"
+	v2 "github.com/zondax/fil-parser/parser/v2"
+	"github.com/zondax/fil-parser/types"
+)
+
+func newTestRegistry(t *testing.T, fallback VersionFallback) *ParserRegistry {
+	registry := NewParserRegistry(fallback)
+	require.NoError(t, registerDefaultParsers(registry, &v1.Parser{}, TraceFormatV1, &v2.Parser{}))
+	return registry
+}
+
+func metadataForVersion(ver string) types.BlockMetadata {
+	return types.BlockMetadata{NodeInfo: types.NodeInfo{NodeMajorMinorVersion: ver, NodeFullVersion: ver + ".0"}}
+}
+
+func TestParserRegistry_Resolve(t *testing.T) {
+	registry := newTestRegistry(t, nil)
+
+	tests := []struct {
+		name            string
+		version         string
+		wantVersion     string
+		wantTraceFormat string
+	}{
+		{name: "legacy traces without version", version: "", wantVersion: v1.Version, wantTraceFormat: TraceFormatV1},
+		{name: "v1 range", version: v1.NodeVersionsSupported[0], wantVersion: v1.Version, wantTraceFormat: TraceFormatV1},
+		{name: "v2 range", version: v2.NodeVersionsSupported[len(v2.NodeVersionsSupported)-1], wantVersion: v2.Version, wantTraceFormat: TraceFormatV2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := registry.Resolve(metadataForVersion(tt.version))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, resolved.Parser.Version())
+			assert.Equal(t, tt.wantTraceFormat, resolved.TraceFormat)
+			assert.False(t, resolved.Fallback)
+		})
+	}
+}
+
+func TestParserRegistry_DefaultFailsOnUnknownVersion(t *testing.T) {
+	registry := newTestRegistry(t, nil)
+
+	for _, version := range []string{"v1.99", "v0.1", "unknown"} {
+		_, err := registry.Resolve(metadataForVersion(version))
+		assert.Error(t, err, version)
+	}
+}
+
+func TestParserRegistry_FallbackToLatest(t *testing.T) {
+	registry := newTestRegistry(t, FallbackToLatest)
+
+	resolved, err := registry.Resolve(metadataForVersion("v1.99"))
+	require.NoError(t, err)
+	assert.Equal(t, v2.Version, resolved.Parser.Version())
+	assert.Equal(t, TraceFormatV2, resolved.TraceFormat)
+	assert.True(t, resolved.Fallback)
+
+	// the latest parser cannot handle the traces of nodes older than every registered one
+	_, err = registry.Resolve(metadataForVersion("v0.1"))
+	assert.Error(t, err)
+
+	_, err = registry.Resolve(metadataForVersion("unknown"))
+	assert.True(t, errors.Is(err, errInvalidNodeVersion))
+}
+
+func TestParserRegistry_FailOnUnknownVersion(t *testing.T) {
+	registry := newTestRegistry(t, FailOnUnknownVersion)
+
+	_, err := registry.Resolve(metadataForVersion("v1.99"))
+	require.Error(t, err)
+
+	resolved, err := registry.Resolve(metadataForVersion("v1.23"))
+	require.NoError(t, err)
+	assert.False(t, resolved.Fallback)
+}
+
+func TestParserRegistry_RegisterOverride(t *testing.T) {
+	registry := newTestRegistry(t, FailOnUnknownVersion)
+	override := &v2.Parser{}
+	require.NoError(t, registry.Register(override, TraceFormatV2, NodeVersionRange{From: "v1.34", To: "v1.35"}))
+
+	resolved, err := registry.Resolve(metadataForVersion("v1.34"))
+	require.NoError(t, err)
+	assert.Same(t, override, resolved.Parser)
+
+	resolved, err = registry.Resolve(metadataForVersion("v1.35"))
+	require.NoError(t, err)
+	assert.Same(t, override, resolved.Parser)
+	assert.Same(t, override, registry.Latest())
+
+	err = registry.Register(override, TraceFormatV2, NodeVersionRange{From: "v1.36", To: "v1.35"})
+	require.Error(t, err)
+	err = registry.Register(override, TraceFormatV2, NodeVersionRange{From: "latest", To: "v1.35"})
+	assert.True(t, errors.Is(err, errInvalidNodeVersion))
+}
+
+func TestParserRegistry_ResolveEvents(t *testing.T) {
+	registry := newTestRegistry(t, FallbackToLatest)
+	builtin := registry.Registered()[1].Parser
+	override := &v2.Parser{}
+	require.NoError(t, registry.Register(override, TraceFormatV2, NodeVersionRange{From: "v1.34", To: "v1.35"}))
+
+	tests := []struct {
+		name         string
+		version      string
+		want         Parser
+		wantFallback bool
+	}{
+		{name: "legacy traces without version", version: "", want: builtin},
+		{name: "v1 range", version: v1.NodeVersionsSupported[0], want: builtin},
+		{name: "v2 range", version: v2.NodeVersionsSupported[0], want: builtin},
+		{name: "override range", version: "v1.35", want: override},
+		{name: "unknown future version", version: "v1.99", want: override, wantFallback: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := registry.ResolveEvents(metadataForVersion(tt.version))
+			require.NoError(t, err)
+			assert.Same(t, tt.want, resolved.Parser)
+			assert.Equal(t, TraceFormatV2, resolved.TraceFormat)
+			assert.Equal(t, tt.wantFallback, resolved.Fallback)
+		})
+	}
+
+	_, err := registry.ResolveEvents(metadataForVersion("unknown"))
+	assert.Error(t, err)
+}
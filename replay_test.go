@@ -0,0 +1,158 @@
+package fil_parser
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/network"
+	"github.com/filecoin-project/lotus/api"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/types/ethtypes"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/actors/cache/impl"
+	"github.com/zondax/fil-parser/actors/cache/impl/common"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools/mocks"
+	"github.com/zondax/fil-parser/tools/recorder"
+	"github.com/zondax/fil-parser/types"
+	rosettaFilecoinLib "github.com/zondax/rosetta-filecoin-lib"
+)
+
+var errActorNotFound = errors.New("actor not found")
+
+// newSnapshotNode returns a node serving the actors of the address snapshot of the height.
+func newSnapshotNode(t *testing.T, height string) *mocks.FullNode {
+	raw, err := readGzFile(getFilename(snapshotPrefix, height))
+	require.NoError(t, err)
+	var snapshot impl.Snapshot
+	require.NoError(t, sonic.Unmarshal(raw, &snapshot))
+
+	byAddress := make(map[string]*types.AddressInfo)
+	codes := make(map[string]cid.Cid)
+	for i := range snapshot.Addresses {
+		info := &snapshot.Addresses[i]
+		byAddress[info.Short] = info
+		if info.Robust != "" {
+			byAddress[info.Robust] = info
+		}
+		code, err := cid.Parse(info.ActorCid)
+		require.NoError(t, err)
+		codes[info.ActorType] = code
+	}
+	lookup := func(addr address.Address) (*types.AddressInfo, error) {
+		info, ok := byAddress[addr.String()]
+		if !ok {
+			return nil, errActorNotFound
+		}
+		return info, nil
+	}
+
+	node := &mocks.FullNode{}
+	node.On("StateNetworkName", mock.Anything).Return(dtypes.NetworkName("calibrationnet"), nil)
+	node.On("StateNetworkVersion", mock.Anything, mock.Anything).Return(network.Version21, nil)
+	node.On("StateActorCodeCIDs", mock.Anything, mock.Anything).Return(codes, nil)
+	node.On("StateGetActor", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, addr address.Address, _ filTypes.TipSetKey) (*filTypes.Actor, error) {
+			info, err := lookup(addr)
+			if err != nil {
+				return nil, err
+			}
+			code, err := cid.Parse(info.ActorCid)
+			if err != nil {
+				return nil, err
+			}
+			return &filTypes.Actor{Code: code}, nil
+		})
+	node.On("StateLookupID", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, addr address.Address, _ filTypes.TipSetKey) (address.Address, error) {
+			info, err := lookup(addr)
+			if err != nil {
+				return address.Undef, err
+			}
+			return address.NewFromString(info.Short)
+		})
+	robust := func(_ context.Context, addr address.Address, _ filTypes.TipSetKey) (address.Address, error) {
+		info, err := lookup(addr)
+		if err != nil || info.Robust == "" {
+			return address.Undef, errActorNotFound
+		}
+		return address.NewFromString(info.Robust)
+	}
+	node.On("StateLookupRobustAddress", mock.Anything, mock.Anything, mock.Anything).Return(robust)
+	node.On("StateAccountKey", mock.Anything, mock.Anything, mock.Anything).Return(robust)
+	node.On("EthGetTransactionHashByCid", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, msgCid cid.Cid) (*ethtypes.EthHash, error) {
+			hash, err := ethtypes.EthHashFromCid(msgCid)
+			return &hash, err
+		})
+	return node
+}
+
+func newNodeParser(t *testing.T, node api.FullNode) *FilecoinParser {
+	p, err := NewFilecoinParser(rosettaFilecoinLib.NewRosettaConstructionFilecoin(node), common.DataSource{Node: node}, gLogger)
+	require.NoError(t, err)
+	require.False(t, p.Helper.IsOffline())
+	return p
+}
+
+// TestParser_ParseTransactions_Replay parses a height with a node, recording its rpc calls, and parses it again from
+// the recorded fixture alone.
+func TestParser_ParseTransactions_Replay(t *testing.T) {
+	const height = "1419335"
+	txsData := readTxsData(t, height)
+
+	rec := recorder.NewRecorder(newSnapshotNode(t, height))
+	want, err := newNodeParser(t, rec).ParseTransactions(context.Background(), txsData)
+	require.NoError(t, err)
+
+	fixture := rec.Fixture()
+	require.NotEmpty(t, fixture.Calls)
+	require.NoError(t, fixture.EncodingErrors())
+	var buf bytes.Buffer
+	require.NoError(t, fixture.Write(&buf))
+	loaded, err := recorder.LoadFixture(&buf)
+	require.NoError(t, err)
+
+	got, err := newNodeParser(t, recorder.NewReplayer(loaded)).ParseTransactions(context.Background(), txsData)
+	require.NoError(t, err)
+
+	assert.Equal(t, 37, len(got.Txs))
+	assert.Equal(t, want.Txs, got.Txs)
+	assert.Equal(t, want.TxCids, got.TxCids)
+	assert.Equal(t, want.Addresses.Copy(), got.Addresses.Copy())
+	for _, tx := range got.Txs {
+		assert.NotEqual(t, parser.UnknownStr, tx.TxType, tx.Id)
+	}
+}
+
+// TestParser_ParseTipset_RecordedFixture replays the rpc calls committed in the rpc fixture of the height.
+// See data/README.md for how the fixture was recorded.
+func TestParser_ParseTipset_RecordedFixture(t *testing.T) {
+	const height = "1419335"
+	raw, err := readGzFile(getFilename("rpc", height))
+	require.NoError(t, err)
+	fixture, err := recorder.LoadFixture(bytes.NewReader(raw))
+	require.NoError(t, err)
+	require.NoError(t, fixture.EncodingErrors())
+
+	want, err := newNodeParser(t, newSnapshotNode(t, height)).ParseTipset(context.Background(), readHeightData(t, height))
+	require.NoError(t, err)
+	got, err := newNodeParser(t, recorder.NewReplayer(fixture)).ParseTipset(context.Background(), readHeightData(t, height))
+	require.NoError(t, err)
+
+	require.NotNil(t, got.Txs)
+	assert.Equal(t, 37, len(got.Txs.Txs))
+	assert.Equal(t, want.Txs.Txs, got.Txs.Txs)
+	assert.Equal(t, want.Txs.Addresses.Copy(), got.Txs.Addresses.Copy())
+	assert.Equal(t, want.MultisigEvents, got.MultisigEvents)
+	assert.Equal(t, want.MinerEvents, got.MinerEvents)
+	assert.Equal(t, want.BlocksTimestamp, got.BlocksTimestamp)
+}
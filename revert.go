@@ -0,0 +1,216 @@
+package fil_parser
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/zondax/fil-parser/types"
+)
+
+// RevertTipset produces a tombstone for every row the tipset produced, so a downstream store can drop them when the tipset is reorged out.
+// Row ids are deterministic, so the tipset is parsed again to rebuild them. The address cache entries of the actors the tipset created are purged
+// afterwards, as those actors may no longer exist on the canonical chain.
+func (p *FilecoinParser) RevertTipset(ctx context.Context, data *types.HeightData) (*types.TipsetTombstones, error) {
+	if data == nil || data.Tipset == nil {
+		return nil, fmt.Errorf("tipset is nil")
+	}
+
+	// a reverted tipset is never canonical, keep whatever is learned while parsing it again out of the canonical cache
+	revertData := *data
+	revertData.Canonical = false
+
+	bundle, err := p.ParseTipset(ctx, &revertData)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse reverted tipset: %w", err)
+	}
+
+	result := &types.TipsetTombstones{
+		Height:     data.Height,
+		TipsetCid:  data.Tipset.GetCidString(),
+		Tombstones: buildTombstones(bundle),
+	}
+	result.PurgedAddresses = p.purgeAddresses(bundle)
+
+	return result, nil
+}
+
+// purgeAddresses removes from the non-canonical cache the addresses the bundle introduced, that is, those of the actors
+// created by one of its transactions. Addresses the bundle only used were known before the tipset and are kept.
+func (p *FilecoinParser) purgeAddresses(bundle *types.TipsetBundle) []*types.AddressInfo {
+	if bundle.Txs == nil || bundle.Txs.Addresses == nil {
+		return []*types.AddressInfo{}
+	}
+
+	txCids := make(map[string]bool, len(bundle.Txs.Txs))
+	for _, tx := range bundle.Txs.Txs {
+		txCids[tx.TxCid] = true
+	}
+	purged := make(map[string]*types.AddressInfo)
+	bundle.Txs.Addresses.Range(func(key string, info *types.AddressInfo) bool {
+		if info.CreationTxCid != "" && txCids[info.CreationTxCid] {
+			purged[key] = info
+		}
+		return true
+	})
+
+	keys := make([]string, 0, len(purged))
+	for key := range purged {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]*types.AddressInfo, 0, len(keys))
+	for _, key := range keys {
+		info := *purged[key]
+		info.IsCanonical = false
+		p.Helper.GetActorsCache().RemoveAddressInfo(info)
+		result = append(result, purged[key])
+	}
+	return result
+}
+
+func buildTombstones(bundle *types.TipsetBundle) []types.Tombstone {
+	var tombstones []types.Tombstone
+	add := func(dataset types.Dataset, kind, id string) {
+		tombstones = append(tombstones, types.Tombstone{ID: id, Kind: kind, Dataset: dataset})
+	}
+
+	if bundle.Txs != nil {
+		for _, tx := range bundle.Txs.Txs {
+			add(types.DatasetTransactions, types.RowKindTransaction, tx.Id)
+		}
+	}
+	if bundle.NativeEvents != nil {
+		for _, event := range bundle.NativeEvents.ParsedEvents {
+			add(types.DatasetNativeEvents, types.RowKindNativeEvent, event.ID)
+		}
+	}
+	if bundle.EthLogs != nil {
+		for _, event := range bundle.EthLogs.ParsedEvents {
+			add(types.DatasetEthLogs, types.RowKindEthLog, event.ID)
+		}
+	}
+	if bundle.BlocksTimestamp != nil {
+		add(types.DatasetBlocksInfo, types.RowKindBlocksTimestamp, bundle.BlocksTimestamp.Id)
+	}
+	if events := bundle.MultisigEvents; events != nil {
+		for _, info := range events.MultisigInfo {
+			add(types.DatasetMultisig, types.RowKindMultisigInfo, info.ID)
+		}
+		for _, proposal := range events.Proposals {
+			add(types.DatasetMultisig, types.RowKindMultisigProposal, proposal.ID)
+		}
+	}
+	if events := bundle.MinerEvents; events != nil {
+		for _, info := range events.MinerInfo {
+			add(types.DatasetMiner, types.RowKindMinerInfo, info.ID)
+		}
+		for _, sector := range events.MinerSectors {
+			add(types.DatasetMiner, types.RowKindMinerSector, sector.ID)
+		}
+		for _, proving := range events.MinerProving {
+			add(types.DatasetMiner, types.RowKindMinerProving, proving.ID)
+		}
+		for _, fault := range events.ConsensusFaults {
+			add(types.DatasetMiner, types.RowKindMinerConsensusFault, fault.ID)
+		}
+		for _, piece := range events.DDOPieces {
+			add(types.DatasetMiner, types.RowKindMinerDDOPiece, piece.ID)
+		}
+	}
+	if events := bundle.DealsEvents; events != nil {
+		for _, message := range events.DealsMessages {
+			add(types.DatasetDeals, types.RowKindDealsMessage, message.ID)
+		}
+		for _, proposal := range events.DealsProposals {
+			add(types.DatasetDeals, types.RowKindDealsProposal, proposal.ID)
+		}
+		for _, activation := range events.DealsActivations {
+			add(types.DatasetDeals, types.RowKindDealsActivation, activation.ID)
+		}
+		for _, spaceInfo := range events.DealsSpaceInfo {
+			add(types.DatasetDeals, types.RowKindDealsSpaceInfo, spaceInfo.ID)
+		}
+		for _, termination := range events.DealsTerminations {
+			add(types.DatasetDeals, types.RowKindDealsTermination, termination.ID)
+		}
+		for _, settlement := range events.DealsSettlements {
+			add(types.DatasetDeals, types.RowKindDealsSettlement, settlement.ID)
+		}
+	}
+	if events := bundle.PaymentChannelEvents; events != nil {
+		for _, channel := range events.Channels {
+			add(types.DatasetPaymentChannel, types.RowKindPaymentChannelCreation, channel.ID)
+		}
+		for _, voucher := range events.Vouchers {
+			add(types.DatasetPaymentChannel, types.RowKindPaymentChannelVoucher, voucher.ID)
+		}
+		for _, settlement := range events.Settlements {
+			add(types.DatasetPaymentChannel, types.RowKindPaymentChannelSettlement, settlement.ID)
+		}
+		for _, payout := range events.Payouts {
+			add(types.DatasetPaymentChannel, types.RowKindPaymentChannelPayout, payout.ID)
+		}
+	}
+	if events := bundle.PowerEvents; events != nil {
+		for _, creation := range events.MinerCreations {
+			add(types.DatasetPower, types.RowKindPowerMinerCreation, creation.ID)
+		}
+		for _, update := range events.ClaimedPower {
+			add(types.DatasetPower, types.RowKindPowerClaimedPower, update.ID)
+		}
+		for _, update := range events.PledgeTotals {
+			add(types.DatasetPower, types.RowKindPowerPledgeTotal, update.ID)
+		}
+		for _, fault := range events.ConsensusFaults {
+			add(types.DatasetPower, types.RowKindPowerConsensusFault, fault.ID)
+		}
+	}
+	if events := bundle.BlockRewardEvents; events != nil {
+		for _, reward := range events.Rewards {
+			add(types.DatasetBlockReward, types.RowKindBlockReward, reward.ID)
+		}
+		for _, reward := range events.EpochRewards {
+			add(types.DatasetBlockReward, types.RowKindEpochReward, reward.ID)
+		}
+	}
+	if events := bundle.ActorCreationEvents; events != nil {
+		for _, creation := range events.Creations {
+			add(types.DatasetActorCreations, types.RowKindActorCreation, creation.ID)
+		}
+	}
+	if events := bundle.DataCapEvents; events != nil {
+		for _, info := range events.DataCapInfo {
+			add(types.DatasetDataCap, types.RowKindDataCapInfo, info.ID)
+		}
+		for _, event := range events.DataCapTokenEvent {
+			add(types.DatasetDataCap, types.RowKindDataCapTokenEvent, event.ID)
+		}
+		for _, event := range events.DataCapAllowanceEvent {
+			add(types.DatasetDataCap, types.RowKindDataCapAllowanceEvent, event.ID)
+		}
+	}
+	if events := bundle.VerifregEvents; events != nil {
+		for _, event := range events.VerifierInfo {
+			add(types.DatasetVerifreg, types.RowKindVerifregVerifier, event.ID)
+		}
+		for _, client := range events.ClientInfo {
+			add(types.DatasetVerifreg, types.RowKindVerifregClient, client.ID)
+		}
+		for _, deal := range events.Deals {
+			add(types.DatasetVerifreg, types.RowKindVerifregDeal, deal.ID)
+		}
+		for _, allocation := range events.Allocations {
+			add(types.DatasetVerifreg, types.RowKindVerifregAllocation, allocation.ID)
+		}
+	}
+
+	sort.SliceStable(tombstones, func(i, j int) bool {
+		if tombstones[i].Kind != tombstones[j].Kind {
+			return tombstones[i].Kind < tombstones[j].Kind
+		}
+		return tombstones[i].ID < tombstones[j].ID
+	})
+	return tombstones
+}
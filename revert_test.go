@@ -0,0 +1,143 @@
+package fil_parser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/types"
+)
+
+func TestBuildTombstones(t *testing.T) {
+	bundle := &types.TipsetBundle{
+		Txs: &types.TxsParsedResult{
+			Txs: []*types.Transaction{{Id: "tx-b"}, {Id: "tx-a"}},
+		},
+		BlocksTimestamp: &types.BlocksTimestamp{Id: "block"},
+		MinerEvents: &types.MinerEvents{
+			MinerSectors:    []*types.MinerSectorEvent{{ID: "sector"}},
+			MinerProving:    []*types.MinerProvingEvent{{ID: "proving"}},
+			ConsensusFaults: []*types.MinerConsensusFault{{ID: "fault"}},
+		},
+		DealsEvents: &types.DealsEvents{
+			DealsProposals:    []*types.DealsProposals{{ID: "proposal"}},
+			DealsTerminations: []*types.DealsTerminations{{ID: "termination"}},
+		},
+		PaymentChannelEvents: &types.PaymentChannelEvents{
+			Vouchers: []*types.PaymentChannelVoucher{{ID: "voucher"}},
+		},
+		PowerEvents: &types.PowerEvents{
+			ClaimedPower: []*types.PowerClaimedPowerUpdate{{ID: "claimed"}},
+		},
+		BlockRewardEvents: &types.BlockRewardEvents{
+			Rewards: []*types.BlockReward{{ID: "reward"}},
+		},
+		ActorCreationEvents: &types.ActorCreationEvents{
+			Creations: []*types.ActorCreation{{ID: "creation"}},
+		},
+		VerifregEvents: &types.VerifregEvents{
+			Allocations: []*types.VerifregAllocationEvent{{ID: "allocation"}},
+		},
+	}
+
+	want := []types.Tombstone{
+		{ID: "creation", Kind: types.RowKindActorCreation, Dataset: types.DatasetActorCreations},
+		{ID: "reward", Kind: types.RowKindBlockReward, Dataset: types.DatasetBlockReward},
+		{ID: "block", Kind: types.RowKindBlocksTimestamp, Dataset: types.DatasetBlocksInfo},
+		{ID: "proposal", Kind: types.RowKindDealsProposal, Dataset: types.DatasetDeals},
+		{ID: "termination", Kind: types.RowKindDealsTermination, Dataset: types.DatasetDeals},
+		{ID: "fault", Kind: types.RowKindMinerConsensusFault, Dataset: types.DatasetMiner},
+		{ID: "proving", Kind: types.RowKindMinerProving, Dataset: types.DatasetMiner},
+		{ID: "sector", Kind: types.RowKindMinerSector, Dataset: types.DatasetMiner},
+		{ID: "voucher", Kind: types.RowKindPaymentChannelVoucher, Dataset: types.DatasetPaymentChannel},
+		{ID: "claimed", Kind: types.RowKindPowerClaimedPower, Dataset: types.DatasetPower},
+		{ID: "tx-a", Kind: types.RowKindTransaction, Dataset: types.DatasetTransactions},
+		{ID: "tx-b", Kind: types.RowKindTransaction, Dataset: types.DatasetTransactions},
+		{ID: "allocation", Kind: types.RowKindVerifregAllocation, Dataset: types.DatasetVerifreg},
+	}
+
+	assert.Equal(t, want, buildTombstones(bundle))
+	// reverting the same tipset twice yields the same tombstones
+	assert.Equal(t, buildTombstones(bundle), buildTombstones(bundle))
+}
+
+func TestPurgeAddresses(t *testing.T) {
+	p := newOfflineParser(t, tools.CalibrationNetwork, "1419335")
+	actorsCache := p.Helper.GetActorsCache()
+
+	created := &types.AddressInfo{Short: "f01100", Robust: "f2ylwzg7xflvmqlzyd3aoxgvnpd62gcmi4ysehbiy", ActorCid: "bafk2bzacect2p7urje3pylrrrjy3tngn6yaih4gtzauuatf2jllasuxd3tyxu", CreationTxCid: "created-in-tipset"}
+	known := &types.AddressInfo{Short: "f01200", Robust: "f1gjdwtnnwjx6p5jgr5dudeodzy5xguuhwvl7lmva", ActorCid: "bafk2bzacea6rabflc7kpwr6y4lzcqsnuahr4zblyq3rhzrrsfceeiw2lufrb4", CreationTxCid: "created-before"}
+	used := &types.AddressInfo{Short: "f01300", ActorCid: "bafk2bzacea6rabflc7kpwr6y4lzcqsnuahr4zblyq3rhzrrsfceeiw2lufrb4"}
+	addresses := types.NewAddressInfoMap()
+	for _, info := range []*types.AddressInfo{created, known, used} {
+		actorsCache.StoreAddressInfo(*info)
+		addresses.Set(info.Short, info)
+	}
+
+	bundle := &types.TipsetBundle{
+		Txs: &types.TxsParsedResult{
+			Txs:       []*types.Transaction{{Id: "tx", TxCid: "created-in-tipset"}},
+			Addresses: addresses,
+		},
+	}
+
+	assert.Equal(t, []*types.AddressInfo{created}, p.purgeAddresses(bundle))
+
+	// only the actor created by the tipset is dropped, the addresses it used were known before and are kept
+	for _, info := range []*types.AddressInfo{created, known, used} {
+		addr, err := address.NewFromString(info.Short)
+		require.NoError(t, err)
+		code, err := actorsCache.GetActorCode(addr, filTypes.EmptyTSK, false, false)
+		if info == created {
+			assert.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, info.ActorCid, code)
+	}
+
+	assert.Empty(t, p.purgeAddresses(&types.TipsetBundle{}))
+}
+
+func TestParser_RevertTipset(t *testing.T) {
+	const height = "1419335"
+	p := newOfflineParser(t, tools.CalibrationNetwork, height)
+	data := readHeightData(t, height)
+
+	parsedResult, err := p.ParseTransactions(context.Background(), readTxsData(t, height))
+	require.NoError(t, err)
+
+	reverted, err := p.RevertTipset(context.Background(), data)
+	require.NoError(t, err)
+	assert.Equal(t, data.Height, reverted.Height)
+	assert.Equal(t, data.Tipset.GetCidString(), reverted.TipsetCid)
+
+	txTombstones := make(map[string]bool)
+	for _, tombstone := range reverted.Tombstones {
+		if tombstone.Kind == types.RowKindTransaction {
+			txTombstones[tombstone.ID] = true
+		}
+	}
+	require.Len(t, txTombstones, len(parsedResult.Txs))
+	for _, tx := range parsedResult.Txs {
+		assert.True(t, txTombstones[tx.Id], tx.Id)
+	}
+
+	// the tipset creates no actor, so the addresses it used are still resolved after the revert
+	assert.Empty(t, reverted.PurgedAddresses)
+	parsedResult.Addresses.Range(func(key string, info *types.AddressInfo) bool {
+		addr, err := address.NewFromString(info.Short)
+		require.NoError(t, err)
+		_, err = p.Helper.GetActorsCache().GetActorCode(addr, filTypes.EmptyTSK, false, false)
+		assert.NoError(t, err, key)
+		return true
+	})
+
+	again, err := p.RevertTipset(context.Background(), data)
+	require.NoError(t, err)
+	assert.Equal(t, reverted.Tombstones, again.Tombstones)
+}
@@ -0,0 +1,62 @@
+package fil_parser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/types"
+)
+
+// TestParser_ParseTransactionsStream checks that streaming a height yields the same output as parsing it at once.
+func TestParser_ParseTransactionsStream(t *testing.T) {
+	const height = "1419335"
+	txsData := readTxsData(t, height)
+
+	want, err := newOfflineParser(t, tools.CalibrationNetwork, height).ParseTransactions(context.Background(), txsData)
+	require.NoError(t, err)
+
+	var (
+		txs    []*types.Transaction
+		txCids []types.TxCidTranslation
+		chunks int
+	)
+	addresses := make(map[string]*types.AddressInfo)
+	ids := make(map[string]bool)
+	err = newOfflineParser(t, tools.CalibrationNetwork, height).ParseTransactionsStream(context.Background(), txsData, func(chunk *types.TxsParsedChunk) error {
+		chunks++
+		for _, tx := range chunk.Txs {
+			assert.False(t, ids[tx.Id], "tx %s emitted twice", tx.Id)
+			ids[tx.Id] = true
+		}
+		txs = append(txs, chunk.Txs...)
+		txCids = append(txCids, chunk.TxCids...)
+		chunk.Addresses.Range(func(key string, info *types.AddressInfo) bool {
+			addresses[key] = info
+			return true
+		})
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Greater(t, chunks, 1)
+	assert.Equal(t, want.Txs, txs)
+	assert.Equal(t, want.TxCids, txCids)
+	assert.Equal(t, want.Addresses.Copy(), addresses)
+}
+
+func TestParser_ParseTransactionsStream_Stopped(t *testing.T) {
+	const height = "1419335"
+	p := newOfflineParser(t, tools.CalibrationNetwork, height)
+
+	chunks := 0
+	err := p.ParseTransactionsStream(context.Background(), readTxsData(t, height), func(_ *types.TxsParsedChunk) error {
+		chunks++
+		return parser.ErrStreamStopped
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, chunks)
+}
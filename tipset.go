@@ -0,0 +1,157 @@
+package fil_parser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/manifest"
+	types2 "github.com/filecoin-project/lotus/chain/types"
+	helper2 "github.com/zondax/fil-parser/parser/helper"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+// ParseTipset parses the requested datasets of a single tipset in one pass. When no dataset is requested, all of them are parsed.
+// Transactions are parsed once and shared by every event generator, and actor names are resolved at most once per address,
+// no matter how many datasets need them. Parsing stops at the first error.
+func (p *FilecoinParser) ParseTipset(ctx context.Context, data *types.HeightData, datasets ...types.Dataset) (*types.TipsetBundle, error) {
+	if data == nil || data.Tipset == nil {
+		return nil, fmt.Errorf("tipset is nil")
+	}
+	if len(datasets) == 0 {
+		datasets = types.AllDatasets()
+	}
+	requested := make(map[types.Dataset]bool, len(datasets))
+	for _, dataset := range datasets {
+		requested[dataset] = true
+	}
+
+	ctx = common.WithActorNames(ctx)
+	bundle := &types.TipsetBundle{Height: data.Height}
+
+	needsTxs := requested[types.DatasetTransactions] || requested[types.DatasetMultisig] || requested[types.DatasetMiner] ||
+		requested[types.DatasetVerifreg] || requested[types.DatasetDataCap] || requested[types.DatasetDeals] ||
+		requested[types.DatasetPaymentChannel] || requested[types.DatasetPower] || requested[types.DatasetBlockReward] ||
+		requested[types.DatasetActorCreations]
+	if needsTxs {
+		txs, err := p.ParseTransactions(ctx, types.TxsData{
+			Traces:    data.Traces,
+			Tipset:    data.Tipset,
+			EthLogs:   data.EthLogs,
+			Metadata:  data.Metadata,
+			Canonical: data.Canonical,
+		})
+		if err != nil {
+			return bundle, fmt.Errorf("could not parse transactions: %w", err)
+		}
+		if requested[types.DatasetTransactions] {
+			bundle.Txs = txs
+		}
+		events, err := p.resolveEventsParser(data.Metadata)
+		if err != nil {
+			return bundle, err
+		}
+		if err := p.parseTipsetEvents(ctx, bundle, requested, events.Parser, txs.Txs, data.Tipset); err != nil {
+			return bundle, err
+		}
+	}
+
+	eventsData := types.EventsData{
+		Tipset:    data.Tipset,
+		NativeLog: data.NativeLog,
+		EthLogs:   data.EthLogs,
+		Metadata:  data.Metadata,
+		Canonical: data.Canonical,
+	}
+	if requested[types.DatasetNativeEvents] {
+		nativeEvents, err := p.ParseNativeEvents(ctx, eventsData)
+		if err != nil {
+			return bundle, fmt.Errorf("could not parse native events: %w", err)
+		}
+		bundle.NativeEvents = nativeEvents
+	}
+	if requested[types.DatasetEthLogs] {
+		ethLogs, err := p.ParseEthLogs(ctx, eventsData)
+		if err != nil {
+			return bundle, fmt.Errorf("could not parse eth logs: %w", err)
+		}
+		bundle.EthLogs = ethLogs
+	}
+
+	if requested[types.DatasetBlocksInfo] {
+		blocksTimestamp, blockAddresses, err := p.ParseBlocksInfo(ctx, data.Height, data.Traces, data.Metadata, data.Tipset, data.Canonical)
+		if err != nil {
+			return bundle, fmt.Errorf("could not parse blocks info: %w", err)
+		}
+		bundle.BlocksTimestamp = blocksTimestamp
+		bundle.BlockAddresses = blockAddresses
+	}
+
+	return bundle, nil
+}
+
+func (p *FilecoinParser) parseTipsetEvents(ctx context.Context, bundle *types.TipsetBundle, requested map[types.Dataset]bool, events Parser, txs []*types.Transaction, tipset *types.ExtendedTipSet) error {
+	var err error
+	tipsetCid := tipset.GetCidString()
+	tipsetKey := tipset.Key()
+
+	if requested[types.DatasetMultisig] {
+		var multisigTxs []*types.Transaction
+		if multisigTxs, err = p.Helper.FilterTxsByActorTypeWith(ctx, txs, manifest.MultisigKey, tipsetKey, true, p.memoizedActorName(ctx)); err != nil {
+			return fmt.Errorf("could not filter multisig txs: %w", err)
+		}
+		if bundle.MultisigEvents, err = events.ParseMultisigEvents(ctx, multisigTxs, tipsetCid, tipsetKey); err != nil {
+			return fmt.Errorf("could not parse multisig events: %w", err)
+		}
+	}
+	if requested[types.DatasetMiner] {
+		if bundle.MinerEvents, err = events.ParseMinerEvents(ctx, txs, tipsetCid, tipsetKey); err != nil {
+			return fmt.Errorf("could not parse miner events: %w", err)
+		}
+	}
+	if requested[types.DatasetVerifreg] {
+		if bundle.VerifregEvents, err = events.ParseVerifregEvents(ctx, txs, tipsetCid, tipsetKey); err != nil {
+			return fmt.Errorf("could not parse verifreg events: %w", err)
+		}
+	}
+	if requested[types.DatasetDataCap] {
+		if bundle.DataCapEvents, err = events.ParseDataCapEvents(ctx, txs, tipsetCid, tipsetKey); err != nil {
+			return fmt.Errorf("could not parse datacap events: %w", err)
+		}
+	}
+	if requested[types.DatasetDeals] {
+		if bundle.DealsEvents, err = events.ParseDealsEvents(ctx, txs, tipsetCid, tipsetKey); err != nil {
+			return fmt.Errorf("could not parse deals events: %w", err)
+		}
+	}
+	if requested[types.DatasetPaymentChannel] {
+		if bundle.PaymentChannelEvents, err = events.ParsePaymentChannelEvents(ctx, txs, tipsetCid, tipsetKey); err != nil {
+			return fmt.Errorf("could not parse payment channel events: %w", err)
+		}
+	}
+	if requested[types.DatasetPower] {
+		if bundle.PowerEvents, err = events.ParsePowerEvents(ctx, txs, tipsetCid, tipsetKey); err != nil {
+			return fmt.Errorf("could not parse power events: %w", err)
+		}
+	}
+	if requested[types.DatasetBlockReward] {
+		if bundle.BlockRewardEvents, err = events.ParseBlockRewardEvents(ctx, txs, tipset); err != nil {
+			return fmt.Errorf("could not parse block reward events: %w", err)
+		}
+	}
+	if requested[types.DatasetActorCreations] {
+		if bundle.ActorCreationEvents, err = events.ParseActorCreations(ctx, txs, tipsetCid, tipsetKey); err != nil {
+			return fmt.Errorf("could not parse actor creations: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// memoizedActorName resolves actor names through the names memoized in ctx.
+func (p *FilecoinParser) memoizedActorName(ctx context.Context) helper2.ActorNameResolver {
+	return func(addr address.Address, height int64, tipsetKey types2.TipSetKey, canonical bool) (string, error) {
+		return common.ResolveActorName(ctx, p.Helper, addr, height, tipsetKey, canonical)
+	}
+}
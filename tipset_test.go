@@ -0,0 +1,72 @@
+package fil_parser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/types"
+)
+
+func readHeightData(t *testing.T, height string) *types.HeightData {
+	txsData := readTxsData(t, height)
+	return &types.HeightData{
+		Height:    uint64(txsData.Tipset.Height()),
+		Traces:    txsData.Traces,
+		Tipset:    txsData.Tipset,
+		EthLogs:   txsData.EthLogs,
+		Metadata:  txsData.Metadata,
+		Canonical: txsData.Canonical,
+	}
+}
+
+func TestParser_ParseTipset(t *testing.T) {
+	const height = "1419335"
+	data := readHeightData(t, height)
+
+	want, err := newOfflineParser(t, tools.CalibrationNetwork, height).ParseTransactions(context.Background(), readTxsData(t, height))
+	require.NoError(t, err)
+
+	bundle, err := newOfflineParser(t, tools.CalibrationNetwork, height).ParseTipset(context.Background(), data)
+	require.NoError(t, err)
+	assert.Equal(t, data.Height, bundle.Height)
+
+	// every dataset is parsed when none is requested, sharing the same transactions
+	require.NotNil(t, bundle.Txs)
+	assert.Equal(t, want.Txs, bundle.Txs.Txs)
+	assert.Equal(t, want.TxCids, bundle.Txs.TxCids)
+	assert.NotNil(t, bundle.NativeEvents)
+	assert.NotNil(t, bundle.EthLogs)
+	assert.NotNil(t, bundle.BlocksTimestamp)
+	assert.NotNil(t, bundle.MultisigEvents)
+	assert.NotNil(t, bundle.MinerEvents)
+	assert.NotNil(t, bundle.VerifregEvents)
+	assert.NotNil(t, bundle.DataCapEvents)
+	assert.NotNil(t, bundle.DealsEvents)
+	assert.NotNil(t, bundle.PaymentChannelEvents)
+	assert.NotNil(t, bundle.PowerEvents)
+	assert.NotNil(t, bundle.BlockRewardEvents)
+	assert.NotNil(t, bundle.ActorCreationEvents)
+}
+
+func TestParser_ParseTipset_Datasets(t *testing.T) {
+	const height = "1419335"
+	p := newOfflineParser(t, tools.CalibrationNetwork, height)
+
+	bundle, err := p.ParseTipset(context.Background(), readHeightData(t, height), types.DatasetMultisig, types.DatasetBlocksInfo)
+	require.NoError(t, err)
+
+	// the transactions are parsed for the multisig events but only returned when requested
+	assert.Nil(t, bundle.Txs)
+	assert.NotNil(t, bundle.MultisigEvents)
+	assert.NotNil(t, bundle.BlocksTimestamp)
+	assert.Nil(t, bundle.NativeEvents)
+	assert.Nil(t, bundle.EthLogs)
+	assert.Nil(t, bundle.MinerEvents)
+	assert.Nil(t, bundle.ActorCreationEvents)
+
+	_, err = p.ParseTipset(context.Background(), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,335 @@
+package actorcreation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/zondax/golem/pkg/logger"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/manifest"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/types/ethtypes"
+	"github.com/ipfs/go-cid"
+	"github.com/zondax/fil-parser/metrics"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/parser/helper"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+const (
+	KeyParams            = "Params"
+	KeyReturn            = "Return"
+	KeyCodeCid           = "CodeCid"
+	KeyConstructorParams = "constructorParams"
+	KeySubAddress        = "subAddress"
+	KeyShort             = "short"
+	KeyRobust            = "robust"
+	KeyActorType         = "actor_type"
+	KeyActorId           = "ActorId"
+	KeyRobustAddress     = "RobustAddress"
+	KeyEthAddress        = "EthAddress"
+
+	// KeyDecodedConstructorParams holds the constructor params decoded by the init actor parser with the parser of the created actor
+	KeyDecodedConstructorParams = "decodedConstructorParams"
+)
+
+type EventGenerator interface {
+	GenerateActorCreations(ctx context.Context, transactions []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.ActorCreationEvents, error)
+}
+
+var _ EventGenerator = &eventGenerator{}
+
+type eventGenerator struct {
+	helper  *helper.Helper
+	logger  *logger.Logger
+	metrics *actorCreationMetricsClient
+	config  parser.Config
+}
+
+func NewEventGenerator(helper *helper.Helper, logger *logger.Logger, metrics metrics.MetricsClient, config parser.Config) EventGenerator {
+	return &eventGenerator{
+		helper:  helper,
+		logger:  logger,
+		metrics: newClient(metrics, "actorCreation"),
+		config:  config,
+	}
+}
+
+// GenerateActorCreations expects every transaction of the tipset, subcalls included, as most actors are created by subcalls
+// and actors created through the EAM are joined with the Exec4 subcall made by the EAM.
+func (eg *eventGenerator) GenerateActorCreations(ctx context.Context, transactions []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.ActorCreationEvents, error) {
+	events := &types.ActorCreationEvents{
+		Creations: []*types.ActorCreation{},
+	}
+
+	txsById := make(map[string]*types.Transaction, len(transactions))
+	children := make(map[string][]*types.Transaction)
+	for _, tx := range transactions {
+		txsById[tx.Id] = tx
+		children[tx.ParentId] = append(children[tx.ParentId], tx)
+	}
+
+	for _, tx := range transactions {
+		if !common.IsTxSuccess(tx) {
+			eg.logger.Debug("failed tx found, skipping it")
+			continue
+		}
+		// filter by method first, so actor names are only resolved for candidate messages
+		if !isInitMethod(tx.TxType) && !isEamMethod(tx.TxType) {
+			continue
+		}
+		// the row of an actor created through the EAM is built from the EAM call
+		if parent, ok := txsById[tx.ParentId]; ok && tx.TxType == parser.MethodExec4 && isEamMethod(parent.TxType) {
+			continue
+		}
+
+		addr, err := address.NewFromString(tx.TxTo)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse address. err: %w", err)
+		}
+
+		// #nosec G115
+		actorName, err := common.ResolveActorName(ctx, eg.helper, addr, int64(tx.Height), tipsetKey, true)
+		if err != nil {
+			_ = eg.metrics.UpdateActorNameFromAddressMetric()
+			return nil, err
+		}
+
+		var creation *types.ActorCreation
+		switch {
+		case isInitMethod(tx.TxType) && strings.Contains(actorName, manifest.InitKey):
+			creation, err = eg.createFromExec(tx, tipsetCid)
+		case isEamMethod(tx.TxType) && strings.Contains(actorName, manifest.EamKey):
+			creation, err = eg.createFromEam(tx, tipsetCid, children[tx.Id])
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not create actor creation. err: %w", err)
+		}
+		events.Creations = append(events.Creations, creation)
+	}
+
+	return events, nil
+}
+
+func isInitMethod(txType string) bool {
+	return txType == parser.MethodExec || txType == parser.MethodExec4
+}
+
+func isEamMethod(txType string) bool {
+	switch txType {
+	case parser.MethodCreate, parser.MethodCreate2, parser.MethodCreateExternal:
+		return true
+	}
+	return false
+}
+
+// createFromExec builds the actor created by Exec or Exec4. The created actor is stored as an AddressInfo in the return.
+func (eg *eventGenerator) createFromExec(tx *types.Transaction, tipsetCid string) (*types.ActorCreation, error) {
+	metadata, err := getMetadata(tx)
+	if err != nil {
+		return nil, err
+	}
+	params, err := common.GetItem[map[string]interface{}](metadata, KeyParams, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing params: %w", err)
+	}
+	ret, err := common.GetItem[map[string]interface{}](metadata, KeyReturn, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing return: %w", err)
+	}
+	idAddress, err := common.GetItem[string](ret, KeyShort, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing id address: %w", err)
+	}
+	robustAddress, err := common.GetItem[string](ret, KeyRobust, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing robust address: %w", err)
+	}
+
+	creation := &types.ActorCreation{
+		ID:            tools.BuildId(tipsetCid, tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType, idAddress),
+		Creator:       eg.consolidateAddress(tx.TxFrom),
+		IDAddress:     idAddress,
+		RobustAddress: robustAddress,
+		Height:        tx.Height,
+		TxCid:         tx.TxCid,
+		TxTimestamp:   tx.TxTimestamp,
+	}
+	if err := eg.setActorCode(creation, tx, params, ret); err != nil {
+		return nil, err
+	}
+
+	subAddress, err := common.GetItem[string](params, KeySubAddress, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing sub address: %w", err)
+	}
+	if strings.HasPrefix(subAddress, parser.EthPrefix) {
+		// delegated addresses are namespaced by the actor that called Exec4
+		creation.EthAddress = subAddress
+		creation.F410Address = eg.delegatedAddress(tx.TxFrom, subAddress)
+	}
+
+	return creation, nil
+}
+
+// createFromEam builds the actor created by an EAM create method, joined with the Exec4 subcall that created it.
+func (eg *eventGenerator) createFromEam(tx *types.Transaction, tipsetCid string, subcalls []*types.Transaction) (*types.ActorCreation, error) {
+	metadata, err := getMetadata(tx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := common.GetItem[map[string]interface{}](metadata, KeyReturn, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing return: %w", err)
+	}
+	actorId, err := common.GetInteger[uint64](ret, KeyActorId, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing actor id: %w", err)
+	}
+	robustAddress, err := common.GetItem[string](ret, KeyRobustAddress, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing robust address: %w", err)
+	}
+	ethAddress, err := common.GetItem[string](ret, KeyEthAddress, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing eth address: %w", err)
+	}
+	idAddress, err := address.NewIDAddress(actorId)
+	if err != nil {
+		return nil, fmt.Errorf("error building id address: %w", err)
+	}
+
+	creation := &types.ActorCreation{
+		ID:            tools.BuildId(tipsetCid, tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType, idAddress.String()),
+		Creator:       eg.consolidateAddress(tx.TxFrom),
+		ActorType:     manifest.EvmKey,
+		IDAddress:     idAddress.String(),
+		RobustAddress: robustAddress,
+		F410Address:   eg.delegatedAddress(tx.TxTo, ethAddress),
+		EthAddress:    ethAddress,
+		Height:        tx.Height,
+		TxCid:         tx.TxCid,
+		TxTimestamp:   tx.TxTimestamp,
+	}
+
+	for _, subcall := range subcalls {
+		if subcall.TxType != parser.MethodExec4 || !common.IsTxSuccess(subcall) {
+			continue
+		}
+		execMetadata, err := getMetadata(subcall)
+		if err != nil {
+			return nil, err
+		}
+		params, err := common.GetItem[map[string]interface{}](execMetadata, KeyParams, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing exec4 params: %w", err)
+		}
+		ret, err := common.GetItem[map[string]interface{}](execMetadata, KeyReturn, true)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing exec4 return: %w", err)
+		}
+		if err := eg.setActorCode(creation, subcall, params, ret); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	return creation, nil
+}
+
+// setActorCode sets the code of the created actor and its constructor params, decoded by the init actor parser.
+// The actor type is read from the Exec return, and resolved from the code cid when the parser could not resolve it.
+func (eg *eventGenerator) setActorCode(creation *types.ActorCreation, tx *types.Transaction, params, ret map[string]interface{}) error {
+	codeCid, err := common.GetItem[string](params, KeyCodeCid, false)
+	if err != nil {
+		return fmt.Errorf("error parsing code cid: %w", err)
+	}
+	rawParams, err := common.GetItem[string](params, KeyConstructorParams, true)
+	if err != nil {
+		return fmt.Errorf("error parsing constructor params: %w", err)
+	}
+	actorType, err := common.GetItem[string](ret, KeyActorType, true)
+	if err != nil {
+		return fmt.Errorf("error parsing actor type: %w", err)
+	}
+
+	if actorType == "" {
+		parsedCid, err := cid.Parse(codeCid)
+		if err != nil {
+			return fmt.Errorf("error parsing code cid %s: %w", codeCid, err)
+		}
+		// #nosec G115
+		actorName, err := eg.helper.GetActorNameFromCid(parsedCid, int64(tx.Height))
+		if err != nil {
+			eg.logger.Errorf("error resolving actor name of code cid %s: %s", codeCid, err)
+		}
+		actorType = tools.ParseActorName(actorName)
+	}
+
+	creation.ActorCid = codeCid
+	creation.ActorType = actorType
+	creation.RawConstructorParams = rawParams
+	creation.ConstructorParams = eg.encodeConstructorParams(tx, params[KeyDecodedConstructorParams])
+	return nil
+}
+
+// encodeConstructorParams returns the decoded constructor params as JSON, empty when the parser could not decode them.
+func (eg *eventGenerator) encodeConstructorParams(tx *types.Transaction, decoded interface{}) string {
+	if decoded == nil {
+		return ""
+	}
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		eg.logger.Errorf("error encoding constructor params of tx %s: %s", tx.TxCid, err)
+		return ""
+	}
+	return string(encoded)
+}
+
+// delegatedAddress returns the f410 address of ethAddress in the namespace of the actor at namespace.
+func (eg *eventGenerator) delegatedAddress(namespace, ethAddress string) string {
+	namespaceAddr, err := address.NewFromString(namespace)
+	if err != nil {
+		eg.logger.Errorf("error parsing namespace address %s: %s", namespace, err)
+		return ""
+	}
+	namespaceId, err := address.IDFromAddress(namespaceAddr)
+	if err != nil {
+		eg.logger.Errorf("error getting id of namespace address %s: %s", namespace, err)
+		return ""
+	}
+	ethAddr, err := ethtypes.ParseEthAddress(ethAddress)
+	if err != nil {
+		eg.logger.Errorf("error parsing eth address %s: %s", ethAddress, err)
+		return ""
+	}
+	delegated, err := address.NewDelegatedAddress(namespaceId, ethAddr[:])
+	if err != nil {
+		eg.logger.Errorf("error building delegated address of %s: %s", ethAddress, err)
+		return ""
+	}
+	return delegated.String()
+}
+
+func (eg *eventGenerator) consolidateAddress(addr string) string {
+	consolidated, err := common.ConsolidateAddress(addr, eg.helper, eg.logger, eg.config, true)
+	if err != nil {
+		eg.logger.Errorf("error consolidating address %s: %s", addr, err)
+	}
+	return consolidated
+}
+
+func getMetadata(tx *types.Transaction) (map[string]interface{}, error) {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(tx.TxMetadata), &metadata); err != nil {
+		return nil, fmt.Errorf("error unmarshalling tx metadata: %w", err)
+	}
+	return metadata, nil
+}
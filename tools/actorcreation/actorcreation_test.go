@@ -0,0 +1,182 @@
+package actorcreation_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	multisig16 "github.com/filecoin-project/go-state-types/builtin/v16/multisig"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/manifest"
+	filApiTypes "github.com/filecoin-project/lotus/api/types"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/chain/types/ethtypes"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	filMetrics "github.com/zondax/fil-parser/metrics"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/parser/helper"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/actorcreation"
+	"github.com/zondax/fil-parser/tools/mocks"
+	"github.com/zondax/fil-parser/types"
+	"github.com/zondax/golem/pkg/logger"
+	metrics2 "github.com/zondax/golem/pkg/metrics"
+	rosettaFilecoinLib "github.com/zondax/rosetta-filecoin-lib"
+)
+
+const (
+	tipsetCid   = "bafy2bzaceczpzd5k7u6hwaim7fdpwx2ujg7uhrdbpijf7q5ryvh7ogmawxupk"
+	txCid       = "bafy2bzacebbpdegvr3i4cosewthysg5xkxpqfn2wfcz6mv2hmoktwbdxkax4s"
+	initCidStr  = "bafk2bzacea6rabflc7kpwr6y4lzcqsnuahr4zblyq3rhzrrsfceeiw2lufrb4"
+	eamCidStr   = "bafk2bzaceapkgfggvxyllnmuogtwasmsv5qi2qzhc2aybockd6kag2g5lzaio"
+	msigCidStr  = "bafk2bzacect2p7urje3pylrrrjy3tngn6yaih4gtzauuatf2jllasuxd3tyxu"
+	evmCidStr   = "bafk2bzacebrr2tyuzydn2j3ls3izgqtfe7aiwyzrj2wm7ppvutm32nvvcs6ek"
+	initActor   = "f01"
+	eamActor    = "f010"
+	creator     = "f01100"
+	robustActor = "f2ddsjma6hfwcqhdp4vv6z4t5fighlhrjrqyxcekq"
+	ethAddress  = "0xd4c5fb16488aa48081296299d54b0c648c9333da"
+)
+
+func setupTest(t *testing.T) actorcreation.EventGenerator {
+	codeCids := map[string]cid.Cid{}
+	for name, str := range map[string]string{
+		manifest.InitKey:     initCidStr,
+		manifest.EamKey:      eamCidStr,
+		manifest.MultisigKey: msigCidStr,
+		manifest.EvmKey:      evmCidStr,
+	} {
+		c, err := cid.Parse(str)
+		require.NoError(t, err)
+		codeCids[name] = c
+	}
+
+	logger := logger.NewDevelopmentLogger()
+	metrics := filMetrics.NewMetricsClient(metrics2.NewNoopMetrics())
+
+	node := &mocks.FullNode{}
+	node.On("StateNetworkName", mock.Anything).Return(dtypes.NetworkName(tools.MainnetNetwork), nil)
+	node.On("StateNetworkVersion", mock.Anything, mock.Anything).Return(filApiTypes.NetworkVersion(16), nil)
+	node.On("StateActorCodeCIDs", mock.Anything, mock.Anything).Return(codeCids, nil)
+
+	initAddr, err := address.NewFromString(initActor)
+	require.NoError(t, err)
+	eamAddr, err := address.NewFromString(eamActor)
+	require.NoError(t, err)
+
+	cache := &mocks.IActorsCache{}
+	cache.On("StoreAddressInfo", mock.Anything).Return(nil)
+	cache.On("GetActorCode", initAddr, mock.Anything, mock.Anything, mock.Anything).Return(initCidStr, nil)
+	cache.On("GetActorCode", eamAddr, mock.Anything, mock.Anything, mock.Anything).Return(eamCidStr, nil)
+
+	lib := rosettaFilecoinLib.NewRosettaConstructionFilecoin(node)
+	helper := helper.NewHelper(lib, cache, node, logger, metrics)
+
+	return actorcreation.NewEventGenerator(helper, logger, metrics, parser.Config{})
+}
+
+func newTx(id, parentId, txType, from, to string, metadata interface{}) *types.Transaction {
+	encoded, _ := json.Marshal(metadata)
+	tx := &types.Transaction{
+		Id:            id,
+		ParentId:      parentId,
+		TxCid:         txCid,
+		TxType:        txType,
+		TxFrom:        from,
+		TxTo:          to,
+		TxMetadata:    string(encoded),
+		Amount:        big.NewInt(0),
+		Status:        tools.GetExitCodeStatus(exitcode.Ok),
+		SubcallStatus: tools.GetExitCodeStatus(exitcode.Ok),
+	}
+	// #nosec G115
+	tx.Height = uint64(tools.V24.Height())
+	return tx
+}
+
+func TestGenerateActorCreations_Exec(t *testing.T) {
+	eg := setupTest(t)
+
+	signer, err := address.NewIDAddress(1200)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	constructorParams := &multisig16.ConstructorParams{
+		Signers:               []address.Address{signer},
+		NumApprovalsThreshold: 1,
+		UnlockDuration:        abi.ChainEpoch(100),
+	}
+	require.NoError(t, constructorParams.MarshalCBOR(&buf))
+	rawParams := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	// the constructor params are decoded by the init actor parser
+	exec := newTx("exec", "", parser.MethodExec, creator, initActor, map[string]interface{}{
+		parser.ParamsKey: parser.ExecParams{CodeCid: msigCidStr, ConstructorParams: rawParams, DecodedConstructorParams: constructorParams},
+		parser.ReturnKey: &types.AddressInfo{Short: "f01500", Robust: robustActor, ActorCid: msigCidStr, ActorType: manifest.MultisigKey},
+	})
+	failed := newTx("failed", "", parser.MethodExec, creator, initActor, map[string]interface{}{})
+	failed.Status = tools.GetExitCodeStatus(exitcode.ErrForbidden)
+
+	events, err := eg.GenerateActorCreations(context.Background(), []*types.Transaction{exec, failed}, tipsetCid, filTypes.EmptyTSK)
+	require.NoError(t, err)
+	require.Len(t, events.Creations, 1)
+
+	created := events.Creations[0]
+	assert.Equal(t, creator, created.Creator)
+	assert.Equal(t, msigCidStr, created.ActorCid)
+	assert.Equal(t, manifest.MultisigKey, created.ActorType)
+	assert.Equal(t, "f01500", created.IDAddress)
+	assert.Equal(t, robustActor, created.RobustAddress)
+	assert.Empty(t, created.F410Address)
+	assert.Empty(t, created.EthAddress)
+	assert.Equal(t, rawParams, created.RawConstructorParams)
+
+	var decoded multisig16.ConstructorParams
+	require.NoError(t, json.Unmarshal([]byte(created.ConstructorParams), &decoded))
+	assert.Equal(t, []address.Address{signer}, decoded.Signers)
+	assert.Equal(t, uint64(1), decoded.NumApprovalsThreshold)
+	assert.Equal(t, abi.ChainEpoch(100), decoded.UnlockDuration)
+}
+
+func TestGenerateActorCreations_Eam(t *testing.T) {
+	eg := setupTest(t)
+
+	robust, err := address.NewFromString(robustActor)
+	require.NoError(t, err)
+	create := newTx("create", "", parser.MethodCreateExternal, creator, eamActor, map[string]interface{}{
+		parser.ParamsKey: "0x6080",
+		parser.ReturnKey: parser.EamCreateReturn{ActorId: 1600, RobustAddress: &robust, EthAddress: ethAddress},
+	})
+	// the Exec4 subcall made by the EAM is part of the same creation
+	exec4 := newTx("exec4", "create", parser.MethodExec4, eamActor, initActor, map[string]interface{}{
+		parser.ParamsKey: parser.Exec4Params{CodeCid: evmCidStr, SubAddress: ethAddress},
+		parser.ReturnKey: &types.AddressInfo{Short: "f01600", Robust: robustActor, ActorCid: evmCidStr, ActorType: manifest.EvmKey},
+	})
+	exec4.Level = 1
+
+	events, err := eg.GenerateActorCreations(context.Background(), []*types.Transaction{create, exec4}, tipsetCid, filTypes.EmptyTSK)
+	require.NoError(t, err)
+	require.Len(t, events.Creations, 1)
+
+	ethAddr, err := ethtypes.ParseEthAddress(ethAddress)
+	require.NoError(t, err)
+	f410, err := ethAddr.ToFilecoinAddress()
+	require.NoError(t, err)
+
+	created := events.Creations[0]
+	assert.Equal(t, creator, created.Creator)
+	assert.Equal(t, evmCidStr, created.ActorCid)
+	assert.Equal(t, manifest.EvmKey, created.ActorType)
+	assert.Equal(t, "f01600", created.IDAddress)
+	assert.Equal(t, robustActor, created.RobustAddress)
+	assert.Equal(t, f410.String(), created.F410Address)
+	assert.Equal(t, ethAddress, created.EthAddress)
+}
@@ -0,0 +1,66 @@
+package actorcreation
+
+import (
+	"github.com/zondax/fil-parser/metrics"
+	metrics2 "github.com/zondax/golem/pkg/metrics"
+	"github.com/zondax/golem/pkg/metrics/collectors"
+)
+
+var (
+	_ metrics.MetricsClient = &actorCreationMetricsClient{}
+	_ metrics2.TaskMetrics  = &actorCreationMetricsClient{}
+)
+
+const parserModule = "parser_module"
+
+type actorCreationMetricsClient struct {
+	metrics.MetricsClient
+	name string
+}
+
+func newClient(metricsClient metrics.MetricsClient, name string) *actorCreationMetricsClient {
+	s := &actorCreationMetricsClient{
+		MetricsClient: metricsClient,
+		name:          name,
+	}
+
+	s.registerModuleMetrics(actorNameFromAddressMetric)
+
+	return s
+}
+
+const (
+	actorNameFromAddress = "fil-parser_actor_creation_actor_name_from_address"
+)
+
+var (
+	actorNameFromAddressMetric = metrics.Metric{
+		Name:    actorNameFromAddress,
+		Help:    "get actor name from address",
+		Labels:  []string{},
+		Handler: &collectors.Gauge{},
+	}
+)
+
+func (c *actorCreationMetricsClient) registerModuleMetrics(metrics ...metrics.Metric) {
+	commonLabels := []string{parserModule}
+	for i := range metrics {
+		metrics[i].Labels = append(metrics[i].Labels, commonLabels...)
+	}
+
+	c.RegisterCustomMetrics(metrics...)
+}
+
+func (c *actorCreationMetricsClient) IncrementMetric(name string, labels ...string) error {
+	labels = append(labels, c.name)
+	return c.MetricsClient.IncrementMetric(name, labels...)
+}
+
+func (c *actorCreationMetricsClient) UpdateMetric(name string, value float64, labels ...string) error {
+	labels = append(labels, c.name)
+	return c.MetricsClient.UpdateMetric(name, value, labels...)
+}
+
+func (c *actorCreationMetricsClient) UpdateActorNameFromAddressMetric() error {
+	return c.IncrementMetric(actorNameFromAddress)
+}
@@ -0,0 +1,373 @@
+package blockreward
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/zondax/golem/pkg/logger"
+
+	"github.com/filecoin-project/go-address"
+	stateBig "github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/filecoin-project/lotus/blockstore"
+	"github.com/filecoin-project/lotus/chain/actors/adt"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/reward"
+	miner2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/miner"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"github.com/zondax/fil-parser/metrics"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/parser/helper"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+const (
+	KeyParams                  = "Params"
+	KeyReturn                  = "Return"
+	KeyMiner                   = "Miner"
+	KeyPenalty                 = "Penalty"
+	KeyGasReward               = "GasReward"
+	KeyWinCount                = "WinCount"
+	KeyThisEpochRewardSmoothed = "ThisEpochRewardSmoothed"
+	KeyPositionEstimate        = "PositionEstimate"
+	KeyThisEpochBaselinePower  = "ThisEpochBaselinePower"
+)
+
+// filterEstimatePrecision is the number of fractional bits of the Q.128 fixed point filter estimates.
+const filterEstimatePrecision = 128
+
+type EventGenerator interface {
+	GenerateBlockRewardEvents(ctx context.Context, transactions []*types.Transaction, tipset *types.ExtendedTipSet) (*types.BlockRewardEvents, error)
+}
+
+var _ EventGenerator = &eventGenerator{}
+
+type eventGenerator struct {
+	helper  *helper.Helper
+	logger  *logger.Logger
+	metrics *blockRewardMetricsClient
+	network string
+	config  parser.Config
+}
+
+func NewEventGenerator(helper *helper.Helper, logger *logger.Logger, metrics metrics.MetricsClient, network string, config parser.Config) EventGenerator {
+	return &eventGenerator{
+		helper:  helper,
+		logger:  logger,
+		metrics: newClient(metrics, "blockReward"),
+		network: network,
+		config:  config,
+	}
+}
+
+// GenerateBlockRewardEvents expects every transaction of the tipset, subcalls included, as the reward reaches the miner
+// through a subcall of AwardBlockReward and the reward actor state is only visible in subcalls made by other actors.
+// An EpochReward row is emitted for every tipset, see createEpochReward.
+func (eg *eventGenerator) GenerateBlockRewardEvents(ctx context.Context, transactions []*types.Transaction, tipset *types.ExtendedTipSet) (*types.BlockRewardEvents, error) {
+	events := &types.BlockRewardEvents{
+		Rewards:      []*types.BlockReward{},
+		EpochRewards: []*types.EpochReward{},
+	}
+	tipsetCid := tipset.GetCidString()
+	tipsetKey := tipset.Key()
+
+	children := make(map[string][]*types.Transaction)
+	for _, tx := range transactions {
+		children[tx.ParentId] = append(children[tx.ParentId], tx)
+	}
+
+	var epochReward *types.EpochReward
+	var kpiTx *types.Transaction
+	var realizedPower *big.Int
+	for _, tx := range transactions {
+		if !common.IsTxSuccess(tx) {
+			eg.logger.Debug("failed tx found, skipping it")
+			continue
+		}
+		// filter by method first, so actor names are only resolved for candidate messages
+		if !isRewardMethod(tx.TxType) {
+			continue
+		}
+		// the reward actor state is the same for every call within the epoch
+		if tx.TxType == parser.MethodThisEpochReward && epochReward != nil {
+			continue
+		}
+
+		addr, err := address.NewFromString(tx.TxTo)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse address. err: %w", err)
+		}
+
+		// #nosec G115
+		actorName, err := common.ResolveActorName(ctx, eg.helper, addr, int64(tx.Height), tipsetKey, true)
+		if err != nil {
+			_ = eg.metrics.UpdateActorNameFromAddressMetric()
+			return nil, err
+		}
+
+		if !strings.Contains(actorName, manifest.RewardKey) {
+			continue
+		}
+
+		switch tx.TxType {
+		case parser.MethodAwardBlockReward:
+			reward, err := eg.createBlockReward(tx, tipset, tipsetCid, children[tx.Id])
+			if err != nil {
+				return nil, fmt.Errorf("could not create block reward. err: %w", err)
+			}
+			events.Rewards = append(events.Rewards, reward)
+		case parser.MethodThisEpochReward:
+			if epochReward, err = eg.parseThisEpochReward(tx, tipsetCid); err != nil {
+				return nil, fmt.Errorf("could not create epoch reward. err: %w", err)
+			}
+		case parser.MethodUpdateNetworkKPI:
+			if realizedPower, err = getRealizedPower(tx); err != nil {
+				return nil, fmt.Errorf("could not parse realized power. err: %w", err)
+			}
+			kpiTx = tx
+		}
+	}
+
+	epochReward, err := eg.createEpochReward(ctx, tipset, tipsetCid, epochReward, kpiTx)
+	if err != nil {
+		return nil, fmt.Errorf("could not create epoch reward. err: %w", err)
+	}
+	if epochReward != nil {
+		epochReward.RealizedPower = realizedPower
+		events.EpochRewards = append(events.EpochRewards, epochReward)
+	}
+
+	return events, nil
+}
+
+func isRewardMethod(txType string) bool {
+	switch txType {
+	case parser.MethodAwardBlockReward, parser.MethodThisEpochReward, parser.MethodUpdateNetworkKPI:
+		return true
+	}
+	return false
+}
+
+// createBlockReward joins the AwardBlockReward params with the blocks mined by the miner.
+// The reward received is the value of the ApplyRewards subcall, or AddLockedFund before actors v2.
+func (eg *eventGenerator) createBlockReward(tx *types.Transaction, tipset *types.ExtendedTipSet, tipsetCid string, subcalls []*types.Transaction) (*types.BlockReward, error) {
+	params, err := getParams(tx)
+	if err != nil {
+		return nil, err
+	}
+	miner, err := common.GetItem[string](params, KeyMiner, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing miner: %w", err)
+	}
+	penalty, err := common.GetBigInt(params, KeyPenalty, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing penalty: %w", err)
+	}
+	gasReward, err := common.GetBigInt(params, KeyGasReward, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing gas reward: %w", err)
+	}
+	winCount, err := common.GetInteger[int64](params, KeyWinCount, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing win count: %w", err)
+	}
+
+	var blockCids []string
+	for _, block := range tipset.Blocks() {
+		if block.Miner.String() == miner {
+			blockCids = append(blockCids, block.Cid().String())
+		}
+	}
+
+	reward := big.NewInt(0)
+	for _, subcall := range subcalls {
+		if subcall.TxTo != miner || !common.IsTxSuccess(subcall) {
+			continue
+		}
+		if (subcall.TxType == parser.MethodApplyRewards || subcall.TxType == parser.MethodAddLockedFund) && subcall.Amount != nil {
+			reward = subcall.Amount
+			break
+		}
+	}
+	immediate, vesting := eg.splitReward(reward, tx.Height)
+
+	return &types.BlockReward{
+		ID:              tools.BuildId(tipsetCid, tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType, miner),
+		MinerAddress:    eg.consolidateAddress(miner),
+		BlockCids:       blockCids,
+		WinCount:        winCount,
+		Penalty:         penalty,
+		GasReward:       gasReward,
+		Reward:          reward,
+		ImmediateReward: immediate,
+		VestingReward:   vesting,
+		Height:          tx.Height,
+		TxCid:           tx.TxCid,
+		TxTimestamp:     tx.TxTimestamp,
+	}, nil
+}
+
+// splitReward returns the part of the reward available to the miner right away and the part locked in its vesting table.
+// The whole reward vests before network version 6, and 75% of it from then on.
+func (eg *eventGenerator) splitReward(reward *big.Int, height uint64) (*big.Int, *big.Int) {
+	// #nosec G115
+	nv := tools.VersionFromHeight(eg.network, int64(height)).FilNetworkVersion()
+	locked, _ := miner2.LockedRewardFromReward(stateBig.NewFromGo(reward), nv)
+	return new(big.Int).Sub(reward, locked.Int), locked.Int
+}
+
+// createEpochReward returns the EpochReward row of the tipset, given the one parsed from a ThisEpochReward call, if any,
+// and the cron UpdateNetworkKPI call, which runs at every epoch.
+// Most epochs have no ThisEpochReward call, so the reward actor state is read from the node instead. In offline mode
+// the row then has no baseline power nor reward estimate, and it is not emitted when the tipset has no KPI update either.
+func (eg *eventGenerator) createEpochReward(ctx context.Context, tipset *types.ExtendedTipSet, tipsetCid string, epochReward *types.EpochReward, kpiTx *types.Transaction) (*types.EpochReward, error) {
+	if epochReward != nil {
+		return epochReward, nil
+	}
+	if kpiTx == nil && eg.helper.IsOffline() {
+		return nil, nil
+	}
+
+	// #nosec G115
+	height := uint64(tipset.Height())
+	epochReward = &types.EpochReward{
+		ID:          tools.BuildId(tipsetCid, fmt.Sprint(height)),
+		Height:      height,
+		TxTimestamp: parser.GetTimestamp(tipset.MinTimestamp()),
+	}
+	if kpiTx != nil {
+		epochReward.TxCid = kpiTx.TxCid
+		epochReward.TxTimestamp = kpiTx.TxTimestamp
+	}
+	if eg.helper.IsOffline() {
+		return epochReward, nil
+	}
+
+	var err error
+	if epochReward.RewardEstimate, epochReward.BaselinePower, err = eg.getRewardState(ctx, tipset); err != nil {
+		return nil, err
+	}
+	return epochReward, nil
+}
+
+// getRewardState reads the reward estimate and baseline power from the reward actor state the tipset is executed on,
+// the same state ThisEpochReward returns.
+func (eg *eventGenerator) getRewardState(ctx context.Context, tipset *types.ExtendedTipSet) (*big.Int, *big.Int, error) {
+	node := eg.helper.GetFilecoinNodeClient()
+	store := adt.WrapStore(ctx, cbor.NewCborStore(blockstore.NewAPIBlockstore(node)))
+
+	act, err := node.StateGetActor(ctx, reward.Address, tipset.Key())
+	if err != nil {
+		return nil, nil, fmt.Errorf("api.StateGetActor(): %s", err)
+	}
+	state, err := reward.Load(store, act)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reward.Load(): %s", err)
+	}
+
+	baselinePower, err := state.ThisEpochBaselinePower()
+	if err != nil {
+		return nil, nil, fmt.Errorf("state.ThisEpochBaselinePower(): %s", err)
+	}
+	smoothed, err := state.ThisEpochRewardSmoothed()
+	if err != nil {
+		return nil, nil, fmt.Errorf("state.ThisEpochRewardSmoothed(): %s", err)
+	}
+	rewardEstimate := new(big.Int).Rsh(smoothed.PositionEstimate.Int, filterEstimatePrecision)
+	return rewardEstimate, baselinePower.Int, nil
+}
+
+// parseThisEpochReward reads the reward actor state returned by ThisEpochReward.
+func (eg *eventGenerator) parseThisEpochReward(tx *types.Transaction, tipsetCid string) (*types.EpochReward, error) {
+	ret, err := getReturn(tx)
+	if err != nil {
+		return nil, err
+	}
+	baselinePower, err := common.GetBigInt(ret, KeyThisEpochBaselinePower, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing baseline power: %w", err)
+	}
+	// the smoothed estimate is optional in actors v0
+	smoothed, err := common.GetItem[map[string]interface{}](ret, KeyThisEpochRewardSmoothed, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing smoothed reward: %w", err)
+	}
+	var rewardEstimate *big.Int
+	if smoothed != nil {
+		position, err := common.GetBigInt(smoothed, KeyPositionEstimate, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing position estimate: %w", err)
+		}
+		rewardEstimate = position.Rsh(position, filterEstimatePrecision)
+	}
+
+	return &types.EpochReward{
+		ID:             tools.BuildId(tipsetCid, fmt.Sprint(tx.Height)),
+		RewardEstimate: rewardEstimate,
+		BaselinePower:  baselinePower,
+		Height:         tx.Height,
+		TxCid:          tx.TxCid,
+		TxTimestamp:    tx.TxTimestamp,
+	}, nil
+}
+
+// getRealizedPower reads the network raw byte power sent by the power actor to UpdateNetworkKPI. The params are the bare amount.
+func getRealizedPower(tx *types.Transaction) (*big.Int, error) {
+	metadata, err := getMetadata(tx)
+	if err != nil {
+		return nil, err
+	}
+	encoded, ok := metadata[KeyParams].(string)
+	if !ok {
+		return nil, fmt.Errorf("params not of type string, of type %T", metadata[KeyParams])
+	}
+	realizedPower, ok := new(big.Int).SetString(encoded, 10)
+	if !ok {
+		return nil, fmt.Errorf("failed to convert string %s to big.Int", encoded)
+	}
+	return realizedPower, nil
+}
+
+func (eg *eventGenerator) consolidateAddress(addr string) string {
+	consolidated, err := common.ConsolidateAddress(addr, eg.helper, eg.logger, eg.config, true)
+	if err != nil {
+		eg.logger.Errorf("error consolidating address %s: %s", addr, err)
+	}
+	return consolidated
+}
+
+func getMetadata(tx *types.Transaction) (map[string]interface{}, error) {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(tx.TxMetadata), &metadata); err != nil {
+		return nil, fmt.Errorf("error unmarshalling tx metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+func getParams(tx *types.Transaction) (map[string]interface{}, error) {
+	metadata, err := getMetadata(tx)
+	if err != nil {
+		return nil, err
+	}
+	params, err := common.GetItem[map[string]interface{}](metadata, KeyParams, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing params: %w", err)
+	}
+	return params, nil
+}
+
+func getReturn(tx *types.Transaction) (map[string]interface{}, error) {
+	metadata, err := getMetadata(tx)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := common.GetItem[map[string]interface{}](metadata, KeyReturn, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing return: %w", err)
+	}
+	return ret, nil
+}
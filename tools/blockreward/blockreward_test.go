@@ -0,0 +1,257 @@
+package blockreward_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/manifest"
+	filApiTypes "github.com/filecoin-project/lotus/api/types"
+	"github.com/filecoin-project/lotus/chain/actors/builtin/reward"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+	builtin7 "github.com/filecoin-project/specs-actors/v7/actors/builtin"
+	reward7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/reward"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	filMetrics "github.com/zondax/fil-parser/metrics"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/parser/helper"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/blockreward"
+	"github.com/zondax/fil-parser/tools/mocks"
+	"github.com/zondax/fil-parser/types"
+	"github.com/zondax/golem/pkg/logger"
+	metrics2 "github.com/zondax/golem/pkg/metrics"
+	rosettaFilecoinLib "github.com/zondax/rosetta-filecoin-lib"
+)
+
+const (
+	txCid        = "bafy2bzacebbpdegvr3i4cosewthysg5xkxpqfn2wfcz6mv2hmoktwbdxkax4s"
+	actorCidStr  = "bafk2bzacea6rabflc7kpwr6y4lzcqsnuahr4zblyq3rhzrrsfceeiw2lufrb4"
+	systemActor  = "f00"
+	rewardActor  = "f02"
+	powerActor   = "f04"
+	minerAddress = "f01000"
+)
+
+func setupTest(t *testing.T) blockreward.EventGenerator {
+	eg, _ := setupTestWithNode(t, false)
+	return eg
+}
+
+// setupTestWithNode returns the generator with its node, which is nil when offline.
+func setupTestWithNode(t *testing.T, offline bool) (blockreward.EventGenerator, *mocks.FullNode) {
+	actorCid, err := cid.Parse(actorCidStr)
+	require.NoError(t, err)
+
+	logger := logger.NewDevelopmentLogger()
+	metrics := filMetrics.NewMetricsClient(metrics2.NewNoopMetrics())
+
+	node := &mocks.FullNode{}
+	node.On("StateNetworkName", mock.Anything).Return(dtypes.NetworkName(tools.MainnetNetwork), nil)
+	node.On("StateNetworkVersion", mock.Anything, mock.Anything).Return(filApiTypes.NetworkVersion(16), nil)
+	node.On("StateActorCodeCIDs", mock.Anything, mock.Anything).Return(map[string]cid.Cid{
+		manifest.RewardKey: actorCid,
+	}, nil)
+
+	cache := &mocks.IActorsCache{}
+	cache.On("StoreAddressInfo", mock.Anything).Return(nil)
+	cache.On("GetActorCode", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(actorCidStr, nil)
+	cache.On("GetActorNameFromAddress", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(manifest.RewardKey, nil)
+
+	// the reward actor state the tipset is executed on, read when no actor calls ThisEpochReward
+	var buf bytes.Buffer
+	require.NoError(t, rewardState().MarshalCBOR(&buf))
+	head, err := cid.Parse(txCid)
+	require.NoError(t, err)
+	node.On("StateGetActor", mock.Anything, reward.Address, mock.Anything).Return(&filTypes.Actor{Code: builtin7.RewardActorCodeID, Head: head}, nil)
+	node.On("ChainReadObj", mock.Anything, head).Return(buf.Bytes(), nil)
+
+	lib := rosettaFilecoinLib.NewRosettaConstructionFilecoin(node)
+	var h *helper.Helper
+	if offline {
+		h = helper.NewHelperWithNetwork(lib, cache, nil, tools.MainnetNetwork, logger, metrics)
+		node = nil
+	} else {
+		h = helper.NewHelper(lib, cache, node, logger, metrics)
+	}
+
+	return blockreward.NewEventGenerator(h, logger, metrics, tools.MainnetNetwork, parser.Config{}), node
+}
+
+func rewardState() *reward7.State {
+	return reward7.ConstructState(abi.NewStoragePower(1))
+}
+
+// newTipset builds a tipset with one block per miner.
+func newTipset(t *testing.T, height int64, miners ...string) *types.ExtendedTipSet {
+	dummyCid, err := cid.Parse(txCid)
+	require.NoError(t, err)
+
+	var blocks []*filTypes.BlockHeader
+	for i, miner := range miners {
+		addr, err := address.NewFromString(miner)
+		require.NoError(t, err)
+		blocks = append(blocks, &filTypes.BlockHeader{
+			Miner:                 addr,
+			Ticket:                &filTypes.Ticket{VRFProof: []byte{byte(i)}},
+			ElectionProof:         &filTypes.ElectionProof{WinCount: 1},
+			Height:                abi.ChainEpoch(height),
+			ParentStateRoot:       dummyCid,
+			ParentMessageReceipts: dummyCid,
+			Messages:              dummyCid,
+		})
+	}
+	tipset, err := filTypes.NewTipSet(blocks)
+	require.NoError(t, err)
+	return &types.ExtendedTipSet{TipSet: *tipset}
+}
+
+func newTx(id, parentId, txType, from, to string, height int64, metadata string, amount int64) *types.Transaction {
+	tx := &types.Transaction{
+		Id:            id,
+		ParentId:      parentId,
+		TxCid:         txCid,
+		TxType:        txType,
+		TxFrom:        from,
+		TxTo:          to,
+		TxMetadata:    metadata,
+		Amount:        big.NewInt(amount),
+		Status:        tools.GetExitCodeStatus(exitcode.Ok),
+		SubcallStatus: tools.GetExitCodeStatus(exitcode.Ok),
+	}
+	// #nosec G115
+	tx.Height = uint64(height)
+	return tx
+}
+
+func TestGenerateBlockRewardEvents_Rewards(t *testing.T) {
+	eg := setupTest(t)
+
+	tests := []struct {
+		name          string
+		height        int64
+		subcallMethod string
+		wantImmediate int64
+		wantVesting   int64
+	}{
+		{name: "vesting split", height: tools.V24.Height(), subcallMethod: parser.MethodApplyRewards, wantImmediate: 250, wantVesting: 750},
+		{name: "fully vested before network version 6", height: tools.V4.Height(), subcallMethod: parser.MethodApplyRewards, wantVesting: 1000},
+		{name: "actors v0", height: tools.V1.Height(), subcallMethod: parser.MethodAddLockedFund, wantVesting: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tipset := newTipset(t, tt.height, minerAddress, "f01001")
+			award := newTx("award", "", parser.MethodAwardBlockReward, systemActor, rewardActor, tt.height,
+				`{"Params":{"Miner":"`+minerAddress+`","Penalty":"5","GasReward":"100","WinCount":2}}`, 0)
+			apply := newTx("apply", "award", tt.subcallMethod, rewardActor, minerAddress, tt.height, `{}`, 1000)
+			apply.Level = 1
+
+			events, err := eg.GenerateBlockRewardEvents(context.Background(), []*types.Transaction{award, apply}, tipset)
+			require.NoError(t, err)
+			require.Len(t, events.Rewards, 1)
+
+			reward := events.Rewards[0]
+			assert.Equal(t, minerAddress, reward.MinerAddress)
+			require.Len(t, reward.BlockCids, 1)
+			assert.Equal(t, tipset.Blocks()[0].Cid().String(), reward.BlockCids[0])
+			assert.Equal(t, int64(2), reward.WinCount)
+			assert.Equal(t, big.NewInt(5), reward.Penalty)
+			assert.Equal(t, big.NewInt(100), reward.GasReward)
+			assert.Equal(t, big.NewInt(1000), reward.Reward)
+			assert.Equal(t, big.NewInt(tt.wantImmediate).String(), reward.ImmediateReward.String())
+			assert.Equal(t, big.NewInt(tt.wantVesting).String(), reward.VestingReward.String())
+			// the epoch has no ThisEpochReward call, so its row is read from the reward actor state
+			require.Len(t, events.EpochRewards, 1)
+			assert.Equal(t, rewardState().ThisEpochBaselinePower.Int, events.EpochRewards[0].BaselinePower)
+		})
+	}
+}
+
+func TestGenerateBlockRewardEvents_BurntReward(t *testing.T) {
+	eg := setupTest(t)
+	height := tools.V24.Height()
+
+	award := newTx("award", "", parser.MethodAwardBlockReward, systemActor, rewardActor, height,
+		`{"Params":{"Miner":"`+minerAddress+`","Penalty":"0","GasReward":"100","WinCount":1}}`, 0)
+	apply := newTx("apply", "award", parser.MethodApplyRewards, rewardActor, minerAddress, height, `{}`, 1000)
+	apply.Level = 1
+	apply.SubcallStatus = tools.GetExitCodeStatus(exitcode.ErrForbidden)
+
+	events, err := eg.GenerateBlockRewardEvents(context.Background(), []*types.Transaction{award, apply}, newTipset(t, height, minerAddress))
+	require.NoError(t, err)
+	require.Len(t, events.Rewards, 1)
+	assert.Zero(t, events.Rewards[0].Reward.Sign())
+	assert.Zero(t, events.Rewards[0].ImmediateReward.Sign())
+	assert.Zero(t, events.Rewards[0].VestingReward.Sign())
+}
+
+func TestGenerateBlockRewardEvents_EpochReward(t *testing.T) {
+	eg := setupTest(t)
+	height := tools.V24.Height()
+
+	// the position estimate is a Q.128 fixed point number, 2^128 * 7
+	position := new(big.Int).Lsh(big.NewInt(7), 128)
+	thisEpochReward := newTx("reward", "", parser.MethodThisEpochReward, minerAddress, rewardActor, height,
+		`{"Return":{"ThisEpochRewardSmoothed":{"PositionEstimate":"`+position.String()+`","VelocityEstimate":"0"},"ThisEpochBaselinePower":"4096"}}`, 0)
+	// later calls within the epoch read the same state
+	again := newTx("again", "", parser.MethodThisEpochReward, minerAddress, rewardActor, height,
+		`{"Return":{"ThisEpochRewardSmoothed":{"PositionEstimate":"0","VelocityEstimate":"0"},"ThisEpochBaselinePower":"1"}}`, 0)
+	kpi := newTx("kpi", "", parser.MethodUpdateNetworkKPI, powerActor, rewardActor, height, `{"Params":"2048"}`, 0)
+
+	events, err := eg.GenerateBlockRewardEvents(context.Background(), []*types.Transaction{thisEpochReward, again, kpi}, newTipset(t, height, minerAddress))
+	require.NoError(t, err)
+	require.Len(t, events.EpochRewards, 1)
+
+	epochReward := events.EpochRewards[0]
+	assert.Equal(t, big.NewInt(7), epochReward.RewardEstimate)
+	assert.Equal(t, big.NewInt(4096), epochReward.BaselinePower)
+	assert.Equal(t, big.NewInt(2048), epochReward.RealizedPower)
+	assert.Empty(t, events.Rewards)
+}
+
+func TestGenerateBlockRewardEvents_EpochRewardFromState(t *testing.T) {
+	eg, _ := setupTestWithNode(t, false)
+	height := tools.V15.Height()
+	state := rewardState()
+
+	kpi := newTx("kpi", "", parser.MethodUpdateNetworkKPI, powerActor, rewardActor, height, `{"Params":"2048"}`, 0)
+	events, err := eg.GenerateBlockRewardEvents(context.Background(), []*types.Transaction{kpi}, newTipset(t, height, minerAddress))
+	require.NoError(t, err)
+	require.Len(t, events.EpochRewards, 1)
+
+	epochReward := events.EpochRewards[0]
+	assert.Equal(t, state.ThisEpochBaselinePower.Int, epochReward.BaselinePower)
+	assert.Equal(t, new(big.Int).Rsh(state.ThisEpochRewardSmoothed.PositionEstimate.Int, 128), epochReward.RewardEstimate)
+	assert.Equal(t, big.NewInt(2048), epochReward.RealizedPower)
+	// #nosec G115
+	assert.Equal(t, uint64(height), epochReward.Height)
+	assert.Equal(t, txCid, epochReward.TxCid)
+}
+
+func TestGenerateBlockRewardEvents_EpochRewardOffline(t *testing.T) {
+	eg, _ := setupTestWithNode(t, true)
+	height := tools.V24.Height()
+	tipset := newTipset(t, height, minerAddress)
+
+	kpi := newTx("kpi", "", parser.MethodUpdateNetworkKPI, powerActor, rewardActor, height, `{"Params":"2048"}`, 0)
+	events, err := eg.GenerateBlockRewardEvents(context.Background(), []*types.Transaction{kpi}, tipset)
+	require.NoError(t, err)
+	require.Len(t, events.EpochRewards, 1)
+	assert.Nil(t, events.EpochRewards[0].BaselinePower)
+	assert.Nil(t, events.EpochRewards[0].RewardEstimate)
+	assert.Equal(t, big.NewInt(2048), events.EpochRewards[0].RealizedPower)
+
+	// without the node nor any reward actor call there is nothing to emit
+	events, err = eg.GenerateBlockRewardEvents(context.Background(), nil, tipset)
+	require.NoError(t, err)
+	assert.Empty(t, events.EpochRewards)
+}
@@ -0,0 +1,111 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ErrHeightOutOfOrder is returned by the state trackers when a tipset is not above the last applied one.
+var ErrHeightOutOfOrder = errors.New("events must be applied in increasing height order")
+
+// CheckTrackerHeight returns ErrHeightOutOfOrder unless height comes after last, the height of the last applied tipset.
+// A tracker that never applied a tipset accepts any height.
+func CheckTrackerHeight(last, height uint64) error {
+	if height <= last && last != 0 {
+		return fmt.Errorf("%w: got %d after %d", ErrHeightOutOfOrder, height, last)
+	}
+	return nil
+}
+
+// trackerSnapshot is the layout shared by the snapshots of the state trackers.
+type trackerSnapshot[T any] struct {
+	Version int    `json:"version"`
+	Height  uint64 `json:"height"`
+	State   T      `json:"state"`
+}
+
+// ExportSnapshot writes the state of a tracker at height. version identifies the layout of state, and must be bumped
+// whenever it changes. The state should be sorted, so the same state always yields the same output.
+func ExportSnapshot[T any](w io.Writer, name string, version int, height uint64, state T) error {
+	snapshot := trackerSnapshot[T]{
+		Version: version,
+		Height:  height,
+		State:   state,
+	}
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		return fmt.Errorf("error encoding %s snapshot: %w", name, err)
+	}
+	return nil
+}
+
+// ImportSnapshot reads a snapshot written by ExportSnapshot, returning its state and height.
+// It fails if the snapshot was written with a version other than version.
+func ImportSnapshot[T any](r io.Reader, name string, version int) (T, uint64, error) {
+	var snapshot trackerSnapshot[T]
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return snapshot.State, 0, fmt.Errorf("error decoding %s snapshot: %w", name, err)
+	}
+	if snapshot.Version != version {
+		return snapshot.State, 0, fmt.Errorf("unsupported %s snapshot version %d", name, snapshot.Version)
+	}
+	return snapshot.State, snapshot.Height, nil
+}
+
+// ExpirationIndex indexes the entries of a tracker by the epoch they expire after.
+type ExpirationIndex[K comparable] struct {
+	epochs map[int64]map[K]struct{}
+}
+
+func NewExpirationIndex[K comparable]() *ExpirationIndex[K] {
+	return &ExpirationIndex[K]{epochs: make(map[int64]map[K]struct{})}
+}
+
+// Add indexes key at epoch.
+func (x *ExpirationIndex[K]) Add(epoch int64, key K) {
+	keys, ok := x.epochs[epoch]
+	if !ok {
+		keys = make(map[K]struct{})
+		x.epochs[epoch] = keys
+	}
+	keys[key] = struct{}{}
+}
+
+// Remove drops key from epoch, if indexed there.
+func (x *ExpirationIndex[K]) Remove(epoch int64, key K) {
+	keys, ok := x.epochs[epoch]
+	if !ok {
+		return
+	}
+	delete(keys, key)
+	if len(keys) == 0 {
+		delete(x.epochs, epoch)
+	}
+}
+
+// PopExpired removes and returns the keys indexed at an epoch before height,
+// sorted by epoch and then with less, so expirations are applied in a deterministic order.
+func (x *ExpirationIndex[K]) PopExpired(height uint64, less func(a, b K) bool) []K {
+	var epochs []int64
+	for epoch := range x.epochs {
+		// #nosec G115
+		if epoch < int64(height) {
+			epochs = append(epochs, epoch)
+		}
+	}
+	sort.Slice(epochs, func(i, j int) bool { return epochs[i] < epochs[j] })
+
+	var expired []K
+	for _, epoch := range epochs {
+		keys := make([]K, 0, len(x.epochs[epoch]))
+		for key := range x.epochs[epoch] {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return less(keys[i], keys[j]) })
+		expired = append(expired, keys...)
+		delete(x.epochs, epoch)
+	}
+	return expired
+}
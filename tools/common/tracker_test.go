@@ -0,0 +1,45 @@
+package common_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/tools/common"
+)
+
+func TestCheckTrackerHeight(t *testing.T) {
+	assert.NoError(t, common.CheckTrackerHeight(0, 0))
+	assert.NoError(t, common.CheckTrackerHeight(10, 11))
+	assert.ErrorIs(t, common.CheckTrackerHeight(10, 10), common.ErrHeightOutOfOrder)
+	assert.ErrorIs(t, common.CheckTrackerHeight(10, 9), common.ErrHeightOutOfOrder)
+}
+
+func TestSnapshot(t *testing.T) {
+	var snapshot bytes.Buffer
+	require.NoError(t, common.ExportSnapshot(&snapshot, "test", 2, 100, []string{"a", "b"}))
+	assert.JSONEq(t, `{"version":2,"height":100,"state":["a","b"]}`, snapshot.String())
+
+	state, height, err := common.ImportSnapshot[[]string](bytes.NewReader(snapshot.Bytes()), "test", 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), height)
+	assert.Equal(t, []string{"a", "b"}, state)
+
+	_, _, err = common.ImportSnapshot[[]string](bytes.NewReader(snapshot.Bytes()), "test", 3)
+	assert.Error(t, err)
+}
+
+func TestExpirationIndex(t *testing.T) {
+	index := common.NewExpirationIndex[uint64]()
+	index.Add(20, 3)
+	index.Add(10, 2)
+	index.Add(10, 1)
+	index.Add(30, 4)
+	index.Remove(30, 4)
+
+	less := func(a, b uint64) bool { return a < b }
+	assert.Empty(t, index.PopExpired(10, less), "entries expire after their epoch")
+	assert.Equal(t, []uint64{1, 2, 3}, index.PopExpired(21, less))
+	assert.Empty(t, index.PopExpired(100, less))
+}
@@ -1,11 +1,13 @@
 package common
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-bitfield"
@@ -25,6 +27,12 @@ const (
 	TxStatusOk = "ok"
 )
 
+// Keys of the batch returns of the builtin actors.
+const (
+	KeyFailCodes = "FailCodes"
+	KeyIdx       = "Idx"
+)
+
 func GetActorNameFromAddress(helper *helper.Helper, addr address.Address, height int64, tipsetKey filTypes.TipSetKey, canonical bool) (string, error) {
 	// #nosec G115
 	actorName, err := helper.GetActorNameFromAddress(addr, height, tipsetKey, canonical)
@@ -41,6 +49,51 @@ func GetActorNameFromAddress(helper *helper.Helper, addr address.Address, height
 	return actorName, nil
 }
 
+type actorNamesKey struct{}
+
+// actorNames memoizes actor names per address and height for the lifetime of a context.
+type actorNames struct {
+	mu    sync.Mutex
+	names map[string]string
+}
+
+// WithActorNames returns a context that memoizes the actor names resolved through ResolveActorName.
+// It is meant to be scoped to a single tipset so every address is only resolved once,
+// regardless of how many event generators look it up.
+func WithActorNames(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(actorNamesKey{}).(*actorNames); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, actorNamesKey{}, &actorNames{names: make(map[string]string)})
+}
+
+// ResolveActorName behaves like GetActorNameFromAddress but reuses the names already resolved in ctx, if any.
+func ResolveActorName(ctx context.Context, helper *helper.Helper, addr address.Address, height int64, tipsetKey filTypes.TipSetKey, canonical bool) (string, error) {
+	memo, ok := ctx.Value(actorNamesKey{}).(*actorNames)
+	if !ok {
+		return GetActorNameFromAddress(helper, addr, height, tipsetKey, canonical)
+	}
+
+	key := fmt.Sprintf("%s-%d", addr.String(), height)
+	memo.mu.Lock()
+	actorName, found := memo.names[key]
+	memo.mu.Unlock()
+	if found {
+		return actorName, nil
+	}
+
+	actorName, err := GetActorNameFromAddress(helper, addr, height, tipsetKey, canonical)
+	if err != nil {
+		return "", err
+	}
+
+	memo.mu.Lock()
+	memo.names[key] = actorName
+	memo.mu.Unlock()
+
+	return actorName, nil
+}
+
 func IsTxSuccess(tx *types.Transaction) bool {
 	return strings.EqualFold(tx.Status, TxStatusOk) && strings.EqualFold(tx.SubcallStatus, TxStatusOk)
 }
@@ -147,6 +200,26 @@ func GetSlice[T any](value map[string]interface{}, key string, canBeNil bool) ([
 	return result, fmt.Errorf("key %s not of type %T , of type %T", key, result, value[key])
 }
 
+// FailedBatchIndexes returns the indexes listed in the FailCodes of a batch return.
+func FailedBatchIndexes(batchReturn map[string]interface{}) (map[int]bool, error) {
+	failed := make(map[int]bool)
+	if len(batchReturn) == 0 {
+		return failed, nil
+	}
+	failCodes, err := GetSlice[map[string]interface{}](batchReturn, KeyFailCodes, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing fail codes: %w", err)
+	}
+	for _, failCode := range failCodes {
+		idx, err := GetInteger[int](failCode, KeyIdx, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing fail code index: %w", err)
+		}
+		failed[idx] = true
+	}
+	return failed, nil
+}
+
 // a bit field is a range of bits representing the different ids (numbers).
 // example: ids: [ 0 1 2 3] -> bitfield: [1 1 1 1 ] -> JSON: [0,4]
 // example: ids: [0 1 3 4 5] -> bitfield: [1 1 0 1 1 1] -> JSON: [ 0,2,1,3 ]
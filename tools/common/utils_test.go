@@ -1,13 +1,27 @@
 package common_test
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"testing"
 
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/manifest"
+	filApiTypes "github.com/filecoin-project/lotus/api/types"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+	"github.com/ipfs/go-cid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	filMetrics "github.com/zondax/fil-parser/metrics"
+	"github.com/zondax/fil-parser/parser/helper"
 	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/tools/mocks"
+	"github.com/zondax/golem/pkg/logger"
+	metrics2 "github.com/zondax/golem/pkg/metrics"
+	rosettaFilecoinLib "github.com/zondax/rosetta-filecoin-lib"
 	"golang.org/x/exp/constraints"
 )
 
@@ -71,6 +85,32 @@ func TestGetIntegerSlice(t *testing.T) {
 	}
 }
 
+func TestFailedBatchIndexes(t *testing.T) {
+	tests := []struct {
+		name        string
+		batchReturn map[string]interface{}
+		want        map[int]bool
+	}{
+		{name: "no return", batchReturn: nil, want: map[int]bool{}},
+		{name: "no failures", batchReturn: map[string]interface{}{common.KeyFailCodes: nil}, want: map[int]bool{}},
+		{
+			name: "failures",
+			batchReturn: map[string]interface{}{common.KeyFailCodes: []interface{}{
+				map[string]interface{}{common.KeyIdx: float64(0), "Code": float64(16)},
+				map[string]interface{}{common.KeyIdx: float64(2), "Code": float64(18)},
+			}},
+			want: map[int]bool{0: true, 2: true},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := common.FailedBatchIndexes(test.batchReturn)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
 func TestJsonEncodedBitfieldToSectorNumbers(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -99,3 +139,45 @@ func printInts[T constraints.Integer](ints []T) {
 	}
 	fmt.Println()
 }
+
+func TestResolveActorName(t *testing.T) {
+	actorCidStr := "bafk2bzacea6rabflc7kpwr6y4lzcqsnuahr4zblyq3rhzrrsfceeiw2lufrb4"
+	actorCid, err := cid.Parse(actorCidStr)
+	require.NoError(t, err)
+
+	node := &mocks.FullNode{}
+	node.On("StateNetworkName", mock.Anything).Return(dtypes.NetworkName("calibrationnet"), nil)
+	node.On("StateNetworkVersion", mock.Anything, mock.Anything).Return(filApiTypes.NetworkVersion(16), nil)
+	node.On("StateActorCodeCIDs", mock.Anything, mock.Anything).Return(map[string]cid.Cid{
+		manifest.MinerKey: actorCid,
+	}, nil)
+
+	cache := &mocks.IActorsCache{}
+	cache.On("GetActorCode", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(actorCidStr, nil)
+
+	log := logger.NewDevelopmentLogger()
+	h := helper.NewHelper(rosettaFilecoinLib.NewRosettaConstructionFilecoin(node), cache, node, log, filMetrics.NewMetricsClient(metrics2.NewNoopMetrics()))
+
+	addr, err := address.NewFromString("f01000")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantCalls int
+	}{
+		{name: "without memo", ctx: context.Background(), wantCalls: 3},
+		{name: "with memo", ctx: common.WithActorNames(context.Background()), wantCalls: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache.Calls = nil
+			for i := 0; i < 3; i++ {
+				got, err := common.ResolveActorName(test.ctx, h, addr, 100, filTypes.EmptyTSK, true)
+				require.NoError(t, err)
+				assert.Equal(t, manifest.MinerKey, got)
+			}
+			cache.AssertNumberOfCalls(t, "GetActorCode", test.wantCalls)
+		})
+	}
+}
@@ -60,7 +60,7 @@ func (eg *eventGenerator) GenerateDataCapEvents(ctx context.Context, transaction
 		}
 
 		// #nosec G115
-		actorName, err := common.GetActorNameFromAddress(eg.helper, addr, int64(tx.Height), tipsetKey, true)
+		actorName, err := common.ResolveActorName(ctx, eg.helper, addr, int64(tx.Height), tipsetKey, true)
 		if err != nil {
 			_ = eg.metrics.UpdateActorNameFromAddressMetric()
 			return nil, err
@@ -0,0 +1,235 @@
+package datacap
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+// ledgerSnapshotVersion is bumped whenever the snapshot layout changes.
+const ledgerSnapshotVersion = 2
+
+// Allowance is the datacap an owner allows an operator to spend on its behalf.
+type Allowance struct {
+	OwnerAddress    string   `json:"owner_address"`
+	OperatorAddress string   `json:"operator_address"`
+	Allowance       *big.Int `json:"allowance"`
+}
+
+type allowanceKey struct {
+	owner    string
+	operator string
+}
+
+// ledgerState is the state of the ledger kept in its snapshots.
+type ledgerState struct {
+	Balances   map[string]*big.Int `json:"balances"`
+	Allowances []*Allowance        `json:"allowances"`
+}
+
+// LedgerChanges holds the changes to the ledger caused by the datacap events of a tipset.
+type LedgerChanges struct {
+	Balances   []*types.DataCapBalanceDelta
+	Allowances []*types.DataCapAllowanceDelta
+	// Mismatches lists the balances returned on chain that differ from the computed ones.
+	Mismatches []*types.DataCapBalanceMismatch
+}
+
+// Ledger keeps the datacap balance of every address and the allowances between owners and operators
+// seen in the datacap events. Each Apply call takes a whole tipset, and the ledger is not safe for concurrent use.
+type Ledger struct {
+	height     uint64
+	balances   map[string]*big.Int
+	allowances map[allowanceKey]*big.Int
+}
+
+func NewLedger() *Ledger {
+	return &Ledger{
+		balances:   make(map[string]*big.Int),
+		allowances: make(map[allowanceKey]*big.Int),
+	}
+}
+
+// ImportLedger restores a ledger from a snapshot written by Export.
+func ImportLedger(r io.Reader) (*Ledger, error) {
+	state, height, err := common.ImportSnapshot[ledgerState](r, "datacap ledger", ledgerSnapshotVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := NewLedger()
+	ledger.height = height
+	for addr, balance := range state.Balances {
+		ledger.balances[addr] = balance
+	}
+	for _, allowance := range state.Allowances {
+		ledger.allowances[allowanceKey{owner: allowance.OwnerAddress, operator: allowance.OperatorAddress}] = allowance.Allowance
+	}
+	return ledger, nil
+}
+
+// Export writes a snapshot of the ledger. Balances are keyed by address and allowances sorted by owner and operator.
+func (l *Ledger) Export(w io.Writer) error {
+	state := ledgerState{
+		Balances:   l.balances,
+		Allowances: l.Allowances(),
+	}
+	return common.ExportSnapshot(w, "datacap ledger", ledgerSnapshotVersion, l.height, state)
+}
+
+// Height returns the height of the last applied tipset.
+func (l *Ledger) Height() uint64 {
+	return l.height
+}
+
+// Balance returns the datacap balance of addr, false if the ledger never saw it.
+func (l *Ledger) Balance(addr string) (*big.Int, bool) {
+	balance, ok := l.balances[addr]
+	return balance, ok
+}
+
+// Allowance returns the datacap owner allows operator to spend, zero if none was granted.
+func (l *Ledger) Allowance(owner, operator string) *big.Int {
+	allowance, ok := l.allowances[allowanceKey{owner: owner, operator: operator}]
+	if !ok {
+		return big.NewInt(0)
+	}
+	return allowance
+}
+
+// Allowances returns every allowance granted, sorted by owner and operator.
+func (l *Ledger) Allowances() []*Allowance {
+	allowances := make([]*Allowance, 0, len(l.allowances))
+	for key, allowance := range l.allowances {
+		allowances = append(allowances, &Allowance{OwnerAddress: key.owner, OperatorAddress: key.operator, Allowance: allowance})
+	}
+	sort.Slice(allowances, func(i, j int) bool {
+		if allowances[i].OwnerAddress != allowances[j].OwnerAddress {
+			return allowances[i].OwnerAddress < allowances[j].OwnerAddress
+		}
+		return allowances[i].OperatorAddress < allowances[j].OperatorAddress
+	})
+	return allowances
+}
+
+// Apply updates the ledger with the datacap events of the tipset at height, in the order they were generated.
+// Each token event moves the balance of its address by its amount, and the result is checked against the balance
+// returned on chain. The chain is authoritative: on a mismatch it is reported and the ledger takes the on-chain balance.
+// Addresses seen for the first time take the on-chain balance without a check, so a ledger can start at any height.
+// Allowances take the value returned on chain.
+// Deltas are the net change per address, or per owner and operator, in the tipset, sorted by address.
+// A tipset rejected by the height check leaves the ledger untouched; no other step can fail.
+func (l *Ledger) Apply(height uint64, events *types.DataCapEvents) (*LedgerChanges, error) {
+	if err := common.CheckTrackerHeight(l.height, height); err != nil {
+		return nil, err
+	}
+
+	l.height = height
+	changes := &LedgerChanges{}
+	if events == nil {
+		return changes, nil
+	}
+
+	previousBalances := make(map[string]*big.Int)
+	for _, event := range events.DataCapTokenEvent {
+		previous, known := l.balances[event.ActorAddress]
+		if _, ok := previousBalances[event.ActorAddress]; !ok {
+			previousBalances[event.ActorAddress] = previous
+		}
+
+		balance := event.Balance
+		if known && event.Amount != nil {
+			computed := new(big.Int).Add(previous, event.Amount)
+			if balance == nil {
+				balance = computed
+			}
+			if computed.Cmp(balance) != 0 {
+				changes.Mismatches = append(changes.Mismatches, &types.DataCapBalanceMismatch{
+					ID:         tools.BuildId(event.ID, fmt.Sprint(height), event.ActorAddress),
+					Height:     height,
+					TxCid:      event.TxCid,
+					ActionType: event.ActionType,
+					Address:    event.ActorAddress,
+					Computed:   computed,
+					OnChain:    balance,
+				})
+			}
+		}
+		if balance == nil {
+			// neither the previous nor the returned balance are known
+			continue
+		}
+		l.balances[event.ActorAddress] = balance
+		if !known && event.Amount != nil {
+			// the balance before the first event of an address follows from the amount moved
+			previousBalances[event.ActorAddress] = new(big.Int).Sub(balance, event.Amount)
+		}
+	}
+
+	addresses := make([]string, 0, len(previousBalances))
+	for addr := range previousBalances {
+		addresses = append(addresses, addr)
+	}
+	sort.Strings(addresses)
+	for _, addr := range addresses {
+		balance, ok := l.balances[addr]
+		if !ok {
+			continue
+		}
+		previous := previousBalances[addr]
+		if previous == nil {
+			previous = big.NewInt(0)
+		}
+		changes.Balances = append(changes.Balances, &types.DataCapBalanceDelta{
+			ID:      tools.BuildId(addr, fmt.Sprint(height)),
+			Height:  height,
+			Address: addr,
+			Delta:   new(big.Int).Sub(balance, previous),
+			Balance: balance,
+		})
+	}
+
+	previousAllowances := make(map[allowanceKey]*big.Int)
+	for _, event := range events.DataCapAllowanceEvent {
+		if event.AllowanceBalance == nil {
+			continue
+		}
+		key := allowanceKey{owner: event.OwnerAddress, operator: event.OperatorAddress}
+		if _, ok := previousAllowances[key]; !ok {
+			previousAllowances[key] = l.Allowance(key.owner, key.operator)
+		}
+		if event.AllowanceBalance.Sign() == 0 {
+			delete(l.allowances, key)
+			continue
+		}
+		l.allowances[key] = event.AllowanceBalance
+	}
+
+	keys := make([]allowanceKey, 0, len(previousAllowances))
+	for key := range previousAllowances {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].owner != keys[j].owner {
+			return keys[i].owner < keys[j].owner
+		}
+		return keys[i].operator < keys[j].operator
+	})
+	for _, key := range keys {
+		allowance := l.Allowance(key.owner, key.operator)
+		changes.Allowances = append(changes.Allowances, &types.DataCapAllowanceDelta{
+			ID:              tools.BuildId(key.owner, key.operator, fmt.Sprint(height)),
+			Height:          height,
+			OwnerAddress:    key.owner,
+			OperatorAddress: key.operator,
+			Delta:           new(big.Int).Sub(allowance, previousAllowances[key]),
+			Allowance:       allowance,
+		})
+	}
+	return changes, nil
+}
@@ -0,0 +1,112 @@
+package datacap_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/tools/datacap"
+	"github.com/zondax/fil-parser/types"
+)
+
+func tokenEvent(addr, actionType string, amount, balance int64) *types.DataCapTokenEvent {
+	return &types.DataCapTokenEvent{ID: addr + actionType, ActorAddress: addr, ActionType: actionType, Amount: big.NewInt(amount), Balance: big.NewInt(balance)}
+}
+
+func TestLedger(t *testing.T) {
+	ledger := datacap.NewLedger()
+
+	changes, err := ledger.Apply(100, &types.DataCapEvents{
+		DataCapTokenEvent: []*types.DataCapTokenEvent{
+			// f01000 already held datacap before the ledger started
+			tokenEvent("f01000", parser.MethodMint, 100, 150),
+			tokenEvent("f01000", parser.MethodTransferExported, -30, 120),
+			tokenEvent("f06", parser.MethodTransferExported, 30, 30),
+		},
+		DataCapAllowanceEvent: []*types.DataCapAllowanceEvent{
+			{OwnerAddress: "f01000", OperatorAddress: "f02000", AllowanceBalance: big.NewInt(50)},
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, changes.Mismatches)
+	require.Len(t, changes.Balances, 2)
+	assert.Equal(t, "f01000", changes.Balances[0].Address)
+	assert.Equal(t, big.NewInt(70), changes.Balances[0].Delta)
+	assert.Equal(t, big.NewInt(120), changes.Balances[0].Balance)
+	assert.Equal(t, "f06", changes.Balances[1].Address)
+	assert.Equal(t, big.NewInt(30), changes.Balances[1].Delta)
+	require.Len(t, changes.Allowances, 1)
+	assert.Equal(t, big.NewInt(50), changes.Allowances[0].Delta)
+
+	t.Run("mismatches take the on-chain balance", func(t *testing.T) {
+		changes, err := ledger.Apply(110, &types.DataCapEvents{
+			DataCapTokenEvent: []*types.DataCapTokenEvent{
+				tokenEvent("f06", parser.MethodBurnExported, -10, 25),
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, changes.Mismatches, 1)
+		assert.Equal(t, big.NewInt(20), changes.Mismatches[0].Computed)
+		assert.Equal(t, big.NewInt(25), changes.Mismatches[0].OnChain)
+		require.Len(t, changes.Balances, 1)
+		assert.Equal(t, big.NewInt(-5), changes.Balances[0].Delta)
+		balance, ok := ledger.Balance("f06")
+		require.True(t, ok)
+		assert.Equal(t, big.NewInt(25), balance)
+	})
+
+	t.Run("allowances", func(t *testing.T) {
+		changes, err := ledger.Apply(120, &types.DataCapEvents{
+			DataCapTokenEvent: []*types.DataCapTokenEvent{
+				tokenEvent("f01000", parser.MethodTransferFromExported, -20, 100),
+				tokenEvent("f03000", parser.MethodTransferFromExported, 20, 20),
+			},
+			DataCapAllowanceEvent: []*types.DataCapAllowanceEvent{
+				{OwnerAddress: "f01000", OperatorAddress: "f02000", AllowanceBalance: big.NewInt(30)},
+				{OwnerAddress: "f01000", OperatorAddress: "f02000", AllowanceBalance: big.NewInt(0)},
+			},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, changes.Mismatches)
+		require.Len(t, changes.Allowances, 1)
+		assert.Equal(t, big.NewInt(-50), changes.Allowances[0].Delta)
+		assert.Equal(t, big.NewInt(0), changes.Allowances[0].Allowance)
+		assert.Empty(t, ledger.Allowances())
+	})
+
+	t.Run("events must be applied in height order", func(t *testing.T) {
+		balance, _ := ledger.Balance("f06")
+		_, err := ledger.Apply(120, &types.DataCapEvents{
+			DataCapTokenEvent: []*types.DataCapTokenEvent{tokenEvent("f06", parser.MethodBurnExported, -10, 0)},
+		})
+		assert.ErrorIs(t, err, common.ErrHeightOutOfOrder)
+		got, _ := ledger.Balance("f06")
+		assert.Equal(t, 0, balance.Cmp(got), "a rejected tipset leaves the balances untouched")
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		var snapshot bytes.Buffer
+		require.NoError(t, ledger.Export(&snapshot))
+		restored, err := datacap.ImportLedger(bytes.NewReader(snapshot.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, ledger.Height(), restored.Height())
+		assert.Equal(t, ledger.Allowances(), restored.Allowances())
+		for _, addr := range []string{"f01000", "f03000", "f06"} {
+			want, _ := ledger.Balance(addr)
+			got, ok := restored.Balance(addr)
+			require.True(t, ok)
+			assert.Equal(t, 0, want.Cmp(got), addr)
+		}
+
+		// restored balances are checked against the chain
+		changes, err := restored.Apply(130, &types.DataCapEvents{
+			DataCapTokenEvent: []*types.DataCapTokenEvent{tokenEvent("f03000", parser.MethodBurnExported, -5, 10)},
+		})
+		require.NoError(t, err)
+		assert.Len(t, changes.Mismatches, 1)
+	})
+}
@@ -150,6 +150,10 @@ func (eg *eventGenerator) parseMint(ctx context.Context, tx *types.Transaction,
 		eg.logger.Errorf("error consolidating to: %s", err)
 	}
 
+	amount, err := common.GetBigInt(params, KeyAmount, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing amount: %w", err)
+	}
 	balance, err := common.GetBigInt(ret, KeyBalance, false)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing balance: %w", err)
@@ -166,6 +170,7 @@ func (eg *eventGenerator) parseMint(ctx context.Context, tx *types.Transaction,
 		TxCid:        tx.TxCid,
 		ActionType:   tx.TxType,
 		Data:         tx.TxMetadata,
+		Amount:       amount,
 		Balance:      balance,
 		Supply:       supply,
 		TxTimestamp:  tx.TxTimestamp,
@@ -183,6 +188,10 @@ func (eg *eventGenerator) parseDestroy(ctx context.Context, tx *types.Transactio
 		eg.logger.Errorf("error consolidating owner: %s", err)
 	}
 
+	amount, err := common.GetBigInt(params, KeyAmount, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing amount: %w", err)
+	}
 	balance, err := common.GetBigInt(ret, KeyBalance, false)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing balance: %w", err)
@@ -195,6 +204,7 @@ func (eg *eventGenerator) parseDestroy(ctx context.Context, tx *types.Transactio
 		TxCid:        tx.TxCid,
 		ActionType:   tx.TxType,
 		Data:         tx.TxMetadata,
+		Amount:       new(big.Int).Neg(amount),
 		Balance:      balance,
 		TxTimestamp:  tx.TxTimestamp,
 	}, nil
@@ -210,6 +220,10 @@ func (eg *eventGenerator) parseTransfer(ctx context.Context, tx *types.Transacti
 		eg.logger.Errorf("error consolidating to: %s", err)
 	}
 
+	fromAmount, toAmount, err := transferAmounts(params, tx.TxFrom, to)
+	if err != nil {
+		return nil, err
+	}
 	fromBalance, err := common.GetBigInt(ret, KeyFromBalance, false)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing balance: %w", err)
@@ -227,6 +241,7 @@ func (eg *eventGenerator) parseTransfer(ctx context.Context, tx *types.Transacti
 				TxCid:        tx.TxCid,
 				ActionType:   tx.TxType,
 				Data:         tx.TxMetadata,
+				Amount:       fromAmount,
 				Balance:      fromBalance,
 				TxTimestamp:  tx.TxTimestamp,
 			},
@@ -237,6 +252,7 @@ func (eg *eventGenerator) parseTransfer(ctx context.Context, tx *types.Transacti
 				TxCid:        tx.TxCid,
 				ActionType:   tx.TxType,
 				Data:         tx.TxMetadata,
+				Amount:       toAmount,
 				Balance:      toBalance,
 				TxTimestamp:  tx.TxTimestamp,
 			},
@@ -261,6 +277,10 @@ func (eg *eventGenerator) parseTransferFrom(ctx context.Context, tx *types.Trans
 	if err != nil {
 		eg.logger.Errorf("error consolidating to: %s", err)
 	}
+	fromAmount, toAmount, err := transferAmounts(params, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
 	fromBalance, err := common.GetBigInt(ret, KeyFromBalance, false)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error parsing fromBalance: %w", err)
@@ -282,6 +302,7 @@ func (eg *eventGenerator) parseTransferFrom(ctx context.Context, tx *types.Trans
 				TxCid:        tx.TxCid,
 				ActionType:   tx.TxType,
 				Data:         tx.TxMetadata,
+				Amount:       fromAmount,
 				Balance:      fromBalance,
 				TxTimestamp:  tx.TxTimestamp,
 			},
@@ -292,6 +313,7 @@ func (eg *eventGenerator) parseTransferFrom(ctx context.Context, tx *types.Trans
 				TxCid:        tx.TxCid,
 				ActionType:   tx.TxType,
 				Data:         tx.TxMetadata,
+				Amount:       toAmount,
 				Balance:      toBalance,
 				TxTimestamp:  tx.TxTimestamp,
 			},
@@ -309,6 +331,10 @@ func (eg *eventGenerator) parseTransferFrom(ctx context.Context, tx *types.Trans
 }
 
 func (eg *eventGenerator) parseBurn(ctx context.Context, tx *types.Transaction, tipsetCid string, params, ret map[string]interface{}) (*types.DataCapTokenEvent, error) {
+	amount, err := common.GetBigInt(params, KeyAmount, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing amount: %w", err)
+	}
 	balance, err := common.GetBigInt(ret, KeyBalance, false)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing balance: %w", err)
@@ -321,6 +347,7 @@ func (eg *eventGenerator) parseBurn(ctx context.Context, tx *types.Transaction,
 		TxCid:        tx.TxCid,
 		ActionType:   tx.TxType,
 		Data:         tx.TxMetadata,
+		Amount:       new(big.Int).Neg(amount),
 		Balance:      balance,
 		TxTimestamp:  tx.TxTimestamp,
 	}, nil
@@ -338,6 +365,10 @@ func (eg *eventGenerator) parseBurnFrom(ctx context.Context, tx *types.Transacti
 		eg.logger.Errorf("error consolidating owner: %s", err)
 	}
 
+	amount, err := common.GetBigInt(params, KeyAmount, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing amount: %w", err)
+	}
 	balance, err := common.GetBigInt(ret, KeyBalance, false)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error parsing balance: %w", err)
@@ -354,6 +385,7 @@ func (eg *eventGenerator) parseBurnFrom(ctx context.Context, tx *types.Transacti
 			TxCid:        tx.TxCid,
 			ActionType:   tx.TxType,
 			Data:         tx.TxMetadata,
+			Amount:       new(big.Int).Neg(amount),
 			Balance:      balance,
 			TxTimestamp:  tx.TxTimestamp,
 		}, &types.DataCapAllowanceEvent{
@@ -441,6 +473,19 @@ func isDatacapTokenMessage(txType string) bool {
 	return false
 }
 
+// transferAmounts returns the balance changes of the sender and the recipient of a transfer.
+// They cancel out when both are the same address, as the balance doesn't change.
+func transferAmounts(params map[string]interface{}, from, to string) (*big.Int, *big.Int, error) {
+	amount, err := common.GetBigInt(params, KeyAmount, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing amount: %w", err)
+	}
+	if from == to {
+		return big.NewInt(0), big.NewInt(0), nil
+	}
+	return new(big.Int).Neg(amount), amount, nil
+}
+
 func getReturn[T any](value map[string]interface{}) (T, error) {
 	ret, err := common.GetItem[T](value, parser.ReturnKey, false)
 	if err != nil {
@@ -188,6 +188,15 @@ func (eg *eventGenerator) parseActivateDeals(tx *types.Transaction, params, ret
 		if err != nil {
 			return err
 		}
+		// From NV22 the sector deals carry the sector number, used to map the sector terminations to the deals
+		var sectorNumber *uint64
+		if _, ok := params[KeySectorNumber]; ok {
+			number, err := common.GetInteger[uint64](params, KeySectorNumber, false)
+			if err != nil {
+				return err
+			}
+			sectorNumber = &number
+		}
 
 		for _, dealID := range dealIDs {
 			dealActivations = append(dealActivations, &types.DealsActivations{
@@ -199,6 +208,7 @@ func (eg *eventGenerator) parseActivateDeals(tx *types.Transaction, params, ret
 				SectorExpiry: sectorExpiry,
 				ActionType:   tx.TxType,
 				TxTimestamp:  tx.TxTimestamp,
+				SectorNumber: sectorNumber,
 			})
 		}
 		// Before NV18(mainnet) and NV17(calibration), ActivateDeals return is empty and we get the deal space info from VerifyDealsForActivation
@@ -0,0 +1,319 @@
+package deals
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+// DealState is the lifecycle state of a storage deal.
+type DealState string
+
+const (
+	DealStatePublished  DealState = "published"
+	DealStateActivated  DealState = "activated"
+	DealStateTerminated DealState = "terminated"
+	DealStateSlashed    DealState = "slashed"
+	DealStateExpired    DealState = "expired"
+)
+
+// dealSnapshotVersion is bumped whenever the snapshot layout changes.
+const dealSnapshotVersion = 3
+
+// Deal is the current state of a storage deal.
+type Deal struct {
+	DealID          uint64    `json:"deal_id"`
+	ProviderAddress string    `json:"provider_address"`
+	ClientAddress   string    `json:"client_address"`
+	State           DealState `json:"state"`
+	StartEpoch      int64     `json:"start_epoch"`
+	EndEpoch        int64     `json:"end_epoch"`
+	SectorExpiry    int64     `json:"sector_expiry"`
+	// TerminationEpoch is the epoch the sector holding the deal was terminated at, if any.
+	TerminationEpoch int64 `json:"termination_epoch"`
+	// SlashedCollateral is the provider collateral burned when the deal was terminated early.
+	SlashedCollateral  *big.Int `json:"slashed_collateral"`
+	ProviderCollateral *big.Int `json:"provider_collateral"`
+	// PaymentsSettled is the sum of the payments settled to the provider.
+	PaymentsSettled *big.Int `json:"payments_settled"`
+	// UpdatedAt is the height of the last change to the deal.
+	UpdatedAt uint64 `json:"updated_at"`
+	// SectorMiner and SectorNumber are the sector the deal was activated in, known from NV22 on.
+	SectorMiner  string  `json:"sector_miner,omitempty"`
+	SectorNumber *uint64 `json:"sector_number,omitempty"`
+}
+
+// sectorKey identifies a sector of a miner, as seen by the market in the activations and terminations.
+type sectorKey struct {
+	miner  string
+	number uint64
+}
+
+// DealTracker keeps the current state of every deal seen in the deals events, fed with the events of each tipset
+// in turn. It is not safe for concurrent use.
+type DealTracker struct {
+	height uint64
+	deals  map[uint64]*Deal
+	// expirations indexes the deals that can still expire by the epoch they expire after:
+	// the start epoch for published deals, which are dropped if not activated in time, and the end epoch for activated ones.
+	expirations *common.ExpirationIndex[uint64]
+	// sectors indexes the deals that can still be terminated by the sector they were activated in,
+	// as the sector terminations don't list the deals from NV22 on.
+	sectors map[sectorKey]map[uint64]struct{}
+}
+
+func NewDealTracker() *DealTracker {
+	return &DealTracker{
+		deals:       make(map[uint64]*Deal),
+		expirations: common.NewExpirationIndex[uint64](),
+		sectors:     make(map[sectorKey]map[uint64]struct{}),
+	}
+}
+
+// ImportDealTracker restores a tracker from a snapshot written by Export.
+func ImportDealTracker(r io.Reader) (*DealTracker, error) {
+	deals, height, err := common.ImportSnapshot[[]*Deal](r, "deal", dealSnapshotVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := NewDealTracker()
+	tracker.height = height
+	for _, deal := range deals {
+		tracker.deals[deal.DealID] = deal
+		tracker.indexExpiration(deal)
+		tracker.indexSector(deal)
+	}
+	return tracker, nil
+}
+
+// Export writes a snapshot of the tracker with the deals sorted by id.
+func (t *DealTracker) Export(w io.Writer) error {
+	return common.ExportSnapshot(w, "deal", dealSnapshotVersion, t.height, t.Deals())
+}
+
+// Height returns the height of the last applied tipset.
+func (t *DealTracker) Height() uint64 {
+	return t.height
+}
+
+// Deal returns the current state of a deal.
+func (t *DealTracker) Deal(dealID uint64) (*Deal, bool) {
+	deal, ok := t.deals[dealID]
+	return deal, ok
+}
+
+// Deals returns every tracked deal, sorted by deal id.
+func (t *DealTracker) Deals() []*Deal {
+	deals := make([]*Deal, 0, len(t.deals))
+	for _, deal := range t.deals {
+		deals = append(deals, deal)
+	}
+	sort.Slice(deals, func(i, j int) bool {
+		return deals[i].DealID < deals[j].DealID
+	})
+	return deals
+}
+
+// Apply updates the tracker with the deals events of the tipset at height and returns the resulting state transitions.
+// Deals that reached their expiration before height expire first. Then the events are applied following the deal
+// lifecycle: proposals, activations, terminations and settlements. A deal terminated before its end epoch is slashed,
+// and a deal whose payments settled for the final time expires. The terminations of a sector apply to the deals
+// activated in it; sectors whose activation wasn't seen are ignored.
+// Only the height check can fail, before the tracker changes, so a rejected tipset leaves the tracker untouched.
+func (t *DealTracker) Apply(height uint64, events *types.DealsEvents) ([]*types.DealStateTransition, error) {
+	if err := common.CheckTrackerHeight(t.height, height); err != nil {
+		return nil, err
+	}
+
+	t.height = height
+	transitions := t.expire(height)
+	if events == nil {
+		return transitions, nil
+	}
+
+	// proposals don't keep the publishing method, take it from the message
+	actionTypes := make(map[string]string, len(events.DealsMessages))
+	for _, message := range events.DealsMessages {
+		actionTypes[message.TxCid] = message.ActionType
+	}
+	for _, proposal := range events.DealsProposals {
+		deal := t.getOrCreate(proposal.DealID)
+		deal.ProviderAddress = proposal.ProviderAddress
+		deal.ClientAddress = proposal.ClientAddress
+		deal.StartEpoch = proposal.StartEpoch
+		deal.EndEpoch = proposal.EndEpoch
+		deal.ProviderCollateral = proposal.ProviderCollateral
+		transitions = t.appendTransition(transitions, deal, DealStatePublished, proposal.TxCid, actionTypes[proposal.TxCid])
+	}
+	for _, activation := range events.DealsActivations {
+		deal := t.getOrCreate(activation.DealID)
+		if isFinal(deal.State) {
+			continue
+		}
+		deal.SectorExpiry = activation.SectorExpiry
+		if activation.SectorNumber != nil {
+			t.unindexSector(deal)
+			deal.SectorMiner = activation.ActorAddress
+			deal.SectorNumber = activation.SectorNumber
+			t.indexSector(deal)
+		}
+		transitions = t.appendTransition(transitions, deal, DealStateActivated, activation.TxCid, activation.ActionType)
+	}
+	for _, termination := range events.DealsTerminations {
+		if termination.SectorNumber == nil {
+			transitions = t.terminate(transitions, t.getOrCreate(termination.DealID), termination)
+			continue
+		}
+		dealIDs := t.sectorDeals(sectorKey{miner: termination.ActorAddress, number: *termination.SectorNumber})
+		for _, dealID := range dealIDs {
+			transitions = t.terminate(transitions, t.deals[dealID], termination)
+		}
+	}
+	for _, settlement := range events.DealsSettlements {
+		deal := t.getOrCreate(settlement.DealID)
+		if deal.PaymentsSettled == nil {
+			deal.PaymentsSettled = big.NewInt(0)
+		}
+		if settlement.Payment != nil {
+			deal.PaymentsSettled = new(big.Int).Add(deal.PaymentsSettled, settlement.Payment)
+		}
+		deal.UpdatedAt = height
+		if isFinal(deal.State) {
+			continue
+		}
+		// only active deals are settled
+		state := DealStateActivated
+		if settlement.Completed {
+			state = DealStateExpired
+		}
+		transitions = t.appendTransition(transitions, deal, state, settlement.TxCid, settlement.ActionType)
+	}
+	return transitions, nil
+}
+
+// terminate moves a deal that is not final yet to the terminated state, and to slashed if it was still running.
+func (t *DealTracker) terminate(transitions []*types.DealStateTransition, deal *Deal, termination *types.DealsTerminations) []*types.DealStateTransition {
+	if isFinal(deal.State) {
+		return transitions
+	}
+	deal.TerminationEpoch = termination.TerminationEpoch
+	transitions = t.appendTransition(transitions, deal, DealStateTerminated, termination.TxCid, termination.ActionType)
+	// the provider collateral is burned if the deal was still running, unknown deals are left terminated
+	if deal.EndEpoch > termination.TerminationEpoch {
+		deal.SlashedCollateral = deal.ProviderCollateral
+		transitions = t.appendTransition(transitions, deal, DealStateSlashed, termination.TxCid, termination.ActionType)
+	}
+	return transitions
+}
+
+// sectorDeals returns the deals activated in a sector, sorted by id.
+func (t *DealTracker) sectorDeals(key sectorKey) []uint64 {
+	dealIDs := make([]uint64, 0, len(t.sectors[key]))
+	for dealID := range t.sectors[key] {
+		dealIDs = append(dealIDs, dealID)
+	}
+	sort.Slice(dealIDs, func(i, j int) bool {
+		return dealIDs[i] < dealIDs[j]
+	})
+	return dealIDs
+}
+
+// expire moves the deals whose expiration is before height to the expired state.
+func (t *DealTracker) expire(height uint64) []*types.DealStateTransition {
+	var transitions []*types.DealStateTransition
+	for _, dealID := range t.expirations.PopExpired(height, func(a, b uint64) bool { return a < b }) {
+		transitions = t.appendTransition(transitions, t.deals[dealID], DealStateExpired, "", "")
+	}
+	return transitions
+}
+
+func (t *DealTracker) getOrCreate(dealID uint64) *Deal {
+	deal, ok := t.deals[dealID]
+	if !ok {
+		deal = &Deal{DealID: dealID}
+		t.deals[dealID] = deal
+	}
+	return deal
+}
+
+// appendTransition moves deal to state, appending the transition to transitions if the state changed.
+func (t *DealTracker) appendTransition(transitions []*types.DealStateTransition, deal *Deal, state DealState, txCid, actionType string) []*types.DealStateTransition {
+	deal.UpdatedAt = t.height
+	from := deal.State
+	t.unindexExpiration(deal)
+	t.unindexSector(deal)
+	deal.State = state
+	t.indexExpiration(deal)
+	t.indexSector(deal)
+	if from == state {
+		return transitions
+	}
+
+	return append(transitions, &types.DealStateTransition{
+		ID:         tools.BuildId(fmt.Sprint(deal.DealID), fmt.Sprint(t.height), string(state)),
+		DealID:     deal.DealID,
+		FromState:  string(from),
+		ToState:    string(state),
+		Height:     t.height,
+		TxCid:      txCid,
+		ActionType: actionType,
+	})
+}
+
+// expiration returns the epoch after which the deal expires, false if it can't expire anymore.
+func expiration(deal *Deal) (int64, bool) {
+	switch deal.State {
+	case DealStatePublished:
+		return deal.StartEpoch, deal.StartEpoch > 0
+	case DealStateActivated:
+		return deal.EndEpoch, deal.EndEpoch > 0
+	}
+	return 0, false
+}
+
+func (t *DealTracker) indexExpiration(deal *Deal) {
+	if epoch, ok := expiration(deal); ok {
+		t.expirations.Add(epoch, deal.DealID)
+	}
+}
+
+func (t *DealTracker) unindexExpiration(deal *Deal) {
+	if epoch, ok := expiration(deal); ok {
+		t.expirations.Remove(epoch, deal.DealID)
+	}
+}
+
+// indexSector indexes the deal by its sector while it can still be terminated.
+func (t *DealTracker) indexSector(deal *Deal) {
+	if deal.SectorNumber == nil || isFinal(deal.State) {
+		return
+	}
+	key := sectorKey{miner: deal.SectorMiner, number: *deal.SectorNumber}
+	dealIDs, ok := t.sectors[key]
+	if !ok {
+		dealIDs = make(map[uint64]struct{})
+		t.sectors[key] = dealIDs
+	}
+	dealIDs[deal.DealID] = struct{}{}
+}
+
+func (t *DealTracker) unindexSector(deal *Deal) {
+	if deal.SectorNumber == nil {
+		return
+	}
+	key := sectorKey{miner: deal.SectorMiner, number: *deal.SectorNumber}
+	delete(t.sectors[key], deal.DealID)
+	if len(t.sectors[key]) == 0 {
+		delete(t.sectors, key)
+	}
+}
+
+func isFinal(state DealState) bool {
+	return state == DealStateTerminated || state == DealStateSlashed || state == DealStateExpired
+}
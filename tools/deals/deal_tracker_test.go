@@ -0,0 +1,102 @@
+package deals_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/tools/deals"
+	"github.com/zondax/fil-parser/types"
+)
+
+func dealStates(transitions []*types.DealStateTransition) []string {
+	var result []string
+	for _, transition := range transitions {
+		result = append(result, transition.FromState+">"+transition.ToState)
+	}
+	return result
+}
+
+func TestDealTracker(t *testing.T) {
+	tracker := deals.NewDealTracker()
+	proposal := func(dealID uint64, start, end int64) *types.DealsProposals {
+		return &types.DealsProposals{DealID: dealID, TxCid: txCid, StartEpoch: start, EndEpoch: end, ProviderCollateral: big.NewInt(100)}
+	}
+	activation := func(dealID uint64) *types.DealsActivations {
+		return &types.DealsActivations{DealID: dealID, TxCid: txCid, ActionType: parser.MethodBatchActivateDeals}
+	}
+
+	transitions, err := tracker.Apply(100, &types.DealsEvents{
+		DealsMessages:  []*types.DealsMessages{{TxCid: txCid, ActionType: parser.MethodPublishStorageDeals}},
+		DealsProposals: []*types.DealsProposals{proposal(1, 200, 1000), proposal(2, 200, 1000), proposal(3, 200, 1000), proposal(4, 150, 1000)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{">published", ">published", ">published", ">published"}, dealStates(transitions))
+	assert.Equal(t, parser.MethodPublishStorageDeals, transitions[0].ActionType)
+
+	transitions, err = tracker.Apply(120, &types.DealsEvents{DealsActivations: []*types.DealsActivations{activation(1), activation(2), activation(3)}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"published>activated", "published>activated", "published>activated"}, dealStates(transitions))
+
+	t.Run("deals not activated before their start epoch expire", func(t *testing.T) {
+		transitions, err := tracker.Apply(160, nil)
+		require.NoError(t, err)
+		require.Len(t, transitions, 1)
+		assert.Equal(t, uint64(4), transitions[0].DealID)
+		assert.Equal(t, "published>expired", dealStates(transitions)[0])
+	})
+
+	t.Run("terminated deals are slashed", func(t *testing.T) {
+		transitions, err := tracker.Apply(300, &types.DealsEvents{
+			DealsTerminations: []*types.DealsTerminations{{DealID: 1, TxCid: txCid, TerminationEpoch: 300, ActionType: parser.MethodOnMinerSectorsTerminate}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"activated>terminated", "terminated>slashed"}, dealStates(transitions))
+		assert.Equal(t, txCid, transitions[1].TxCid)
+		deal, ok := tracker.Deal(1)
+		require.True(t, ok)
+		assert.Equal(t, big.NewInt(100), deal.SlashedCollateral)
+	})
+
+	t.Run("settlements", func(t *testing.T) {
+		transitions, err := tracker.Apply(400, &types.DealsEvents{DealsSettlements: []*types.DealsSettlements{
+			{DealID: 2, TxCid: txCid, Payment: big.NewInt(10), ActionType: parser.MethodSettleDealPaymentsExported},
+			{DealID: 2, TxCid: txCid, Payment: big.NewInt(5), Completed: true, ActionType: parser.MethodSettleDealPaymentsExported},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"activated>expired"}, dealStates(transitions))
+		deal, ok := tracker.Deal(2)
+		require.True(t, ok)
+		assert.Equal(t, big.NewInt(15), deal.PaymentsSettled)
+	})
+
+	t.Run("events must be applied in height order", func(t *testing.T) {
+		height := tracker.Height()
+		_, err := tracker.Apply(400, &types.DealsEvents{DealsTerminations: []*types.DealsTerminations{{DealID: 3, TerminationEpoch: 400}}})
+		assert.ErrorIs(t, err, common.ErrHeightOutOfOrder)
+		assert.Equal(t, height, tracker.Height())
+		deal, ok := tracker.Deal(3)
+		require.True(t, ok)
+		assert.Equal(t, deals.DealStateActivated, deal.State, "a rejected tipset leaves the deals untouched")
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		var snapshot bytes.Buffer
+		require.NoError(t, tracker.Export(&snapshot))
+		restored, err := deals.ImportDealTracker(bytes.NewReader(snapshot.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, tracker.Height(), restored.Height())
+		assert.Equal(t, tracker.Deals(), restored.Deals())
+
+		// deal 3 reaches its end epoch after the restore
+		transitions, err := restored.Apply(1001, nil)
+		require.NoError(t, err)
+		require.Len(t, transitions, 1)
+		assert.Equal(t, uint64(3), transitions[0].DealID)
+		assert.Equal(t, "activated>expired", dealStates(transitions)[0])
+	})
+}
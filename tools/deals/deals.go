@@ -43,10 +43,12 @@ func NewEventGenerator(helper *helper.Helper, logger *logger.Logger, metrics met
 
 func (eg *eventGenerator) GenerateDealsEvents(ctx context.Context, transactions []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.DealsEvents, error) {
 	events := &types.DealsEvents{
-		DealsMessages:    []*types.DealsMessages{},
-		DealsProposals:   []*types.DealsProposals{},
-		DealsActivations: []*types.DealsActivations{},
-		DealsSpaceInfo:   []*types.DealsSpaceInfo{},
+		DealsMessages:     []*types.DealsMessages{},
+		DealsProposals:    []*types.DealsProposals{},
+		DealsActivations:  []*types.DealsActivations{},
+		DealsSpaceInfo:    []*types.DealsSpaceInfo{},
+		DealsTerminations: []*types.DealsTerminations{},
+		DealsSettlements:  []*types.DealsSettlements{},
 	}
 
 	for _, tx := range transactions {
@@ -61,7 +63,7 @@ func (eg *eventGenerator) GenerateDealsEvents(ctx context.Context, transactions
 		}
 
 		// #nosec G115
-		actorName, err := common.GetActorNameFromAddress(eg.helper, addr, int64(tx.Height), tipsetKey, true)
+		actorName, err := common.ResolveActorName(ctx, eg.helper, addr, int64(tx.Height), tipsetKey, true)
 		if err != nil {
 			_ = eg.metrics.UpdateActorNameFromAddressMetric()
 			return nil, err
@@ -95,6 +97,20 @@ func (eg *eventGenerator) GenerateDealsEvents(ctx context.Context, transactions
 			events.DealsActivations = append(events.DealsActivations, dealActivations...)
 			events.DealsSpaceInfo = append(events.DealsSpaceInfo, dealSpaceInfo...)
 		}
+		if eg.isDealTermination(tx.TxType) {
+			dealTerminations, err := eg.createDealTerminations(tx)
+			if err != nil {
+				return nil, fmt.Errorf("could not create deal terminations. err: %w", err)
+			}
+			events.DealsTerminations = append(events.DealsTerminations, dealTerminations...)
+		}
+		if eg.isDealSettlement(tx.TxType) {
+			dealSettlements, err := eg.createDealSettlements(tx)
+			if err != nil {
+				return nil, fmt.Errorf("could not create deal settlements. err: %w", err)
+			}
+			events.DealsSettlements = append(events.DealsSettlements, dealSettlements...)
+		}
 	}
 
 	return events, nil
@@ -111,6 +127,7 @@ func (eg *eventGenerator) isDealsStateMessage(actorName, txType string) bool {
 		strings.EqualFold(txType, parser.MethodVerifyDealsForActivation),
 		strings.EqualFold(txType, parser.MethodActivateDeals),
 		strings.EqualFold(txType, parser.MethodSettleDealPaymentsExported),
+		strings.EqualFold(txType, parser.MethodOnMinerSectorsTerminate),
 		strings.EqualFold(txType, parser.MethodSectorContentChanged):
 		return true
 	}
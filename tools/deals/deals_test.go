@@ -1,17 +1,23 @@
 package deals_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"testing"
 
+	"github.com/filecoin-project/go-bitfield"
 	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/filecoin-project/go-state-types/manifest"
 	filApiTypes "github.com/filecoin-project/lotus/api/types"
 	filTypes "github.com/filecoin-project/lotus/chain/types"
 	"github.com/filecoin-project/lotus/node/modules/dtypes"
 	cid "github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"github.com/zondax/fil-parser/actors/v2/market"
 	filMetrics "github.com/zondax/fil-parser/metrics"
 	"github.com/zondax/fil-parser/parser"
 	"github.com/zondax/fil-parser/parser/helper"
@@ -321,3 +327,145 @@ func TestActivateDeals(t *testing.T) {
 		})
 	}
 }
+
+func TestDealTerminationsAndSettlements(t *testing.T) {
+	eg := setupTest(t, "mainnet")
+
+	tests := []struct {
+		name             string
+		txType           string
+		metadata         string
+		height           uint64
+		wantTerminations []uint64
+		wantSettlements  map[uint64]string
+	}{
+		{
+			name:             "OnMinerSectorsTerminate - before NV22",
+			txType:           parser.MethodOnMinerSectorsTerminate,
+			metadata:         `{"MethodNum":"7","Params":{"Epoch":3573062,"DealIDs":[78950968,78951195]}}`,
+			height:           3573062,
+			wantTerminations: []uint64{78950968, 78951195},
+		},
+		{
+			name:     "OnMinerSectorsTerminate - sectors without deals",
+			txType:   parser.MethodOnMinerSectorsTerminate,
+			metadata: `{"MethodNum":"7","Params":{"Epoch":3573062,"DealIDs":null}}`,
+			height:   3573062,
+		},
+		{
+			name:            "SettleDealPaymentsExported - failed settlement",
+			txType:          parser.MethodSettleDealPaymentsExported,
+			metadata:        `{"MethodNum":"2630614370","Params":[5,3],"Return":{"Results":{"SuccessCount":2,"FailCodes":[{"Idx":1,"Code":16}]},"Settlements":[{"Payment":"1000","Completed":false},{"Payment":"2500","Completed":true}]}}`,
+			height:          4500000,
+			wantSettlements: map[uint64]string{5: "1000", 7: "2500"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tx := &types.Transaction{
+				TxCid:         txCid,
+				TxType:        test.txType,
+				TxFrom:        txFrom,
+				TxTo:          txTo,
+				TxMetadata:    test.metadata,
+				Status:        tools.GetExitCodeStatus(exitcode.Ok),
+				SubcallStatus: tools.GetExitCodeStatus(exitcode.Ok),
+			}
+			tx.Height = test.height
+			events, err := eg.GenerateDealsEvents(context.Background(), []*types.Transaction{tx}, tipsetCid, filTypes.EmptyTSK)
+			require.NoError(t, err)
+			require.Len(t, events.DealsMessages, 1)
+
+			var terminations []uint64
+			for _, termination := range events.DealsTerminations {
+				assert.Equal(t, int64(test.height), termination.TerminationEpoch)
+				assert.Equal(t, txFrom, termination.ActorAddress)
+				assert.Nil(t, termination.SectorNumber)
+				terminations = append(terminations, termination.DealID)
+			}
+			assert.Equal(t, test.wantTerminations, terminations)
+
+			require.Len(t, events.DealsSettlements, len(test.wantSettlements))
+			for _, settlement := range events.DealsSettlements {
+				assert.Equal(t, test.wantSettlements[settlement.DealID], settlement.Payment.String())
+				assert.Equal(t, settlement.DealID == 7, settlement.Completed)
+			}
+		})
+	}
+}
+
+// onMinerSectorsTerminateMetadata encodes the params of OnMinerSectorsTerminate with the builtin-actors layout used
+// from NV22 on, a bitfield of the terminated sectors, and returns the tx metadata of the market actor parser for them.
+func onMinerSectorsTerminateMetadata(t *testing.T, height int64, epoch uint64, sectors ...uint64) string {
+	var raw bytes.Buffer
+	w := cbg.NewCborWriter(&raw)
+	require.NoError(t, w.WriteMajorTypeHeader(cbg.MajArray, 2))
+	require.NoError(t, w.WriteMajorTypeHeader(cbg.MajUnsignedInt, epoch))
+	sectorBitField := bitfield.NewFromSet(sectors)
+	require.NoError(t, sectorBitField.MarshalCBOR(w))
+
+	metadata, err := market.New(logger.NewDevelopmentLogger()).OnMinerSectorsTerminateExported(tools.MainnetNetwork, height, raw.Bytes())
+	require.NoError(t, err)
+	metadata[parser.MethodNumKey] = "7"
+	encoded, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	return string(encoded)
+}
+
+// TestDealTerminationsBySector activates deals with a mainnet ActivateDeals and terminates their sectors with
+// the NV22 params, which no longer list the deals, checking the DealTracker maps the sectors back to the deals.
+func TestDealTerminationsBySector(t *testing.T) {
+	eg := setupTest(t, "mainnet")
+	newTx := func(height uint64, txType, metadata string) *types.Transaction {
+		tx := &types.Transaction{
+			TxCid:         txCid,
+			TxType:        txType,
+			TxFrom:        txFrom,
+			TxTo:          txTo,
+			TxMetadata:    metadata,
+			Status:        tools.GetExitCodeStatus(exitcode.Ok),
+			SubcallStatus: tools.GetExitCodeStatus(exitcode.Ok),
+		}
+		tx.Height = height
+		return tx
+	}
+	tracker := deals.NewDealTracker()
+
+	// sectors 37656, 37888 and 37549 hold the deals 78950968, 78951195 and 79166044
+	activation := newTx(3857557, parser.MethodActivateDeals, `{"MethodNum":"6","Params":{"Sectors":[{"SectorNumber":37656,"SectorType":8,"SectorExpiry":4920235,"DealIDs":[78950968]},{"SectorNumber":37888,"SectorType":8,"SectorExpiry":4920235,"DealIDs":[78951195]},{"SectorNumber":37549,"SectorType":8,"SectorExpiry":4920235,"DealIDs":[79166044]}],"ComputeCID":false},"Return":{"ActivationResults":{"SuccessCount":3,"FailCodes":null},"Activations":[{"NonVerifiedDealSpace":"0","VerifiedInfos":[{"Client":3061409,"AllocationId":61239862,"Data":{"/":"baga6ea4seaqgvrjfj65lawcocwvrpgq7h53oghvto6akrys6wllhbbckchfgefy"},"Size":34359738368}],"UnsealedCid":{}},{"NonVerifiedDealSpace":"0","VerifiedInfos":[{"Client":3061409,"AllocationId":61240089,"Data":{"/":"baga6ea4seaqbuieim7slc3wu7kms436xpnorao5jxr6tqftnqsysfxcp5dnduia"},"Size":34359738368}],"UnsealedCid":{}},{"NonVerifiedDealSpace":"0","VerifiedInfos":[{"Client":3061409,"AllocationId":61454935,"Data":{"/":"baga6ea4seaqfodzysx243k4s6ieuxzzoawew4ckycynubcd5t67vxctunfjt6pq"},"Size":34359738368}],"UnsealedCid":{}}]}}`)
+	events, err := eg.GenerateDealsEvents(context.Background(), []*types.Transaction{activation}, tipsetCid, filTypes.EmptyTSK)
+	require.NoError(t, err)
+	require.Len(t, events.DealsActivations, 3)
+	require.NotNil(t, events.DealsActivations[0].SectorNumber)
+	assert.Equal(t, uint64(37656), *events.DealsActivations[0].SectorNumber)
+	_, err = tracker.Apply(activation.Height, events)
+	require.NoError(t, err)
+
+	// sector 1 was never seen activating deals
+	termination := newTx(4500000, parser.MethodOnMinerSectorsTerminate, onMinerSectorsTerminateMetadata(t, 4500000, 4500000, 1, 37656, 37888))
+	events, err = eg.GenerateDealsEvents(context.Background(), []*types.Transaction{termination}, tipsetCid, filTypes.EmptyTSK)
+	require.NoError(t, err)
+
+	var sectors []uint64
+	for _, termination := range events.DealsTerminations {
+		assert.Zero(t, termination.DealID)
+		assert.Equal(t, int64(4500000), termination.TerminationEpoch)
+		require.NotNil(t, termination.SectorNumber)
+		sectors = append(sectors, *termination.SectorNumber)
+	}
+	assert.Equal(t, []uint64{1, 37656, 37888}, sectors)
+
+	transitions, err := tracker.Apply(termination.Height, events)
+	require.NoError(t, err)
+	var terminated []uint64
+	for _, transition := range transitions {
+		if transition.ToState == string(deals.DealStateTerminated) {
+			terminated = append(terminated, transition.DealID)
+		}
+	}
+	assert.Equal(t, []uint64{78950968, 78951195}, terminated)
+	deal, ok := tracker.Deal(79166044)
+	require.True(t, ok)
+	assert.Equal(t, deals.DealStateActivated, deal.State)
+}
@@ -0,0 +1,177 @@
+package deals
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+const (
+	KeyEpoch       = "Epoch"
+	KeyResults     = "Results"
+	KeySettlements = "Settlements"
+	KeyPayment     = "Payment"
+	KeyCompleted   = "Completed"
+
+	KeySectorNumber   = "SectorNumber"
+	KeySectorNumbers  = "SectorNumbers"
+	KeySectorBitField = "SectorBitField"
+)
+
+// createDealTerminations returns a row per deal of the sectors terminated by a miner.
+// From NV22 on the params only list the terminated sectors, so a row is returned per sector instead and the
+// DealTracker maps it to the deals activated in it.
+// The market ignores the deals that already ended, they expire as usual.
+func (eg *eventGenerator) createDealTerminations(tx *types.Transaction) ([]*types.DealsTerminations, error) {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(tx.TxMetadata), &metadata); err != nil {
+		return nil, fmt.Errorf("error unmarshalling tx metadata: %w", err)
+	}
+	params, err := common.GetItem[map[string]interface{}](metadata, KeyParams, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing params: %w", err)
+	}
+	epoch, err := common.GetInteger[int64](params, KeyEpoch, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing epoch: %w", err)
+	}
+
+	newTermination := func(id string) *types.DealsTerminations {
+		return &types.DealsTerminations{
+			ID:               tools.BuildId(tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType, id),
+			Height:           tx.Height,
+			ActorAddress:     tx.TxFrom,
+			TxCid:            tx.TxCid,
+			TerminationEpoch: epoch,
+			ActionType:       tx.TxType,
+			TxTimestamp:      tx.TxTimestamp,
+		}
+	}
+
+	//#nosec G115
+	version := tools.VersionFromHeight(eg.network, int64(tx.Height))
+	if version.NodeVersion() < tools.V22.NodeVersion() {
+		// sectors without deals are terminated with a nil list
+		dealIDs, err := common.GetIntegerSlice[uint64](params, KeyDealIDs, true)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing deal ids: %w", err)
+		}
+		terminations := make([]*types.DealsTerminations, 0, len(dealIDs))
+		for _, dealID := range dealIDs {
+			termination := newTermination(fmt.Sprint(dealID))
+			termination.DealID = dealID
+			terminations = append(terminations, termination)
+		}
+		return terminations, nil
+	}
+
+	/*
+		From NV22 on, the params hold the sectors instead of their deals, decoded either as a bitfield or as a list
+		type OnMinerSectorsTerminateParams struct {
+			Epoch   abi.ChainEpoch
+			Sectors bitfield.BitField
+		}
+	*/
+	sectorNumbers, err := common.GetIntegerSlice[uint64](params, KeySectorNumbers, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing sector numbers: %w", err)
+	}
+	sectorBitField, err := common.GetIntegerSlice[int](params, KeySectorBitField, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing sector bitfield: %w", err)
+	}
+	if len(sectorBitField) > 0 {
+		bitFieldSectors, err := common.JsonEncodedBitfieldToIDs(sectorBitField)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing sector bitfield: %w", err)
+		}
+		sectorNumbers = append(sectorNumbers, bitFieldSectors...)
+	}
+
+	terminations := make([]*types.DealsTerminations, 0, len(sectorNumbers))
+	for _, sectorNumber := range sectorNumbers {
+		termination := newTermination("sector-" + fmt.Sprint(sectorNumber))
+		termination.SectorNumber = &sectorNumber
+		terminations = append(terminations, termination)
+	}
+	return terminations, nil
+}
+
+// createDealSettlements returns a row per deal settled by SettleDealPaymentsExported.
+// The params are a bitfield of deal ids and the return holds a settlement per deal that didn't fail, in the same order.
+func (eg *eventGenerator) createDealSettlements(tx *types.Transaction) ([]*types.DealsSettlements, error) {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(tx.TxMetadata), &metadata); err != nil {
+		return nil, fmt.Errorf("error unmarshalling tx metadata: %w", err)
+	}
+	dealsBitField, err := common.GetIntegerSlice[int](metadata, KeyParams, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing params: %w", err)
+	}
+	dealIDs, err := common.JsonEncodedBitfieldToIDs(dealsBitField)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing deals bitfield: %w", err)
+	}
+	ret, err := common.GetItem[map[string]interface{}](metadata, KeyReturn, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing return: %w", err)
+	}
+	results, err := common.GetItem[map[string]interface{}](ret, KeyResults, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing results: %w", err)
+	}
+	failedSettlements, err := common.FailedBatchIndexes(results)
+	if err != nil {
+		return nil, err
+	}
+	settlements, err := common.GetSlice[map[string]interface{}](ret, KeySettlements, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing settlements: %w", err)
+	}
+
+	dealSettlements := make([]*types.DealsSettlements, 0, len(settlements))
+	settlementIdx := 0
+	for i, dealID := range dealIDs {
+		if failedSettlements[i] {
+			continue
+		}
+		if settlementIdx >= len(settlements) {
+			return nil, fmt.Errorf("missing settlement for deal %d", dealID)
+		}
+		settlement := settlements[settlementIdx]
+		settlementIdx++
+
+		payment, err := common.GetBigInt(settlement, KeyPayment, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing payment: %w", err)
+		}
+		completed, err := common.GetItem[bool](settlement, KeyCompleted, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing completed: %w", err)
+		}
+		dealSettlements = append(dealSettlements, &types.DealsSettlements{
+			ID:           tools.BuildId(tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType, fmt.Sprint(dealID)),
+			Height:       tx.Height,
+			ActorAddress: tx.TxFrom,
+			TxCid:        tx.TxCid,
+			DealID:       dealID,
+			Payment:      payment,
+			Completed:    completed,
+			ActionType:   tx.TxType,
+			TxTimestamp:  tx.TxTimestamp,
+		})
+	}
+	return dealSettlements, nil
+}
+
+func (eg *eventGenerator) isDealTermination(txType string) bool {
+	return txType == parser.MethodOnMinerSectorsTerminate
+}
+
+func (eg *eventGenerator) isDealSettlement(txType string) bool {
+	return txType == parser.MethodSettleDealPaymentsExported
+}
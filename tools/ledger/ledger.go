@@ -0,0 +1,199 @@
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+// Ledger accumulates the net balance change of every address touched by the transactions of a single tipset.
+// Addresses are kept as found in the transactions, so they are robust or short depending on the parser config.
+type Ledger struct {
+	tipsetCid string
+	height    uint64
+	changes   map[string]*types.BalanceChange
+}
+
+func NewLedger(tipsetCid string, height uint64) *Ledger {
+	return &Ledger{
+		tipsetCid: tipsetCid,
+		height:    height,
+		changes:   make(map[string]*types.BalanceChange),
+	}
+}
+
+// BuildBalanceChanges returns the balance changes produced by the transactions of a single tipset, sorted by address.
+func BuildBalanceChanges(result *types.TxsParsedResult, tipsetCid string, height uint64) ([]*types.BalanceChange, error) {
+	ledger := NewLedger(tipsetCid, height)
+	if result != nil {
+		if err := ledger.AddTransactions(result.Txs); err != nil {
+			return nil, err
+		}
+	}
+	return ledger.BalanceChanges(), nil
+}
+
+// AddTransactions adds the funds moved by txs to the ledger.
+// A transaction only moves funds when it and every one of its parents succeeded, as a failed call reverts all of its subcalls.
+// Fees are always charged, whether they come as separate transactions or in the FeeData column.
+// txs must contain whole messages, that is, every subcall must be passed along with its parents,
+// which holds for both TxsParsedResult and the chunks produced when streaming transactions.
+func (l *Ledger) AddTransactions(txs []*types.Transaction) error {
+	txsById := make(map[string]*types.Transaction, len(txs))
+	for _, tx := range txs {
+		txsById[tx.Id] = tx
+	}
+
+	for _, tx := range txs {
+		switch {
+		case tx.TxType == parser.TotalFeeOp:
+			if err := l.addFees(tx.TxCid, tx.TxFrom, tx.TxMetadata); err != nil {
+				return err
+			}
+			continue
+		case tx.TxType == parser.TxTypeGenesis:
+			l.credit(tx.TxTo, tx.Amount)
+			continue
+		}
+
+		if tx.FeeData != "" {
+			if err := l.addFees(tx.TxCid, tx.TxFrom, tx.FeeData); err != nil {
+				return err
+			}
+		}
+
+		if tx.Amount == nil || tx.Amount.Sign() == 0 || !isApplied(tx, txsById) {
+			continue
+		}
+		l.debit(tx.TxFrom, tx.Amount)
+		l.credit(tx.TxTo, tx.Amount)
+	}
+
+	return nil
+}
+
+// BalanceChanges returns a balance change per address, sorted by address.
+func (l *Ledger) BalanceChanges() []*types.BalanceChange {
+	changes := make([]*types.BalanceChange, 0, len(l.changes))
+	for _, change := range l.changes {
+		change.Delta = new(big.Int).Sub(change.Credit, change.Debit)
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Address < changes[j].Address
+	})
+	return changes
+}
+
+// isApplied returns true if tx and all of its parents succeeded.
+func isApplied(tx *types.Transaction, txsById map[string]*types.Transaction) bool {
+	seen := make(map[string]bool)
+	for current := tx; current != nil; current = txsById[current.ParentId] {
+		if !common.IsTxSuccess(current) {
+			return false
+		}
+		if seen[current.Id] {
+			break
+		}
+		seen[current.Id] = true
+	}
+	return true
+}
+
+func (l *Ledger) addFees(txCid, payer, rawFees string) error {
+	var fees parser.FeesMetadata
+	if err := json.Unmarshal([]byte(rawFees), &fees); err != nil {
+		return fmt.Errorf("error parsing fees metadata of tx %s: %w", txCid, err)
+	}
+
+	minerTip, err := parseAmount(fees.MinerFee.Amount)
+	if err != nil {
+		return fmt.Errorf("error parsing miner tip of tx %s: %w", txCid, err)
+	}
+	baseFeeBurn, err := parseAmount(fees.BurnFee.Amount)
+	if err != nil {
+		return fmt.Errorf("error parsing base fee burn of tx %s: %w", txCid, err)
+	}
+	overEstimationBurn, err := parseAmount(fees.OverEstimationBurnFee.Amount)
+	if err != nil {
+		return fmt.Errorf("error parsing over estimation burn of tx %s: %w", txCid, err)
+	}
+	if minerTip.Sign() != 0 && fees.MinerFee.MinerAddress == "" {
+		return fmt.Errorf("miner tip of tx %s has no miner address", txCid)
+	}
+
+	// the payer is charged the sum of the splits, so the ledger always balances out
+	total := new(big.Int).Add(minerTip, baseFeeBurn)
+	total.Add(total, overEstimationBurn)
+	if total.Sign() == 0 {
+		return nil
+	}
+
+	l.debit(payer, total)
+	payerChange := l.change(payer)
+	payerChange.FeesPaid.Add(payerChange.FeesPaid, total)
+	l.credit(fees.MinerFee.MinerAddress, minerTip)
+	l.credit(burnAddress(fees.BurnFee.BurnAddress), baseFeeBurn)
+	l.credit(burnAddress(fees.OverEstimationBurnFee.BurnAddress), overEstimationBurn)
+	return nil
+}
+
+func (l *Ledger) credit(addr string, amount *big.Int) {
+	if amount == nil || amount.Sign() == 0 {
+		return
+	}
+	change := l.change(addr)
+	change.Credit.Add(change.Credit, amount)
+}
+
+func (l *Ledger) debit(addr string, amount *big.Int) {
+	if amount == nil || amount.Sign() == 0 {
+		return
+	}
+	change := l.change(addr)
+	change.Debit.Add(change.Debit, amount)
+}
+
+func (l *Ledger) change(addr string) *types.BalanceChange {
+	change, ok := l.changes[addr]
+	if !ok {
+		change = &types.BalanceChange{
+			ID:        tools.BuildId(l.tipsetCid, addr, fmt.Sprint(l.height)),
+			Height:    l.height,
+			TipsetCid: l.tipsetCid,
+			Address:   addr,
+			Delta:     big.NewInt(0),
+			Credit:    big.NewInt(0),
+			Debit:     big.NewInt(0),
+			FeesPaid:  big.NewInt(0),
+		}
+		l.changes[addr] = change
+	}
+	return change
+}
+
+func burnAddress(addr string) string {
+	if addr == "" {
+		return parser.BurnAddress
+	}
+	return addr
+}
+
+func parseAmount(amount string) (*big.Int, error) {
+	amount = strings.TrimSpace(amount)
+	if amount == "" {
+		return big.NewInt(0), nil
+	}
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("failed to convert string %s to big.Int", amount)
+	}
+	return value, nil
+}
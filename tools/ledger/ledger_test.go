@@ -0,0 +1,82 @@
+package ledger_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools/ledger"
+	"github.com/zondax/fil-parser/types"
+)
+
+func feesMetadata(t *testing.T, minerTip, baseFeeBurn, overEstimationBurn string) string {
+	metadata, err := json.Marshal(parser.FeesMetadata{
+		MinerFee:              parser.MinerFee{MinerAddress: "f01000", Amount: minerTip},
+		BurnFee:               parser.BurnFee{BurnAddress: parser.BurnAddress, Amount: baseFeeBurn},
+		OverEstimationBurnFee: parser.OverEstimationBurnFee{BurnAddress: parser.BurnAddress, Amount: overEstimationBurn},
+	})
+	require.NoError(t, err)
+	return string(metadata)
+}
+
+func TestBuildBalanceChanges(t *testing.T) {
+	txs := []*types.Transaction{
+		// successful message with a successful subcall and a subcall that failed
+		{Id: "main", ParentId: "root", TxFrom: "f0100", TxTo: "f0200", Amount: big.NewInt(50), Status: "Ok", SubcallStatus: "Ok"},
+		{Id: "sub-ok", ParentId: "main", TxFrom: "f0200", TxTo: "f0300", Amount: big.NewInt(20), Status: "Ok", SubcallStatus: "Ok"},
+		{Id: "sub-failed", ParentId: "main", TxFrom: "f0200", TxTo: "f0400", Amount: big.NewInt(10), Status: "Ok", SubcallStatus: "ErrInsufficientFunds"},
+		// succeeded on its own but reverted along with its failed parent
+		{Id: "sub-reverted", ParentId: "sub-failed", TxFrom: "f0400", TxTo: "f0500", Amount: big.NewInt(5), Status: "Ok", SubcallStatus: "Ok"},
+		{Id: "fee", ParentId: "main", TxFrom: "f0100", TxTo: parser.BurnAddress, TxType: parser.TotalFeeOp, Amount: big.NewInt(6),
+			Status: "Ok", SubcallStatus: "Ok", TxMetadata: feesMetadata(t, "1", "4", "1")},
+		// failed message still pays its fees
+		{Id: "failed", ParentId: "root", TxFrom: "f0600", TxTo: "f0200", Amount: big.NewInt(100), Status: "SysErrOutOfGas", SubcallStatus: "SysErrOutOfGas",
+			FeeData: feesMetadata(t, "2", "3", "0")},
+		{Id: "genesis", TxFrom: parser.TxFromGenesis, TxTo: "f0700", TxType: parser.TxTypeGenesis, Amount: big.NewInt(1000), Status: "Ok", SubcallStatus: "Ok"},
+	}
+
+	changes, err := ledger.BuildBalanceChanges(&types.TxsParsedResult{Txs: txs}, "tipset", 10)
+	require.NoError(t, err)
+
+	want := map[string][3]int64{ // delta, credit, debit
+		"f0100":            {-56, 0, 56},
+		"f0200":            {30, 50, 20},
+		"f0300":            {20, 20, 0},
+		"f0600":            {-5, 0, 5},
+		"f0700":            {1000, 1000, 0},
+		"f01000":           {3, 3, 0},
+		parser.BurnAddress: {8, 8, 0},
+	}
+	require.Len(t, changes, len(want))
+
+	total := big.NewInt(0)
+	for i, change := range changes {
+		if i > 0 {
+			assert.Less(t, changes[i-1].Address, change.Address)
+		}
+		expected, ok := want[change.Address]
+		require.Truef(t, ok, "unexpected address %s", change.Address)
+		assert.Equalf(t, big.NewInt(expected[0]), change.Delta, "delta of %s", change.Address)
+		assert.Equalf(t, big.NewInt(expected[1]), change.Credit, "credit of %s", change.Address)
+		assert.Equalf(t, big.NewInt(expected[2]), change.Debit, "debit of %s", change.Address)
+		assert.Equal(t, uint64(10), change.Height)
+		total.Add(total, change.Delta)
+	}
+	assert.Equal(t, int64(1000), total.Int64(), "only the genesis balance is minted")
+
+	fees := map[string]int64{"f0100": 6, "f0600": 5}
+	for _, change := range changes {
+		assert.Equal(t, big.NewInt(fees[change.Address]), change.FeesPaid)
+	}
+}
+
+func TestBuildBalanceChanges_InvalidFees(t *testing.T) {
+	txs := []*types.Transaction{
+		{Id: "fee", TxFrom: "f0100", TxType: parser.TotalFeeOp, TxMetadata: feesMetadata(t, "not-a-number", "0", "0")},
+	}
+	_, err := ledger.BuildBalanceChanges(&types.TxsParsedResult{Txs: txs}, "tipset", 10)
+	require.Error(t, err)
+}
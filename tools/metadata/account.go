@@ -0,0 +1,58 @@
+package metadata
+
+import (
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	since := tools.V0.NodeVersion()
+	register(manifest.AccountKey, parser.MethodSend, 1, since, func() Metadata { return &Send{} })
+	register(manifest.AccountKey, parser.MethodConstructor, 1, since, func() Metadata { return &AccountConstructor{} })
+	register(manifest.AccountKey, parser.MethodPubkeyAddress, 1, since, func() Metadata { return &PubkeyAddress{} })
+	register(manifest.AccountKey, parser.MethodFallback, 1, since, func() Metadata { return &Fallback{} })
+	// methods added with actors v9
+	since = tools.V17.NodeVersion()
+	register(manifest.AccountKey, parser.MethodAuthenticateMessage, 1, since, func() Metadata { return &AuthenticateMessage{} })
+	register(manifest.AccountKey, parser.MethodUniversalReceiverHook, 1, since, func() Metadata { return &AccountUniversalReceiverHook{} })
+	register(manifest.AccountKey, parser.MethodReceive, 1, since, func() Metadata { return &AccountUniversalReceiverHook{} })
+}
+
+type AccountConstructor struct {
+	Schema
+	// Params is the pubkey address of the account
+	Params string `json:"params" source:"Params"`
+}
+
+type PubkeyAddress struct {
+	Schema
+	// Params are the raw params, base64 encoded
+	Params string `json:"params,omitempty" source:"Params,optional"`
+	// Return is the pubkey address of the account
+	Return string `json:"return,omitempty" source:"Return,optional"`
+}
+
+type AuthenticateMessage struct {
+	Schema
+	Params struct {
+		// Signature and Message are base64 encoded
+		Signature string `json:"signature" source:"Signature"`
+		Message   string `json:"message" source:"Message"`
+	} `json:"params" source:"Params"`
+	Return *bool `json:"return,omitempty" source:"Return,optional"`
+}
+
+// AccountUniversalReceiverHook is shared by UniversalReceiverHook and Receive, its name in actors v9.
+type AccountUniversalReceiverHook struct {
+	Schema
+	// Params are the raw params of the hook, base64 encoded
+	Params string `json:"params,omitempty" source:"Params,optional"`
+}
+
+// Fallback is the metadata of the methods an actor does not implement.
+type Fallback struct {
+	Schema
+	// ParamsRaw are the raw params, hex encoded
+	ParamsRaw string `json:"params_raw,omitempty" source:"ParamsRaw,optional"`
+}
@@ -0,0 +1,239 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+	"github.com/zondax/fil-parser/tools/common"
+)
+
+// sourceTag is the struct tag listing the keys a field is read from in the raw metadata.
+// Keys are separated by '|' and tried in order, so fields renamed across go-state-types versions can list every name.
+// Fields are required unless the tag ends in ",optional". Fields without the tag are not read from the raw metadata,
+// except for embedded structs, whose fields are read from the same object.
+const sourceTag = "source"
+
+var (
+	bigIntType   = reflect.TypeOf(big.Int{})
+	cidType      = reflect.TypeOf(cid.Cid{})
+	bitfieldType = reflect.TypeOf(Bitfield{})
+)
+
+// Bitfield is a set of ids, such as sector numbers, encoded as a run-length bitfield in the raw metadata.
+type Bitfield []uint64
+
+// convert fills dst, a pointer to a struct, from the raw metadata following the source tags of its fields.
+func convert(raw map[string]interface{}, dst interface{}) error {
+	value := reflect.ValueOf(dst)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("destination must be a pointer to a struct, got %T", dst)
+	}
+	return convertStruct(raw, value.Elem(), "")
+}
+
+func convertStruct(raw map[string]interface{}, dst reflect.Value, path string) error {
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Type().Field(i)
+		tag, ok := field.Tag.Lookup(sourceTag)
+		if !ok && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			// embedded structs read their fields from the same object
+			if err := convertStruct(raw, dst.Field(i), path); err != nil {
+				return err
+			}
+			continue
+		}
+		if !ok || !field.IsExported() {
+			continue
+		}
+
+		keys, optional := parseSourceTag(tag)
+		item, key, found := lookup(raw, keys)
+		fieldPath := joinPath(path, keys[0])
+		if found {
+			fieldPath = joinPath(path, key)
+		}
+		if !found || item == nil {
+			if optional {
+				continue
+			}
+			return fmt.Errorf("key %s not found", fieldPath)
+		}
+
+		if err := convertValue(item, dst.Field(i), fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func convertValue(item interface{}, dst reflect.Value, path string) error {
+	switch dst.Type() {
+	case bigIntType:
+		value, err := toBigInt(item)
+		if err != nil {
+			return fmt.Errorf("key %s: %w", path, err)
+		}
+		dst.Set(reflect.ValueOf(*value))
+		return nil
+	case cidType:
+		value, err := toCid(item)
+		if err != nil {
+			return fmt.Errorf("key %s: %w", path, err)
+		}
+		dst.Set(reflect.ValueOf(value))
+		return nil
+	case bitfieldType:
+		value, err := toBitfield(item)
+		if err != nil {
+			return fmt.Errorf("key %s: %w", path, err)
+		}
+		dst.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+		if err := convertValue(item, elem.Elem(), path); err != nil {
+			return err
+		}
+		dst.Set(elem)
+	case reflect.Struct:
+		nested, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("key %s not of type object, of type %T", path, item)
+		}
+		return convertStruct(nested, dst, path)
+	case reflect.Slice:
+		items, ok := item.([]interface{})
+		if !ok {
+			return fmt.Errorf("key %s not of type array, of type %T", path, item)
+		}
+		slice := reflect.MakeSlice(dst.Type(), len(items), len(items))
+		for i, elem := range items {
+			if err := convertValue(elem, slice.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+	case reflect.String:
+		value, ok := item.(string)
+		if !ok {
+			return fmt.Errorf("key %s not of type string, of type %T", path, item)
+		}
+		dst.SetString(value)
+	case reflect.Bool:
+		value, ok := item.(bool)
+		if !ok {
+			return fmt.Errorf("key %s not of type bool, of type %T", path, item)
+		}
+		dst.SetBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := toBigInt(item)
+		if err != nil || !value.IsInt64() {
+			return fmt.Errorf("key %s not an integer: %v", path, item)
+		}
+		if dst.OverflowInt(value.Int64()) {
+			return fmt.Errorf("key %s overflows %s: %v", path, dst.Type(), item)
+		}
+		dst.SetInt(value.Int64())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := toBigInt(item)
+		if err != nil || !value.IsUint64() {
+			return fmt.Errorf("key %s not an unsigned integer: %v", path, item)
+		}
+		if dst.OverflowUint(value.Uint64()) {
+			return fmt.Errorf("key %s overflows %s: %v", path, dst.Type(), item)
+		}
+		dst.SetUint(value.Uint64())
+	case reflect.Map, reflect.Interface:
+		value := reflect.ValueOf(item)
+		if !value.Type().AssignableTo(dst.Type()) {
+			return fmt.Errorf("key %s not of type %s, of type %T", path, dst.Type(), item)
+		}
+		dst.Set(value)
+	default:
+		return fmt.Errorf("key %s: unsupported field type %s", path, dst.Type())
+	}
+	return nil
+}
+
+func parseSourceTag(tag string) ([]string, bool) {
+	name, options, _ := strings.Cut(tag, ",")
+	return strings.Split(name, "|"), options == "optional"
+}
+
+func lookup(raw map[string]interface{}, keys []string) (interface{}, string, bool) {
+	for _, key := range keys {
+		if item, ok := raw[key]; ok {
+			return item, key, true
+		}
+	}
+	return nil, "", false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// toBigInt accepts both json numbers and the decimal strings used to encode token amounts.
+func toBigInt(item interface{}) (*big.Int, error) {
+	var str string
+	switch value := item.(type) {
+	case json.Number:
+		str = value.String()
+	case string:
+		str = value
+	case float64:
+		str = strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return nil, fmt.Errorf("not a number, of type %T", item)
+	}
+	result, ok := new(big.Int).SetString(str, 10)
+	if !ok {
+		return nil, fmt.Errorf("failed to convert string %s to big.Int", str)
+	}
+	return result, nil
+}
+
+func toCid(item interface{}) (cid.Cid, error) {
+	switch value := item.(type) {
+	case string:
+		return cid.Decode(value)
+	case map[string]interface{}:
+		str, err := common.GetItem[string](value, "/", false)
+		if err != nil {
+			return cid.Cid{}, err
+		}
+		return cid.Decode(str)
+	}
+	return cid.Cid{}, fmt.Errorf("not a cid, of type %T", item)
+}
+
+func toBitfield(item interface{}) (Bitfield, error) {
+	items, ok := item.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("not a bitfield, of type %T", item)
+	}
+	runs := make([]int, len(items))
+	for i, run := range items {
+		value, err := toBigInt(run)
+		if err != nil || !value.IsInt64() {
+			return nil, fmt.Errorf("invalid bitfield run %v", run)
+		}
+		runs[i] = int(value.Int64())
+	}
+	ids, err := common.JsonEncodedBitfieldToIDs(runs)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
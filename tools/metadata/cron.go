@@ -0,0 +1,25 @@
+package metadata
+
+import (
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	since := tools.V0.NodeVersion()
+	register(manifest.CronKey, parser.MethodSend, 1, since, func() Metadata { return &Send{} })
+	register(manifest.CronKey, parser.MethodConstructor, 1, since, func() Metadata { return &CronConstructor{} })
+	register(manifest.CronKey, parser.MethodEpochTick, 1, since, func() Metadata { return &Empty{} })
+}
+
+type CronConstructor struct {
+	Schema
+	Params struct {
+		// Entries are the actor methods called on every epoch tick
+		Entries []struct {
+			Receiver  string `json:"receiver" source:"Receiver"`
+			MethodNum uint64 `json:"method_num" source:"MethodNum"`
+		} `json:"entries" source:"Entries,optional"`
+	} `json:"params" source:"Params"`
+}
@@ -0,0 +1,195 @@
+package metadata
+
+import (
+	"math/big"
+
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	// the datacap actor was introduced with actors v9
+	since := tools.V17.NodeVersion()
+	register(manifest.DatacapKey, parser.MethodSend, 1, since, func() Metadata { return &Send{} })
+	register(manifest.DatacapKey, parser.MethodConstructor, 1, since, func() Metadata { return &DatacapConstructor{} })
+	// the exported methods share the params and return of the builtin ones
+	methods := []struct {
+		names []string
+		new   func() Metadata
+	}{
+		{[]string{parser.MethodMint, parser.MethodMintExported}, func() Metadata { return &Mint{} }},
+		{[]string{parser.MethodDestroy, parser.MethodDestroyExported}, func() Metadata { return &Destroy{} }},
+		{[]string{parser.MethodTransfer, parser.MethodTransferExported}, func() Metadata { return &Transfer{} }},
+		{[]string{parser.MethodTransferFrom, parser.MethodTransferFromExported}, func() Metadata { return &TransferFrom{} }},
+		{[]string{parser.MethodIncreaseAllowance, parser.MethodIncreaseAllowanceExported}, func() Metadata { return &IncreaseAllowance{} }},
+		{[]string{parser.MethodDecreaseAllowance, parser.MethodDecreaseAllowanceExported}, func() Metadata { return &DecreaseAllowance{} }},
+		{[]string{parser.MethodRevokeAllowance, parser.MethodRevokeAllowanceExported}, func() Metadata { return &RevokeAllowance{} }},
+		{[]string{parser.MethodBurn, parser.MethodBurnExported}, func() Metadata { return &Burn{} }},
+		{[]string{parser.MethodBurnFrom, parser.MethodBurnFromExported}, func() Metadata { return &BurnFrom{} }},
+		{[]string{parser.MethodAllowance, parser.MethodAllowanceExported}, func() Metadata { return &Allowance{} }},
+		{[]string{parser.MethodBalanceOf, parser.MethodBalanceExported}, func() Metadata { return &Balance{} }},
+		{[]string{parser.MethodName, parser.MethodNameExported}, func() Metadata { return &Name{} }},
+		{[]string{parser.MethodSymbol, parser.MethodSymbolExported}, func() Metadata { return &Symbol{} }},
+		{[]string{parser.MethodTotalSupply, parser.MethodTotalSupplyExported}, func() Metadata { return &TotalSupply{} }},
+		{[]string{parser.MethodGranularityExported}, func() Metadata { return &Granularity{} }},
+	}
+	for _, method := range methods {
+		for _, name := range method.names {
+			register(manifest.DatacapKey, name, 1, since, method.new)
+		}
+	}
+}
+
+type DatacapConstructor struct {
+	Schema
+	// Params is the address of the governor, the verified registry
+	Params string `json:"params" source:"Params"`
+}
+
+type Mint struct {
+	Schema
+	Params struct {
+		To        string   `json:"to" source:"To"`
+		Amount    *big.Int `json:"amount" source:"Amount"`
+		Operators []string `json:"operators,omitempty" source:"Operators,optional"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		Balance *big.Int `json:"balance" source:"Balance"`
+		Supply  *big.Int `json:"supply" source:"Supply"`
+		// RecipientData is the base64 encoded return of the receiver hook of the recipient
+		RecipientData string `json:"recipient_data,omitempty" source:"RecipientData,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type Destroy struct {
+	Schema
+	Params struct {
+		Owner  string   `json:"owner" source:"Owner"`
+		Amount *big.Int `json:"amount" source:"Amount"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		Balance *big.Int `json:"balance" source:"Balance"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type Transfer struct {
+	Schema
+	Params struct {
+		To     string   `json:"to" source:"To"`
+		Amount *big.Int `json:"amount" source:"Amount"`
+		// OperatorData is base64 encoded and passed to the receiver hook of the recipient
+		OperatorData string `json:"operator_data,omitempty" source:"OperatorData,optional"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		FromBalance *big.Int `json:"from_balance" source:"FromBalance"`
+		ToBalance   *big.Int `json:"to_balance" source:"ToBalance"`
+		// RecipientData is the base64 encoded return of the receiver hook of the recipient
+		RecipientData string `json:"recipient_data,omitempty" source:"RecipientData,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type TransferFrom struct {
+	Schema
+	Params struct {
+		From   string   `json:"from" source:"From"`
+		To     string   `json:"to" source:"To"`
+		Amount *big.Int `json:"amount" source:"Amount"`
+		// OperatorData is base64 encoded and passed to the receiver hook of the recipient
+		OperatorData string `json:"operator_data,omitempty" source:"OperatorData,optional"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		FromBalance *big.Int `json:"from_balance" source:"FromBalance"`
+		ToBalance   *big.Int `json:"to_balance" source:"ToBalance"`
+		Allowance   *big.Int `json:"allowance" source:"Allowance"`
+		// RecipientData is the base64 encoded return of the receiver hook of the recipient
+		RecipientData string `json:"recipient_data,omitempty" source:"RecipientData,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type IncreaseAllowance struct {
+	Schema
+	Params struct {
+		Operator string   `json:"operator" source:"Operator"`
+		Increase *big.Int `json:"increase" source:"Increase"`
+	} `json:"params" source:"Params"`
+	// Return is the new allowance of the operator
+	Return *big.Int `json:"return,omitempty" source:"Return,optional"`
+}
+
+type DecreaseAllowance struct {
+	Schema
+	Params struct {
+		Operator string   `json:"operator" source:"Operator"`
+		Decrease *big.Int `json:"decrease" source:"Decrease"`
+	} `json:"params" source:"Params"`
+	// Return is the new allowance of the operator
+	Return *big.Int `json:"return,omitempty" source:"Return,optional"`
+}
+
+type RevokeAllowance struct {
+	Schema
+	Params struct {
+		Operator string `json:"operator" source:"Operator"`
+	} `json:"params" source:"Params"`
+	// Return is the allowance the operator had before being revoked
+	Return *big.Int `json:"return,omitempty" source:"Return,optional"`
+}
+
+type Burn struct {
+	Schema
+	Params struct {
+		Amount *big.Int `json:"amount" source:"Amount"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		Balance *big.Int `json:"balance" source:"Balance"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type BurnFrom struct {
+	Schema
+	Params struct {
+		Owner  string   `json:"owner" source:"Owner"`
+		Amount *big.Int `json:"amount" source:"Amount"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		Balance   *big.Int `json:"balance" source:"Balance"`
+		Allowance *big.Int `json:"allowance" source:"Allowance"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type Allowance struct {
+	Schema
+	Params struct {
+		Owner    string `json:"owner" source:"Owner"`
+		Operator string `json:"operator" source:"Operator"`
+	} `json:"params" source:"Params"`
+	Return *big.Int `json:"return,omitempty" source:"Return,optional"`
+}
+
+type Balance struct {
+	Schema
+	// Params is the address whose balance is queried
+	Params string   `json:"params" source:"Params"`
+	Return *big.Int `json:"return,omitempty" source:"Return,optional"`
+}
+
+type Name struct {
+	Schema
+	Return *string `json:"return,omitempty" source:"Return,optional"`
+}
+
+type Symbol struct {
+	Schema
+	Return *string `json:"return,omitempty" source:"Return,optional"`
+}
+
+type TotalSupply struct {
+	Schema
+	Return *big.Int `json:"return,omitempty" source:"Return,optional"`
+}
+
+type Granularity struct {
+	Schema
+	Return *uint64 `json:"return,omitempty" source:"Return,optional"`
+}
@@ -0,0 +1,54 @@
+package metadata
+
+import (
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	// the eam actor was introduced with actors v10
+	since := tools.V18.NodeVersion()
+	register(manifest.EamKey, parser.MethodSend, 1, since, func() Metadata { return &Send{} })
+	register(manifest.EamKey, parser.MethodConstructor, 1, since, func() Metadata { return &Empty{} })
+	register(manifest.EamKey, parser.MethodCreate, 1, since, func() Metadata { return &Create{} })
+	register(manifest.EamKey, parser.MethodCreate2, 1, since, func() Metadata { return &Create2{} })
+	register(manifest.EamKey, parser.MethodCreateExternal, 1, since, func() Metadata { return &CreateExternal{} })
+}
+
+// EamCreateReturn is the evm actor created by the eam.
+type EamCreateReturn struct {
+	ActorID       uint64 `json:"actor_id" source:"ActorId"`
+	RobustAddress string `json:"robust_address,omitempty" source:"RobustAddress,optional"`
+	EthAddress    string `json:"eth_address" source:"EthAddress"`
+}
+
+type Create struct {
+	Schema
+	Params struct {
+		// Initcode is the base64 encoded evm bytecode
+		Initcode string `json:"initcode" source:"Initcode"`
+		Nonce    uint64 `json:"nonce" source:"Nonce"`
+	} `json:"params" source:"Params"`
+	Return  *EamCreateReturn `json:"return,omitempty" source:"Return,optional"`
+	EthHash string           `json:"eth_hash,omitempty" source:"ethHash,optional"`
+}
+
+type Create2 struct {
+	Schema
+	Params struct {
+		// Initcode is the base64 encoded evm bytecode
+		Initcode string `json:"initcode" source:"Initcode"`
+		Salt     []byte `json:"salt" source:"Salt"`
+	} `json:"params" source:"Params"`
+	Return  *EamCreateReturn `json:"return,omitempty" source:"Return,optional"`
+	EthHash string           `json:"eth_hash,omitempty" source:"ethHash,optional"`
+}
+
+type CreateExternal struct {
+	Schema
+	// Params is the 0x prefixed hex encoded evm bytecode
+	Params  string           `json:"params" source:"Params"`
+	Return  *EamCreateReturn `json:"return,omitempty" source:"Return,optional"`
+	EthHash string           `json:"eth_hash,omitempty" source:"ethHash,optional"`
+}
@@ -0,0 +1,19 @@
+package metadata
+
+import (
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	// the ethaccount actor was introduced with actors v10
+	since := tools.V18.NodeVersion()
+	register(manifest.EthAccountKey, parser.MethodSend, 1, since, func() Metadata { return &Send{} })
+	register(manifest.EthAccountKey, parser.MethodFallback, 1, since, func() Metadata { return &Fallback{} })
+	register(manifest.EthAccountKey, parser.MethodConstructor, 1, since, func() Metadata { return &Empty{} })
+	register(manifest.EthAccountKey, parser.MethodValueTransfer, 1, since, func() Metadata { return &Empty{} })
+	// the miner methods an eth account can call on itself are parsed with the miner layouts
+	register(manifest.EthAccountKey, parser.MethodChangeOwnerAddressExported, 1, since, func() Metadata { return &ChangeOwnerAddress{} })
+	register(manifest.EthAccountKey, parser.MethodChangeMultiaddrsExported, 1, since, func() Metadata { return &ChangeMultiaddrs{} })
+}
@@ -0,0 +1,101 @@
+package metadata
+
+import (
+	"math/big"
+
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/ipfs/go-cid"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	// the evm actor was introduced with actors v10
+	since := tools.V18.NodeVersion()
+	register(manifest.EvmKey, parser.MethodSend, 1, since, func() Metadata { return &Send{} })
+	register(manifest.EvmKey, parser.MethodConstructor, 1, since, func() Metadata { return &EvmConstructor{} })
+	register(manifest.EvmKey, parser.MethodResurrect, 1, since, func() Metadata { return &EvmConstructor{} })
+	register(manifest.EvmKey, parser.MethodInvokeContract, 1, since, func() Metadata { return &InvokeContract{} })
+	register(manifest.EvmKey, parser.MethodInvokeContractReadOnly, 1, since, func() Metadata { return &InvokeContract{} })
+	register(manifest.EvmKey, parser.MethodInvokeContractDelegate, 1, since, func() Metadata { return &InvokeContractDelegate{} })
+	register(manifest.EvmKey, parser.MethodGetBytecode, 1, since, func() Metadata { return &GetBytecode{} })
+	register(manifest.EvmKey, parser.MethodGetBytecodeHash, 1, since, func() Metadata { return &GetBytecodeHash{} })
+	register(manifest.EvmKey, parser.MethodGetStorageAt, 1, since, func() Metadata { return &GetStorageAt{} })
+	register(manifest.EvmKey, parser.MethodHandleFilecoinMethod, 1, since, func() Metadata { return &HandleFilecoinMethod{} })
+	register(manifest.EvmKey, parser.MethodValueTransfer, 1, since, func() Metadata { return &Empty{} })
+	// the miner methods a contract can call on itself are parsed with the miner layouts
+	register(manifest.EvmKey, parser.MethodChangeOwnerAddressExported, 1, since, func() Metadata { return &ChangeOwnerAddress{} })
+	register(manifest.EvmKey, parser.MethodChangeMultiaddrsExported, 1, since, func() Metadata { return &ChangeMultiaddrs{} })
+}
+
+// EvmConstructor is shared by Constructor and Resurrect.
+type EvmConstructor struct {
+	Schema
+	Params struct {
+		// Creator is the eth address of the creator, as a list of its 20 bytes
+		Creator []uint8 `json:"creator" source:"Creator"`
+		// Initcode is the base64 encoded evm bytecode
+		Initcode string `json:"initcode,omitempty" source:"Initcode,optional"`
+	} `json:"params" source:"Params"`
+}
+
+// InvokeContract is shared by InvokeContract and InvokeContractReadOnly.
+type InvokeContract struct {
+	Schema
+	// Params and Return are the 0x prefixed hex encoded calldata and output of the contract call
+	Params string `json:"params,omitempty" source:"Params,optional"`
+	Return string `json:"return,omitempty" source:"Return,optional"`
+}
+
+type InvokeContractDelegate struct {
+	Schema
+	Params struct {
+		Code cid.Cid `json:"code" source:"Code"`
+		// Input is the base64 encoded calldata
+		Input string `json:"input,omitempty" source:"Input,optional"`
+		// Caller is the eth address of the caller, as a list of its 20 bytes
+		Caller []uint8  `json:"caller" source:"Caller"`
+		Value  *big.Int `json:"value" source:"Value"`
+	} `json:"params" source:"Params"`
+	// Return is the base64 encoded output of the call
+	Return string `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetBytecode struct {
+	Schema
+	Return *struct {
+		// Cid is nil when the contract has no bytecode
+		Cid *cid.Cid `json:"cid,omitempty" source:"Cid,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetBytecodeHash struct {
+	Schema
+	// Return is the base64 encoded keccak256 hash of the bytecode
+	Return string `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetStorageAt struct {
+	Schema
+	Params struct {
+		// StorageKey is the 32 bytes storage slot
+		StorageKey []uint8 `json:"storage_key" source:"StorageKey"`
+	} `json:"params" source:"Params"`
+	// Return is the base64 encoded value of the slot
+	Return string `json:"return,omitempty" source:"Return,optional"`
+}
+
+// HandleFilecoinMethod is a builtin actor method called on a contract. The params and return are decoded when they are
+// cbor, and kept hex encoded in ParamsRaw and ReturnRaw otherwise.
+type HandleFilecoinMethod struct {
+	Schema
+	Params *struct {
+		Method uint64      `json:"method" source:"Method"`
+		Args   interface{} `json:"args,omitempty" source:"Args,optional"`
+	} `json:"params,omitempty" source:"Params,optional"`
+	Return *struct {
+		Result interface{} `json:"result,omitempty" source:"Result,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+	ParamsRaw string `json:"params_raw,omitempty" source:"ParamsRaw,optional"`
+	ReturnRaw string `json:"return_raw,omitempty" source:"ReturnRaw,optional"`
+}
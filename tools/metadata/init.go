@@ -0,0 +1,52 @@
+package metadata
+
+import (
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	register(manifest.InitKey, parser.MethodSend, 1, tools.V0.NodeVersion(), func() Metadata { return &Send{} })
+	register(manifest.InitKey, parser.MethodConstructor, 1, tools.V0.NodeVersion(), func() Metadata { return &InitConstructor{} })
+	register(manifest.InitKey, parser.MethodExec, 1, tools.V0.NodeVersion(), func() Metadata { return &Exec{} })
+	register(manifest.InitKey, parser.MethodExec4, 1, tools.V18.NodeVersion(), func() Metadata { return &Exec4{} })
+}
+
+// CreatedActor is the AddressInfo the parser stores as the return of the methods creating an actor.
+type CreatedActor struct {
+	IDAddress     string `json:"id_address" source:"short"`
+	RobustAddress string `json:"robust_address" source:"robust"`
+	ActorCid      string `json:"actor_cid,omitempty" source:"actor_cid,optional"`
+	ActorType     string `json:"actor_type,omitempty" source:"actor_type,optional"`
+}
+
+type InitConstructor struct {
+	Schema
+	Params struct {
+		NetworkName string `json:"network_name" source:"NetworkName"`
+	} `json:"params" source:"Params"`
+}
+
+type ExecParams struct {
+	CodeCid string `json:"code_cid" source:"CodeCid"`
+	// ConstructorParams are the raw cbor params, base64 encoded
+	ConstructorParams string `json:"constructor_params" source:"constructorParams"`
+	// DecodedConstructorParams are the constructor params as parsed by the created actor, nil when they could not be parsed
+	DecodedConstructorParams interface{} `json:"decoded_constructor_params,omitempty" source:"decodedConstructorParams,optional"`
+}
+
+type Exec struct {
+	Schema
+	Params ExecParams    `json:"params" source:"Params"`
+	Return *CreatedActor `json:"return,omitempty" source:"Return,optional"`
+}
+
+type Exec4 struct {
+	Schema
+	Params struct {
+		ExecParams
+		SubAddress string `json:"sub_address" source:"subAddress"`
+	} `json:"params" source:"Params"`
+	Return *CreatedActor `json:"return,omitempty" source:"Return,optional"`
+}
@@ -0,0 +1,354 @@
+package metadata
+
+import (
+	"math/big"
+
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/ipfs/go-cid"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	since := tools.V0.NodeVersion()
+	// the exported methods were added with actors v10 and share the params and return of the builtin ones
+	exported := tools.V18.NodeVersion()
+	register(manifest.MarketKey, parser.MethodSend, 1, since, func() Metadata { return &Send{} })
+	register(manifest.MarketKey, parser.MethodConstructor, 1, since, func() Metadata { return &Empty{} })
+	register(manifest.MarketKey, parser.MethodCronTick, 1, since, func() Metadata { return &Empty{} })
+	register(manifest.MarketKey, parser.MethodAddBalance, 1, since, func() Metadata { return &AddBalance{} })
+	register(manifest.MarketKey, parser.MethodAddBalanceExported, 1, exported, func() Metadata { return &AddBalance{} })
+	register(manifest.MarketKey, parser.MethodWithdrawBalance, 1, since, func() Metadata { return &MarketWithdrawBalance{} })
+	register(manifest.MarketKey, parser.MethodWithdrawBalanceExported, 1, exported, func() Metadata { return &MarketWithdrawBalance{} })
+
+	register(manifest.MarketKey, parser.MethodPublishStorageDeals, 1, since, func() Metadata { return &PublishStorageDeals{} })
+	// actors v6 returned the indexes of the valid deals
+	register(manifest.MarketKey, parser.MethodPublishStorageDeals, 2, tools.V14.NodeVersion(), func() Metadata { return &PublishStorageDeals{} })
+	register(manifest.MarketKey, parser.MethodPublishStorageDealsExported, 2, exported, func() Metadata { return &PublishStorageDeals{} })
+
+	register(manifest.MarketKey, parser.MethodVerifyDealsForActivation, 1, since, func() Metadata { return &VerifyDealsForActivation{} })
+	// specs-actors v2 returned the deal space
+	register(manifest.MarketKey, parser.MethodVerifyDealsForActivation, 2, tools.V4.NodeVersion(), func() Metadata { return &VerifyDealsForActivation{} })
+	// specs-actors v3 verified the deals of several sectors at once
+	register(manifest.MarketKey, parser.MethodVerifyDealsForActivation, 3, tools.V10.NodeVersion(), func() Metadata { return &VerifyDealsForActivation{} })
+	// actors v9 added the sector type and returned the unsealed cid instead of the deal weights
+	register(manifest.MarketKey, parser.MethodVerifyDealsForActivation, 4, tools.V17.NodeVersion(), func() Metadata { return &VerifyDealsForActivation{} })
+	// actors v12 returned the unsealed cids as a list
+	register(manifest.MarketKey, parser.MethodVerifyDealsForActivation, 5, tools.V21.NodeVersion(), func() Metadata { return &VerifyDealsForActivation{} })
+	// actors v13 added the sector number
+	register(manifest.MarketKey, parser.MethodVerifyDealsForActivation, 6, tools.V22.NodeVersion(), func() Metadata { return &VerifyDealsForActivation{} })
+
+	register(manifest.MarketKey, parser.MethodActivateDeals, 1, since, func() Metadata { return &ActivateDeals{} })
+	// actors v9 returned the space and verified infos of the activated deals
+	register(manifest.MarketKey, parser.MethodActivateDeals, 2, tools.V17.NodeVersion(), func() Metadata { return &ActivateDeals{} })
+	// actors v11 activated the deals of several sectors at once
+	register(manifest.MarketKey, parser.MethodActivateDeals, 3, tools.V20.NodeVersion(), func() Metadata { return &ActivateDeals{} })
+
+	register(manifest.MarketKey, parser.MethodOnMinerSectorsTerminate, 1, since, func() Metadata { return &OnMinerSectorsTerminate{} })
+	// actors v13 terminates sectors instead of deals, see OnMinerSectorsTerminate
+	register(manifest.MarketKey, parser.MethodOnMinerSectorsTerminate, 2, tools.V22.NodeVersion(), func() Metadata { return &OnMinerSectorsTerminate{} })
+
+	register(manifest.MarketKey, parser.MethodComputeDataCommitment, 1, since, func() Metadata { return &ComputeDataCommitment{} })
+	// actors v5 computed the commitments of several sectors at once
+	register(manifest.MarketKey, parser.MethodComputeDataCommitment, 2, tools.V13.NodeVersion(), func() Metadata { return &ComputeDataCommitment{} })
+
+	register(manifest.MarketKey, parser.MethodGetBalance, 1, exported, func() Metadata { return &GetBalance{} })
+	register(manifest.MarketKey, parser.MethodGetDealDataCommitment, 1, exported, func() Metadata { return &GetDealDataCommitment{} })
+	register(manifest.MarketKey, parser.MethodGetDealClient, 1, exported, func() Metadata { return &GetDealClient{} })
+	register(manifest.MarketKey, parser.MethodGetDealProvider, 1, exported, func() Metadata { return &GetDealProvider{} })
+	register(manifest.MarketKey, parser.MethodGetDealLabel, 1, exported, func() Metadata { return &GetDealLabel{} })
+	register(manifest.MarketKey, parser.MethodGetDealTerm, 1, exported, func() Metadata { return &GetDealTerm{} })
+	register(manifest.MarketKey, parser.MethodGetDealTotalPrice, 1, exported, func() Metadata { return &GetDealTotalPrice{} })
+	register(manifest.MarketKey, parser.MethodGetDealClientCollateral, 1, exported, func() Metadata { return &GetDealClientCollateral{} })
+	register(manifest.MarketKey, parser.MethodGetDealProviderCollateral, 1, exported, func() Metadata { return &GetDealProviderCollateral{} })
+	register(manifest.MarketKey, parser.MethodGetDealVerified, 1, exported, func() Metadata { return &GetDealVerified{} })
+	register(manifest.MarketKey, parser.MethodGetDealActivation, 1, exported, func() Metadata { return &GetDealActivation{} })
+
+	// methods added with actors v13
+	since = tools.V22.NodeVersion()
+	register(manifest.MarketKey, parser.MethodGetDealSectorExported, 1, since, func() Metadata { return &GetDealSector{} })
+	register(manifest.MarketKey, parser.MethodSettleDealPaymentsExported, 1, since, func() Metadata { return &SettleDealPayments{} })
+	register(manifest.MarketKey, parser.MethodSectorContentChanged, 1, since, func() Metadata { return &SectorContentChanged{} })
+}
+
+type AddBalance struct {
+	Schema
+	// Params is the address of the client or provider whose escrow is credited
+	Params string `json:"params" source:"Params"`
+}
+
+type MarketWithdrawBalance struct {
+	Schema
+	Params struct {
+		ProviderOrClientAddress string   `json:"provider_or_client_address" source:"ProviderOrClientAddress"`
+		Amount                  *big.Int `json:"amount" source:"Amount"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		AmountWithdrawn *big.Int `json:"amount_withdrawn,omitempty" source:"AmountWithdrawn,optional"`
+		// Raw is the base64 encoded return, kept when it is not the amount withdrawn
+		Raw string `json:"raw,omitempty" source:"Raw,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+// Signature is a signature and its type, base64 encoded.
+type Signature struct {
+	Type int64  `json:"type" source:"Type"`
+	Data string `json:"data" source:"Data,optional"`
+}
+
+type DealProposal struct {
+	PieceCID             cid.Cid  `json:"piece_cid" source:"PieceCID"`
+	PieceSize            uint64   `json:"piece_size" source:"PieceSize"`
+	VerifiedDeal         bool     `json:"verified_deal" source:"VerifiedDeal"`
+	Client               string   `json:"client" source:"Client"`
+	Provider             string   `json:"provider" source:"Provider"`
+	Label                string   `json:"label" source:"Label,optional"`
+	StartEpoch           int64    `json:"start_epoch" source:"StartEpoch"`
+	EndEpoch             int64    `json:"end_epoch" source:"EndEpoch"`
+	StoragePricePerEpoch *big.Int `json:"storage_price_per_epoch" source:"StoragePricePerEpoch"`
+	ProviderCollateral   *big.Int `json:"provider_collateral" source:"ProviderCollateral"`
+	ClientCollateral     *big.Int `json:"client_collateral" source:"ClientCollateral"`
+}
+
+type PublishStorageDeals struct {
+	Schema
+	Params struct {
+		Deals []struct {
+			Proposal        DealProposal `json:"proposal" source:"Proposal"`
+			ClientSignature Signature    `json:"client_signature" source:"ClientSignature"`
+		} `json:"deals" source:"Deals"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		IDs []uint64 `json:"ids" source:"IDs"`
+		// ValidDeals are the indexes of the published deals in the params, only returned from schema version 2
+		ValidDeals Bitfield `json:"valid_deals,omitempty" source:"ValidDeals,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+// SectorDeals are the deals to verify or activate in a sector.
+type SectorDeals struct {
+	// SectorNumber is only sent from actors v13
+	SectorNumber *uint64 `json:"sector_number,omitempty" source:"SectorNumber,optional"`
+	// SectorType is only sent from actors v9
+	SectorType   *int64   `json:"sector_type,omitempty" source:"SectorType,optional"`
+	SectorExpiry int64    `json:"sector_expiry" source:"SectorExpiry"`
+	DealIDs      []uint64 `json:"deal_ids" source:"DealIDs,optional"`
+}
+
+// VerifyDealsForActivation changed its layout with most actors versions, the fields missing in a schema version are left empty:
+//   - schema version 1 and 2 verify the deals of a single sector and return their weights, adding the deal space in version 2
+//   - schema version 3 and 4 verify the deals of several sectors, returning their weights in version 3 and their unsealed cid in version 4
+//   - schema version 5 and 6 return the unsealed cids as a list, sending the sector number in version 6
+type VerifyDealsForActivation struct {
+	Schema
+	Params struct {
+		DealIDs      []uint64      `json:"deal_ids,omitempty" source:"DealIDs,optional"`
+		SectorStart  *int64        `json:"sector_start,omitempty" source:"SectorStart,optional"`
+		SectorExpiry *int64        `json:"sector_expiry,omitempty" source:"SectorExpiry,optional"`
+		Sectors      []SectorDeals `json:"sectors,omitempty" source:"Sectors,optional"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		DealWeight         *big.Int `json:"deal_weight,omitempty" source:"DealWeight,optional"`
+		VerifiedDealWeight *big.Int `json:"verified_deal_weight,omitempty" source:"VerifiedDealWeight,optional"`
+		DealSpace          *uint64  `json:"deal_space,omitempty" source:"DealSpace,optional"`
+		Sectors            []struct {
+			DealWeight         *big.Int `json:"deal_weight,omitempty" source:"DealWeight,optional"`
+			VerifiedDealWeight *big.Int `json:"verified_deal_weight,omitempty" source:"VerifiedDealWeight,optional"`
+			DealSpace          *uint64  `json:"deal_space,omitempty" source:"DealSpace,optional"`
+			CommD              *cid.Cid `json:"comm_d,omitempty" source:"CommD,optional"`
+		} `json:"sectors,omitempty" source:"Sectors,optional"`
+		UnsealedCIDs []*cid.Cid `json:"unsealed_cids,omitempty" source:"UnsealedCIDs,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+// VerifiedDealInfo is the datacap allocation claimed by an activated deal.
+type VerifiedDealInfo struct {
+	Client       uint64  `json:"client" source:"Client"`
+	AllocationID uint64  `json:"allocation_id" source:"AllocationId"`
+	Data         cid.Cid `json:"data" source:"Data"`
+	Size         uint64  `json:"size" source:"Size"`
+}
+
+type DealActivation struct {
+	NonVerifiedDealSpace *big.Int           `json:"non_verified_deal_space" source:"NonVerifiedDealSpace"`
+	VerifiedInfos        []VerifiedDealInfo `json:"verified_infos,omitempty" source:"VerifiedInfos,optional"`
+}
+
+// ActivateDeals activates the deals of a single sector in schema versions 1 and 2, returning the activation from version 2,
+// and the deals of several sectors from schema version 3.
+type ActivateDeals struct {
+	Schema
+	Params struct {
+		DealIDs      []uint64      `json:"deal_ids,omitempty" source:"DealIDs,optional"`
+		SectorExpiry *int64        `json:"sector_expiry,omitempty" source:"SectorExpiry,optional"`
+		Sectors      []SectorDeals `json:"sectors,omitempty" source:"Sectors,optional"`
+		ComputeCID   bool          `json:"compute_cid,omitempty" source:"ComputeCID,optional"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		// NonVerifiedDealSpace and VerifiedInfos are the activation of the single sector of schema version 2
+		NonVerifiedDealSpace *big.Int           `json:"non_verified_deal_space,omitempty" source:"NonVerifiedDealSpace,optional"`
+		VerifiedInfos        []VerifiedDealInfo `json:"verified_infos,omitempty" source:"VerifiedInfos,optional"`
+		// ActivationResults and Activations are the activations of the sectors from schema version 3
+		ActivationResults *BatchReturn     `json:"activation_results,omitempty" source:"ActivationResults,optional"`
+		Activations       []DealActivation `json:"activations,omitempty" source:"Activations,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type OnMinerSectorsTerminate struct {
+	Schema
+	Params struct {
+		Epoch int64 `json:"epoch" source:"Epoch"`
+		// DealIDs are the deals terminated, only sent in schema version 1
+		DealIDs []uint64 `json:"deal_ids,omitempty" source:"DealIDs,optional"`
+		// SectorNumbers are the sectors terminated, only sent from schema version 2. The market terminates the deals
+		// activated in them. SectorBitField is the same set as it is decoded before the parser falls back to the list.
+		SectorNumbers  []uint64 `json:"sector_numbers,omitempty" source:"SectorNumbers,optional"`
+		SectorBitField Bitfield `json:"sector_bitfield,omitempty" source:"SectorBitField,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type ComputeDataCommitment struct {
+	Schema
+	Params struct {
+		// DealIDs and SectorType are only sent in schema version 1
+		DealIDs    []uint64 `json:"deal_ids,omitempty" source:"DealIDs,optional"`
+		SectorType *int64   `json:"sector_type,omitempty" source:"SectorType,optional"`
+		// Inputs are only sent from schema version 2
+		Inputs []struct {
+			DealIDs    []uint64 `json:"deal_ids" source:"DealIDs,optional"`
+			SectorType int64    `json:"sector_type" source:"SectorType"`
+		} `json:"inputs,omitempty" source:"Inputs,optional"`
+	} `json:"params" source:"Params"`
+	// the returned commitments are not kept, as the parser encodes them as empty objects
+}
+
+type GetBalance struct {
+	Schema
+	// Params is the address of the client or provider queried
+	Params string `json:"params" source:"Params"`
+	Return *struct {
+		Balance *big.Int `json:"balance" source:"Balance"`
+		Locked  *big.Int `json:"locked" source:"Locked"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+// the deal getters take the id of the deal queried as params
+
+type GetDealDataCommitment struct {
+	Schema
+	Params uint64 `json:"params" source:"Params"`
+	Return *struct {
+		Data cid.Cid `json:"data" source:"Data"`
+		Size uint64  `json:"size" source:"Size"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetDealClient struct {
+	Schema
+	Params uint64 `json:"params" source:"Params"`
+	// Return is the actor id of the client
+	Return *uint64 `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetDealProvider struct {
+	Schema
+	Params uint64 `json:"params" source:"Params"`
+	// Return is the actor id of the provider
+	Return *uint64 `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetDealLabel struct {
+	Schema
+	Params uint64 `json:"params" source:"Params"`
+	// Return is empty when the label is not a string
+	Return *string `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetDealTerm struct {
+	Schema
+	Params uint64 `json:"params" source:"Params"`
+	Return *struct {
+		Start    int64 `json:"start" source:"Start"`
+		Duration int64 `json:"duration" source:"Duration"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetDealTotalPrice struct {
+	Schema
+	Params uint64   `json:"params" source:"Params"`
+	Return *big.Int `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetDealClientCollateral struct {
+	Schema
+	Params uint64   `json:"params" source:"Params"`
+	Return *big.Int `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetDealProviderCollateral struct {
+	Schema
+	Params uint64   `json:"params" source:"Params"`
+	Return *big.Int `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetDealVerified struct {
+	Schema
+	Params uint64 `json:"params" source:"Params"`
+	Return *bool  `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetDealActivation struct {
+	Schema
+	Params uint64 `json:"params" source:"Params"`
+	// the epochs are -1 while the deal is not activated or terminated
+	Return *struct {
+		Activated  int64 `json:"activated" source:"Activated"`
+		Terminated int64 `json:"terminated" source:"Terminated"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetDealSector struct {
+	Schema
+	Params *uint64 `json:"params,omitempty" source:"Params,optional"`
+	// Return is the sector of the deal, which the parser writes as a decimal string
+	Return *uint64 `json:"return,omitempty" source:"Return,optional"`
+}
+
+// BatchReturn reports which items of a batch failed. Items not listed in FailCodes succeeded.
+type BatchReturn struct {
+	SuccessCount uint64 `json:"success_count" source:"SuccessCount"`
+	FailCodes    []struct {
+		Idx  uint64 `json:"idx" source:"Idx"`
+		Code int64  `json:"code" source:"Code"`
+	} `json:"fail_codes" source:"FailCodes,optional"`
+}
+
+type SettleDealPayments struct {
+	Schema
+	// Params are the ids of the deals to settle
+	Params Bitfield `json:"params" source:"Params"`
+	Return *struct {
+		Results     BatchReturn `json:"results" source:"Results"`
+		Settlements []struct {
+			Payment   *big.Int `json:"payment" source:"Payment"`
+			Completed bool     `json:"completed" source:"Completed"`
+		} `json:"settlements" source:"Settlements,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type SectorContentChanged struct {
+	Schema
+	// Params are the pieces added to each sector, sent by the miner to notify the market
+	Params []struct {
+		Sector                 uint64 `json:"sector" source:"Sector"`
+		MinimumCommitmentEpoch int64  `json:"minimum_commitment_epoch" source:"MinimumCommitmentEpoch"`
+		Added                  []struct {
+			Data cid.Cid `json:"data" source:"Data"`
+			Size uint64  `json:"size" source:"Size"`
+			// Payload is base64 encoded
+			Payload string `json:"payload,omitempty" source:"Payload,optional"`
+		} `json:"added" source:"Added,optional"`
+	} `json:"params" source:"Params"`
+	// Return tells whether each piece was accepted. The parser only decodes the pieces of the first sector.
+	Return []bool `json:"return,omitempty" source:"Return,optional"`
+}
@@ -0,0 +1,150 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/types"
+)
+
+var ErrUnsupportedMethod = errors.New("no typed metadata for method")
+
+// Schema identifies the layout of a typed metadata struct.
+// Version is bumped whenever the layout changes, so consumers can tell which fields to expect.
+type Schema struct {
+	Actor   string `json:"actor"`
+	Method  string `json:"method"`
+	Version int    `json:"schema_version"`
+}
+
+func (s Schema) GetSchema() Schema {
+	return s
+}
+
+func (s *Schema) setSchema(schema Schema) {
+	*s = schema
+}
+
+// Metadata is the typed counterpart of Transaction.TxMetadata for a given actor method.
+// Every implementation embeds Schema.
+type Metadata interface {
+	GetSchema() Schema
+	setSchema(schema Schema)
+}
+
+// Send is the metadata of a plain value transfer, accepted by every actor.
+type Send struct {
+	Schema
+	// Params are the raw params of the message, base64 encoded
+	Params string `json:"params,omitempty" source:"Params,optional"`
+}
+
+// Empty is the metadata of the methods without params nor return, such as the cron ticks.
+type Empty struct {
+	Schema
+}
+
+// schemaEntry is a metadata layout used from a given network version onwards.
+type schemaEntry struct {
+	version int
+	since   uint
+	new     func() Metadata
+}
+
+var registry = map[string][]schemaEntry{}
+
+// register adds the layout of an actor method used from the since network version onwards.
+func register(actor, method string, version int, since uint, newMetadata func() Metadata) {
+	key := registryKey(actor, method)
+	entries := append(registry[key], schemaEntry{version: version, since: since, new: newMetadata})
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].since < entries[j].since
+	})
+	registry[key] = entries
+}
+
+func registryKey(actor, method string) string {
+	return fmt.Sprintf("%s/%s", actor, method)
+}
+
+// actorKey strips the actor name down to its manifest key, so both "storageminer" and "fil/14/storageminer" are accepted.
+func actorKey(actorName string) string {
+	parts := strings.Split(actorName, "/")
+	return parts[len(parts)-1]
+}
+
+// Supported returns true if the actor method has typed metadata.
+func Supported(actorName, method string) bool {
+	_, ok := registry[registryKey(actorKey(actorName), method)]
+	return ok
+}
+
+// Decoder turns the free-form Transaction.TxMetadata into the typed metadata of its actor method.
+type Decoder struct {
+	network string
+}
+
+func NewDecoder(network string) *Decoder {
+	return &Decoder{network: network}
+}
+
+// Decode returns the typed metadata of the actor method at the given height.
+// It fails when a required field is missing or has an unexpected type, instead of leaving it empty.
+func (d *Decoder) Decode(actorName, method string, height int64, txMetadata string) (Metadata, error) {
+	actor := actorKey(actorName)
+	entries, ok := registry[registryKey(actor, method)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s", ErrUnsupportedMethod, actor, method)
+	}
+
+	networkVersion := tools.VersionFromHeight(d.network, height).NodeVersion()
+	var entry *schemaEntry
+	for i := range entries {
+		if entries[i].since <= networkVersion {
+			entry = &entries[i]
+		}
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%w: %s %s at height %d", ErrUnsupportedMethod, actor, method, height)
+	}
+
+	raw := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(txMetadata)))
+	// keep numbers as json.Number, as float64 can't hold every epoch, id or token amount
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("error unmarshalling tx metadata: %w", err)
+	}
+
+	metadata := entry.new()
+	if err := convert(raw, metadata); err != nil {
+		return nil, fmt.Errorf("error decoding %s %s metadata: %w", actor, method, err)
+	}
+	metadata.setSchema(Schema{Actor: actor, Method: method, Version: entry.version})
+	return metadata, nil
+}
+
+// DecodeTx decodes the metadata of tx, which was sent to an actor of the given name.
+func (d *Decoder) DecodeTx(actorName string, tx *types.Transaction) (Metadata, error) {
+	// #nosec G115
+	return d.Decode(actorName, tx.TxType, int64(tx.Height), tx.TxMetadata)
+}
+
+// DecodeAs decodes the metadata of tx into T, failing if the method decodes into a different type.
+func DecodeAs[T Metadata](d *Decoder, actorName string, tx *types.Transaction) (T, error) {
+	var zero T
+	metadata, err := d.DecodeTx(actorName, tx)
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := metadata.(T)
+	if !ok {
+		return zero, fmt.Errorf("metadata of %s is %T, not %T", tx.TxType, metadata, zero)
+	}
+	return typed, nil
+}
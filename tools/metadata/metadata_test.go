@@ -0,0 +1,465 @@
+package metadata_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	builtinActors "github.com/filecoin-project/go-state-types/actors"
+	stateBig "github.com/filecoin-project/go-state-types/big"
+	datacap15 "github.com/filecoin-project/go-state-types/builtin/v15/datacap"
+	eam15 "github.com/filecoin-project/go-state-types/builtin/v15/eam"
+	init15 "github.com/filecoin-project/go-state-types/builtin/v15/init"
+	market15 "github.com/filecoin-project/go-state-types/builtin/v15/market"
+	multisig15 "github.com/filecoin-project/go-state-types/builtin/v15/multisig"
+	paych15 "github.com/filecoin-project/go-state-types/builtin/v15/paych"
+	power15 "github.com/filecoin-project/go-state-types/builtin/v15/power"
+	reward15 "github.com/filecoin-project/go-state-types/builtin/v15/reward"
+	miner16 "github.com/filecoin-project/go-state-types/builtin/v16/miner"
+	miner8 "github.com/filecoin-project/go-state-types/builtin/v8/miner"
+	"github.com/filecoin-project/go-state-types/crypto"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/manifest"
+	filApiTypes "github.com/filecoin-project/lotus/api/types"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+	power0 "github.com/filecoin-project/specs-actors/actors/builtin/power"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	typegen "github.com/whyrusleeping/cbor-gen"
+	"github.com/zondax/fil-parser/actors"
+	actorsV2 "github.com/zondax/fil-parser/actors/v2"
+	marketTypes "github.com/zondax/fil-parser/actors/v2/market/types"
+	"github.com/zondax/fil-parser/actors/v2/schema"
+	filMetrics "github.com/zondax/fil-parser/metrics"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/parser/helper"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/metadata"
+	"github.com/zondax/fil-parser/tools/mocks"
+	"github.com/zondax/fil-parser/types"
+	"github.com/zondax/golem/pkg/logger"
+	metrics2 "github.com/zondax/golem/pkg/metrics"
+	rosettaFilecoinLib "github.com/zondax/rosetta-filecoin-lib"
+)
+
+const (
+	msgCidStr  = "bafy2bzacebbpdegvr3i4cosewthysg5xkxpqfn2wfcz6mv2hmoktwbdxkax4s"
+	msigCidStr = "bafk2bzacect2p7urje3pylrrrjy3tngn6yaih4gtzauuatf2jllasuxd3tyxu"
+	pieceCid   = "baga6ea4seaqao7s73y24kcutaosvacpdjgfe5pw76ooefnyqw4ynr3d2y6x2mpq"
+)
+
+var (
+	minerID, _     = address.NewIDAddress(1500)
+	minerRobust, _ = address.NewFromString("f2ddsjma6hfwcqhdp4vv6z4t5fighlhrjrqyxcekq")
+)
+
+func newActorParser(t *testing.T) actors.ActorParserInterface {
+	msigCid, err := cid.Parse(msigCidStr)
+	require.NoError(t, err)
+
+	logger := logger.NewDevelopmentLogger()
+	metrics := filMetrics.NewMetricsClient(metrics2.NewNoopMetrics())
+
+	node := &mocks.FullNode{}
+	node.On("StateNetworkName", mock.Anything).Return(dtypes.NetworkName(tools.MainnetNetwork), nil)
+	node.On("StateNetworkVersion", mock.Anything, mock.Anything).Return(filApiTypes.NetworkVersion(16), nil)
+	node.On("StateActorCodeCIDs", mock.Anything, mock.Anything).Return(map[string]cid.Cid{manifest.MultisigKey: msigCid}, nil)
+	cache := &mocks.IActorsCache{}
+	cache.On("StoreAddressInfo", mock.Anything).Return(nil)
+
+	lib := rosettaFilecoinLib.NewRosettaConstructionFilecoin(node)
+	h := helper.NewHelper(lib, cache, node, logger, metrics)
+	return actorsV2.NewActorParser(tools.MainnetNetwork, h, logger, metrics)
+}
+
+// parserMetadata runs params and ret through the actor parser and encodes the result as the parser builds Transaction.TxMetadata.
+func parserMetadata(t *testing.T, actorName, method string, height int64, params, ret typegen.CBORMarshaler) string {
+	msgCid, err := cid.Parse(msgCidStr)
+	require.NoError(t, err)
+	var rawParams, rawReturn bytes.Buffer
+	require.NoError(t, params.MarshalCBOR(&rawParams))
+	if ret != nil {
+		require.NoError(t, ret.MarshalCBOR(&rawReturn))
+	}
+
+	msg := &parser.LotusMessage{To: minerID, From: minerID, Cid: msgCid, Params: rawParams.Bytes()}
+	_, parsed, _, err := newActorParser(t).GetMetadata(context.Background(), actorName, method, msg, msgCid,
+		&parser.LotusMessageReceipt{ExitCode: exitcode.Ok, Return: rawReturn.Bytes()}, height, filTypes.EmptyTSK, true)
+	require.NoError(t, err)
+	encoded, err := json.Marshal(parsed)
+	require.NoError(t, err)
+	return string(encoded)
+}
+
+// txMetadata encodes params and ret the same way the actor parsers build Transaction.TxMetadata.
+func txMetadata(t *testing.T, params, ret interface{}) string {
+	raw := map[string]interface{}{parser.ParamsKey: params}
+	if ret != nil {
+		raw[parser.ReturnKey] = ret
+	}
+	encoded, err := json.Marshal(raw)
+	require.NoError(t, err)
+	return string(encoded)
+}
+
+func TestDecode_Miner(t *testing.T) {
+	decoder := metadata.NewDecoder(tools.MainnetNetwork)
+	height := tools.V24.Height()
+	sealedCid, err := cid.Decode("bagboea4b5abcatlxechwbp7kjpjguna6r6q7ejrhe6mdp3lf34pmswn27pkkiekz")
+	require.NoError(t, err)
+
+	t.Run("PreCommitSectorBatch2", func(t *testing.T) {
+		params := &miner16.PreCommitSectorBatchParams2{Sectors: []miner16.SectorPreCommitInfo{{
+			SealProof:     abi.RegisteredSealProof_StackedDrg32GiBV1_1,
+			SectorNumber:  42,
+			SealedCID:     sealedCid,
+			SealRandEpoch: 100,
+			DealIDs:       []abi.DealID{1, 2},
+			Expiration:    5_000_000,
+		}}}
+		tx := &types.Transaction{TxType: parser.MethodPreCommitSectorBatch2, TxMetadata: txMetadata(t, params, nil)}
+		tx.Height = uint64(height)
+
+		decoded, err := metadata.DecodeAs[*metadata.PreCommitSectorBatch2](decoder, "fil/16/"+manifest.MinerKey, tx)
+		require.NoError(t, err)
+		assert.Equal(t, metadata.Schema{Actor: manifest.MinerKey, Method: parser.MethodPreCommitSectorBatch2, Version: 1}, decoded.GetSchema())
+		require.Len(t, decoded.Params.Sectors, 1)
+		sector := decoded.Params.Sectors[0]
+		assert.Equal(t, uint64(42), sector.SectorNumber)
+		assert.Equal(t, sealedCid, sector.SealedCID)
+		assert.Equal(t, []uint64{1, 2}, sector.DealIDs)
+		assert.Equal(t, int64(5_000_000), sector.Expiration)
+		assert.Nil(t, sector.UnsealedCid)
+	})
+
+	t.Run("TerminateSectors", func(t *testing.T) {
+		params := &miner16.TerminateSectorsParams{Terminations: []miner16.TerminationDeclaration{{
+			Deadline: 3, Partition: 1, Sectors: bitfield.NewFromSet([]uint64{4, 5, 9}),
+		}}}
+		decoded, err := decoder.Decode(manifest.MinerKey, parser.MethodTerminateSectors, height, txMetadata(t, params, &miner16.TerminateSectorsReturn{Done: true}))
+		require.NoError(t, err)
+		terminate, ok := decoded.(*metadata.TerminateSectors)
+		require.True(t, ok)
+		require.Len(t, terminate.Params.Terminations, 1)
+		assert.Equal(t, metadata.Bitfield{4, 5, 9}, terminate.Params.Terminations[0].Sectors)
+		assert.Equal(t, uint64(3), terminate.Params.Terminations[0].Deadline)
+		require.NotNil(t, terminate.Return)
+		assert.True(t, terminate.Return.Done)
+	})
+
+	t.Run("missing required field", func(t *testing.T) {
+		_, err := decoder.Decode(manifest.MinerKey, parser.MethodChangeWorkerAddress, height, txMetadata(t, map[string]interface{}{"Worker": "f01234"}, nil))
+		require.ErrorContains(t, err, "Params.NewWorker")
+	})
+}
+
+func TestDecode_VersionedSchema(t *testing.T) {
+	decoder := metadata.NewDecoder(tools.MainnetNetwork)
+	owner, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+
+	legacy := &power0.CreateMinerParams{Owner: owner, Worker: owner, SealProofType: abi.RegisteredSealProof_StackedDrg32GiBV1}
+	decoded, err := decoder.Decode(manifest.PowerKey, parser.MethodCreateMiner, tools.V4.Height(), txMetadata(t, legacy, nil))
+	require.NoError(t, err)
+	createMiner := decoded.(*metadata.CreateMiner)
+	assert.Equal(t, 1, createMiner.Version)
+	assert.Equal(t, int64(abi.RegisteredSealProof_StackedDrg32GiBV1), createMiner.Params.ProofType)
+	assert.Nil(t, createMiner.Return)
+
+	current := &power15.CreateMinerParams{Owner: owner, Worker: owner, WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1_1}
+	ret := &power15.CreateMinerReturn{IDAddress: minerID, RobustAddress: minerRobust}
+	decoded, err = decoder.Decode(manifest.PowerKey, parser.MethodCreateMiner, tools.V24.Height(),
+		parserMetadata(t, manifest.PowerKey, parser.MethodCreateMiner, tools.V24.Height(), current, ret))
+	require.NoError(t, err)
+	createMiner = decoded.(*metadata.CreateMiner)
+	assert.Equal(t, 2, createMiner.Version)
+	assert.Equal(t, int64(abi.RegisteredPoStProof_StackedDrgWindow32GiBV1_1), createMiner.Params.ProofType)
+	require.NotNil(t, createMiner.Return)
+	assert.Equal(t, minerID.String(), createMiner.Return.IDAddress)
+	assert.Equal(t, minerRobust.String(), createMiner.Return.RobustAddress)
+	assert.Equal(t, manifest.MinerKey, createMiner.Return.ActorType)
+
+	sealedCid, err := cid.Decode("bagboea4b5abcatlxechwbp7kjpjguna6r6q7ejrhe6mdp3lf34pmswn27pkkiekz")
+	require.NoError(t, err)
+	preCommit := &miner8.PreCommitSectorParams{
+		SealProof: abi.RegisteredSealProof_StackedDrg32GiBV1_1, SectorNumber: 42, SealedCID: sealedCid, Expiration: 5_000_000,
+		ReplaceCapacity: true, ReplaceSectorDeadline: 3, ReplaceSectorPartition: 1, ReplaceSectorNumber: 7,
+	}
+	decoded, err = decoder.Decode(manifest.MinerKey, parser.MethodPreCommitSector, tools.V16.Height(),
+		parserMetadata(t, manifest.MinerKey, parser.MethodPreCommitSector, tools.V16.Height(), preCommit, nil))
+	require.NoError(t, err)
+	sector := decoded.(*metadata.PreCommitSector).Params
+	assert.Equal(t, uint64(42), sector.SectorNumber)
+	assert.True(t, sector.ReplaceCapacity)
+	assert.Equal(t, uint64(3), sector.ReplaceSectorDeadline)
+	assert.Equal(t, uint64(7), sector.ReplaceSectorNumber)
+
+	terminate := &marketTypes.OnMinerSectorsTerminateParams{Epoch: 100, SectorBitField: bitfield.NewFromSet([]uint64{4, 5}), SectorNumbers: []uint64{4, 5}}
+	decoded, err = decoder.Decode(manifest.MarketKey, parser.MethodOnMinerSectorsTerminate, tools.V22.Height(), txMetadata(t, terminate, nil))
+	require.NoError(t, err)
+	terminated := decoded.(*metadata.OnMinerSectorsTerminate)
+	assert.Equal(t, 2, terminated.Version)
+	assert.Equal(t, int64(100), terminated.Params.Epoch)
+	assert.Equal(t, []uint64{4, 5}, terminated.Params.SectorNumbers)
+	assert.Equal(t, metadata.Bitfield{4, 5}, terminated.Params.SectorBitField)
+	assert.Empty(t, terminated.Params.DealIDs)
+}
+
+// TestDecode_ParserOutput decodes the metadata the actor parsers emit, including the methods whose params or return
+// are transformed by the parser instead of being the go-state-types structs.
+func TestDecode_ParserOutput(t *testing.T) {
+	decoder := metadata.NewDecoder(tools.MainnetNetwork)
+	height := tools.V24.Height()
+	msigCid, err := cid.Parse(msigCidStr)
+	require.NoError(t, err)
+	piece, err := cid.Parse(pieceCid)
+	require.NoError(t, err)
+
+	decode := func(t *testing.T, actorName, method string, params, ret typegen.CBORMarshaler) metadata.Metadata {
+		decoded, err := decoder.Decode(actorName, method, height, parserMetadata(t, actorName, method, height, params, ret))
+		require.NoError(t, err)
+		return decoded
+	}
+
+	t.Run("init Exec", func(t *testing.T) {
+		var ctorParams bytes.Buffer
+		require.NoError(t, (&multisig15.ConstructorParams{Signers: []address.Address{minerID}, NumApprovalsThreshold: 1}).MarshalCBOR(&ctorParams))
+		params := &init15.ExecParams{CodeCID: msigCid, ConstructorParams: ctorParams.Bytes()}
+		ret := &init15.ExecReturn{IDAddress: minerID, RobustAddress: minerRobust}
+
+		exec := decode(t, manifest.InitKey, parser.MethodExec, params, ret).(*metadata.Exec)
+		assert.Equal(t, msigCidStr, exec.Params.CodeCid)
+		assert.NotEmpty(t, exec.Params.ConstructorParams)
+		require.NotNil(t, exec.Return)
+		assert.Equal(t, minerID.String(), exec.Return.IDAddress)
+		assert.Equal(t, minerRobust.String(), exec.Return.RobustAddress)
+	})
+
+	t.Run("market PublishStorageDeals", func(t *testing.T) {
+		label, err := market15.NewLabelFromString("label")
+		require.NoError(t, err)
+		params := &market15.PublishStorageDealsParams{Deals: []market15.ClientDealProposal{{
+			Proposal: market15.DealProposal{
+				PieceCID: piece, PieceSize: 2048, VerifiedDeal: true, Client: minerID, Provider: minerRobust, Label: label,
+				StartEpoch: 10, EndEpoch: 20, StoragePricePerEpoch: stateBig.NewInt(1), ProviderCollateral: stateBig.NewInt(2), ClientCollateral: stateBig.NewInt(3),
+			},
+			ClientSignature: crypto.Signature{Type: crypto.SigTypeBLS, Data: []byte{1}},
+		}}}
+		ret := &market15.PublishStorageDealsReturn{IDs: []abi.DealID{77}, ValidDeals: bitfield.NewFromSet([]uint64{0})}
+
+		publish := decode(t, manifest.MarketKey, parser.MethodPublishStorageDeals, params, ret).(*metadata.PublishStorageDeals)
+		require.Len(t, publish.Params.Deals, 1)
+		proposal := publish.Params.Deals[0].Proposal
+		assert.Equal(t, piece, proposal.PieceCID)
+		assert.Equal(t, "label", proposal.Label)
+		assert.Equal(t, minerRobust.String(), proposal.Provider)
+		assert.Equal(t, big.NewInt(3), proposal.ClientCollateral)
+		require.NotNil(t, publish.Return)
+		assert.Equal(t, []uint64{77}, publish.Return.IDs)
+		assert.Equal(t, metadata.Bitfield{0}, publish.Return.ValidDeals)
+	})
+
+	t.Run("datacap TransferExported", func(t *testing.T) {
+		params := &datacap15.TransferParams{To: minerID, Amount: stateBig.NewInt(100)}
+		ret := &datacap15.TransferReturn{FromBalance: stateBig.NewInt(900), ToBalance: stateBig.NewInt(100)}
+
+		transfer := decode(t, manifest.DatacapKey, parser.MethodTransferExported, params, ret).(*metadata.Transfer)
+		assert.Equal(t, minerID.String(), transfer.Params.To)
+		assert.Equal(t, big.NewInt(100), transfer.Params.Amount)
+		require.NotNil(t, transfer.Return)
+		assert.Equal(t, big.NewInt(900), transfer.Return.FromBalance)
+	})
+
+	t.Run("eam Create", func(t *testing.T) {
+		params := &eam15.CreateParams{Initcode: []byte{0x60, 0x80}, Nonce: 3}
+		ret := &eam15.CreateReturn{ActorID: 1600, RobustAddress: &minerRobust, EthAddress: [20]byte{0xd4, 0xc5}}
+
+		create := decode(t, manifest.EamKey, parser.MethodCreate, params, ret).(*metadata.Create)
+		assert.Equal(t, uint64(3), create.Params.Nonce)
+		require.NotNil(t, create.Return)
+		assert.Equal(t, uint64(1600), create.Return.ActorID)
+		assert.Equal(t, minerRobust.String(), create.Return.RobustAddress)
+		assert.Equal(t, "0xd4c5000000000000000000000000000000000000", create.Return.EthAddress)
+		assert.NotEmpty(t, create.EthHash)
+	})
+
+	t.Run("reward AwardBlockReward", func(t *testing.T) {
+		params := &reward15.AwardBlockRewardParams{Miner: minerID, Penalty: stateBig.NewInt(5), GasReward: stateBig.NewInt(100), WinCount: 2}
+
+		award := decode(t, manifest.RewardKey, parser.MethodAwardBlockReward, params, nil).(*metadata.AwardBlockReward)
+		assert.Equal(t, minerID.String(), award.Params.Miner)
+		assert.Equal(t, big.NewInt(100), award.Params.GasReward)
+		assert.Equal(t, int64(2), award.Params.WinCount)
+	})
+
+	t.Run("paymentchannel UpdateChannelState", func(t *testing.T) {
+		params := &paych15.UpdateChannelStateParams{Sv: paych15.SignedVoucher{
+			ChannelAddr: minerRobust, Lane: 1, Nonce: 2, Amount: stateBig.NewInt(500), MinSettleHeight: 30,
+		}}
+
+		update := decode(t, manifest.PaychKey, parser.MethodUpdateChannelState, params, nil).(*metadata.UpdateChannelState)
+		assert.Equal(t, minerRobust.String(), update.Params.Sv.ChannelAddr)
+		assert.Equal(t, uint64(1), update.Params.Sv.Lane)
+		assert.Equal(t, big.NewInt(500), update.Params.Sv.Amount)
+	})
+
+	t.Run("evm InvokeContract", func(t *testing.T) {
+		params := abi.CborBytes{0xa9, 0x05}
+		ret := abi.CborBytes{0x01}
+
+		invoke := decode(t, manifest.EvmKey, parser.MethodInvokeContract, &params, &ret).(*metadata.InvokeContract)
+		assert.Equal(t, "0xa905", invoke.Params)
+		assert.Equal(t, "0x01", invoke.Return)
+	})
+}
+
+func TestDecode_LargeAmounts(t *testing.T) {
+	decoder := metadata.NewDecoder(tools.MainnetNetwork)
+	allowance, ok := new(big.Int).SetString("1125899906842624000000000000", 10)
+	require.True(t, ok)
+
+	raw := `{"Params":{"Address":"f01234","Allowance":"1125899906842624000000000000"}}`
+	decoded, err := decoder.Decode(manifest.VerifregKey, parser.MethodAddVerifier, tools.V24.Height(), raw)
+	require.NoError(t, err)
+	assert.Equal(t, allowance, decoded.(*metadata.AddVerifier).Params.Allowance)
+
+	encoded, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"actor":"verifiedregistry","method":"AddVerifier","schema_version":1,
+		"params":{"address":"f01234","allowance":1125899906842624000000000000}}`, string(encoded))
+}
+
+func TestDecode_Unsupported(t *testing.T) {
+	decoder := metadata.NewDecoder(tools.MainnetNetwork)
+	_, err := decoder.Decode(manifest.MinerKey, "UnknownMethod", tools.V24.Height(), "{}")
+	assert.True(t, errors.Is(err, metadata.ErrUnsupportedMethod))
+	assert.False(t, metadata.Supported(manifest.MinerKey, "UnknownMethod"))
+	assert.True(t, metadata.Supported("fil/16/"+manifest.MultisigKey, parser.MethodPropose))
+}
+
+// TestDecode_TransactionTypes checks every method the actor parsers emit has typed metadata.
+func TestDecode_TransactionTypes(t *testing.T) {
+	actorParser, ok := newActorParser(t).(*actorsV2.ActorParser)
+	require.True(t, ok)
+	versions := tools.GetSupportedVersions(tools.MainnetNetwork)
+	latest, err := builtinActors.VersionForNetwork(versions[len(versions)-1].FilNetworkVersion())
+	require.NoError(t, err)
+
+	for _, actorName := range manifest.GetBuiltinActorsKeys(latest) {
+		actor, err := actorParser.GetActor(actorName)
+		require.NoError(t, err)
+		for method := range actor.TransactionTypes() {
+			assert.True(t, metadata.Supported(actorName, method), "%s %s", actorName, method)
+		}
+	}
+}
+
+// TestDecode_SchemaSamples decodes an instance of every typed schema document, so the metadata layouts accept every
+// shape the parser emits at every network version.
+func TestDecode_SchemaSamples(t *testing.T) {
+	documents, err := schema.NewGenerator(tools.MainnetNetwork, logger.NewDevelopmentLogger()).Generate(context.Background())
+	require.NoError(t, err)
+	heights := map[string]int64{}
+	for _, version := range tools.GetSupportedVersions(tools.MainnetNetwork) {
+		heights[version.String()] = version.Height()
+	}
+
+	// the documents describing the go-state-types values where the parser writes its own types, which the metadata follows
+	parserTypes := map[string]int64{
+		// from actors v11 the parser wraps the power in a struct
+		manifest.RewardKey + "/" + parser.MethodConstructor: tools.V19.Height(),
+		// the parser writes the piece results of the first sector only
+		manifest.MarketKey + "/" + parser.MethodSectorContentChanged: tools.V22.Height(),
+		// the parser writes the return under the params key
+		manifest.MinerKey + "/" + parser.MethodGetSectorSize: tools.V18.Height(),
+		// the parser writes the claimed space of each sector
+		manifest.VerifregKey + "/" + parser.MethodClaimAllocations: tools.V17.Height(),
+	}
+
+	decoder := metadata.NewDecoder(tools.MainnetNetwork)
+	for _, document := range documents {
+		height := heights[document.Version]
+		if since, ok := parserTypes[document.Actor+"/"+document.Method]; !document.Typed || (ok && height >= since) {
+			continue
+		}
+		defs, _ := document.Schema["$defs"].(map[string]interface{})
+		instance, ok := sampleOf(document.Schema, defs).(map[string]interface{})
+		require.True(t, ok, document.Path())
+		raw, err := json.Marshal(instance)
+		require.NoError(t, err)
+
+		_, err = decoder.Decode(document.Actor, document.Method, height, string(raw))
+		assert.NoError(t, err, "%s: %s", document.Path(), raw)
+	}
+}
+
+// sampleOf builds an instance of a JSON schema, taking the first branch of every anyOf.
+func sampleOf(s map[string]interface{}, defs map[string]interface{}) interface{} {
+	if ref, ok := s["$ref"].(string); ok {
+		def, _ := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]interface{})
+		return sampleOf(def, defs)
+	}
+	if anyOf, ok := s["anyOf"].([]interface{}); ok {
+		first, _ := anyOf[0].(map[string]interface{})
+		return sampleOf(first, defs)
+	}
+
+	switch s["description"] {
+	case "filecoin address":
+		return minerID.String()
+	case "arbitrary precision integer":
+		return "1"
+	case "run-length encoded bitfield":
+		return []interface{}{0, 2}
+	}
+
+	switch s["type"] {
+	case "string":
+		if s["contentEncoding"] == "base64" {
+			return "AQI="
+		}
+		// plain strings also hold decimal amounts and ids
+		return "1"
+	case "integer", "number":
+		return 1
+	case "boolean":
+		return true
+	case "array":
+		items, _ := s["items"].(map[string]interface{})
+		count := 1
+		if minItems, ok := s["minItems"].(int); ok {
+			count = minItems
+		}
+		sample := make([]interface{}, count)
+		for i := range sample {
+			sample[i] = sampleOf(items, defs)
+		}
+		return sample
+	case "object":
+		properties, _ := s["properties"].(map[string]interface{})
+		if _, ok := properties["/"]; ok {
+			return map[string]interface{}{"/": pieceCid}
+		}
+		sample := map[string]interface{}{}
+		for name, property := range properties {
+			property, _ := property.(map[string]interface{})
+			sample[name] = sampleOf(property, defs)
+		}
+		if additional, ok := s["additionalProperties"].(map[string]interface{}); ok {
+			sample["key"] = sampleOf(additional, defs)
+		}
+		return sample
+	}
+	// custom encodings and interfaces can't be sampled from the schema
+	return nil
+}
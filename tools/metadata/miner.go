@@ -0,0 +1,615 @@
+package metadata
+
+import (
+	"math/big"
+
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/ipfs/go-cid"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	since := tools.V0.NodeVersion()
+	register(manifest.MinerKey, parser.MethodSend, 1, since, func() Metadata { return &Send{} })
+	register(manifest.MinerKey, parser.MethodConstructor, 1, since, func() Metadata { return &MinerConstructor{} })
+	// specs-actors v3 replaced the seal proof type by the window post proof type
+	register(manifest.MinerKey, parser.MethodConstructor, 2, tools.V10.NodeVersion(), func() Metadata { return &MinerConstructor{} })
+	register(manifest.MinerKey, parser.MethodControlAddresses, 1, since, func() Metadata { return &ControlAddresses{} })
+	register(manifest.MinerKey, parser.MethodSubmitWindowedPoSt, 1, since, func() Metadata { return &SubmitWindowedPoSt{} })
+	register(manifest.MinerKey, parser.MethodPreCommitSector, 1, since, func() Metadata { return &PreCommitSector{} })
+	register(manifest.MinerKey, parser.MethodPreCommitSectorBatch, 1, since, func() Metadata { return &PreCommitSectorBatch{} })
+	register(manifest.MinerKey, parser.MethodPreCommitSectorBatch2, 1, since, func() Metadata { return &PreCommitSectorBatch2{} })
+	register(manifest.MinerKey, parser.MethodProveCommitSector, 1, since, func() Metadata { return &ProveCommitSector{} })
+	register(manifest.MinerKey, parser.MethodProveCommitAggregate, 1, since, func() Metadata { return &ProveCommitAggregate{} })
+	register(manifest.MinerKey, parser.MethodProveReplicaUpdates, 1, since, func() Metadata { return &ProveReplicaUpdates{} })
+	register(manifest.MinerKey, parser.MethodTerminateSectors, 1, since, func() Metadata { return &TerminateSectors{} })
+	register(manifest.MinerKey, parser.MethodDeclareFaults, 1, since, func() Metadata { return &DeclareFaults{} })
+	register(manifest.MinerKey, parser.MethodDeclareFaultsRecovered, 1, since, func() Metadata { return &DeclareFaultsRecovered{} })
+	register(manifest.MinerKey, parser.MethodExtendSectorExpiration, 1, since, func() Metadata { return &ExtendSectorExpiration{} })
+	register(manifest.MinerKey, parser.MethodOnDeferredCronEvent, 1, since, func() Metadata { return &OnDeferredCronEvent{} })
+	// actors v6 replaced the event type by the cbor encoded event and the reward and power estimates
+	register(manifest.MinerKey, parser.MethodOnDeferredCronEvent, 2, tools.V14.NodeVersion(), func() Metadata { return &OnDeferredCronEvent{} })
+	register(manifest.MinerKey, parser.MethodCheckSectorProven, 1, since, func() Metadata { return &CheckSectorProven{} })
+	register(manifest.MinerKey, parser.MethodApplyRewards, 1, since, func() Metadata { return &ApplyRewards{} })
+	register(manifest.MinerKey, parser.MethodReportConsensusFault, 1, since, func() Metadata { return &ReportConsensusFault{} })
+	register(manifest.MinerKey, parser.MethodConfirmSectorProofsValid, 1, since, func() Metadata { return &ConfirmSectorProofsValid{} })
+	// actors v6 added the reward and power estimates
+	register(manifest.MinerKey, parser.MethodConfirmSectorProofsValid, 2, tools.V14.NodeVersion(), func() Metadata { return &ConfirmSectorProofsValid{} })
+	register(manifest.MinerKey, parser.MethodCompactPartitions, 1, since, func() Metadata { return &CompactPartitions{} })
+	register(manifest.MinerKey, parser.MethodCompactSectorNumbers, 1, since, func() Metadata { return &CompactSectorNumbers{} })
+	register(manifest.MinerKey, parser.MethodConfirmUpdateWorkerKey, 1, since, func() Metadata { return &Empty{} })
+	register(manifest.MinerKey, parser.MethodAddLockedFund, 1, since, func() Metadata { return &AddLockedFund{} })
+	register(manifest.MinerKey, parser.MethodDisputeWindowedPoSt, 1, tools.V10.NodeVersion(), func() Metadata { return &DisputeWindowedPoSt{} })
+	// GetBeneficiary and the methods below are not in go-state-types, the parser accepts them at every version
+	register(manifest.MinerKey, parser.MethodGetBeneficiary, 1, since, func() Metadata { return &GetBeneficiary{} })
+	register(manifest.MinerKey, parser.MethodMovePartitions, 1, since, func() Metadata { return &MovePartitions{} })
+	for _, method := range []string{parser.MethodInitialPledge, parser.MethodInitialPledgeExported} {
+		register(manifest.MinerKey, method, 1, since, func() Metadata { return &InitialPledge{} })
+	}
+	for _, method := range []string{parser.MethodMaxTerminationFee, parser.MethodMaxTerminationFeeExported} {
+		register(manifest.MinerKey, method, 1, since, func() Metadata { return &MaxTerminationFee{} })
+	}
+
+	// the exported methods were added with actors v10 and share the params and return of the builtin ones
+	exported := tools.V18.NodeVersion()
+	methods := []struct {
+		name, exported string
+		new            func() Metadata
+	}{
+		{parser.MethodChangeWorkerAddress, parser.MethodChangeWorkerAddressExported, func() Metadata { return &ChangeWorkerAddress{} }},
+		{parser.MethodConfirmChangeWorkerAddress, parser.MethodConfirmChangeWorkerAddressExported, func() Metadata { return &Empty{} }},
+		{parser.MethodChangePeerID, parser.MethodChangePeerIDExported, func() Metadata { return &ChangePeerID{} }},
+		{parser.MethodChangeMultiaddrs, parser.MethodChangeMultiaddrsExported, func() Metadata { return &ChangeMultiaddrs{} }},
+		{parser.MethodChangeOwnerAddress, parser.MethodChangeOwnerAddressExported, func() Metadata { return &ChangeOwnerAddress{} }},
+		{parser.MethodRepayDebt, parser.MethodRepayDebtExported, func() Metadata { return &Empty{} }},
+	}
+	for _, method := range methods {
+		register(manifest.MinerKey, method.name, 1, since, method.new)
+		register(manifest.MinerKey, method.exported, 1, exported, method.new)
+	}
+	register(manifest.MinerKey, parser.MethodWithdrawBalance, 1, since, func() Metadata { return &MinerWithdrawBalance{} })
+	// actors v6 returned the amount withdrawn
+	register(manifest.MinerKey, parser.MethodWithdrawBalance, 2, tools.V14.NodeVersion(), func() Metadata { return &MinerWithdrawBalance{} })
+	register(manifest.MinerKey, parser.MethodWithdrawBalanceExported, 2, exported, func() Metadata { return &MinerWithdrawBalance{} })
+
+	// methods added with actors v9
+	since = tools.V17.NodeVersion()
+	register(manifest.MinerKey, parser.MethodProveReplicaUpdates2, 1, since, func() Metadata { return &ProveReplicaUpdates2{} })
+	register(manifest.MinerKey, parser.MethodExtendSectorExpiration2, 1, since, func() Metadata { return &ExtendSectorExpiration2{} })
+	register(manifest.MinerKey, parser.MethodChangeBeneficiary, 1, since, func() Metadata { return &ChangeBeneficiary{} })
+	register(manifest.MinerKey, parser.MethodChangeBeneficiaryExported, 1, exported, func() Metadata { return &ChangeBeneficiary{} })
+
+	// getters added with actors v10
+	register(manifest.MinerKey, parser.MethodGetOwner, 1, exported, func() Metadata { return &GetOwner{} })
+	register(manifest.MinerKey, parser.MethodIsControllingAddressExported, 1, exported, func() Metadata { return &IsControllingAddress{} })
+	register(manifest.MinerKey, parser.MethodGetSectorSize, 1, exported, func() Metadata { return &GetSectorSize{} })
+	register(manifest.MinerKey, parser.MethodGetAvailableBalance, 1, exported, func() Metadata { return &GetAvailableBalance{} })
+	register(manifest.MinerKey, parser.MethodGetVestingFunds, 1, exported, func() Metadata { return &GetVestingFunds{} })
+	// actors v16 replaced the list of vesting funds by its head and a link to the rest
+	register(manifest.MinerKey, parser.MethodGetVestingFunds, 2, tools.V25.NodeVersion(), func() Metadata { return &GetVestingFunds{} })
+	register(manifest.MinerKey, parser.MethodGetPeerID, 1, exported, func() Metadata { return &GetPeerID{} })
+	register(manifest.MinerKey, parser.MethodGetMultiaddrs, 1, exported, func() Metadata { return &GetMultiaddrs{} })
+
+	// methods added with actors v13 and v14
+	register(manifest.MinerKey, parser.MethodProveCommitSectors3, 1, tools.V22.NodeVersion(), func() Metadata { return &ProveCommitSectors3{} })
+	register(manifest.MinerKey, parser.MethodProveReplicaUpdates3, 1, tools.V22.NodeVersion(), func() Metadata { return &ProveReplicaUpdates3{} })
+	register(manifest.MinerKey, parser.MethodProveCommitSectorsNI, 1, tools.V23.NodeVersion(), func() Metadata { return &ProveCommitSectorsNI{} })
+	register(manifest.MinerKey, parser.MethodInternalSectorSetupForPreseal, 1, tools.V23.NodeVersion(), func() Metadata { return &InternalSectorSetupForPreseal{} })
+}
+
+type MinerConstructor struct {
+	Schema
+	Params struct {
+		OwnerAddr    string   `json:"owner_addr" source:"OwnerAddr"`
+		WorkerAddr   string   `json:"worker_addr" source:"WorkerAddr"`
+		ControlAddrs []string `json:"control_addrs,omitempty" source:"ControlAddrs,optional"`
+		// ProofType is the seal proof type in schema version 1 and the window post proof type in schema version 2
+		ProofType int64 `json:"proof_type" source:"WindowPoStProofType|SealProofType"`
+		// PeerID is base64 encoded
+		PeerID string `json:"peer_id,omitempty" source:"PeerId,optional"`
+		// Multiaddrs are base64 encoded
+		Multiaddrs []string `json:"multiaddrs,omitempty" source:"Multiaddrs,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type ControlAddresses struct {
+	Schema
+	// Params are the raw params of the message, base64 encoded
+	Params string `json:"params,omitempty" source:"Params,optional"`
+	Return *struct {
+		Owner        string   `json:"owner" source:"owner"`
+		Worker       string   `json:"worker" source:"worker"`
+		ControlAddrs []string `json:"control_addrs,omitempty" source:"controlAddrs,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type SubmitWindowedPoSt struct {
+	Schema
+	Params struct {
+		Deadline   uint64 `json:"deadline" source:"Deadline"`
+		Partitions []struct {
+			Index   uint64   `json:"index" source:"Index"`
+			Skipped Bitfield `json:"skipped" source:"Skipped"`
+		} `json:"partitions" source:"Partitions,optional"`
+		Proofs []struct {
+			PoStProof int64 `json:"post_proof" source:"PoStProof"`
+			// ProofBytes are base64 encoded
+			ProofBytes string `json:"proof_bytes" source:"ProofBytes,optional"`
+		} `json:"proofs" source:"Proofs,optional"`
+		ChainCommitEpoch int64 `json:"chain_commit_epoch" source:"ChainCommitEpoch"`
+		// ChainCommitRand is base64 encoded
+		ChainCommitRand string `json:"chain_commit_rand,omitempty" source:"ChainCommitRand,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type PreCommitSectorParams struct {
+	SealProof     int64    `json:"seal_proof" source:"SealProof"`
+	SectorNumber  uint64   `json:"sector_number" source:"SectorNumber"`
+	SealedCID     cid.Cid  `json:"sealed_cid" source:"SealedCID"`
+	SealRandEpoch int64    `json:"seal_rand_epoch" source:"SealRandEpoch"`
+	DealIDs       []uint64 `json:"deal_ids" source:"DealIDs,optional"`
+	Expiration    int64    `json:"expiration" source:"Expiration"`
+	// ReplaceCapacity and the sector it replaces are only sent before actors v9 and were deprecated by actors v7
+	ReplaceCapacity        bool   `json:"replace_capacity,omitempty" source:"ReplaceCapacity,optional"`
+	ReplaceSectorDeadline  uint64 `json:"replace_sector_deadline,omitempty" source:"ReplaceSectorDeadline,optional"`
+	ReplaceSectorPartition uint64 `json:"replace_sector_partition,omitempty" source:"ReplaceSectorPartition,optional"`
+	ReplaceSectorNumber    uint64 `json:"replace_sector_number,omitempty" source:"ReplaceSectorNumber,optional"`
+}
+
+type PreCommitSector struct {
+	Schema
+	Params PreCommitSectorParams `json:"params" source:"Params"`
+}
+
+type PreCommitSectorBatch struct {
+	Schema
+	Params struct {
+		Sectors []PreCommitSectorParams `json:"sectors" source:"Sectors"`
+	} `json:"params" source:"Params"`
+}
+
+type SectorPreCommitInfo struct {
+	SealProof     int64    `json:"seal_proof" source:"SealProof"`
+	SectorNumber  uint64   `json:"sector_number" source:"SectorNumber"`
+	SealedCID     cid.Cid  `json:"sealed_cid" source:"SealedCID"`
+	SealRandEpoch int64    `json:"seal_rand_epoch" source:"SealRandEpoch"`
+	DealIDs       []uint64 `json:"deal_ids" source:"DealIDs,optional"`
+	Expiration    int64    `json:"expiration" source:"Expiration"`
+	UnsealedCid   *cid.Cid `json:"unsealed_cid,omitempty" source:"UnsealedCid,optional"`
+}
+
+type PreCommitSectorBatch2 struct {
+	Schema
+	Params struct {
+		Sectors []SectorPreCommitInfo `json:"sectors" source:"Sectors"`
+	} `json:"params" source:"Params"`
+}
+
+type ProveCommitSector struct {
+	Schema
+	Params struct {
+		SectorNumber uint64 `json:"sector_number" source:"SectorNumber"`
+		// Proof is base64 encoded
+		Proof string `json:"proof,omitempty" source:"Proof,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type ProveCommitAggregate struct {
+	Schema
+	Params struct {
+		SectorNumbers Bitfield `json:"sector_numbers" source:"SectorNumbers"`
+		// AggregateProof is base64 encoded
+		AggregateProof string `json:"aggregate_proof,omitempty" source:"AggregateProof,optional"`
+	} `json:"params" source:"Params"`
+}
+
+// PieceActivationManifest is a piece added to a sector, with the verified allocation it claims and the actors notified.
+type PieceActivationManifest struct {
+	CID                   cid.Cid `json:"cid" source:"CID"`
+	Size                  uint64  `json:"size" source:"Size"`
+	VerifiedAllocationKey *struct {
+		Client uint64 `json:"client" source:"Client"`
+		ID     uint64 `json:"id" source:"ID"`
+	} `json:"verified_allocation_key,omitempty" source:"VerifiedAllocationKey,optional"`
+	Notify []struct {
+		Address string `json:"address" source:"Address"`
+		// Payload is base64 encoded
+		Payload string `json:"payload,omitempty" source:"Payload,optional"`
+	} `json:"notify,omitempty" source:"Notify,optional"`
+}
+
+type ProveCommitSectors3 struct {
+	Schema
+	Params struct {
+		SectorActivations []struct {
+			SectorNumber uint64                    `json:"sector_number" source:"SectorNumber"`
+			Pieces       []PieceActivationManifest `json:"pieces" source:"Pieces,optional"`
+		} `json:"sector_activations" source:"SectorActivations"`
+		// SectorProofs and AggregateProof are base64 encoded, only one of them is set
+		SectorProofs               []string `json:"sector_proofs,omitempty" source:"SectorProofs,optional"`
+		AggregateProof             string   `json:"aggregate_proof,omitempty" source:"AggregateProof,optional"`
+		AggregateProofType         *int64   `json:"aggregate_proof_type,omitempty" source:"AggregateProofType,optional"`
+		RequireActivationSuccess   bool     `json:"require_activation_success" source:"RequireActivationSuccess"`
+		RequireNotificationSuccess bool     `json:"require_notification_success" source:"RequireNotificationSuccess"`
+	} `json:"params" source:"Params"`
+	Return *BatchReturn `json:"return,omitempty" source:"Return,optional"`
+}
+
+type ProveCommitSectorsNI struct {
+	Schema
+	Params struct {
+		Sectors []struct {
+			SealingNumber uint64  `json:"sealing_number" source:"SealingNumber"`
+			SealerID      uint64  `json:"sealer_id" source:"SealerID"`
+			SealedCID     cid.Cid `json:"sealed_cid" source:"SealedCID"`
+			SectorNumber  uint64  `json:"sector_number" source:"SectorNumber"`
+			SealRandEpoch int64   `json:"seal_rand_epoch" source:"SealRandEpoch"`
+			Expiration    int64   `json:"expiration" source:"Expiration"`
+		} `json:"sectors" source:"Sectors"`
+		SealProofType int64 `json:"seal_proof_type" source:"SealProofType"`
+		// AggregateProof is base64 encoded
+		AggregateProof           string `json:"aggregate_proof,omitempty" source:"AggregateProof,optional"`
+		AggregateProofType       int64  `json:"aggregate_proof_type" source:"AggregateProofType"`
+		ProvingDeadline          uint64 `json:"proving_deadline" source:"ProvingDeadline"`
+		RequireActivationSuccess bool   `json:"require_activation_success" source:"RequireActivationSuccess"`
+	} `json:"params" source:"Params"`
+	Return *BatchReturn `json:"return,omitempty" source:"Return,optional"`
+}
+
+type ReplicaUpdate struct {
+	SectorID           uint64   `json:"sector_id" source:"SectorID"`
+	Deadline           uint64   `json:"deadline" source:"Deadline"`
+	Partition          uint64   `json:"partition" source:"Partition"`
+	NewSealedSectorCID cid.Cid  `json:"new_sealed_sector_cid" source:"NewSealedSectorCID"`
+	Deals              []uint64 `json:"deals" source:"Deals,optional"`
+	UpdateProofType    int64    `json:"update_proof_type" source:"UpdateProofType"`
+	// ReplicaProof is base64 encoded
+	ReplicaProof string `json:"replica_proof,omitempty" source:"ReplicaProof,optional"`
+}
+
+type ProveReplicaUpdates struct {
+	Schema
+	Params struct {
+		Updates []ReplicaUpdate `json:"updates" source:"Updates"`
+	} `json:"params" source:"Params"`
+	// Return are the sectors successfully updated
+	Return Bitfield `json:"return,omitempty" source:"Return,optional"`
+}
+
+type ProveReplicaUpdates2 struct {
+	Schema
+	Params struct {
+		Updates []struct {
+			ReplicaUpdate
+			NewUnsealedSectorCID cid.Cid `json:"new_unsealed_sector_cid" source:"NewUnsealedSectorCID"`
+		} `json:"updates" source:"Updates"`
+	} `json:"params" source:"Params"`
+	// Return are the sectors successfully updated
+	Return Bitfield `json:"return,omitempty" source:"Return,optional"`
+}
+
+type ProveReplicaUpdates3 struct {
+	Schema
+	Params struct {
+		SectorUpdates []struct {
+			Sector       uint64                    `json:"sector" source:"Sector"`
+			Deadline     uint64                    `json:"deadline" source:"Deadline"`
+			Partition    uint64                    `json:"partition" source:"Partition"`
+			NewSealedCID cid.Cid                   `json:"new_sealed_cid" source:"NewSealedCID"`
+			Pieces       []PieceActivationManifest `json:"pieces" source:"Pieces,optional"`
+		} `json:"sector_updates" source:"SectorUpdates"`
+		// SectorProofs and AggregateProof are base64 encoded, only one of them is set
+		SectorProofs               []string `json:"sector_proofs,omitempty" source:"SectorProofs,optional"`
+		AggregateProof             string   `json:"aggregate_proof,omitempty" source:"AggregateProof,optional"`
+		UpdateProofsType           int64    `json:"update_proofs_type" source:"UpdateProofsType"`
+		AggregateProofType         *int64   `json:"aggregate_proof_type,omitempty" source:"AggregateProofType,optional"`
+		RequireActivationSuccess   bool     `json:"require_activation_success" source:"RequireActivationSuccess"`
+		RequireNotificationSuccess bool     `json:"require_notification_success" source:"RequireNotificationSuccess"`
+	} `json:"params" source:"Params"`
+	Return *BatchReturn `json:"return,omitempty" source:"Return,optional"`
+}
+
+// SectorsDeclaration is the location of a set of sectors, shared by terminations, faults and recoveries.
+type SectorsDeclaration struct {
+	Deadline  uint64   `json:"deadline" source:"Deadline"`
+	Partition uint64   `json:"partition" source:"Partition"`
+	Sectors   Bitfield `json:"sectors" source:"Sectors"`
+}
+
+type TerminateSectors struct {
+	Schema
+	Params struct {
+		Terminations []SectorsDeclaration `json:"terminations" source:"Terminations"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		Done bool `json:"done" source:"Done"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type DeclareFaults struct {
+	Schema
+	Params struct {
+		Faults []SectorsDeclaration `json:"faults" source:"Faults"`
+	} `json:"params" source:"Params"`
+}
+
+type DeclareFaultsRecovered struct {
+	Schema
+	Params struct {
+		Recoveries []SectorsDeclaration `json:"recoveries" source:"Recoveries"`
+	} `json:"params" source:"Params"`
+}
+
+type ExpirationExtension struct {
+	SectorsDeclaration
+	NewExpiration int64 `json:"new_expiration" source:"NewExpiration"`
+}
+
+type ExtendSectorExpiration struct {
+	Schema
+	Params struct {
+		Extensions []ExpirationExtension `json:"extensions" source:"Extensions,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type ExtendSectorExpiration2 struct {
+	Schema
+	Params struct {
+		Extensions []struct {
+			ExpirationExtension
+			SectorsWithClaims []struct {
+				SectorNumber   uint64   `json:"sector_number" source:"SectorNumber"`
+				MaintainClaims []uint64 `json:"maintain_claims,omitempty" source:"MaintainClaims,optional"`
+				DropClaims     []uint64 `json:"drop_claims,omitempty" source:"DropClaims,optional"`
+			} `json:"sectors_with_claims,omitempty" source:"SectorsWithClaims,optional"`
+		} `json:"extensions" source:"Extensions,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type OnDeferredCronEvent struct {
+	Schema
+	Params struct {
+		// EventType is only sent in schema version 1
+		EventType *int64 `json:"event_type,omitempty" source:"EventType,optional"`
+		// EventPayload is the base64 encoded cbor event, only sent from schema version 2
+		EventPayload            string          `json:"event_payload,omitempty" source:"EventPayload,optional"`
+		RewardSmoothed          *FilterEstimate `json:"reward_smoothed,omitempty" source:"RewardSmoothed,optional"`
+		QualityAdjPowerSmoothed *FilterEstimate `json:"quality_adj_power_smoothed,omitempty" source:"QualityAdjPowerSmoothed,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type CheckSectorProven struct {
+	Schema
+	Params struct {
+		SectorNumber uint64 `json:"sector_number" source:"SectorNumber"`
+	} `json:"params" source:"Params"`
+}
+
+type ApplyRewards struct {
+	Schema
+	Params struct {
+		Reward  *big.Int `json:"reward" source:"Reward"`
+		Penalty *big.Int `json:"penalty" source:"Penalty"`
+	} `json:"params" source:"Params"`
+}
+
+type ReportConsensusFault struct {
+	Schema
+	// the block headers are cbor encoded, then base64 encoded
+	Params struct {
+		BlockHeader1     string `json:"block_header_1" source:"BlockHeader1"`
+		BlockHeader2     string `json:"block_header_2" source:"BlockHeader2"`
+		BlockHeaderExtra string `json:"block_header_extra,omitempty" source:"BlockHeaderExtra,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type ConfirmSectorProofsValid struct {
+	Schema
+	Params struct {
+		Sectors []uint64 `json:"sectors" source:"Sectors,optional"`
+		// the estimates are only sent from schema version 2
+		RewardSmoothed          *FilterEstimate `json:"reward_smoothed,omitempty" source:"RewardSmoothed,optional"`
+		RewardBaselinePower     *big.Int        `json:"reward_baseline_power,omitempty" source:"RewardBaselinePower,optional"`
+		QualityAdjPowerSmoothed *FilterEstimate `json:"quality_adj_power_smoothed,omitempty" source:"QualityAdjPowerSmoothed,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type InternalSectorSetupForPreseal struct {
+	Schema
+	Params struct {
+		Sectors                 []uint64       `json:"sectors" source:"Sectors,optional"`
+		RewardSmoothed          FilterEstimate `json:"reward_smoothed" source:"RewardSmoothed"`
+		RewardBaselinePower     *big.Int       `json:"reward_baseline_power" source:"RewardBaselinePower"`
+		QualityAdjPowerSmoothed FilterEstimate `json:"quality_adj_power_smoothed" source:"QualityAdjPowerSmoothed"`
+	} `json:"params" source:"Params"`
+}
+
+type CompactPartitions struct {
+	Schema
+	Params struct {
+		Deadline   uint64   `json:"deadline" source:"Deadline"`
+		Partitions Bitfield `json:"partitions" source:"Partitions"`
+	} `json:"params" source:"Params"`
+}
+
+type CompactSectorNumbers struct {
+	Schema
+	Params struct {
+		MaskSectorNumbers Bitfield `json:"mask_sector_numbers" source:"MaskSectorNumbers"`
+	} `json:"params" source:"Params"`
+}
+
+type MovePartitions struct {
+	Schema
+	Params struct {
+		OrigDeadline uint64   `json:"orig_deadline" source:"OrigDeadline"`
+		DestDeadline uint64   `json:"dest_deadline" source:"DestDeadline"`
+		Partitions   Bitfield `json:"partitions" source:"Partitions"`
+	} `json:"params" source:"Params"`
+}
+
+type DisputeWindowedPoSt struct {
+	Schema
+	Params struct {
+		Deadline  uint64 `json:"deadline" source:"Deadline"`
+		PoStIndex uint64 `json:"post_index" source:"PoStIndex"`
+	} `json:"params" source:"Params"`
+}
+
+type ChangeWorkerAddress struct {
+	Schema
+	Params struct {
+		NewWorker       string   `json:"new_worker" source:"NewWorker"`
+		NewControlAddrs []string `json:"new_control_addrs" source:"NewControlAddrs,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type ChangePeerID struct {
+	Schema
+	Params struct {
+		// NewID is base64 encoded
+		NewID string `json:"new_id" source:"NewID,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type ChangeMultiaddrs struct {
+	Schema
+	Params struct {
+		// NewMultiaddrs are base64 encoded
+		NewMultiaddrs []string `json:"new_multiaddrs" source:"NewMultiaddrs,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type ChangeOwnerAddress struct {
+	Schema
+	// Params is the address of the new owner
+	Params string `json:"params" source:"Params"`
+}
+
+type ChangeBeneficiary struct {
+	Schema
+	Params struct {
+		NewBeneficiary string   `json:"new_beneficiary" source:"NewBeneficiary"`
+		NewQuota       *big.Int `json:"new_quota" source:"NewQuota"`
+		NewExpiration  int64    `json:"new_expiration" source:"NewExpiration"`
+	} `json:"params" source:"Params"`
+}
+
+type GetBeneficiary struct {
+	Schema
+	// Params are the raw params of the message, base64 encoded
+	Params string `json:"params,omitempty" source:"Params,optional"`
+	Return *struct {
+		Active struct {
+			Beneficiary string `json:"beneficiary" source:"beneficiary"`
+			Term        struct {
+				Quota      *big.Int `json:"quota" source:"quota"`
+				UsedQuota  *big.Int `json:"used_quota" source:"usedQuota"`
+				Expiration int64    `json:"expiration" source:"expiration"`
+			} `json:"term" source:"term"`
+		} `json:"active" source:"active"`
+		// Proposed is the zero value when there is no pending change
+		Proposed struct {
+			NewBeneficiary        string   `json:"new_beneficiary" source:"newBeneficiary"`
+			NewQuota              *big.Int `json:"new_quota,omitempty" source:"newQuota,optional"`
+			NewExpiration         int64    `json:"new_expiration" source:"newExpiration"`
+			ApprovedByBeneficiary bool     `json:"approved_by_beneficiary" source:"approvedByBeneficiary"`
+			ApprovedByNominee     bool     `json:"approved_by_nominee" source:"approvedByNominee"`
+		} `json:"proposed" source:"proposed"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type AddLockedFund struct {
+	Schema
+	// Params is the amount locked
+	Params *big.Int `json:"params" source:"Params"`
+}
+
+type MinerWithdrawBalance struct {
+	Schema
+	Params struct {
+		AmountRequested *big.Int `json:"amount_requested" source:"AmountRequested"`
+	} `json:"params" source:"Params"`
+	// Return is the amount withdrawn, only returned from schema version 2
+	Return *big.Int `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetOwner struct {
+	Schema
+	Return *struct {
+		Owner string `json:"owner" source:"Owner"`
+		// Proposed is the pending new owner, if any
+		Proposed string `json:"proposed,omitempty" source:"Proposed,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type IsControllingAddress struct {
+	Schema
+	// Params is the address checked
+	Params string `json:"params" source:"Params"`
+	Return *bool  `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetSectorSize struct {
+	Schema
+	// Params holds the returned sector size, the parser stores it under the params key
+	Params struct {
+		SectorSize uint64 `json:"sector_size" source:"SectorSize"`
+	} `json:"params" source:"Params"`
+}
+
+type GetAvailableBalance struct {
+	Schema
+	Return *big.Int `json:"return,omitempty" source:"Return,optional"`
+}
+
+type VestingFund struct {
+	Epoch  int64    `json:"epoch" source:"Epoch"`
+	Amount *big.Int `json:"amount" source:"Amount"`
+}
+
+type GetVestingFunds struct {
+	Schema
+	Return *struct {
+		// Funds are only returned in schema version 1
+		Funds []VestingFund `json:"funds,omitempty" source:"Funds,optional"`
+		// Head is the next fund to vest and Tail links to the rest, only returned from schema version 2
+		Head *VestingFund `json:"head,omitempty" source:"Head,optional"`
+		Tail *cid.Cid     `json:"tail,omitempty" source:"Tail,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetPeerID struct {
+	Schema
+	Return *struct {
+		// PeerID is base64 encoded
+		PeerID string `json:"peer_id" source:"PeerId,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetMultiaddrs struct {
+	Schema
+	Return *struct {
+		// Multiaddrs are the cbor encoded multiaddrs, base64 encoded
+		Multiaddrs string `json:"multiaddrs" source:"Multiaddrs|MultiAddrs,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type InitialPledge struct {
+	Schema
+	Return *struct {
+		Amount *big.Int `json:"amount" source:"Amount"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type MaxTerminationFee struct {
+	Schema
+	Params struct {
+		Power         *big.Int `json:"power" source:"Power"`
+		InitialPledge *big.Int `json:"initial_pledge" source:"InitialPledge"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		MaxFee *big.Int `json:"max_fee" source:"MaxFee"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
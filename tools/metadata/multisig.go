@@ -0,0 +1,137 @@
+package metadata
+
+import (
+	"math/big"
+
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	since := tools.V0.NodeVersion()
+	register(manifest.MultisigKey, parser.MethodSend, 1, since, func() Metadata { return &Send{} })
+	register(manifest.MultisigKey, parser.MethodFallback, 1, since, func() Metadata { return &Fallback{} })
+	register(manifest.MultisigKey, parser.MethodConstructor, 1, since, func() Metadata { return &MultisigConstructor{} })
+	// specs-actors v2 added the epoch the vesting starts at
+	register(manifest.MultisigKey, parser.MethodConstructor, 2, tools.V4.NodeVersion(), func() Metadata { return &MultisigConstructor{} })
+	register(manifest.MultisigKey, parser.MethodMsigUniversalReceiverHook, 1, tools.V17.NodeVersion(), func() Metadata { return &MultisigUniversalReceiverHook{} })
+
+	// the exported methods were added with actors v10 and share the params and return of the builtin ones
+	exported := tools.V18.NodeVersion()
+	methods := []struct {
+		names []string
+		new   func() Metadata
+	}{
+		{[]string{parser.MethodPropose, parser.MethodProposeExported}, func() Metadata { return &Propose{} }},
+		{[]string{parser.MethodApprove, parser.MethodApproveExported}, func() Metadata { return &Approve{} }},
+		{[]string{parser.MethodCancel, parser.MethodCancelExported}, func() Metadata { return &Cancel{} }},
+		{[]string{parser.MethodAddSigner, parser.MethodAddSignerExported}, func() Metadata { return &AddSigner{} }},
+		{[]string{parser.MethodRemoveSigner, parser.MethodRemoveSignerExported}, func() Metadata { return &RemoveSigner{} }},
+		{[]string{parser.MethodSwapSigner, parser.MethodSwapSignerExported}, func() Metadata { return &SwapSigner{} }},
+		{[]string{parser.MethodChangeNumApprovalsThreshold, parser.MethodChangeNumApprovalsThresholdExported}, func() Metadata { return &ChangeNumApprovalsThreshold{} }},
+		{[]string{parser.MethodLockBalance, parser.MethodLockBalanceExported}, func() Metadata { return &LockBalance{} }},
+	}
+	for _, method := range methods {
+		register(manifest.MultisigKey, method.names[0], 1, since, method.new)
+		register(manifest.MultisigKey, method.names[1], 1, exported, method.new)
+	}
+}
+
+type MultisigConstructor struct {
+	Schema
+	Params struct {
+		Signers               []string `json:"signers" source:"Signers,optional"`
+		NumApprovalsThreshold uint64   `json:"num_approvals_threshold" source:"NumApprovalsThreshold"`
+		UnlockDuration        int64    `json:"unlock_duration" source:"UnlockDuration"`
+		// StartEpoch is only sent from schema version 2
+		StartEpoch *int64 `json:"start_epoch,omitempty" source:"StartEpoch,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type MultisigUniversalReceiverHook struct {
+	Schema
+	// Params are the raw params of the hook, base64 encoded
+	Params string `json:"params,omitempty" source:"Params,optional"`
+}
+
+// ProposalResult is the outcome of a proposal, returned when proposing or approving it.
+type ProposalResult struct {
+	Applied bool `json:"applied" source:"Applied"`
+	// Code is the exit code of the proposed message, only meaningful when Applied is true
+	Code int64 `json:"code" source:"Code"`
+	// Ret is the base64 encoded return of the proposed message, only set when Applied is true
+	Ret string `json:"ret,omitempty" source:"Ret,optional"`
+}
+
+type Propose struct {
+	Schema
+	Params struct {
+		To     string `json:"to" source:"To"`
+		Value  string `json:"value" source:"Value"`
+		Method string `json:"method" source:"Method"`
+		// Params and Return are the metadata of the proposed message, as parsed by its actor
+		Params map[string]interface{} `json:"params,omitempty" source:"Params,optional"`
+		Return map[string]interface{} `json:"return,omitempty" source:"Return,optional"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		TxnID uint64 `json:"txn_id" source:"TxnID"`
+		ProposalResult
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type TxnIDParams struct {
+	ID           uint64 `json:"id" source:"ID"`
+	ProposalHash string `json:"proposal_hash,omitempty" source:"ProposalHash,optional"`
+}
+
+type Approve struct {
+	Schema
+	Params TxnIDParams     `json:"params" source:"Params"`
+	Return *ProposalResult `json:"return,omitempty" source:"Return,optional"`
+}
+
+type Cancel struct {
+	Schema
+	Params TxnIDParams `json:"params" source:"Params"`
+}
+
+type AddSigner struct {
+	Schema
+	Params struct {
+		Signer   string `json:"signer" source:"Signer"`
+		Increase bool   `json:"increase" source:"Increase"`
+	} `json:"params" source:"Params"`
+}
+
+type RemoveSigner struct {
+	Schema
+	Params struct {
+		Signer   string `json:"signer" source:"Signer"`
+		Decrease bool   `json:"decrease" source:"Decrease"`
+	} `json:"params" source:"Params"`
+}
+
+type SwapSigner struct {
+	Schema
+	Params struct {
+		From string `json:"from" source:"From"`
+		To   string `json:"to" source:"To"`
+	} `json:"params" source:"Params"`
+}
+
+type ChangeNumApprovalsThreshold struct {
+	Schema
+	Params struct {
+		NewThreshold uint64 `json:"new_threshold" source:"NewThreshold"`
+	} `json:"params" source:"Params"`
+}
+
+type LockBalance struct {
+	Schema
+	Params struct {
+		StartEpoch     int64    `json:"start_epoch" source:"StartEpoch"`
+		UnlockDuration int64    `json:"unlock_duration" source:"UnlockDuration"`
+		Amount         *big.Int `json:"amount" source:"Amount"`
+	} `json:"params" source:"Params"`
+}
@@ -0,0 +1,66 @@
+package metadata
+
+import (
+	"math/big"
+
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	since := tools.V0.NodeVersion()
+	register(manifest.PaychKey, parser.MethodSend, 1, since, func() Metadata { return &Send{} })
+	register(manifest.PaychKey, parser.MethodConstructor, 1, since, func() Metadata { return &PaymentChannelConstructor{} })
+	register(manifest.PaychKey, parser.MethodSettle, 1, since, func() Metadata { return &Empty{} })
+	register(manifest.PaychKey, parser.MethodCollect, 1, since, func() Metadata { return &Empty{} })
+	register(manifest.PaychKey, parser.MethodUpdateChannelState, 1, since, func() Metadata { return &UpdateChannelState{} })
+	// specs-actors v2 dropped the proof of the voucher
+	register(manifest.PaychKey, parser.MethodUpdateChannelState, 2, tools.V4.NodeVersion(), func() Metadata { return &UpdateChannelState{} })
+	// actors v7 renamed the secret preimage of the voucher to its hash
+	register(manifest.PaychKey, parser.MethodUpdateChannelState, 3, tools.V15.NodeVersion(), func() Metadata { return &UpdateChannelState{} })
+}
+
+type PaymentChannelConstructor struct {
+	Schema
+	Params struct {
+		From string `json:"from" source:"From"`
+		To   string `json:"to" source:"To"`
+	} `json:"params" source:"Params"`
+}
+
+type SignedVoucher struct {
+	ChannelAddr string `json:"channel_addr" source:"ChannelAddr"`
+	TimeLockMin int64  `json:"time_lock_min" source:"TimeLockMin"`
+	TimeLockMax int64  `json:"time_lock_max" source:"TimeLockMax"`
+	// SecretHash is base64 encoded, named SecretPreimage before schema version 3
+	SecretHash string `json:"secret_hash,omitempty" source:"SecretHash|SecretPreimage,optional"`
+	// Extra is a method the voucher requires to be called on another actor before redeeming it
+	Extra *struct {
+		Actor  string `json:"actor" source:"Actor"`
+		Method uint64 `json:"method" source:"Method"`
+		// Data is base64 encoded
+		Data string `json:"data,omitempty" source:"Data,optional"`
+	} `json:"extra,omitempty" source:"Extra,optional"`
+	Lane  uint64 `json:"lane" source:"Lane"`
+	Nonce uint64 `json:"nonce" source:"Nonce"`
+	// Amount is the total redeemable on the lane, not the increment of this voucher
+	Amount          *big.Int `json:"amount" source:"Amount"`
+	MinSettleHeight int64    `json:"min_settle_height" source:"MinSettleHeight"`
+	Merges          []struct {
+		Lane  uint64 `json:"lane" source:"Lane"`
+		Nonce uint64 `json:"nonce" source:"Nonce"`
+	} `json:"merges,omitempty" source:"Merges,optional"`
+	Signature *Signature `json:"signature,omitempty" source:"Signature,optional"`
+}
+
+type UpdateChannelState struct {
+	Schema
+	Params struct {
+		Sv SignedVoucher `json:"sv" source:"Sv"`
+		// Secret is base64 encoded
+		Secret string `json:"secret,omitempty" source:"Secret,optional"`
+		// Proof is base64 encoded, only sent in schema version 1
+		Proof string `json:"proof,omitempty" source:"Proof,optional"`
+	} `json:"params" source:"Params"`
+}
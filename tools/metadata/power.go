@@ -0,0 +1,132 @@
+package metadata
+
+import (
+	"math/big"
+
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/ipfs/go-cid"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	since := tools.V0.NodeVersion()
+	exported := tools.V18.NodeVersion()
+	register(manifest.PowerKey, parser.MethodSend, 1, since, func() Metadata { return &Send{} })
+	// the power actor is constructed at genesis without params
+	register(manifest.PowerKey, parser.MethodConstructor, 1, since, func() Metadata { return &Empty{} })
+	register(manifest.PowerKey, parser.MethodCronTick, 1, since, func() Metadata { return &Empty{} })
+	register(manifest.PowerKey, parser.MethodOnEpochTickEnd, 1, since, func() Metadata { return &Empty{} })
+	register(manifest.PowerKey, parser.MethodCreateMiner, 1, since, func() Metadata { return &CreateMiner{} })
+	// specs-actors v3 replaced the seal proof type by the window post proof type
+	register(manifest.PowerKey, parser.MethodCreateMiner, 2, tools.V10.NodeVersion(), func() Metadata { return &CreateMiner{} })
+	register(manifest.PowerKey, parser.MethodCreateMinerExported, 2, exported, func() Metadata { return &CreateMiner{} })
+	register(manifest.PowerKey, parser.MethodUpdateClaimedPower, 1, since, func() Metadata { return &UpdateClaimedPower{} })
+	register(manifest.PowerKey, parser.MethodEnrollCronEvent, 1, since, func() Metadata { return &EnrollCronEvent{} })
+	register(manifest.PowerKey, parser.MethodUpdatePledgeTotal, 1, since, func() Metadata { return &UpdatePledgeTotal{} })
+	register(manifest.PowerKey, parser.MethodOnConsensusFault, 1, since, func() Metadata { return &OnConsensusFault{} })
+	register(manifest.PowerKey, parser.MethodSubmitPoRepForBulkVerify, 1, since, func() Metadata { return &SubmitPoRepForBulkVerify{} })
+	register(manifest.PowerKey, parser.MethodCurrentTotalPower, 1, since, func() Metadata { return &CurrentTotalPower{} })
+	// actors v15 returned the ramp of the fip-0081 pledge calculation
+	register(manifest.PowerKey, parser.MethodCurrentTotalPower, 2, tools.V24.NodeVersion(), func() Metadata { return &CurrentTotalPower{} })
+	register(manifest.PowerKey, parser.MethodNetworkRawPowerExported, 1, exported, func() Metadata { return &NetworkRawPower{} })
+	register(manifest.PowerKey, parser.MethodMinerRawPowerExported, 1, exported, func() Metadata { return &MinerRawPower{} })
+	register(manifest.PowerKey, parser.MethodMinerCountExported, 1, exported, func() Metadata { return &MinerCount{} })
+	register(manifest.PowerKey, parser.MethodMinerConsensusCountExported, 1, exported, func() Metadata { return &MinerConsensusCount{} })
+}
+
+type CreateMiner struct {
+	Schema
+	Params struct {
+		Owner  string `json:"owner" source:"Owner"`
+		Worker string `json:"worker" source:"Worker"`
+		// ProofType is the seal proof type in schema version 1 and the window post proof type in schema version 2
+		ProofType  int64    `json:"proof_type" source:"WindowPoStProofType|SealProofType"`
+		Peer       string   `json:"peer,omitempty" source:"Peer,optional"`
+		Multiaddrs []string `json:"multiaddrs,omitempty" source:"Multiaddrs,optional"`
+	} `json:"params" source:"Params"`
+	Return *CreatedActor `json:"return,omitempty" source:"Return,optional"`
+}
+
+type UpdateClaimedPower struct {
+	Schema
+	Params struct {
+		RawByteDelta         *big.Int `json:"raw_byte_delta" source:"RawByteDelta"`
+		QualityAdjustedDelta *big.Int `json:"quality_adjusted_delta" source:"QualityAdjustedDelta"`
+	} `json:"params" source:"Params"`
+}
+
+type EnrollCronEvent struct {
+	Schema
+	Params struct {
+		EventEpoch int64 `json:"event_epoch" source:"EventEpoch"`
+		// Payload is base64 encoded
+		Payload string `json:"payload,omitempty" source:"Payload,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type UpdatePledgeTotal struct {
+	Schema
+	// Params is the change of the pledge locked by the miner
+	Params *big.Int `json:"params" source:"Params"`
+}
+
+type OnConsensusFault struct {
+	Schema
+	// Params is the pledge the faulty miner removes from the network total
+	Params *big.Int `json:"params,omitempty" source:"Params,optional"`
+}
+
+type SubmitPoRepForBulkVerify struct {
+	Schema
+	Params struct {
+		SealProof    int64    `json:"seal_proof" source:"SealProof"`
+		Miner        uint64   `json:"miner" source:"Miner"`
+		SectorNumber uint64   `json:"sector_number" source:"Number"`
+		DealIDs      []uint64 `json:"deal_ids" source:"DealIDs,optional"`
+		SealedCID    cid.Cid  `json:"sealed_cid" source:"SealedCID"`
+		UnsealedCID  cid.Cid  `json:"unsealed_cid" source:"UnsealedCID"`
+		// Randomness, InteractiveRandomness and Proof are base64 encoded
+		Randomness            string `json:"randomness,omitempty" source:"Randomness,optional"`
+		InteractiveRandomness string `json:"interactive_randomness,omitempty" source:"InteractiveRandomness,optional"`
+		Proof                 string `json:"proof,omitempty" source:"Proof,optional"`
+	} `json:"params" source:"Params"`
+}
+
+type CurrentTotalPower struct {
+	Schema
+	Return *struct {
+		RawBytePower            *big.Int        `json:"raw_byte_power" source:"RawBytePower"`
+		QualityAdjPower         *big.Int        `json:"quality_adj_power" source:"QualityAdjPower"`
+		PledgeCollateral        *big.Int        `json:"pledge_collateral" source:"PledgeCollateral"`
+		QualityAdjPowerSmoothed *FilterEstimate `json:"quality_adj_power_smoothed" source:"QualityAdjPowerSmoothed"`
+		// RampStartEpoch and RampDurationEpochs are only returned from schema version 2
+		RampStartEpoch     *int64  `json:"ramp_start_epoch,omitempty" source:"RampStartEpoch,optional"`
+		RampDurationEpochs *uint64 `json:"ramp_duration_epochs,omitempty" source:"RampDurationEpochs,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type NetworkRawPower struct {
+	Schema
+	Return *big.Int `json:"return,omitempty" source:"Return,optional"`
+}
+
+type MinerRawPower struct {
+	Schema
+	// Params is the actor id of the miner
+	Params uint64 `json:"params" source:"Params"`
+	Return *struct {
+		RawBytePower          *big.Int `json:"raw_byte_power" source:"RawBytePower"`
+		MeetsConsensusMinimum bool     `json:"meets_consensus_minimum" source:"MeetsConsensusMinimum"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type MinerCount struct {
+	Schema
+	Return *uint64 `json:"return,omitempty" source:"Return,optional"`
+}
+
+type MinerConsensusCount struct {
+	Schema
+	Return *uint64 `json:"return,omitempty" source:"Return,optional"`
+}
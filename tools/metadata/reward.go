@@ -0,0 +1,68 @@
+package metadata
+
+import (
+	"math/big"
+
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	since := tools.V0.NodeVersion()
+	register(manifest.RewardKey, parser.MethodSend, 1, since, func() Metadata { return &Send{} })
+	register(manifest.RewardKey, parser.MethodConstructor, 1, since, func() Metadata { return &RewardConstructor{} })
+	// actors v11 wrapped the constructor power in a struct
+	register(manifest.RewardKey, parser.MethodConstructor, 2, tools.V19.NodeVersion(), func() Metadata { return &RewardConstructorV2{} })
+	register(manifest.RewardKey, parser.MethodAwardBlockReward, 1, since, func() Metadata { return &AwardBlockReward{} })
+	register(manifest.RewardKey, parser.MethodThisEpochReward, 1, since, func() Metadata { return &ThisEpochReward{} })
+	// specs-actors v2 dropped the unsmoothed reward of the epoch
+	register(manifest.RewardKey, parser.MethodThisEpochReward, 2, tools.V4.NodeVersion(), func() Metadata { return &ThisEpochReward{} })
+	register(manifest.RewardKey, parser.MethodUpdateNetworkKPI, 1, since, func() Metadata { return &UpdateNetworkKPI{} })
+}
+
+type RewardConstructor struct {
+	Schema
+	// Params is the baseline power the network starts with
+	Params *big.Int `json:"params,omitempty" source:"Params,optional"`
+}
+
+// RewardConstructorV2 is the layout of RewardConstructor from schema version 2.
+type RewardConstructorV2 struct {
+	Schema
+	Params *struct {
+		Power *big.Int `json:"power" source:"Power"`
+	} `json:"params,omitempty" source:"Params,optional"`
+}
+
+type AwardBlockReward struct {
+	Schema
+	Params struct {
+		Miner     string   `json:"miner" source:"Miner"`
+		Penalty   *big.Int `json:"penalty" source:"Penalty"`
+		GasReward *big.Int `json:"gas_reward" source:"GasReward"`
+		WinCount  int64    `json:"win_count" source:"WinCount"`
+	} `json:"params" source:"Params"`
+}
+
+// FilterEstimate is a Q.128 fixed point estimate of a value and its rate of change.
+type FilterEstimate struct {
+	PositionEstimate *big.Int `json:"position_estimate" source:"PositionEstimate"`
+	VelocityEstimate *big.Int `json:"velocity_estimate" source:"VelocityEstimate"`
+}
+
+type ThisEpochReward struct {
+	Schema
+	Return *struct {
+		// ThisEpochReward is only returned in schema version 1
+		ThisEpochReward         *big.Int        `json:"this_epoch_reward,omitempty" source:"ThisEpochReward,optional"`
+		ThisEpochRewardSmoothed *FilterEstimate `json:"this_epoch_reward_smoothed,omitempty" source:"ThisEpochRewardSmoothed,optional"`
+		ThisEpochBaselinePower  *big.Int        `json:"this_epoch_baseline_power" source:"ThisEpochBaselinePower"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type UpdateNetworkKPI struct {
+	Schema
+	// Params is the network raw byte power, sent by the power actor on every epoch
+	Params *big.Int `json:"params" source:"Params"`
+}
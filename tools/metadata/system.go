@@ -0,0 +1,13 @@
+package metadata
+
+import (
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	since := tools.V0.NodeVersion()
+	register(manifest.SystemKey, parser.MethodSend, 1, since, func() Metadata { return &Send{} })
+	register(manifest.SystemKey, parser.MethodConstructor, 1, since, func() Metadata { return &Empty{} })
+}
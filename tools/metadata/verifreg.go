@@ -0,0 +1,211 @@
+package metadata
+
+import (
+	"math/big"
+
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/ipfs/go-cid"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+)
+
+func init() {
+	since := tools.V0.NodeVersion()
+	exported := tools.V18.NodeVersion()
+	register(manifest.VerifregKey, parser.MethodSend, 1, since, func() Metadata { return &Send{} })
+	register(manifest.VerifregKey, parser.MethodConstructor, 1, since, func() Metadata { return &VerifregConstructor{} })
+	register(manifest.VerifregKey, parser.MethodAddVerifier, 1, since, func() Metadata { return &AddVerifier{} })
+	register(manifest.VerifregKey, parser.MethodRemoveVerifier, 1, since, func() Metadata { return &RemoveVerifier{} })
+	register(manifest.VerifregKey, parser.MethodAddVerifiedClient, 1, since, func() Metadata { return &AddVerifiedClient{} })
+	register(manifest.VerifregKey, parser.MethodAddVerifiedClientExported, 1, exported, func() Metadata { return &AddVerifiedClient{} })
+	register(manifest.VerifregKey, parser.MethodUseBytes, 1, since, func() Metadata { return &UseBytes{} })
+	register(manifest.VerifregKey, parser.MethodRestoreBytes, 1, since, func() Metadata { return &RestoreBytes{} })
+	register(manifest.VerifregKey, parser.MethodRemoveVerifiedClientDataCap, 1, tools.V15.NodeVersion(), func() Metadata { return &RemoveVerifiedClientDataCap{} })
+
+	// methods added with actors v9, when the datacap moved to its own actor. UseBytes and RestoreBytes were
+	// deprecated by them and keep their layout under their new names.
+	since = tools.V17.NodeVersion()
+	register(manifest.VerifregKey, parser.MethodVerifiedDeprecated1, 1, since, func() Metadata { return &RestoreBytes{} })
+	register(manifest.VerifregKey, parser.MethodVerifiedDeprecated2, 1, since, func() Metadata { return &UseBytes{} })
+	register(manifest.VerifregKey, parser.MethodClaimAllocations, 1, since, func() Metadata { return &ClaimAllocations{} })
+	// actors v12 grouped the claims by sector
+	register(manifest.VerifregKey, parser.MethodClaimAllocations, 2, tools.V21.NodeVersion(), func() Metadata { return &ClaimAllocations{} })
+	register(manifest.VerifregKey, parser.MethodUniversalReceiverHook, 1, since, func() Metadata { return &VerifregUniversalReceiverHook{} })
+	register(manifest.VerifregKey, parser.MethodGetClaims, 1, since, func() Metadata { return &GetClaims{} })
+	register(manifest.VerifregKey, parser.MethodGetClaimsExported, 1, exported, func() Metadata { return &GetClaims{} })
+	register(manifest.VerifregKey, parser.MethodExtendClaimTerms, 1, since, func() Metadata { return &ExtendClaimTerms{} })
+	register(manifest.VerifregKey, parser.MethodExtendClaimTermsExported, 1, exported, func() Metadata { return &ExtendClaimTerms{} })
+	register(manifest.VerifregKey, parser.MethodRemoveExpiredAllocations, 1, since, func() Metadata { return &RemoveExpiredAllocations{} })
+	register(manifest.VerifregKey, parser.MethodRemoveExpiredAllocationsExported, 1, exported, func() Metadata { return &RemoveExpiredAllocations{} })
+	register(manifest.VerifregKey, parser.MethodRemoveExpiredClaims, 1, since, func() Metadata { return &RemoveExpiredClaims{} })
+	register(manifest.VerifregKey, parser.MethodRemoveExpiredClaimsExported, 1, exported, func() Metadata { return &RemoveExpiredClaims{} })
+}
+
+// AddressAllowance is the datacap allowance granted to an address.
+type AddressAllowance struct {
+	Address   string   `json:"address" source:"Address"`
+	Allowance *big.Int `json:"allowance" source:"Allowance"`
+}
+
+type VerifregConstructor struct {
+	Schema
+	// Params is the address of the root key
+	Params string `json:"params" source:"Params"`
+}
+
+type AddVerifier struct {
+	Schema
+	Params AddressAllowance `json:"params" source:"Params"`
+}
+
+type RemoveVerifier struct {
+	Schema
+	// Params is the address of the verifier removed
+	Params string `json:"params" source:"Params"`
+}
+
+type AddVerifiedClient struct {
+	Schema
+	Params AddressAllowance `json:"params" source:"Params"`
+}
+
+// UseBytes is sent by the market to spend the datacap of a verified deal, until actors v9.
+type UseBytes struct {
+	Schema
+	Params struct {
+		Address  string   `json:"address" source:"Address"`
+		DealSize *big.Int `json:"deal_size" source:"DealSize"`
+	} `json:"params" source:"Params"`
+}
+
+// RestoreBytes is sent by the market to give back the datacap of a verified deal that failed, until actors v9.
+type RestoreBytes struct {
+	Schema
+	Params struct {
+		Address  string   `json:"address" source:"Address"`
+		DealSize *big.Int `json:"deal_size" source:"DealSize"`
+	} `json:"params" source:"Params"`
+}
+
+type RemoveDataCapRequest struct {
+	Verifier          string    `json:"verifier" source:"Verifier"`
+	VerifierSignature Signature `json:"verifier_signature" source:"VerifierSignature"`
+}
+
+type RemoveVerifiedClientDataCap struct {
+	Schema
+	Params struct {
+		VerifiedClientToRemove string               `json:"verified_client_to_remove" source:"VerifiedClientToRemove"`
+		DataCapAmountToRemove  *big.Int             `json:"data_cap_amount_to_remove" source:"DataCapAmountToRemove"`
+		VerifierRequest1       RemoveDataCapRequest `json:"verifier_request_1" source:"VerifierRequest1"`
+		VerifierRequest2       RemoveDataCapRequest `json:"verifier_request_2" source:"VerifierRequest2"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		VerifiedClient string   `json:"verified_client" source:"VerifiedClient"`
+		DataCapRemoved *big.Int `json:"data_cap_removed" source:"DataCapRemoved"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+// AllocationClaim is the claim of a datacap allocation by the sector holding its piece.
+type AllocationClaim struct {
+	Client       uint64  `json:"client" source:"Client"`
+	AllocationID uint64  `json:"allocation_id" source:"AllocationId"`
+	Data         cid.Cid `json:"data" source:"Data"`
+	Size         uint64  `json:"size" source:"Size"`
+}
+
+// ClaimAllocations sends one entry per claim in schema version 1 and one entry per sector, listing its claims, from schema version 2.
+type ClaimAllocations struct {
+	Schema
+	Params struct {
+		Sectors []struct {
+			Sector       uint64 `json:"sector" source:"Sector"`
+			SectorExpiry int64  `json:"sector_expiry" source:"SectorExpiry"`
+			// Client, AllocationID, Data and Size are only sent in schema version 1, with one entry per claim
+			Client       *uint64  `json:"client,omitempty" source:"Client,optional"`
+			AllocationID *uint64  `json:"allocation_id,omitempty" source:"AllocationId,optional"`
+			Data         *cid.Cid `json:"data,omitempty" source:"Data,optional"`
+			Size         *uint64  `json:"size,omitempty" source:"Size,optional"`
+			// Claims are only sent from schema version 2
+			Claims []AllocationClaim `json:"claims,omitempty" source:"Claims,optional"`
+		} `json:"sectors" source:"Sectors,optional"`
+		AllOrNothing bool `json:"all_or_nothing" source:"AllOrNothing"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		BatchInfo BatchReturn `json:"batch_info" source:"BatchInfo"`
+		// ClaimedSpace is the space claimed by each sector
+		ClaimedSpace []*big.Int `json:"claimed_space,omitempty" source:"ClaimedSpace,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type VerifregUniversalReceiverHook struct {
+	Schema
+	Params struct {
+		Type uint64 `json:"type" source:"Type_"`
+		// Payload is the base64 encoded datacap transfer, carrying the allocation requests
+		Payload string `json:"payload,omitempty" source:"Payload,optional"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		AllocationResults BatchReturn `json:"allocation_results" source:"AllocationResults"`
+		ExtensionResults  BatchReturn `json:"extension_results" source:"ExtensionResults"`
+		NewAllocations    []uint64    `json:"new_allocations,omitempty" source:"NewAllocations,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type GetClaims struct {
+	Schema
+	Params struct {
+		Provider uint64   `json:"provider" source:"Provider"`
+		ClaimIDs []uint64 `json:"claim_ids" source:"ClaimIds,optional"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		BatchInfo BatchReturn `json:"batch_info" source:"BatchInfo"`
+		Claims    []struct {
+			Provider  uint64  `json:"provider" source:"Provider"`
+			Client    uint64  `json:"client" source:"Client"`
+			Data      cid.Cid `json:"data" source:"Data"`
+			Size      uint64  `json:"size" source:"Size"`
+			TermMin   int64   `json:"term_min" source:"TermMin"`
+			TermMax   int64   `json:"term_max" source:"TermMax"`
+			TermStart int64   `json:"term_start" source:"TermStart"`
+			Sector    uint64  `json:"sector" source:"Sector"`
+		} `json:"claims" source:"Claims,optional"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type ExtendClaimTerms struct {
+	Schema
+	Params struct {
+		Terms []struct {
+			Provider uint64 `json:"provider" source:"Provider"`
+			ClaimID  uint64 `json:"claim_id" source:"ClaimId"`
+			TermMax  int64  `json:"term_max" source:"TermMax"`
+		} `json:"terms" source:"Terms,optional"`
+	} `json:"params" source:"Params"`
+	Return *BatchReturn `json:"return,omitempty" source:"Return,optional"`
+}
+
+type RemoveExpiredAllocations struct {
+	Schema
+	Params struct {
+		Client        uint64   `json:"client" source:"Client"`
+		AllocationIDs []uint64 `json:"allocation_ids" source:"AllocationIds,optional"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		Considered       []uint64    `json:"considered" source:"Considered,optional"`
+		Results          BatchReturn `json:"results" source:"Results"`
+		DataCapRecovered *big.Int    `json:"datacap_recovered" source:"DataCapRecovered"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
+
+type RemoveExpiredClaims struct {
+	Schema
+	Params struct {
+		Provider uint64   `json:"provider" source:"Provider"`
+		ClaimIDs []uint64 `json:"claim_ids" source:"ClaimIds,optional"`
+	} `json:"params" source:"Params"`
+	Return *struct {
+		Considered []uint64    `json:"considered" source:"Considered,optional"`
+		Results    BatchReturn `json:"results" source:"Results"`
+	} `json:"return,omitempty" source:"Return,optional"`
+}
@@ -0,0 +1,159 @@
+package miner
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/filecoin-project/go-state-types/manifest"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+const (
+	KeyBlockHeader1     = "BlockHeader1"
+	KeyBlockHeader2     = "BlockHeader2"
+	KeyBlockHeaderExtra = "BlockHeaderExtra"
+)
+
+// Consensus fault types, following the checks done by the node when verifying a report.
+const (
+	FaultTypeDoubleForkMining = "double_fork_mining"
+	FaultTypeTimeOffsetMining = "time_offset_mining"
+	FaultTypeParentGrinding   = "parent_grinding"
+)
+
+func (eg *eventGenerator) isConsensusFaultMessage(actorName, txType string) bool {
+	return actorName == manifest.MinerKey && txType == parser.MethodReportConsensusFault
+}
+
+// createConsensusFault builds the slashing record of a report. The penalty and the reporter reward are the
+// successful sends made by the offending miner in the subcalls of the report, found through children, to the burn
+// address and to the reporter. Other transfers are left out of both.
+func (eg *eventGenerator) createConsensusFault(tx *types.Transaction, tipsetCid string, children map[string][]*types.Transaction) (*types.MinerConsensusFault, error) {
+	var value map[string]interface{}
+	err := json.Unmarshal([]byte(tx.TxMetadata), &value)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling tx metadata: %w", err)
+	}
+
+	params, err := common.GetItem[map[string]interface{}](value, KeyParams, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing params: %w", err)
+	}
+
+	header1, err := decodeBlockHeader(params, KeyBlockHeader1, false)
+	if err != nil {
+		return nil, err
+	}
+	header2, err := decodeBlockHeader(params, KeyBlockHeader2, false)
+	if err != nil {
+		return nil, err
+	}
+	headerExtra, err := decodeBlockHeader(params, KeyBlockHeaderExtra, true)
+	if err != nil {
+		return nil, err
+	}
+
+	fault := &types.MinerConsensusFault{
+		ID:              tools.BuildId(tipsetCid, tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType),
+		MinerAddress:    tx.TxTo,
+		Reporter:        tx.TxFrom,
+		Height:          tx.Height,
+		TxCid:           tx.TxCid,
+		BlockHeader1Cid: header1.Cid().String(),
+		BlockHeader2Cid: header2.Cid().String(),
+		FaultEpoch:      int64(header1.Height),
+		FaultType:       faultType(header1, header2, headerExtra),
+		PenaltyBurned:   big.NewInt(0),
+		ReporterReward:  big.NewInt(0),
+		TxTimestamp:     tx.TxTimestamp,
+	}
+	if headerExtra != nil {
+		fault.BlockHeaderExtraCid = headerExtra.Cid().String()
+	}
+
+	reporter := eg.robustAddress(tx.TxFrom)
+	for _, subcall := range descendants(tx, children) {
+		// only funds leaving the offending miner are part of the slashing
+		if subcall.TxFrom != tx.TxTo || subcall.Amount == nil || subcall.Amount.Sign() <= 0 || !common.IsTxSuccess(subcall) {
+			continue
+		}
+		if subcall.TxType != parser.MethodSend {
+			continue
+		}
+		switch {
+		case subcall.TxTo == parser.BurnAddress:
+			fault.PenaltyBurned.Add(fault.PenaltyBurned, subcall.Amount)
+		case eg.robustAddress(subcall.TxTo) == reporter:
+			fault.ReporterReward.Add(fault.ReporterReward, subcall.Amount)
+		}
+	}
+
+	return fault, nil
+}
+
+// robustAddress consolidates addr so the short and robust forms of an address compare equal.
+// addr is returned as is when it can't be consolidated.
+func (eg *eventGenerator) robustAddress(addr string) string {
+	consolidated, err := eg.consolidateAddress(addr)
+	if err != nil || consolidated == "" {
+		eg.logger.Debugf("could not consolidate address %s: %v", addr, err)
+		return addr
+	}
+	return consolidated
+}
+
+// descendants returns every subcall nested under tx, walking the tree down by level.
+func descendants(tx *types.Transaction, children map[string][]*types.Transaction) []*types.Transaction {
+	var result []*types.Transaction
+	pending := children[tx.Id]
+	for len(pending) > 0 {
+		subcall := pending[0]
+		pending = pending[1:]
+		if subcall.Level <= tx.Level || subcall.Id == tx.Id {
+			continue
+		}
+		result = append(result, subcall)
+		pending = append(pending, children[subcall.Id]...)
+	}
+	return result
+}
+
+func decodeBlockHeader(params map[string]interface{}, key string, canBeNil bool) (*filTypes.BlockHeader, error) {
+	encoded, err := common.GetItem[string](params, key, canBeNil)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", key, err)
+	}
+	if encoded == "" {
+		if canBeNil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error parsing %s: empty block header", key)
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", key, err)
+	}
+	header, err := filTypes.DecodeBlock(raw)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s block header: %w", key, err)
+	}
+	return header, nil
+}
+
+func faultType(header1, header2, headerExtra *filTypes.BlockHeader) string {
+	switch {
+	case header1.Height == header2.Height:
+		return FaultTypeDoubleForkMining
+	case filTypes.CidArrsEqual(header1.Parents, header2.Parents):
+		return FaultTypeTimeOffsetMining
+	case headerExtra != nil:
+		return FaultTypeParentGrinding
+	}
+	return ""
+}
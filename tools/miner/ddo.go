@@ -0,0 +1,226 @@
+package miner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+const (
+	KeyCID          = "CID"
+	KeySize         = "Size"
+	KeyClient       = "Client"
+	KeyID           = "ID"
+	KeyClaims       = "Claims"
+	KeyAllocationId = "AllocationId"
+	KeyBatchInfo    = "BatchInfo"
+)
+
+// claimKey identifies the claim of a verified allocation in a sector.
+type claimKey struct {
+	sectorNumber uint64
+	allocationID uint64
+}
+
+func (eg *eventGenerator) isDDOMessage(actorName, txType string) bool {
+	if !strings.Contains(actorName, manifest.MinerKey) {
+		return false
+	}
+	return txType == parser.MethodProveCommitSectors3 || txType == parser.MethodProveReplicaUpdates3
+}
+
+// createDDOPieces returns a row per piece activated by ProveCommitSectors3 or ProveReplicaUpdates3.
+// Pieces of sectors that failed to activate are skipped. Verified pieces are marked as claimed when the
+// ClaimAllocations subcall to the verifreg actor claimed their allocation.
+func (eg *eventGenerator) createDDOPieces(tx *types.Transaction, tipsetCid string, children map[string][]*types.Transaction) ([]*types.MinerDDOPiece, error) {
+	var value map[string]interface{}
+	if err := json.Unmarshal([]byte(tx.TxMetadata), &value); err != nil {
+		return nil, fmt.Errorf("error unmarshalling tx metadata: %w", err)
+	}
+	params, err := common.GetItem[map[string]interface{}](value, KeyParams, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing params: %w", err)
+	}
+
+	sectorsKey, sectorNumberKey := KeySectorActivations, KeySectorNumber
+	if tx.TxType == parser.MethodProveReplicaUpdates3 {
+		sectorsKey, sectorNumberKey = KeySectorUpdates, KeySector
+	}
+	sectors, err := common.GetSlice[map[string]interface{}](params, sectorsKey, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", sectorsKey, err)
+	}
+	// both methods return a batch return with the index of every failed sector
+	batchReturn, err := common.GetItem[map[string]interface{}](value, parser.ReturnKey, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing return: %w", err)
+	}
+	failedSectors, err := common.FailedBatchIndexes(batchReturn)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := claimedAllocations(tx, children)
+	if err != nil {
+		return nil, err
+	}
+
+	var pieces []*types.MinerDDOPiece
+	for i, sector := range sectors {
+		if failedSectors[i] {
+			continue
+		}
+		sectorNumber, err := common.GetInteger[uint64](sector, sectorNumberKey, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing sector number: %w", err)
+		}
+		manifests, err := common.GetSlice[map[string]interface{}](sector, KeyPieces, true)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing pieces: %w", err)
+		}
+		for pieceIdx, pieceManifest := range manifests {
+			piece, err := eg.createDDOPiece(tx, tipsetCid, sectorNumber, pieceIdx, pieceManifest)
+			if err != nil {
+				return nil, err
+			}
+			piece.Claimed = piece.Verified && claims[claimKey{sectorNumber: sectorNumber, allocationID: piece.AllocationID}]
+			pieces = append(pieces, piece)
+		}
+	}
+	return pieces, nil
+}
+
+func (eg *eventGenerator) createDDOPiece(tx *types.Transaction, tipsetCid string, sectorNumber uint64, pieceIdx int, pieceManifest map[string]interface{}) (*types.MinerDDOPiece, error) {
+	pieceCid, err := common.GetItem[map[string]interface{}](pieceManifest, KeyCID, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing piece cid: %w", err)
+	}
+	pieceCidStr, err := common.GetItem[string](pieceCid, "/", false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing piece cid: %w", err)
+	}
+	pieceSize, err := common.GetInteger[uint64](pieceManifest, KeySize, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing piece size: %w", err)
+	}
+
+	piece := &types.MinerDDOPiece{
+		ID:              tools.BuildId(tipsetCid, tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType, fmt.Sprint(sectorNumber), fmt.Sprint(pieceIdx)),
+		MinerAddress:    tx.TxTo,
+		SectorNumber:    sectorNumber,
+		Height:          tx.Height,
+		TxCid:           tx.TxCid,
+		ActionType:      tx.TxType,
+		PieceCid:        pieceCidStr,
+		PieceSize:       pieceSize,
+		NotifyAddresses: []string{},
+		TxTimestamp:     tx.TxTimestamp,
+	}
+
+	// unverified pieces have no allocation key
+	allocationKey, err := common.GetItem[map[string]interface{}](pieceManifest, KeyVerifiedAllocationKey, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing verified allocation key: %w", err)
+	}
+	if len(allocationKey) > 0 {
+		clientID, err := common.GetInteger[uint64](allocationKey, KeyClient, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing allocation client: %w", err)
+		}
+		allocationID, err := common.GetInteger[uint64](allocationKey, KeyID, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing allocation id: %w", err)
+		}
+		client, err := common.ConsolidateIDAddress(clientID, eg.helper, eg.logger, eg.config, true)
+		if err != nil {
+			eg.logger.Errorf("error consolidating allocation client: %s", err)
+		}
+		piece.Verified = true
+		piece.AllocationClient = client
+		piece.AllocationID = allocationID
+	}
+
+	notifications, err := common.GetSlice[map[string]interface{}](pieceManifest, KeyNotify, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing notify: %w", err)
+	}
+	for _, notify := range notifications {
+		addr, err := common.GetItem[string](notify, KeyAddress, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing notify address: %w", err)
+		}
+		if eg.config.ConsolidateRobustAddress {
+			consolidatedAddr, err := eg.consolidateAddress(addr)
+			if err != nil {
+				eg.logger.Errorf("error consolidating notify address: %s", err)
+			} else {
+				addr = consolidatedAddr
+			}
+		}
+		piece.NotifyAddresses = append(piece.NotifyAddresses, addr)
+	}
+	return piece, nil
+}
+
+// claimedAllocations returns the allocations claimed by the successful ClaimAllocations subcalls of tx.
+// Claims are grouped by sector and the return lists the index of every sector that failed to claim.
+func claimedAllocations(tx *types.Transaction, children map[string][]*types.Transaction) (map[claimKey]bool, error) {
+	claims := make(map[claimKey]bool)
+	for _, subcall := range descendants(tx, children) {
+		if subcall.TxType != parser.MethodClaimAllocations || !common.IsTxSuccess(subcall) {
+			continue
+		}
+		var value map[string]interface{}
+		if err := json.Unmarshal([]byte(subcall.TxMetadata), &value); err != nil {
+			return nil, fmt.Errorf("error unmarshalling claim allocations metadata: %w", err)
+		}
+		params, err := common.GetItem[map[string]interface{}](value, KeyParams, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing claim allocations params: %w", err)
+		}
+		sectors, err := common.GetSlice[map[string]interface{}](params, KeySectors, true)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing claim allocations sectors: %w", err)
+		}
+		ret, err := common.GetItem[map[string]interface{}](value, parser.ReturnKey, true)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing claim allocations return: %w", err)
+		}
+		batchInfo, err := common.GetItem[map[string]interface{}](ret, KeyBatchInfo, true)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing claim allocations batch info: %w", err)
+		}
+		failedSectors, err := common.FailedBatchIndexes(batchInfo)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, sector := range sectors {
+			if failedSectors[i] {
+				continue
+			}
+			sectorNumber, err := common.GetInteger[uint64](sector, KeySector, false)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing claimed sector: %w", err)
+			}
+			sectorClaims, err := common.GetSlice[map[string]interface{}](sector, KeyClaims, true)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing claims: %w", err)
+			}
+			for _, claim := range sectorClaims {
+				allocationID, err := common.GetInteger[uint64](claim, KeyAllocationId, false)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing claimed allocation id: %w", err)
+				}
+				claims[claimKey{sectorNumber: sectorNumber, allocationID: allocationID}] = true
+			}
+		}
+	}
+	return claims, nil
+}
@@ -41,12 +41,22 @@ func NewEventGenerator(helper *helper.Helper, logger *logger.Logger, metrics met
 
 func (eg *eventGenerator) GenerateMinerEvents(ctx context.Context, transactions []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.MinerEvents, error) {
 	events := &types.MinerEvents{
-		MinerInfo:    []*types.MinerInfo{},
-		MinerSectors: []*types.MinerSectorEvent{},
+		MinerInfo:       []*types.MinerInfo{},
+		MinerSectors:    []*types.MinerSectorEvent{},
+		MinerProving:    []*types.MinerProvingEvent{},
+		ConsensusFaults: []*types.MinerConsensusFault{},
+		DDOPieces:       []*types.MinerDDOPiece{},
 	}
 
+	children := make(map[string][]*types.Transaction)
 	for _, tx := range transactions {
-		if !common.IsTxSuccess(tx) {
+		children[tx.ParentId] = append(children[tx.ParentId], tx)
+	}
+
+	for _, tx := range transactions {
+		success := common.IsTxSuccess(tx)
+		// failed disputes are kept, as they show the disputed PoSt was valid
+		if !success && tx.TxType != parser.MethodDisputeWindowedPoSt {
 			eg.logger.Debug("failed tx found, skipping it")
 			continue
 		}
@@ -63,16 +73,32 @@ func (eg *eventGenerator) GenerateMinerEvents(ctx context.Context, transactions
 		}
 
 		// #nosec G115
-		actorName, err := common.GetActorNameFromAddress(eg.helper, addr, int64(tx.Height), tipsetKey, true)
+		actorName, err := common.ResolveActorName(ctx, eg.helper, addr, int64(tx.Height), tipsetKey, true)
 		if err != nil {
 			_ = eg.metrics.UpdateActorNameFromAddressMetric()
 			return nil, err
 		}
 
-		if !eg.isMinerStateMessage(actorName, tx.TxType) {
+		if eg.isMinerProvingMessage(actorName, tx.TxType) {
+			provingEvent, err := eg.createProvingEvent(tx, tipsetCid)
+			if err != nil {
+				return nil, fmt.Errorf("could not create miner proving event. err: %w", err)
+			}
+			events.MinerProving = append(events.MinerProving, provingEvent)
+		}
+
+		if !success || !eg.isMinerStateMessage(actorName, tx.TxType) {
 			continue
 		}
 
+		if eg.isConsensusFaultMessage(actorName, tx.TxType) {
+			consensusFault, err := eg.createConsensusFault(tx, tipsetCid, children)
+			if err != nil {
+				return nil, fmt.Errorf("could not create consensus fault. err: %w", err)
+			}
+			events.ConsensusFaults = append(events.ConsensusFaults, consensusFault)
+		}
+
 		minerInfo, err := eg.createMinerInfo(tx, tipsetCid, actorAddress)
 		if err != nil {
 			return nil, fmt.Errorf("could not create miner info. err: %w", err)
@@ -87,6 +113,14 @@ func (eg *eventGenerator) GenerateMinerEvents(ctx context.Context, transactions
 			}
 			events.MinerSectors = append(events.MinerSectors, minerSectors...)
 		}
+
+		if eg.isDDOMessage(actorName, tx.TxType) {
+			ddoPieces, err := eg.createDDOPieces(tx, tipsetCid, children)
+			if err != nil {
+				return nil, fmt.Errorf("could not create ddo pieces. err: %w", err)
+			}
+			events.DDOPieces = append(events.DDOPieces, ddoPieces...)
+		}
 	}
 
 	return events, nil
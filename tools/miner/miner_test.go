@@ -2,8 +2,15 @@ package miner_test
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
 	"testing"
 
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	filBig "github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/filecoin-project/go-state-types/manifest"
 	filApiTypes "github.com/filecoin-project/lotus/api/types"
@@ -57,6 +64,11 @@ func setupTest(*testing.T) miner.EventGenerator {
 	cache.On("StoreAddressInfo", mock.Anything).Return(nil)
 	cache.On("GetActorCode", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(actorCidStr, nil)
 	cache.On("GetActorNameFromAddress", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(manifest.MinerKey, nil)
+	// the consensus fault reporter is the only address known in both formats
+	cache.On("GetShortAddress", isAddress(reporterRobust), mock.Anything).Return(reporterShort, nil)
+	cache.On("GetRobustAddress", isAddress(reporterShort), mock.Anything).Return(reporterRobust, nil)
+	cache.On("GetShortAddress", mock.Anything, mock.Anything).Return("", errors.New("address not found"))
+	cache.On("GetRobustAddress", mock.Anything, mock.Anything).Return("", errors.New("address not found"))
 
 	lib := rosettaFilecoinLib.NewRosettaConstructionFilecoin(node)
 	helper := helper.NewHelper(lib, cache, node, logger, metrics)
@@ -64,6 +76,17 @@ func setupTest(*testing.T) miner.EventGenerator {
 	return miner.NewEventGenerator(helper, logger, metrics, parser.Config{})
 }
 
+const (
+	reporterRobust = "f1gjdwtnnwjx6p5jgr5dudeodzy5xguuhwvl7lmva"
+	reporterShort  = "f01500"
+)
+
+func isAddress(want string) interface{} {
+	return mock.MatchedBy(func(addr address.Address) bool {
+		return addr.String() == want
+	})
+}
+
 func assertSectorEvents(t *testing.T, want []*types.MinerSectorEvent, got []*types.MinerSectorEvent) {
 	require.Equal(t, len(want), len(got))
 
@@ -289,6 +312,17 @@ func TestMinerSectors_ProveCommitStage(t *testing.T) {
 				MinerSectors: getSectorEvents(t, parser.MethodProveCommitSectors3, txTo, txCid, 3282),
 			},
 		},
+		{
+			name:      "Prove Commit Sectors 3 with a failed activation",
+			txType:    parser.MethodProveCommitSectors3,
+			actorName: manifest.MinerKey,
+			txFrom:    txFrom,
+			txTo:      txTo,
+			metadata:  `{"MethodNum":"34","Params":{"SectorActivations":[{"SectorNumber":3282,"Pieces":null},{"SectorNumber":3283,"Pieces":null}],"SectorProofs":["",""],"AggregateProof":null,"AggregateProofType":null,"RequireActivationSuccess":false,"RequireNotificationSuccess":false},"Return":{"SuccessCount":1,"FailCodes":[{"Idx":0,"Code":16}]}}`,
+			want: &types.MinerEvents{
+				MinerSectors: getSectorEvents(t, parser.MethodProveCommitSectors3, txTo, txCid, 3283),
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -379,6 +413,83 @@ func TestMinerSectors_TerminationFaultAndRecoveries(t *testing.T) {
 	}
 }
 
+func TestMinerSectors_ReplicaUpdateStage(t *testing.T) {
+	eg := setupTest(t)
+
+	tests := []struct {
+		name      string
+		txType    string
+		actorName string
+		txFrom    string
+		txTo      string
+		metadata  string
+		want      *types.MinerEvents
+		wantData  []string
+	}{
+		{
+			name:      "Prove Replica Updates",
+			txType:    parser.MethodProveReplicaUpdates,
+			actorName: manifest.MinerKey,
+			txFrom:    txFrom,
+			txTo:      txTo,
+			// only sector 10 is set in the returned bitfield
+			metadata: `{"MethodNum":"27","Params":{"Updates":[{"SectorID":10,"Deadline":1,"Partition":0,"NewSealedSectorCID":{"/":"bagboea4b5abcayrsf5tv5ea7nq6o3jjesdrqddzy3u5jbxul6azth4ndlvsj3qqe"},"Deals":[100,101],"UpdateProofType":3,"ReplicaProof":""},{"SectorID":11,"Deadline":1,"Partition":0,"NewSealedSectorCID":{"/":"bagboea4b5abcakafd36kxd4yea75jkou34ajvszgkk6vmw7ja7c23us3p3iacjks"},"Deals":[102],"UpdateProofType":3,"ReplicaProof":""}]},"Return":[10,1]}`,
+			want: &types.MinerEvents{
+				MinerSectors: getSectorEvents(t, parser.MethodProveReplicaUpdates, txTo, txCid, 10),
+			},
+			wantData: []string{`{"Deadline":1,"Partition":0,"NewSealedCID":{"/":"bagboea4b5abcayrsf5tv5ea7nq6o3jjesdrqddzy3u5jbxul6azth4ndlvsj3qqe"},"DealIDs":[100,101],"UpdateProofType":3}`},
+		},
+		{
+			name:      "Prove Replica Updates 2",
+			txType:    parser.MethodProveReplicaUpdates2,
+			actorName: manifest.MinerKey,
+			txFrom:    txFrom,
+			txTo:      txTo,
+			// only sector 10 is set in the returned bitfield
+			metadata: `{"MethodNum":"29","Params":{"Updates":[{"SectorID":10,"Deadline":1,"Partition":0,"NewSealedSectorCID":{"/":"bagboea4b5abcayrsf5tv5ea7nq6o3jjesdrqddzy3u5jbxul6azth4ndlvsj3qqe"},"NewUnsealedSectorCID":{"/":"baga6ea4seaqao7s73y24kcutaosvacpdjgfe5pw76ooefnyqw4ynr3d2y6x2mpq"},"Deals":[100],"UpdateProofType":3,"ReplicaProof":""},{"SectorID":11,"Deadline":1,"Partition":0,"NewSealedSectorCID":{"/":"bagboea4b5abcakafd36kxd4yea75jkou34ajvszgkk6vmw7ja7c23us3p3iacjks"},"NewUnsealedSectorCID":{"/":"baga6ea4seaqao7s73y24kcutaosvacpdjgfe5pw76ooefnyqw4ynr3d2y6x2mpq"},"Deals":[101],"UpdateProofType":3,"ReplicaProof":""}]},"Return":[10,1]}`,
+			want: &types.MinerEvents{
+				MinerSectors: getSectorEvents(t, parser.MethodProveReplicaUpdates2, txTo, txCid, 10),
+			},
+			wantData: []string{`{"Deadline":1,"Partition":0,"NewSealedCID":{"/":"bagboea4b5abcayrsf5tv5ea7nq6o3jjesdrqddzy3u5jbxul6azth4ndlvsj3qqe"},"NewUnsealedCID":{"/":"baga6ea4seaqao7s73y24kcutaosvacpdjgfe5pw76ooefnyqw4ynr3d2y6x2mpq"},"DealIDs":[100],"UpdateProofType":3}`},
+		},
+		{
+			name:      "Prove Replica Updates 3",
+			txType:    parser.MethodProveReplicaUpdates3,
+			actorName: manifest.MinerKey,
+			txFrom:    txFrom,
+			txTo:      txTo,
+			// the first update failed
+			metadata: `{"MethodNum":"35","Params":{"SectorUpdates":[{"Sector":12,"Deadline":3,"Partition":1,"NewSealedCID":{"/":"bagboea4b5abcayrsf5tv5ea7nq6o3jjesdrqddzy3u5jbxul6azth4ndlvsj3qqe"},"Pieces":null},{"Sector":13,"Deadline":3,"Partition":1,"NewSealedCID":{"/":"bagboea4b5abcakafd36kxd4yea75jkou34ajvszgkk6vmw7ja7c23us3p3iacjks"},"Pieces":[{"CID":{"/":"baga6ea4seaqao7s73y24kcutaosvacpdjgfe5pw76ooefnyqw4ynr3d2y6x2mpq"},"Size":34359738368,"VerifiedAllocationKey":{"Client":1234,"ID":5},"Notify":null}]}],"SectorProofs":["",""],"AggregateProof":null,"UpdateProofsType":3,"AggregateProofType":null,"RequireActivationSuccess":false,"RequireNotificationSuccess":false},"Return":{"SuccessCount":1,"FailCodes":[{"Idx":0,"Code":16}]}}`,
+			want: &types.MinerEvents{
+				MinerSectors: getSectorEvents(t, parser.MethodProveReplicaUpdates3, txTo, txCid, 13),
+			},
+			wantData: []string{`{"Deadline":3,"Partition":1,"NewSealedCID":{"/":"bagboea4b5abcakafd36kxd4yea75jkou34ajvszgkk6vmw7ja7c23us3p3iacjks"},"UpdateProofType":3,"Pieces":[{"CID":{"/":"baga6ea4seaqao7s73y24kcutaosvacpdjgfe5pw76ooefnyqw4ynr3d2y6x2mpq"},"Size":34359738368,"VerifiedAllocationKey":{"Client":1234,"ID":5},"Notify":null}]}`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.txType, func(t *testing.T) {
+			events, err := eg.GenerateMinerEvents(context.Background(), []*types.Transaction{
+				{
+					TxCid:         txCid,
+					TxType:        test.txType,
+					TxFrom:        test.txFrom,
+					TxTo:          test.txTo,
+					TxMetadata:    test.metadata,
+					Status:        tools.GetExitCodeStatus(exitcode.Ok),
+					SubcallStatus: tools.GetExitCodeStatus(exitcode.Ok),
+				},
+			}, tipsetCid, filTypes.EmptyTSK)
+			require.NoError(t, err)
+
+			assertSectorEvents(t, test.want.MinerSectors, events.MinerSectors)
+			for i, data := range test.wantData {
+				assert.JSONEq(t, data, events.MinerSectors[i].Data)
+			}
+		})
+	}
+}
+
 func TestMinerSectors_ExpiryExtension(t *testing.T) {
 	eg := setupTest(t)
 
@@ -446,3 +557,261 @@ func TestMinerSectors_ExpiryExtension(t *testing.T) {
 		})
 	}
 }
+
+func TestMinerProving(t *testing.T) {
+	eg := setupTest(t)
+	disputer := "f1gjdwtnnwjx6p5jgr5dudeodzy5xguuhwvl7lmva"
+
+	tests := []struct {
+		name     string
+		txType   string
+		txFrom   string
+		status   exitcode.ExitCode
+		metadata string
+		want     *types.MinerProvingEvent
+	}{
+		{
+			name:     "Submit Windowed PoSt",
+			txType:   parser.MethodSubmitWindowedPoSt,
+			txFrom:   txFrom,
+			status:   exitcode.Ok,
+			metadata: `{"MethodNum":"5","Params":{"Deadline":12,"Partitions":[{"Index":0,"Skipped":[0]},{"Index":1,"Skipped":[2,3]}],"Proofs":[{"PoStProof":13,"ProofBytes":""}],"ChainCommitEpoch":3500000,"ChainCommitRand":""}}`,
+			want: &types.MinerProvingEvent{
+				Deadline:         12,
+				Partitions:       []uint64{0, 1},
+				SkippedSectors:   []uint64{2, 3, 4},
+				ProofType:        13,
+				ChainCommitEpoch: 3500000,
+			},
+		},
+		{
+			name:     "Dispute Windowed PoSt",
+			txType:   parser.MethodDisputeWindowedPoSt,
+			txFrom:   disputer,
+			status:   exitcode.Ok,
+			metadata: `{"MethodNum":"32","Params":{"Deadline":7,"PoStIndex":1}}`,
+			want: &types.MinerProvingEvent{
+				Deadline:       7,
+				Partitions:     []uint64{},
+				SkippedSectors: []uint64{},
+				Disputer:       disputer,
+				PoStIndex:      1,
+				DisputeOutcome: miner.DisputeOutcomeSucceeded,
+			},
+		},
+		{
+			name:     "Rejected Dispute Windowed PoSt",
+			txType:   parser.MethodDisputeWindowedPoSt,
+			txFrom:   disputer,
+			status:   exitcode.ErrIllegalArgument,
+			metadata: `{"MethodNum":"32","Params":{"Deadline":7,"PoStIndex":0}}`,
+			want: &types.MinerProvingEvent{
+				Deadline:       7,
+				Partitions:     []uint64{},
+				SkippedSectors: []uint64{},
+				Disputer:       disputer,
+				DisputeOutcome: miner.DisputeOutcomeRejected,
+			},
+		},
+		{
+			name:     "Dispute Windowed PoSt out of gas",
+			txType:   parser.MethodDisputeWindowedPoSt,
+			txFrom:   disputer,
+			status:   exitcode.SysErrOutOfGas,
+			metadata: `{"MethodNum":"32","Params":{"Deadline":7,"PoStIndex":0}}`,
+			want: &types.MinerProvingEvent{
+				Deadline:       7,
+				Partitions:     []uint64{},
+				SkippedSectors: []uint64{},
+				Disputer:       disputer,
+				DisputeOutcome: miner.DisputeOutcomeError,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tx := &types.Transaction{
+				TxCid:         txCid,
+				TxType:        test.txType,
+				TxFrom:        test.txFrom,
+				TxTo:          txTo,
+				TxMetadata:    test.metadata,
+				Status:        tools.GetExitCodeStatus(test.status),
+				SubcallStatus: tools.GetExitCodeStatus(test.status),
+			}
+			tx.Height = 10
+			events, err := eg.GenerateMinerEvents(context.Background(), []*types.Transaction{tx}, tipsetCid, filTypes.EmptyTSK)
+			require.NoError(t, err)
+			require.Len(t, events.MinerProving, 1)
+
+			got := events.MinerProving[0]
+			assert.NotEmpty(t, got.ID)
+			assert.Equal(t, txTo, got.MinerAddress)
+			assert.Equal(t, test.txType, got.ActionType)
+			assert.Equal(t, uint64(10), got.Height)
+			assert.Equal(t, test.want.Deadline, got.Deadline)
+			assert.Equal(t, test.want.Partitions, got.Partitions)
+			assert.Equal(t, test.want.SkippedSectors, got.SkippedSectors)
+			assert.Equal(t, test.want.ProofType, got.ProofType)
+			assert.Equal(t, test.want.ChainCommitEpoch, got.ChainCommitEpoch)
+			assert.Equal(t, test.want.Disputer, got.Disputer)
+			assert.Equal(t, test.want.PoStIndex, got.PoStIndex)
+			assert.Equal(t, test.want.DisputeOutcome, got.DisputeOutcome)
+
+			if test.status != exitcode.Ok {
+				assert.Empty(t, events.MinerInfo, "failed txs don't update the miner info")
+			}
+		})
+	}
+}
+
+func encodedBlockHeader(t *testing.T, height abi.ChainEpoch, parents []cid.Cid) string {
+	minerAddr, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+	header := &filTypes.BlockHeader{
+		Miner:                 minerAddr,
+		Parents:               parents,
+		ParentWeight:          filBig.NewInt(1),
+		Height:                height,
+		ParentStateRoot:       actorCid,
+		ParentMessageReceipts: actorCid,
+		Messages:              actorCid,
+		ParentBaseFee:         filBig.NewInt(100),
+	}
+	raw, err := header.Serialize()
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func TestMinerConsensusFaults(t *testing.T) {
+	eg := setupTest(t)
+	reporter := reporterRobust
+
+	tests := []struct {
+		name          string
+		header1       string
+		header2       string
+		wantFaultType string
+	}{
+		{
+			name:          "double fork mining",
+			header1:       encodedBlockHeader(t, 100, []cid.Cid{actorCid}),
+			header2:       encodedBlockHeader(t, 100, nil),
+			wantFaultType: miner.FaultTypeDoubleForkMining,
+		},
+		{
+			name:          "time offset mining",
+			header1:       encodedBlockHeader(t, 100, []cid.Cid{actorCid}),
+			header2:       encodedBlockHeader(t, 101, []cid.Cid{actorCid}),
+			wantFaultType: miner.FaultTypeTimeOffsetMining,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ok := tools.GetExitCodeStatus(exitcode.Ok)
+			txs := []*types.Transaction{
+				{Id: "report", TxCid: txCid, TxType: parser.MethodReportConsensusFault, TxFrom: reporter, TxTo: txTo, Status: ok, SubcallStatus: ok,
+					TxMetadata: fmt.Sprintf(`{"MethodNum":"15","Params":{"BlockHeader1":%q,"BlockHeader2":%q,"BlockHeaderExtra":""}}`, test.header1, test.header2)},
+				{Id: "power", ParentId: "report", Level: 1, TxCid: txCid, TxType: parser.MethodOnConsensusFault, TxFrom: txTo, TxTo: "f04", Amount: big.NewInt(0), Status: ok, SubcallStatus: ok},
+				{Id: "burn", ParentId: "report", Level: 1, TxCid: txCid, TxType: parser.MethodSend, TxFrom: txTo, TxTo: parser.BurnAddress, Amount: big.NewInt(100), Status: ok, SubcallStatus: ok},
+				{Id: "reward", ParentId: "report", Level: 1, TxCid: txCid, TxType: parser.MethodSend, TxFrom: txTo, TxTo: reporter, Amount: big.NewInt(5), Status: ok, SubcallStatus: ok},
+				// the reporter in its short format
+				{Id: "reward-short", ParentId: "report", Level: 1, TxCid: txCid, TxType: parser.MethodSend, TxFrom: txTo, TxTo: reporterShort, Amount: big.NewInt(2), Status: ok, SubcallStatus: ok},
+				// neither burnt nor paid to the reporter
+				{Id: "transfer", ParentId: "report", Level: 1, TxCid: txCid, TxType: parser.MethodSend, TxFrom: txTo, TxTo: "f01999", Amount: big.NewInt(3), Status: ok, SubcallStatus: ok},
+				// burn of a different message
+				{Id: "other-burn", ParentId: "other", Level: 1, TxCid: txCid, TxType: parser.MethodSend, TxFrom: txTo, TxTo: parser.BurnAddress, Amount: big.NewInt(7), Status: ok, SubcallStatus: ok},
+			}
+
+			events, err := eg.GenerateMinerEvents(context.Background(), txs, tipsetCid, filTypes.EmptyTSK)
+			require.NoError(t, err)
+			require.Len(t, events.ConsensusFaults, 1)
+
+			fault := events.ConsensusFaults[0]
+			assert.Equal(t, txTo, fault.MinerAddress)
+			assert.Equal(t, reporter, fault.Reporter)
+			assert.Equal(t, txCid, fault.TxCid)
+			assert.Equal(t, int64(100), fault.FaultEpoch)
+			assert.Equal(t, test.wantFaultType, fault.FaultType)
+			assert.NotEmpty(t, fault.BlockHeader1Cid)
+			assert.NotEqual(t, fault.BlockHeader1Cid, fault.BlockHeader2Cid)
+			assert.Empty(t, fault.BlockHeaderExtraCid)
+			assert.Equal(t, big.NewInt(100), fault.PenaltyBurned)
+			assert.Equal(t, big.NewInt(7), fault.ReporterReward)
+		})
+	}
+}
+
+func TestMinerDDOPieces(t *testing.T) {
+	eg := setupTest(t)
+	ok := tools.GetExitCodeStatus(exitcode.Ok)
+	client, err := address.NewIDAddress(1000)
+	require.NoError(t, err)
+
+	pieces := func(allocationID int) string {
+		return fmt.Sprintf(`[{"CID":{"/":"baga6ea4seaqgvrjfj65lawcocwvrpgq7h53oghvto6akrys6wllhbbckchfgefy"},"Size":34359738368,"VerifiedAllocationKey":{"Client":1000,"ID":%d},"Notify":null},`+
+			`{"CID":{"/":"baga6ea4seaqbuieim7slc3wu7kms436xpnorao5jxr6tqftnqsysfxcp5dnduia"},"Size":2048,"VerifiedAllocationKey":null,"Notify":[{"Address":"f05","Payload":""}]}]`, allocationID)
+	}
+
+	tests := []struct {
+		name     string
+		txType   string
+		metadata string
+		claims   string
+		// want holds the sector, allocation id and claimed flag of every verified piece
+		want [][3]interface{}
+	}{
+		{
+			name:   "Prove Commit Sectors 3",
+			txType: parser.MethodProveCommitSectors3,
+			metadata: fmt.Sprintf(`{"MethodNum":"34","Params":{"SectorActivations":[{"SectorNumber":10,"Pieces":%s},{"SectorNumber":11,"Pieces":%s},{"SectorNumber":12,"Pieces":%s}],"RequireActivationSuccess":false},"Return":{"SuccessCount":2,"FailCodes":[{"Idx":2,"Code":16}]}}`,
+				pieces(501), pieces(502), pieces(503)),
+			// the claim of sector 11 failed
+			claims: `{"MethodNum":"9","Params":{"Sectors":[{"Sector":10,"SectorExpiry":5000000,"Claims":[{"Client":1000,"AllocationId":501,"Data":{"/":"baga6ea4seaqgvrjfj65lawcocwvrpgq7h53oghvto6akrys6wllhbbckchfgefy"},"Size":34359738368}]},` +
+				`{"Sector":11,"SectorExpiry":5000000,"Claims":[{"Client":1000,"AllocationId":502,"Data":{"/":"baga6ea4seaqgvrjfj65lawcocwvrpgq7h53oghvto6akrys6wllhbbckchfgefy"},"Size":34359738368}]}],"AllOrNothing":false},` +
+				`"Return":{"BatchInfo":{"SuccessCount":1,"FailCodes":[{"Idx":1,"Code":16}]},"ClaimedSpace":["34359738368"]}}`,
+			want: [][3]interface{}{{uint64(10), uint64(501), true}, {uint64(11), uint64(502), false}},
+		},
+		{
+			name:     "Prove Replica Updates 3 without claims",
+			txType:   parser.MethodProveReplicaUpdates3,
+			metadata: fmt.Sprintf(`{"MethodNum":"35","Params":{"SectorUpdates":[{"Sector":20,"Partition":0,"Deadline":1,"NewSealedCID":{"/":"bagboea4b5abcayrsf5tv5ea7nq6o3jjesdrqddzy3u5jbxul6azth4ndlvsj3qqe"},"Pieces":%s}],"UpdateProofsType":3},"Return":{"SuccessCount":1,"FailCodes":null}}`, pieces(601)),
+			want:     [][3]interface{}{{uint64(20), uint64(601), false}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			txs := []*types.Transaction{
+				{Id: "prove", TxCid: txCid, TxType: test.txType, TxFrom: txFrom, TxTo: txTo, TxMetadata: test.metadata, Status: ok, SubcallStatus: ok},
+			}
+			if test.claims != "" {
+				txs = append(txs, &types.Transaction{Id: "claim", ParentId: "prove", Level: 1, TxCid: txCid, TxType: parser.MethodClaimAllocations,
+					TxFrom: txTo, TxTo: "f06", TxMetadata: test.claims, Status: ok, SubcallStatus: ok})
+			}
+
+			events, err := eg.GenerateMinerEvents(context.Background(), txs, tipsetCid, filTypes.EmptyTSK)
+			require.NoError(t, err)
+			require.Len(t, events.DDOPieces, 2*len(test.want))
+
+			var verified [][3]interface{}
+			for _, piece := range events.DDOPieces {
+				assert.Equal(t, txTo, piece.MinerAddress)
+				assert.Equal(t, test.txType, piece.ActionType)
+				if !piece.Verified {
+					assert.Equal(t, uint64(2048), piece.PieceSize)
+					assert.Equal(t, []string{"f05"}, piece.NotifyAddresses)
+					assert.False(t, piece.Claimed)
+					continue
+				}
+				assert.Equal(t, "baga6ea4seaqgvrjfj65lawcocwvrpgq7h53oghvto6akrys6wllhbbckchfgefy", piece.PieceCid)
+				assert.Equal(t, client.String(), piece.AllocationClient)
+				assert.Empty(t, piece.NotifyAddresses)
+				verified = append(verified, [3]interface{}{piece.SectorNumber, piece.AllocationID, piece.Claimed})
+			}
+			assert.Equal(t, test.want, verified)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package miner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/manifest"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+const (
+	KeyPartitions       = "Partitions"
+	KeyIndex            = "Index"
+	KeySkipped          = "Skipped"
+	KeyProofs           = "Proofs"
+	KeyPoStProof        = "PoStProof"
+	KeyChainCommitEpoch = "ChainCommitEpoch"
+	KeyPoStIndex        = "PoStIndex"
+)
+
+const (
+	// DisputeOutcomeSucceeded means the disputed PoSt was invalid and the miner was penalized.
+	DisputeOutcomeSucceeded = "succeeded"
+	// DisputeOutcomeRejected means the actor rejected the dispute as an illegal argument. The exit code doesn't tell
+	// whether the disputed PoSt verified or the deadline or PoSt index were invalid.
+	DisputeOutcomeRejected = "rejected"
+	// DisputeOutcomeError means the dispute message failed for a reason unrelated to the PoSt, such as running out of gas.
+	DisputeOutcomeError = "error"
+)
+
+func (eg *eventGenerator) isMinerProvingMessage(actorName, txType string) bool {
+	if actorName != manifest.MinerKey {
+		return false
+	}
+	return txType == parser.MethodSubmitWindowedPoSt || txType == parser.MethodDisputeWindowedPoSt
+}
+
+func (eg *eventGenerator) createProvingEvent(tx *types.Transaction, tipsetCid string) (*types.MinerProvingEvent, error) {
+	var value map[string]interface{}
+	err := json.Unmarshal([]byte(tx.TxMetadata), &value)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling tx metadata: %w", err)
+	}
+
+	params, err := common.GetItem[map[string]interface{}](value, KeyParams, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing params: %w", err)
+	}
+
+	deadline, err := common.GetInteger[uint64](params, KeyDeadline, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing deadline: %w", err)
+	}
+
+	event := &types.MinerProvingEvent{
+		ID:             tools.BuildId(tipsetCid, tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType),
+		MinerAddress:   tx.TxTo,
+		Height:         tx.Height,
+		TxCid:          tx.TxCid,
+		ActionType:     tx.TxType,
+		Deadline:       deadline,
+		Partitions:     []uint64{},
+		SkippedSectors: []uint64{},
+		TxTimestamp:    tx.TxTimestamp,
+	}
+
+	switch tx.TxType {
+	case parser.MethodSubmitWindowedPoSt:
+		if err := parseSubmitWindowedPoSt(params, event); err != nil {
+			return nil, err
+		}
+	case parser.MethodDisputeWindowedPoSt:
+		postIndex, err := common.GetInteger[uint64](params, KeyPoStIndex, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing post index: %w", err)
+		}
+		event.PoStIndex = postIndex
+		event.Disputer = tx.TxFrom
+		event.DisputeOutcome = disputeOutcome(tx)
+	}
+
+	return event, nil
+}
+
+// disputeOutcome derives the outcome of a DisputeWindowedPoSt from its exit code.
+func disputeOutcome(tx *types.Transaction) string {
+	if common.IsTxSuccess(tx) {
+		return DisputeOutcomeSucceeded
+	}
+	if strings.EqualFold(tx.SubcallStatus, tools.GetExitCodeStatus(exitcode.ErrIllegalArgument)) {
+		return DisputeOutcomeRejected
+	}
+	return DisputeOutcomeError
+}
+
+func parseSubmitWindowedPoSt(params map[string]interface{}, event *types.MinerProvingEvent) error {
+	partitions, err := common.GetSlice[map[string]interface{}](params, KeyPartitions, false)
+	if err != nil {
+		return fmt.Errorf("error parsing partitions: %w", err)
+	}
+	for _, partition := range partitions {
+		index, err := common.GetInteger[uint64](partition, KeyIndex, false)
+		if err != nil {
+			return fmt.Errorf("error parsing partition index: %w", err)
+		}
+		event.Partitions = append(event.Partitions, index)
+
+		skippedBitField, err := common.GetIntegerSlice[int](partition, KeySkipped, true)
+		if err != nil {
+			return fmt.Errorf("error parsing skipped sectors: %w", err)
+		}
+		if len(skippedBitField) == 0 {
+			continue
+		}
+		skipped, err := common.JsonEncodedBitfieldToIDs(skippedBitField)
+		if err != nil {
+			return fmt.Errorf("error parsing skipped sectors bitfield: %w", err)
+		}
+		event.SkippedSectors = append(event.SkippedSectors, skipped...)
+	}
+
+	// there is a proof per distinct proof type of the proven sectors, which in practice is always one
+	proofs, err := common.GetSlice[map[string]interface{}](params, KeyProofs, true)
+	if err != nil {
+		return fmt.Errorf("error parsing proofs: %w", err)
+	}
+	if len(proofs) > 0 {
+		proofType, err := common.GetInteger[int64](proofs[0], KeyPoStProof, false)
+		if err != nil {
+			return fmt.Errorf("error parsing proof type: %w", err)
+		}
+		event.ProofType = proofType
+	}
+
+	chainCommitEpoch, err := common.GetInteger[int64](params, KeyChainCommitEpoch, false)
+	if err != nil {
+		return fmt.Errorf("error parsing chain commit epoch: %w", err)
+	}
+	event.ChainCommitEpoch = chainCommitEpoch
+	return nil
+}
@@ -32,6 +32,19 @@ const (
 	KeyAddress               = "Address"
 	KeySealerID              = "SealerID"
 	KeyVerifiedAllocationKey = "VerifiedAllocationKey"
+	KeyUpdates               = "Updates"
+	KeySectorUpdates         = "SectorUpdates"
+	KeySectorID              = "SectorID"
+	KeySector                = "Sector"
+	KeyDeadline              = "Deadline"
+	KeyPartition             = "Partition"
+	KeyDeals                 = "Deals"
+	KeyNewSealedSectorCID    = "NewSealedSectorCID"
+	KeyNewUnsealedSectorCID  = "NewUnsealedSectorCID"
+	KeyNewSealedCID          = "NewSealedCID"
+	KeyNewUnsealedCID        = "NewUnsealedCID"
+	KeyUpdateProofType       = "UpdateProofType"
+	KeyUpdateProofsType      = "UpdateProofsType"
 )
 
 func (eg *eventGenerator) isMinerSectorMessage(actorName, txType string) bool {
@@ -52,6 +65,11 @@ func (eg *eventGenerator) isMinerSectorMessage(actorName, txType string) bool {
 		parser.MethodConfirmSectorProofsValid,
 		parser.MethodProveCommitAggregate,
 
+		// replica update (snap deal) stage
+		parser.MethodProveReplicaUpdates,
+		parser.MethodProveReplicaUpdates2,
+		parser.MethodProveReplicaUpdates3,
+
 		// termination and recovery stage
 		parser.MethodTerminateSectors,
 		parser.MethodDeclareFaults,
@@ -87,12 +105,19 @@ func (eg *eventGenerator) createSectorEvents(ctx context.Context, tx *types.Tran
 		return sectorEvents, nil
 
 	case parser.MethodConfirmSectorProofsValid, parser.MethodProveCommitAggregate, parser.MethodProveCommitSector, parser.MethodProveCommitSectors3, parser.MethodProveCommitSectorsNI:
-		sectorEvents, err := eg.parseProveCommitStage(ctx, tx, tipsetCid, params)
+		sectorEvents, err := eg.parseProveCommitStage(ctx, tx, tipsetCid, params, value)
 		if err != nil {
 			return nil, fmt.Errorf("error parsing prove commit stage: %w", err)
 		}
 		return sectorEvents, nil
 
+	case parser.MethodProveReplicaUpdates, parser.MethodProveReplicaUpdates2, parser.MethodProveReplicaUpdates3:
+		sectorEvents, err := eg.parseReplicaUpdateStage(ctx, tx, tipsetCid, params, value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing replica update stage: %w", err)
+		}
+		return sectorEvents, nil
+
 	case parser.MethodTerminateSectors, parser.MethodDeclareFaults, parser.MethodDeclareFaultsRecovered:
 		sectorEvents, err := eg.parseSectorTerminationFaultAndRecoveries(ctx, tx, tipsetCid, params)
 		if err != nil {
@@ -169,7 +194,7 @@ func (eg *eventGenerator) parsePreCommitStage(_ context.Context, tx *types.Trans
 	return sectorEvents, nil
 }
 
-func (eg *eventGenerator) parseProveCommitStage(ctx context.Context, tx *types.Transaction, tipsetCid string, params map[string]interface{}) ([]*types.MinerSectorEvent, error) {
+func (eg *eventGenerator) parseProveCommitStage(ctx context.Context, tx *types.Transaction, tipsetCid string, params, value map[string]interface{}) ([]*types.MinerSectorEvent, error) {
 	var sectorEvents []*types.MinerSectorEvent
 
 	switch tx.TxType {
@@ -192,13 +217,164 @@ func (eg *eventGenerator) parseProveCommitStage(ctx context.Context, tx *types.T
 	case parser.MethodConfirmSectorProofsValid:
 		return eg.parseConfirmSectorProofsValid(ctx, tx, tipsetCid, params)
 	case parser.MethodProveCommitSectors3:
-		return eg.parseProveCommitSectors3(ctx, tx, tipsetCid, params)
+		return eg.parseProveCommitSectors3(ctx, tx, tipsetCid, params, value)
 	case parser.MethodProveCommitSectorsNI:
-		return eg.parseProveCommitSectorsNI(ctx, tx, tipsetCid, params)
+		return eg.parseProveCommitSectorsNI(ctx, tx, tipsetCid, params, value)
 	}
 	return nil, fmt.Errorf("unexpected method: %s", tx.TxType)
 }
 
+// parseReplicaUpdateStage creates an event per sector upgraded with data (snap deal).
+// Updates reported as failed in the return are skipped, as the sector keeps its previous sealed cid.
+func (eg *eventGenerator) parseReplicaUpdateStage(ctx context.Context, tx *types.Transaction, tipsetCid string, params, value map[string]interface{}) ([]*types.MinerSectorEvent, error) {
+	if tx.TxType == parser.MethodProveReplicaUpdates3 {
+		return eg.parseProveReplicaUpdates3(ctx, tx, tipsetCid, params, value)
+	}
+
+	updates, err := common.GetSlice[map[string]interface{}](params, KeyUpdates, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing updates: %w", err)
+	}
+
+	// ProveReplicaUpdates and ProveReplicaUpdates2 return the bitfield of the updated sectors
+	var updatedSectors map[uint64]bool
+	if value[parser.ReturnKey] != nil {
+		updatedBitField, err := common.GetIntegerSlice[int](value, parser.ReturnKey, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing return: %w", err)
+		}
+		updatedSectorNumbers, err := common.JsonEncodedBitfieldToIDs(updatedBitField)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing updated sectors bitfield: %w", err)
+		}
+		updatedSectors = make(map[uint64]bool, len(updatedSectorNumbers))
+		for _, sectorNumber := range updatedSectorNumbers {
+			updatedSectors[sectorNumber] = true
+		}
+	}
+
+	var sectorEvents []*types.MinerSectorEvent
+	for _, update := range updates {
+		sectorNumber, err := common.GetInteger[uint64](update, KeySectorID, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing sector id: %w", err)
+		}
+		if updatedSectors != nil && !updatedSectors[sectorNumber] {
+			continue
+		}
+		data, err := replicaUpdateData(update)
+		if err != nil {
+			return nil, err
+		}
+		newSealedCID, err := common.GetItem[map[string]interface{}](update, KeyNewSealedSectorCID, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing new sealed sector cid: %w", err)
+		}
+		data[KeyNewSealedCID] = newSealedCID
+		if tx.TxType == parser.MethodProveReplicaUpdates2 {
+			newUnsealedCID, err := common.GetItem[map[string]interface{}](update, KeyNewUnsealedSectorCID, false)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing new unsealed sector cid: %w", err)
+			}
+			data[KeyNewUnsealedCID] = newUnsealedCID
+		}
+		dealIDs, err := common.GetIntegerSlice[uint64](update, KeyDeals, true)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing deal ids: %w", err)
+		}
+		data[KeyDealIDs] = dealIDs
+		updateProofType, err := common.GetInteger[int64](update, KeyUpdateProofType, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing update proof type: %w", err)
+		}
+		data[KeyUpdateProofType] = updateProofType
+
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling event: %w", err)
+		}
+		sectorEvents = append(sectorEvents, createSectorEvent(tipsetCid, tx, sectorNumber, jsonData))
+	}
+	return sectorEvents, nil
+}
+
+func (eg *eventGenerator) parseProveReplicaUpdates3(ctx context.Context, tx *types.Transaction, tipsetCid string, params, value map[string]interface{}) ([]*types.MinerSectorEvent, error) {
+	sectorUpdates, err := common.GetSlice[map[string]interface{}](params, KeySectorUpdates, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing sector updates: %w", err)
+	}
+	updateProofType, err := common.GetInteger[int64](params, KeyUpdateProofsType, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing update proofs type: %w", err)
+	}
+
+	// the batch return lists the index of every failed update
+	batchReturn, err := common.GetItem[map[string]interface{}](value, parser.ReturnKey, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing return: %w", err)
+	}
+	failedUpdates, err := common.FailedBatchIndexes(batchReturn)
+	if err != nil {
+		return nil, err
+	}
+
+	var sectorEvents []*types.MinerSectorEvent
+	for i, sectorUpdate := range sectorUpdates {
+		if failedUpdates[i] {
+			continue
+		}
+		sectorNumber, err := common.GetInteger[uint64](sectorUpdate, KeySector, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing sector number: %w", err)
+		}
+		data, err := replicaUpdateData(sectorUpdate)
+		if err != nil {
+			return nil, err
+		}
+		newSealedCID, err := common.GetItem[map[string]interface{}](sectorUpdate, KeyNewSealedCID, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing new sealed cid: %w", err)
+		}
+		data[KeyNewSealedCID] = newSealedCID
+		data[KeyUpdateProofType] = updateProofType
+
+		pieces, err := common.GetSlice[map[string]interface{}](sectorUpdate, KeyPieces, true)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing pieces: %w", err)
+		}
+		if eg.config.ConsolidateRobustAddress && len(pieces) > 0 {
+			pieces, err = eg.consolidatePieceActivationManifests(ctx, pieces)
+			if err != nil {
+				return nil, fmt.Errorf("error consolidating piece activation manifests: %w", err)
+			}
+		}
+		data[KeyPieces] = pieces
+
+		jsonData, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling event: %w", err)
+		}
+		sectorEvents = append(sectorEvents, createSectorEvent(tipsetCid, tx, sectorNumber, jsonData))
+	}
+	return sectorEvents, nil
+}
+
+// replicaUpdateData returns the location of the updated sector, shared by every replica update method.
+func replicaUpdateData(update map[string]interface{}) (map[string]interface{}, error) {
+	deadline, err := common.GetInteger[uint64](update, KeyDeadline, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing deadline: %w", err)
+	}
+	partition, err := common.GetInteger[uint64](update, KeyPartition, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing partition: %w", err)
+	}
+	return map[string]interface{}{
+		KeyDeadline:  deadline,
+		KeyPartition: partition,
+	}, nil
+}
+
 func (eg *eventGenerator) parseSectorTerminationFaultAndRecoveries(_ context.Context, tx *types.Transaction, tipsetCid string, params map[string]interface{}) ([]*types.MinerSectorEvent, error) {
 	var sectorEvents []*types.MinerSectorEvent
 	var parameterName string
@@ -273,13 +449,20 @@ func (eg *eventGenerator) parseSectorExpiryExtensions(_ context.Context, tx *typ
 	return sectorEvents, nil
 }
 
-func (eg *eventGenerator) parseProveCommitSectorsNI(_ context.Context, tx *types.Transaction, tipsetCid string, params map[string]interface{}) ([]*types.MinerSectorEvent, error) {
+func (eg *eventGenerator) parseProveCommitSectorsNI(_ context.Context, tx *types.Transaction, tipsetCid string, params, value map[string]interface{}) ([]*types.MinerSectorEvent, error) {
 	sectorActivations, err := common.GetSlice[map[string]interface{}](params, KeySectors, false)
 	if err != nil {
 		return nil, err
 	}
+	failedActivations, err := failedActivationIndexes(value)
+	if err != nil {
+		return nil, err
+	}
 	var sectorEvents []*types.MinerSectorEvent
-	for _, sectorActivation := range sectorActivations {
+	for i, sectorActivation := range sectorActivations {
+		if failedActivations[i] {
+			continue
+		}
 		sectorNumber, err := common.GetInteger[uint64](sectorActivation, KeySectorNumber, false)
 		if err != nil {
 			return nil, fmt.Errorf("error parsing sector number: %w", err)
@@ -307,13 +490,20 @@ func (eg *eventGenerator) parseProveCommitSectorsNI(_ context.Context, tx *types
 	return sectorEvents, nil
 }
 
-func (eg *eventGenerator) parseProveCommitSectors3(ctx context.Context, tx *types.Transaction, tipsetCid string, params map[string]interface{}) ([]*types.MinerSectorEvent, error) {
+func (eg *eventGenerator) parseProveCommitSectors3(ctx context.Context, tx *types.Transaction, tipsetCid string, params, value map[string]interface{}) ([]*types.MinerSectorEvent, error) {
 	sectorActivations, err := common.GetSlice[map[string]interface{}](params, KeySectorActivations, false)
 	if err != nil {
 		return nil, err
 	}
+	failedActivations, err := failedActivationIndexes(value)
+	if err != nil {
+		return nil, err
+	}
 	var sectorEvents []*types.MinerSectorEvent
-	for _, sectorActivation := range sectorActivations {
+	for i, sectorActivation := range sectorActivations {
+		if failedActivations[i] {
+			continue
+		}
 		sectorNumber, err := common.GetInteger[uint64](sectorActivation, KeySectorNumber, false)
 		if err != nil {
 			return nil, fmt.Errorf("error parsing sector number: %w", err)
@@ -342,6 +532,16 @@ func (eg *eventGenerator) parseProveCommitSectors3(ctx context.Context, tx *type
 	return sectorEvents, nil
 }
 
+// failedActivationIndexes returns the indexes of the sectors that failed activation,
+// as listed in the batch return of ProveCommitSectors3 and ProveCommitSectorsNI.
+func failedActivationIndexes(value map[string]interface{}) (map[int]bool, error) {
+	batchReturn, err := common.GetItem[map[string]interface{}](value, parser.ReturnKey, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing return: %w", err)
+	}
+	return common.FailedBatchIndexes(batchReturn)
+}
+
 func (eg *eventGenerator) parseConfirmSectorProofsValid(_ context.Context, tx *types.Transaction, tipsetCid string, params map[string]interface{}) ([]*types.MinerSectorEvent, error) {
 	sectors, err := common.GetIntegerSlice[int64](params, KeySectors, false)
 	if err != nil {
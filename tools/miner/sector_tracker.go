@@ -0,0 +1,317 @@
+package miner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+// SectorState is the lifecycle state of a sector.
+type SectorState string
+
+const (
+	SectorStatePreCommitted SectorState = "precommitted"
+	SectorStateActive       SectorState = "active"
+	SectorStateFaulty       SectorState = "faulty"
+	SectorStateRecovering   SectorState = "recovering"
+	SectorStateTerminated   SectorState = "terminated"
+	SectorStateExpired      SectorState = "expired"
+)
+
+// sectorSnapshotVersion is bumped whenever the snapshot layout changes.
+const sectorSnapshotVersion = 2
+
+// Sector is the current state of a sector.
+type Sector struct {
+	MinerAddress string      `json:"miner_address"`
+	SectorNumber uint64      `json:"sector_number"`
+	State        SectorState `json:"state"`
+	Expiration   int64       `json:"expiration"`
+	DealIDs      []uint64    `json:"deal_ids"`
+	// Deadline and Partition are only known once a message references the sector location.
+	Deadline  *uint64 `json:"deadline,omitempty"`
+	Partition *uint64 `json:"partition,omitempty"`
+	// UpdatedAt is the height of the last change to the sector.
+	UpdatedAt uint64 `json:"updated_at"`
+}
+
+type sectorKey struct {
+	minerAddress string
+	sectorNumber uint64
+}
+
+// sectorEventData holds the fields of MinerSectorEvent.Data used to track the sector state.
+type sectorEventData struct {
+	Expiration    *int64   `json:"Expiration"`
+	NewExpiration *int64   `json:"NewExpiration"`
+	DealIDs       []uint64 `json:"DealIDs"`
+	Deadline      *uint64  `json:"Deadline"`
+	Partition     *uint64  `json:"Partition"`
+}
+
+// SectorTracker keeps the current state of every sector seen in the miner events.
+// Tipsets are applied one call each, in increasing height order. The tracker is not safe for concurrent use.
+type SectorTracker struct {
+	height  uint64
+	sectors map[string]map[uint64]*Sector
+	// expirations indexes the sectors that can still expire by their expiration epoch
+	expirations *common.ExpirationIndex[sectorKey]
+}
+
+func NewSectorTracker() *SectorTracker {
+	return &SectorTracker{
+		sectors:     make(map[string]map[uint64]*Sector),
+		expirations: common.NewExpirationIndex[sectorKey](),
+	}
+}
+
+// ImportSectorTracker restores a tracker from a snapshot written by Export.
+func ImportSectorTracker(r io.Reader) (*SectorTracker, error) {
+	sectors, height, err := common.ImportSnapshot[[]*Sector](r, "sector", sectorSnapshotVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := NewSectorTracker()
+	tracker.height = height
+	for _, sector := range sectors {
+		tracker.set(sector)
+		tracker.indexExpiration(sector)
+	}
+	return tracker, nil
+}
+
+// Export writes a snapshot of the tracker, with the sectors sorted by miner and sector number.
+func (t *SectorTracker) Export(w io.Writer) error {
+	return common.ExportSnapshot(w, "sector", sectorSnapshotVersion, t.height, t.Sectors())
+}
+
+// Height returns the height of the last applied tipset.
+func (t *SectorTracker) Height() uint64 {
+	return t.height
+}
+
+// Sector returns the current state of a sector.
+func (t *SectorTracker) Sector(minerAddress string, sectorNumber uint64) (*Sector, bool) {
+	sector, ok := t.sectors[minerAddress][sectorNumber]
+	return sector, ok
+}
+
+// Sectors returns every tracked sector, sorted by miner and sector number.
+func (t *SectorTracker) Sectors() []*Sector {
+	sectors := make([]*Sector, 0)
+	for _, minerSectors := range t.sectors {
+		for _, sector := range minerSectors {
+			sectors = append(sectors, sector)
+		}
+	}
+	sort.Slice(sectors, func(i, j int) bool {
+		if sectors[i].MinerAddress != sectors[j].MinerAddress {
+			return sectors[i].MinerAddress < sectors[j].MinerAddress
+		}
+		return sectors[i].SectorNumber < sectors[j].SectorNumber
+	})
+	return sectors
+}
+
+// Apply updates the tracker with the miner events of the tipset at height and returns the resulting state transitions.
+// Sectors whose expiration is before height expire first, then sector events are applied in order, followed by the
+// proving events, which mark skipped sectors as faulty and recovering sectors in the proven partitions as active.
+// The events are validated before any change, so on error the tracker is left as it was and the tipset can be retried.
+func (t *SectorTracker) Apply(height uint64, events *types.MinerEvents) ([]*types.SectorStateTransition, error) {
+	if err := common.CheckTrackerHeight(t.height, height); err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = &types.MinerEvents{}
+	}
+	data := make([]sectorEventData, len(events.MinerSectors))
+	for i, event := range events.MinerSectors {
+		if event.Data == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(event.Data), &data[i]); err != nil {
+			return nil, fmt.Errorf("error parsing data of %s sector event %d: %w", event.ActionType, event.SectorNumber, err)
+		}
+	}
+
+	t.height = height
+	transitions := t.expire(height)
+	for i, event := range events.MinerSectors {
+		if transition := t.applySectorEvent(event, data[i]); transition != nil {
+			transitions = append(transitions, transition)
+		}
+	}
+	for _, event := range events.MinerProving {
+		if event.ActionType != parser.MethodSubmitWindowedPoSt {
+			continue
+		}
+		transitions = append(transitions, t.applyProvingEvent(event)...)
+	}
+	return transitions, nil
+}
+
+func (t *SectorTracker) applySectorEvent(event *types.MinerSectorEvent, data sectorEventData) *types.SectorStateTransition {
+	sector, found := t.Sector(event.MinerAddress, event.SectorNumber)
+	if !found {
+		sector = &Sector{MinerAddress: event.MinerAddress, SectorNumber: event.SectorNumber}
+	}
+	if data.Deadline != nil && data.Partition != nil {
+		sector.Deadline = data.Deadline
+		sector.Partition = data.Partition
+	}
+
+	var next SectorState
+	switch event.ActionType {
+	case parser.MethodPreCommitSector, parser.MethodPreCommitSectorBatch, parser.MethodPreCommitSectorBatch2:
+		next = SectorStatePreCommitted
+		sector.DealIDs = data.DealIDs
+	case parser.MethodProveCommitSector, parser.MethodProveCommitAggregate, parser.MethodConfirmSectorProofsValid,
+		parser.MethodProveCommitSectors3, parser.MethodProveCommitSectorsNI:
+		next = SectorStateActive
+	case parser.MethodProveReplicaUpdates, parser.MethodProveReplicaUpdates2, parser.MethodProveReplicaUpdates3:
+		// the sector keeps its state, only its content changes
+		if !found {
+			return nil
+		}
+		next = sector.State
+		if event.ActionType != parser.MethodProveReplicaUpdates3 {
+			// ProveReplicaUpdates3 activates pieces instead of deals
+			sector.DealIDs = data.DealIDs
+		}
+	case parser.MethodDeclareFaults:
+		next = SectorStateFaulty
+	case parser.MethodDeclareFaultsRecovered:
+		next = SectorStateRecovering
+	case parser.MethodTerminateSectors:
+		next = SectorStateTerminated
+	case parser.MethodExtendSectorExpiration, parser.MethodExtendSectorExpiration2:
+		if !found {
+			return nil
+		}
+		next = sector.State
+	default:
+		return nil
+	}
+
+	expiration := sector.Expiration
+	switch {
+	case data.NewExpiration != nil:
+		expiration = *data.NewExpiration
+	case data.Expiration != nil:
+		expiration = *data.Expiration
+	}
+	t.setExpiration(sector, expiration)
+
+	return t.transition(sector, next, event.TxCid, event.ActionType)
+}
+
+func (t *SectorTracker) applyProvingEvent(event *types.MinerProvingEvent) []*types.SectorStateTransition {
+	var transitions []*types.SectorStateTransition
+	skipped := make(map[uint64]bool, len(event.SkippedSectors))
+	for _, sectorNumber := range event.SkippedSectors {
+		skipped[sectorNumber] = true
+		sector, ok := t.Sector(event.MinerAddress, sectorNumber)
+		if !ok || (sector.State != SectorStateActive && sector.State != SectorStateRecovering) {
+			continue
+		}
+		transitions = append(transitions, t.transition(sector, SectorStateFaulty, event.TxCid, event.ActionType))
+	}
+
+	partitions := make(map[uint64]bool, len(event.Partitions))
+	for _, partition := range event.Partitions {
+		partitions[partition] = true
+	}
+	var recovered []*Sector
+	for _, sector := range t.sectors[event.MinerAddress] {
+		if sector.State != SectorStateRecovering || skipped[sector.SectorNumber] || sector.Deadline == nil || sector.Partition == nil {
+			continue
+		}
+		if *sector.Deadline == event.Deadline && partitions[*sector.Partition] {
+			recovered = append(recovered, sector)
+		}
+	}
+	sort.Slice(recovered, func(i, j int) bool {
+		return recovered[i].SectorNumber < recovered[j].SectorNumber
+	})
+	for _, sector := range recovered {
+		transitions = append(transitions, t.transition(sector, SectorStateActive, event.TxCid, event.ActionType))
+	}
+	return transitions
+}
+
+// expire moves the sectors whose expiration is before height to the expired state.
+func (t *SectorTracker) expire(height uint64) []*types.SectorStateTransition {
+	keys := t.expirations.PopExpired(height, func(a, b sectorKey) bool {
+		if a.minerAddress != b.minerAddress {
+			return a.minerAddress < b.minerAddress
+		}
+		return a.sectorNumber < b.sectorNumber
+	})
+
+	var transitions []*types.SectorStateTransition
+	for _, key := range keys {
+		sector := t.sectors[key.minerAddress][key.sectorNumber]
+		transitions = append(transitions, t.transition(sector, SectorStateExpired, "", ""))
+	}
+	return transitions
+}
+
+// transition moves sector to state, returning nil if the state didn't change.
+func (t *SectorTracker) transition(sector *Sector, state SectorState, txCid, actionType string) *types.SectorStateTransition {
+	sector.UpdatedAt = t.height
+	t.set(sector)
+	from := sector.State
+	if from == state {
+		return nil
+	}
+	sector.State = state
+	t.indexExpiration(sector)
+
+	return &types.SectorStateTransition{
+		ID:           tools.BuildId(sector.MinerAddress, fmt.Sprint(sector.SectorNumber), fmt.Sprint(t.height), string(state)),
+		MinerAddress: sector.MinerAddress,
+		SectorNumber: sector.SectorNumber,
+		FromState:    string(from),
+		ToState:      string(state),
+		Height:       t.height,
+		Expiration:   sector.Expiration,
+		TxCid:        txCid,
+		ActionType:   actionType,
+	}
+}
+
+func (t *SectorTracker) set(sector *Sector) {
+	minerSectors, ok := t.sectors[sector.MinerAddress]
+	if !ok {
+		minerSectors = make(map[uint64]*Sector)
+		t.sectors[sector.MinerAddress] = minerSectors
+	}
+	minerSectors[sector.SectorNumber] = sector
+}
+
+func (t *SectorTracker) setExpiration(sector *Sector, expiration int64) {
+	t.unindexExpiration(sector)
+	sector.Expiration = expiration
+	t.indexExpiration(sector)
+}
+
+// indexExpiration adds the sector to the expiration index, unless it already reached a final state.
+func (t *SectorTracker) indexExpiration(sector *Sector) {
+	key := sectorKey{minerAddress: sector.MinerAddress, sectorNumber: sector.SectorNumber}
+	if sector.State == SectorStateTerminated || sector.State == SectorStateExpired || sector.Expiration <= 0 {
+		t.unindexExpiration(sector)
+		return
+	}
+	t.expirations.Add(sector.Expiration, key)
+}
+
+func (t *SectorTracker) unindexExpiration(sector *Sector) {
+	t.expirations.Remove(sector.Expiration, sectorKey{minerAddress: sector.MinerAddress, sectorNumber: sector.SectorNumber})
+}
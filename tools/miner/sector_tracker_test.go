@@ -0,0 +1,135 @@
+package miner_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/tools/miner"
+	"github.com/zondax/fil-parser/types"
+)
+
+func sectorEvent(t *testing.T, txType string, sectorNumber uint64, data map[string]interface{}) *types.MinerSectorEvent {
+	jsonData, err := json.Marshal(data)
+	require.NoError(t, err)
+	return &types.MinerSectorEvent{
+		MinerAddress: txTo,
+		SectorNumber: sectorNumber,
+		TxCid:        txCid,
+		ActionType:   txType,
+		Data:         string(jsonData),
+	}
+}
+
+func states(transitions []*types.SectorStateTransition) []string {
+	var result []string
+	for _, transition := range transitions {
+		result = append(result, transition.FromState+">"+transition.ToState)
+	}
+	return result
+}
+
+func TestSectorTracker(t *testing.T) {
+	tracker := miner.NewSectorTracker()
+	location := map[string]interface{}{miner.KeyDeadline: 2, miner.KeyPartition: 0}
+
+	transitions, err := tracker.Apply(100, &types.MinerEvents{MinerSectors: []*types.MinerSectorEvent{
+		sectorEvent(t, parser.MethodPreCommitSectorBatch2, 1, map[string]interface{}{miner.KeyExpiration: 500, miner.KeyDealIDs: []uint64{7}}),
+		sectorEvent(t, parser.MethodPreCommitSectorBatch2, 2, map[string]interface{}{miner.KeyExpiration: 300}),
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{">precommitted", ">precommitted"}, states(transitions))
+
+	transitions, err = tracker.Apply(110, &types.MinerEvents{MinerSectors: []*types.MinerSectorEvent{
+		sectorEvent(t, parser.MethodProveCommitAggregate, 1, nil),
+		sectorEvent(t, parser.MethodProveCommitAggregate, 2, nil),
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"precommitted>active", "precommitted>active"}, states(transitions))
+
+	transitions, err = tracker.Apply(120, &types.MinerEvents{MinerSectors: []*types.MinerSectorEvent{
+		sectorEvent(t, parser.MethodDeclareFaults, 1, location),
+		sectorEvent(t, parser.MethodDeclareFaultsRecovered, 1, location),
+		sectorEvent(t, parser.MethodExtendSectorExpiration2, 2, map[string]interface{}{miner.KeyNewExpiration: 145}),
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"active>faulty", "faulty>recovering"}, states(transitions))
+
+	t.Run("proving a partition activates its recovering sectors", func(t *testing.T) {
+		transitions, err := tracker.Apply(130, &types.MinerEvents{MinerProving: []*types.MinerProvingEvent{{
+			MinerAddress: txTo,
+			ActionType:   parser.MethodSubmitWindowedPoSt,
+			Deadline:     2,
+			Partitions:   []uint64{0},
+		}}})
+		require.NoError(t, err)
+		require.Len(t, transitions, 1)
+		assert.Equal(t, "recovering>active", states(transitions)[0])
+		assert.Equal(t, uint64(1), transitions[0].SectorNumber)
+		assert.Equal(t, uint64(130), transitions[0].Height)
+	})
+
+	t.Run("sectors expire once their expiration is reached", func(t *testing.T) {
+		transitions, err := tracker.Apply(150, nil)
+		require.NoError(t, err)
+		require.Len(t, transitions, 1)
+		assert.Equal(t, uint64(2), transitions[0].SectorNumber)
+		assert.Equal(t, "active>expired", states(transitions)[0])
+		assert.Empty(t, transitions[0].TxCid)
+	})
+
+	t.Run("events must be applied in height order", func(t *testing.T) {
+		_, err := tracker.Apply(150, nil)
+		assert.ErrorIs(t, err, common.ErrHeightOutOfOrder)
+	})
+
+	t.Run("a tipset with an invalid event is not applied", func(t *testing.T) {
+		events := &types.MinerEvents{MinerSectors: []*types.MinerSectorEvent{
+			sectorEvent(t, parser.MethodDeclareFaults, 1, location),
+			{MinerAddress: txTo, SectorNumber: 2, ActionType: parser.MethodDeclareFaults, Data: "{"},
+		}}
+		_, err := tracker.Apply(155, events)
+		require.Error(t, err)
+		assert.Equal(t, uint64(150), tracker.Height())
+		sector, ok := tracker.Sector(txTo, 1)
+		require.True(t, ok)
+		assert.Equal(t, miner.SectorStateActive, sector.State)
+
+		// the tipset can be retried once fixed
+		events.MinerSectors = events.MinerSectors[:1]
+		transitions, err := tracker.Apply(155, events)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"active>faulty"}, states(transitions))
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		var snapshot bytes.Buffer
+		require.NoError(t, tracker.Export(&snapshot))
+		restored, err := miner.ImportSectorTracker(bytes.NewReader(snapshot.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, tracker.Height(), restored.Height())
+		assert.Equal(t, tracker.Sectors(), restored.Sectors())
+
+		sector, ok := restored.Sector(txTo, 1)
+		require.True(t, ok)
+		assert.Equal(t, miner.SectorStateFaulty, sector.State)
+		assert.Equal(t, []uint64{7}, sector.DealIDs)
+
+		// the restored tracker keeps expiring and terminating sectors
+		transitions, err := restored.Apply(160, &types.MinerEvents{MinerSectors: []*types.MinerSectorEvent{
+			sectorEvent(t, parser.MethodTerminateSectors, 1, location),
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"faulty>terminated"}, states(transitions))
+		transitions, err = restored.Apply(600, nil)
+		require.NoError(t, err)
+		assert.Empty(t, transitions)
+
+		_, err = miner.ImportSectorTracker(bytes.NewReader([]byte(`{"version":0}`)))
+		assert.Error(t, err)
+	})
+}
@@ -233,6 +233,11 @@ func (_m *IActorsCache) NewImpl(source common.DataSource, _a1 *logger.Logger, _a
 	return r0
 }
 
+// RemoveAddressInfo provides a mock function with given fields: info
+func (_m *IActorsCache) RemoveAddressInfo(info fil_parsertypes.AddressInfo) {
+	_m.Called(info)
+}
+
 // StoreAddressInfo provides a mock function with given fields: info
 func (_m *IActorsCache) StoreAddressInfo(info fil_parsertypes.AddressInfo) {
 	_m.Called(info)
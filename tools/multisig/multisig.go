@@ -37,6 +37,8 @@ const (
 	metadataValue      = "Value"
 	metadataSigner     = "Signer"
 	metadataSigners    = "Signers"
+	metadataApplied    = "Applied"
+	metadataCode       = "Code"
 )
 
 var proposeTranslateMap = map[string]string{
@@ -77,7 +79,7 @@ func (eg *eventGenerator) GenerateMultisigEvents(ctx context.Context, transactio
 		MultisigInfo: []*types.MultisigInfo{},
 	}
 
-	for _, tx := range transactions {
+	for i, tx := range transactions {
 		if !common.IsTxSuccess(tx) {
 			eg.logger.Debug("failed tx found, skipping it")
 			continue
@@ -95,6 +97,8 @@ func (eg *eventGenerator) GenerateMultisigEvents(ctx context.Context, transactio
 			if err != nil {
 				return nil, fmt.Errorf("could not create proposal. Err: %s", err)
 			}
+			// #nosec G115
+			proposal.TxIndex = uint64(i)
 			events.Proposals = append(events.Proposals, proposal)
 		} else {
 			// Only consider transactions where tx.TxTo is a multisig address
@@ -106,7 +110,7 @@ func (eg *eventGenerator) GenerateMultisigEvents(ctx context.Context, transactio
 			}
 
 			// #nosec G115
-			actorName, err := common.GetActorNameFromAddress(eg.helper, addrTo, int64(tx.Height), tipsetKey, true)
+			actorName, err := common.ResolveActorName(ctx, eg.helper, addrTo, int64(tx.Height), tipsetKey, true)
 			if err != nil {
 				_ = eg.metrics.UpdateActorNameFromAddressMetric()
 				return nil, err
@@ -120,6 +124,8 @@ func (eg *eventGenerator) GenerateMultisigEvents(ctx context.Context, transactio
 			if err != nil {
 				return nil, fmt.Errorf("could not create multisig info. err: %w", err)
 			}
+			// #nosec G115
+			multisigInfo.TxIndex = uint64(i)
 			events.MultisigInfo = append(events.MultisigInfo, multisigInfo)
 		}
 	}
@@ -140,6 +146,13 @@ func (eg *eventGenerator) createProposal(ctx context.Context, tx *types.Transact
 		if txnID, ok := ret[metadataTxnIDField].(float64); ok {
 			proposal.ProposalID = int64(txnID)
 		}
+		// Propose and Approve execute the proposed call when the threshold is met
+		if applied, ok := ret[metadataApplied].(bool); ok {
+			proposal.Applied = applied
+		}
+		if code, ok := ret[metadataCode].(float64); ok && proposal.Applied {
+			proposal.ExitCode = int64(code)
+		}
 	}
 
 	proposal.ID = tools.BuildId(tipsetCid, tx.TxCid, proposal.Signer, proposal.MultisigAddress, fmt.Sprint(proposal.ProposalID), fmt.Sprint(tx.Height), tx.TxType)
@@ -279,6 +292,7 @@ func (eg *eventGenerator) createMultisigInfo(ctx context.Context, tx *types.Tran
 		Signer:          tx.TxFrom,
 		ActionType:      tx.TxType,
 		Value:           string(b),
+		Amount:          tx.Amount,
 	}, nil
 }
 
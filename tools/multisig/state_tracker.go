@@ -0,0 +1,397 @@
+package multisig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+// Outcomes of a multisig proposal.
+const (
+	ProposalOutcomeExecuted  = "executed"
+	ProposalOutcomeCancelled = "cancelled"
+	// ProposalOutcomeExpired is the outcome of proposals dropped because every signer that approved them was removed.
+	ProposalOutcomeExpired = "expired"
+)
+
+const (
+	metadataThreshold    = "NumApprovalsThreshold"
+	metadataNewThreshold = "NewThreshold"
+	metadataIncrease     = "Increase"
+	metadataDecrease     = "Decrease"
+	metadataFrom         = "From"
+	metadataTo           = "To"
+)
+
+// multisigSnapshotVersion is bumped whenever the snapshot layout changes.
+const multisigSnapshotVersion = 2
+
+// PendingTransaction is a multisig proposal waiting for approvals.
+type PendingTransaction struct {
+	ProposalID int64 `json:"proposal_id"`
+	// Proposer is empty when the proposal was made before the tracker started.
+	Proposer        string   `json:"proposer"`
+	TxTypeToExecute string   `json:"tx_type_to_execute"`
+	Value           string   `json:"value"`
+	Approvals       []string `json:"approvals"`
+	ProposedAt      uint64   `json:"proposed_at"`
+	TxCid           string   `json:"tx_cid"`
+}
+
+// Multisig is the current state of a multisig actor.
+type Multisig struct {
+	Address   string   `json:"address"`
+	Signers   []string `json:"signers"`
+	Threshold uint64   `json:"threshold"`
+	// Pending holds the proposals waiting for approvals, sorted by proposal id.
+	Pending []*PendingTransaction `json:"pending"`
+	// UpdatedAt is the height of the last change to the multisig.
+	UpdatedAt uint64 `json:"updated_at"`
+}
+
+// MultisigStateTracker keeps the signers, threshold and pending proposals of every multisig seen in the multisig events.
+// Each Apply call takes the events of the next tipset. The tracker is not safe for concurrent use.
+// Signers are compared as found in the events, so the parser must consolidate addresses the same way for every height.
+type MultisigStateTracker struct {
+	height    uint64
+	multisigs map[string]*Multisig
+}
+
+func NewMultisigStateTracker() *MultisigStateTracker {
+	return &MultisigStateTracker{
+		multisigs: make(map[string]*Multisig),
+	}
+}
+
+// ImportMultisigStateTracker restores a tracker from a snapshot written by Export.
+func ImportMultisigStateTracker(r io.Reader) (*MultisigStateTracker, error) {
+	multisigs, height, err := common.ImportSnapshot[[]*Multisig](r, "multisig", multisigSnapshotVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := NewMultisigStateTracker()
+	tracker.height = height
+	for _, multisig := range multisigs {
+		tracker.multisigs[multisig.Address] = multisig
+	}
+	return tracker, nil
+}
+
+// Export writes a snapshot of the tracker, with the multisigs sorted by address.
+func (t *MultisigStateTracker) Export(w io.Writer) error {
+	return common.ExportSnapshot(w, "multisig", multisigSnapshotVersion, t.height, t.Multisigs())
+}
+
+// Height returns the height of the last applied tipset.
+func (t *MultisigStateTracker) Height() uint64 {
+	return t.height
+}
+
+// Multisig returns the current state of a multisig.
+func (t *MultisigStateTracker) Multisig(addr string) (*Multisig, bool) {
+	multisig, ok := t.multisigs[addr]
+	return multisig, ok
+}
+
+// Multisigs returns every tracked multisig, sorted by address.
+func (t *MultisigStateTracker) Multisigs() []*Multisig {
+	multisigs := make([]*Multisig, 0, len(t.multisigs))
+	for _, multisig := range t.multisigs {
+		multisigs = append(multisigs, multisig)
+	}
+	sort.Slice(multisigs, func(i, j int) bool {
+		return multisigs[i].Address < multisigs[j].Address
+	})
+	return multisigs
+}
+
+// Seed sets the signers and threshold of the genesis multisigs, as returned by ParseGenesisMultisig.
+// It must be called before any tipset is applied.
+func (t *MultisigStateTracker) Seed(genesis []*types.MultisigInfo) error {
+	if t.height != 0 {
+		return fmt.Errorf("tracker already at height %d, genesis multisigs must be seeded first", t.height)
+	}
+	params, err := decodeInfoParams(genesis)
+	if err != nil {
+		return err
+	}
+	for i, info := range genesis {
+		t.applyInfo(info, params[i])
+	}
+	return nil
+}
+
+// Apply updates the tracker with the multisig events of the tipset at height and returns the outcomes of the
+// proposals resolved in it. Signer and threshold changes and proposals are applied in execution order, by tx index,
+// so a proposal only sees the signers removed before it.
+// Removing a signer drops its approvals, and proposals left without approvals expire.
+// The events are decoded before any change, so a tipset that fails leaves the tracker as it was and can be retried.
+func (t *MultisigStateTracker) Apply(height uint64, events *types.MultisigEvents) ([]*types.MultisigProposalOutcome, error) {
+	if err := common.CheckTrackerHeight(t.height, height); err != nil {
+		return nil, err
+	}
+	if events == nil {
+		t.height = height
+		return nil, nil
+	}
+	params, err := decodeInfoParams(events.MultisigInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	t.height = height
+	var outcomes []*types.MultisigProposalOutcome
+	infos, proposals := events.MultisigInfo, events.Proposals
+	// both lists are in tx index order and a tx is either a change or a proposal, so merging them keeps the execution order
+	for i, j := 0, 0; i < len(infos) || j < len(proposals); {
+		if i < len(infos) && (j == len(proposals) || infos[i].TxIndex <= proposals[j].TxIndex) {
+			t.applyInfo(infos[i], params[i])
+			if isRemoveOrSwapSigner(infos[i].ActionType) {
+				outcomes = append(outcomes, t.expire(infos[i], params[i])...)
+			}
+			i++
+			continue
+		}
+		if outcome := t.applyProposal(proposals[j]); outcome != nil {
+			outcomes = append(outcomes, outcome)
+		}
+		j++
+	}
+	return outcomes, nil
+}
+
+// applyProposal applies a propose, approve or cancel and returns the outcome of the proposal when it is resolved.
+func (t *MultisigStateTracker) applyProposal(proposal *types.MultisigProposal) *types.MultisigProposalOutcome {
+	multisig := t.getOrCreate(proposal.MultisigAddress)
+	multisig.UpdatedAt = t.height
+	switch proposal.ActionType {
+	case parser.MethodPropose:
+		pending := &PendingTransaction{
+			ProposalID:      proposal.ProposalID,
+			Proposer:        proposal.Signer,
+			TxTypeToExecute: proposal.TxTypeToExecute,
+			Value:           proposal.Value,
+			Approvals:       []string{proposal.Signer},
+			ProposedAt:      t.height,
+			TxCid:           proposal.TxCid,
+		}
+		if proposal.Applied {
+			return t.newOutcome(multisig, pending, ProposalOutcomeExecuted, proposal)
+		}
+		multisig.addPending(pending)
+	case parser.MethodApprove:
+		pending, ok := multisig.pending(proposal.ProposalID)
+		if !ok {
+			// proposed before the tracker started
+			pending = &PendingTransaction{ProposalID: proposal.ProposalID}
+			multisig.addPending(pending)
+		}
+		pending.Approvals = appendSigner(pending.Approvals, proposal.Signer)
+		if proposal.Applied {
+			multisig.removePending(proposal.ProposalID)
+			return t.newOutcome(multisig, pending, ProposalOutcomeExecuted, proposal)
+		}
+	case parser.MethodCancel:
+		pending, ok := multisig.pending(proposal.ProposalID)
+		if !ok {
+			pending = &PendingTransaction{ProposalID: proposal.ProposalID}
+		}
+		multisig.removePending(proposal.ProposalID)
+		return t.newOutcome(multisig, pending, ProposalOutcomeCancelled, proposal)
+	}
+	return nil
+}
+
+// decodeInfoParams decodes the params of the changes of signers or threshold in infos.
+// Other infos get nil params.
+func decodeInfoParams(infos []*types.MultisigInfo) ([]map[string]interface{}, error) {
+	params := make([]map[string]interface{}, len(infos))
+	for i, info := range infos {
+		switch info.ActionType {
+		case parser.MultisigConstructorMethod,
+			parser.MethodAddSigner, parser.MethodAddSignerExported,
+			parser.MethodRemoveSigner, parser.MethodRemoveSignerExported,
+			parser.MethodSwapSigner, parser.MethodSwapSignerExported,
+			parser.MethodChangeNumApprovalsThreshold, parser.MethodChangeNumApprovalsThresholdExported:
+			if err := json.Unmarshal([]byte(info.Value), &params[i]); err != nil {
+				return nil, fmt.Errorf("error unmarshalling multisig %s %s params: %w", info.MultisigAddress, info.ActionType, err)
+			}
+		}
+	}
+	return params, nil
+}
+
+// applyInfo applies a change of signers or threshold, with the params decoded by decodeInfoParams.
+func (t *MultisigStateTracker) applyInfo(info *types.MultisigInfo, params map[string]interface{}) {
+	if params == nil {
+		return
+	}
+
+	multisig := t.getOrCreate(info.MultisigAddress)
+	multisig.UpdatedAt = t.height
+	switch info.ActionType {
+	case parser.MultisigConstructorMethod:
+		multisig.Signers = nil
+		if signers, ok := params[metadataSigners].([]interface{}); ok {
+			for _, signer := range signers {
+				if signer, ok := signer.(string); ok {
+					multisig.Signers = append(multisig.Signers, signer)
+				}
+			}
+		}
+		if threshold, ok := params[metadataThreshold].(float64); ok {
+			multisig.Threshold = uint64(threshold)
+		}
+	case parser.MethodAddSigner, parser.MethodAddSignerExported:
+		if signer, ok := params[metadataSigner].(string); ok {
+			multisig.Signers = appendSigner(multisig.Signers, signer)
+		}
+		if increase, ok := params[metadataIncrease].(bool); ok && increase {
+			multisig.Threshold++
+		}
+	case parser.MethodRemoveSigner, parser.MethodRemoveSignerExported:
+		if signer, ok := params[metadataSigner].(string); ok {
+			multisig.Signers = removeSigner(multisig.Signers, signer)
+		}
+		if decrease, ok := params[metadataDecrease].(bool); ok && decrease && multisig.Threshold > 0 {
+			multisig.Threshold--
+		}
+	case parser.MethodSwapSigner, parser.MethodSwapSignerExported:
+		from, _ := params[metadataFrom].(string)
+		to, _ := params[metadataTo].(string)
+		if from != "" && to != "" {
+			multisig.Signers = appendSigner(removeSigner(multisig.Signers, from), to)
+		}
+	case parser.MethodChangeNumApprovalsThreshold, parser.MethodChangeNumApprovalsThresholdExported:
+		if threshold, ok := params[metadataNewThreshold].(float64); ok {
+			multisig.Threshold = uint64(threshold)
+		}
+	}
+}
+
+// expire drops the approvals of the signer removed by info, and the proposals left without approvals.
+func (t *MultisigStateTracker) expire(info *types.MultisigInfo, params map[string]interface{}) []*types.MultisigProposalOutcome {
+	removed, _ := params[metadataSigner].(string)
+	if from, ok := params[metadataFrom].(string); ok {
+		removed = from
+	}
+	if removed == "" {
+		return nil
+	}
+
+	multisig := t.getOrCreate(info.MultisigAddress)
+	var outcomes []*types.MultisigProposalOutcome
+	remaining := multisig.Pending[:0]
+	for _, pending := range multisig.Pending {
+		pending.Approvals = removeSigner(pending.Approvals, removed)
+		// the approvals of proposals made before the tracker started are not all known
+		if pending.Proposer != "" && len(pending.Approvals) == 0 {
+			outcomes = append(outcomes, &types.MultisigProposalOutcome{
+				ID:              tools.BuildId(multisig.Address, fmt.Sprint(pending.ProposalID), fmt.Sprint(t.height), ProposalOutcomeExpired),
+				MultisigAddress: multisig.Address,
+				ProposalID:      pending.ProposalID,
+				Proposer:        pending.Proposer,
+				TxTypeToExecute: pending.TxTypeToExecute,
+				Value:           pending.Value,
+				Approvals:       []string{},
+				Outcome:         ProposalOutcomeExpired,
+				Height:          t.height,
+				TxCid:           info.TxCid,
+				ActionType:      info.ActionType,
+			})
+			continue
+		}
+		remaining = append(remaining, pending)
+	}
+	multisig.Pending = remaining
+	return outcomes
+}
+
+func (t *MultisigStateTracker) newOutcome(multisig *Multisig, pending *PendingTransaction, outcome string, proposal *types.MultisigProposal) *types.MultisigProposalOutcome {
+	result := &types.MultisigProposalOutcome{
+		ID:              tools.BuildId(multisig.Address, fmt.Sprint(pending.ProposalID), fmt.Sprint(t.height), outcome),
+		MultisigAddress: multisig.Address,
+		ProposalID:      pending.ProposalID,
+		Proposer:        pending.Proposer,
+		TxTypeToExecute: pending.TxTypeToExecute,
+		Value:           pending.Value,
+		Approvals:       pending.Approvals,
+		Outcome:         outcome,
+		Height:          t.height,
+		TxCid:           proposal.TxCid,
+		ActionType:      proposal.ActionType,
+	}
+	if outcome == ProposalOutcomeExecuted {
+		result.ExitCode = proposal.ExitCode
+	}
+	return result
+}
+
+func (t *MultisigStateTracker) getOrCreate(addr string) *Multisig {
+	multisig, ok := t.multisigs[addr]
+	if !ok {
+		multisig = &Multisig{Address: addr, Signers: []string{}, Pending: []*PendingTransaction{}}
+		t.multisigs[addr] = multisig
+	}
+	return multisig
+}
+
+func (m *Multisig) pending(proposalID int64) (*PendingTransaction, bool) {
+	for _, pending := range m.Pending {
+		if pending.ProposalID == proposalID {
+			return pending, true
+		}
+	}
+	return nil, false
+}
+
+func (m *Multisig) addPending(pending *PendingTransaction) {
+	m.removePending(pending.ProposalID)
+	m.Pending = append(m.Pending, pending)
+	sort.Slice(m.Pending, func(i, j int) bool {
+		return m.Pending[i].ProposalID < m.Pending[j].ProposalID
+	})
+}
+
+func (m *Multisig) removePending(proposalID int64) {
+	for i, pending := range m.Pending {
+		if pending.ProposalID == proposalID {
+			m.Pending = append(m.Pending[:i], m.Pending[i+1:]...)
+			return
+		}
+	}
+}
+
+func appendSigner(signers []string, signer string) []string {
+	for _, s := range signers {
+		if s == signer {
+			return signers
+		}
+	}
+	return append(signers, signer)
+}
+
+func removeSigner(signers []string, signer string) []string {
+	result := make([]string, 0, len(signers))
+	for _, s := range signers {
+		if s != signer {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func isRemoveOrSwapSigner(txType string) bool {
+	switch txType {
+	case parser.MethodRemoveSigner, parser.MethodRemoveSignerExported, parser.MethodSwapSigner, parser.MethodSwapSignerExported:
+		return true
+	}
+	return false
+}
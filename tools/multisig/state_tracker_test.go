@@ -0,0 +1,167 @@
+package multisig_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/tools/multisig"
+	"github.com/zondax/fil-parser/types"
+)
+
+const msigAddress = "f02000"
+
+func TestMultisigStateTracker(t *testing.T) {
+	tracker := multisig.NewMultisigStateTracker()
+	require.NoError(t, tracker.Seed([]*types.MultisigInfo{{
+		MultisigAddress: msigAddress,
+		ActionType:      parser.MultisigConstructorMethod,
+		Value:           `{"Signers":["f01001","f01002","f01003"],"NumApprovalsThreshold":2,"UnlockDuration":0,"StartEpoch":0}`,
+	}}))
+
+	propose := func(proposalID int64, signer string) *types.MultisigProposal {
+		return &types.MultisigProposal{MultisigAddress: msigAddress, ProposalID: proposalID, Signer: signer, ActionType: parser.MethodPropose, TxTypeToExecute: parser.MethodSend}
+	}
+
+	outcomes, err := tracker.Apply(100, &types.MultisigEvents{Proposals: []*types.MultisigProposal{
+		propose(0, "f01001"), propose(1, "f01001"), propose(2, "f01003"),
+	}})
+	require.NoError(t, err)
+	assert.Empty(t, outcomes)
+	msig, ok := tracker.Multisig(msigAddress)
+	require.True(t, ok)
+	assert.Equal(t, uint64(2), msig.Threshold)
+	require.Len(t, msig.Pending, 3)
+
+	t.Run("approved proposals are executed", func(t *testing.T) {
+		outcomes, err := tracker.Apply(110, &types.MultisigEvents{Proposals: []*types.MultisigProposal{
+			{MultisigAddress: msigAddress, ProposalID: 0, Signer: "f01002", ActionType: parser.MethodApprove, Applied: true, ExitCode: int64(exitcode.ErrInsufficientFunds)},
+			{MultisigAddress: msigAddress, ProposalID: 1, Signer: "f01001", ActionType: parser.MethodCancel},
+		}})
+		require.NoError(t, err)
+		require.Len(t, outcomes, 2)
+		assert.Equal(t, multisig.ProposalOutcomeExecuted, outcomes[0].Outcome)
+		assert.Equal(t, int64(exitcode.ErrInsufficientFunds), outcomes[0].ExitCode)
+		assert.Equal(t, []string{"f01001", "f01002"}, outcomes[0].Approvals)
+		assert.Equal(t, "f01001", outcomes[0].Proposer)
+		assert.Equal(t, multisig.ProposalOutcomeCancelled, outcomes[1].Outcome)
+		assert.Len(t, msig.Pending, 1)
+	})
+
+	t.Run("removing the only approver expires the proposal", func(t *testing.T) {
+		outcomes, err := tracker.Apply(120, &types.MultisigEvents{MultisigInfo: []*types.MultisigInfo{
+			{MultisigAddress: msigAddress, ActionType: parser.MethodRemoveSigner, Value: `{"Signer":"f01003","Decrease":true}`},
+		}})
+		require.NoError(t, err)
+		require.Len(t, outcomes, 1)
+		assert.Equal(t, int64(2), outcomes[0].ProposalID)
+		assert.Equal(t, multisig.ProposalOutcomeExpired, outcomes[0].Outcome)
+		assert.Equal(t, []string{"f01001", "f01002"}, msig.Signers)
+		assert.Equal(t, uint64(1), msig.Threshold)
+		assert.Empty(t, msig.Pending)
+	})
+
+	t.Run("signer changes", func(t *testing.T) {
+		_, err := tracker.Apply(130, &types.MultisigEvents{MultisigInfo: []*types.MultisigInfo{
+			{MultisigAddress: msigAddress, ActionType: parser.MethodAddSigner, Value: `{"Signer":"f01004","Increase":true}`},
+			{MultisigAddress: msigAddress, ActionType: parser.MethodSwapSigner, Value: `{"From":"f01001","To":"f01005"}`},
+			{MultisigAddress: msigAddress, ActionType: parser.MethodChangeNumApprovalsThreshold, Value: `{"NewThreshold":3}`},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"f01002", "f01004", "f01005"}, msig.Signers)
+		assert.Equal(t, uint64(3), msig.Threshold)
+	})
+
+	t.Run("a tipset with invalid params is not applied", func(t *testing.T) {
+		events := &types.MultisigEvents{MultisigInfo: []*types.MultisigInfo{
+			{MultisigAddress: msigAddress, ActionType: parser.MethodRemoveSigner, Value: `{"Signer":"f01002","Decrease":true}`},
+			{MultisigAddress: msigAddress, ActionType: parser.MethodChangeNumApprovalsThreshold, Value: `{`},
+		}}
+		_, err := tracker.Apply(135, events)
+		require.Error(t, err)
+		assert.Equal(t, uint64(130), tracker.Height())
+		assert.Equal(t, []string{"f01002", "f01004", "f01005"}, msig.Signers)
+		assert.Equal(t, uint64(3), msig.Threshold)
+
+		// the tipset can be retried once fixed
+		events.MultisigInfo = events.MultisigInfo[:1]
+		_, err = tracker.Apply(135, events)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"f01004", "f01005"}, msig.Signers)
+		assert.Equal(t, uint64(2), msig.Threshold)
+	})
+
+	t.Run("events must be applied in height order", func(t *testing.T) {
+		_, err := tracker.Apply(135, nil)
+		assert.ErrorIs(t, err, common.ErrHeightOutOfOrder)
+		assert.Error(t, tracker.Seed(nil))
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		_, err := tracker.Apply(140, &types.MultisigEvents{Proposals: []*types.MultisigProposal{propose(3, "f01005")}})
+		require.NoError(t, err)
+
+		var snapshot bytes.Buffer
+		require.NoError(t, tracker.Export(&snapshot))
+		restored, err := multisig.ImportMultisigStateTracker(bytes.NewReader(snapshot.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, tracker.Height(), restored.Height())
+		assert.Equal(t, tracker.Multisigs(), restored.Multisigs())
+
+		outcomes, err := restored.Apply(150, &types.MultisigEvents{Proposals: []*types.MultisigProposal{
+			{MultisigAddress: msigAddress, ProposalID: 3, Signer: "f01004", ActionType: parser.MethodApprove, Applied: true},
+		}})
+		require.NoError(t, err)
+		require.Len(t, outcomes, 1)
+		assert.Equal(t, []string{"f01005", "f01004"}, outcomes[0].Approvals)
+	})
+}
+
+func TestMultisigStateTracker_ExecutionOrder(t *testing.T) {
+	tracker := multisig.NewMultisigStateTracker()
+	require.NoError(t, tracker.Seed([]*types.MultisigInfo{{
+		MultisigAddress: msigAddress,
+		ActionType:      parser.MultisigConstructorMethod,
+		Value:           `{"Signers":["f01001","f01002","f01003"],"NumApprovalsThreshold":2,"UnlockDuration":0,"StartEpoch":0}`,
+	}}))
+	_, err := tracker.Apply(100, &types.MultisigEvents{Proposals: []*types.MultisigProposal{
+		{MultisigAddress: msigAddress, ProposalID: 0, Signer: "f01001", ActionType: parser.MethodPropose, TxTypeToExecute: parser.MethodSend},
+	}})
+	require.NoError(t, err)
+
+	// the proposal is executed before its proposer is removed, so it does not expire
+	outcomes, err := tracker.Apply(110, &types.MultisigEvents{
+		MultisigInfo: []*types.MultisigInfo{
+			{MultisigAddress: msigAddress, ActionType: parser.MethodRemoveSigner, Value: `{"Signer":"f01001","Decrease":false}`, TxIndex: 3},
+		},
+		Proposals: []*types.MultisigProposal{
+			{MultisigAddress: msigAddress, ProposalID: 0, Signer: "f01002", ActionType: parser.MethodApprove, Applied: true, TxIndex: 1},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, outcomes, 1)
+	assert.Equal(t, multisig.ProposalOutcomeExecuted, outcomes[0].Outcome)
+	assert.Equal(t, []string{"f01001", "f01002"}, outcomes[0].Approvals)
+	msig, ok := tracker.Multisig(msigAddress)
+	require.True(t, ok)
+	assert.Equal(t, []string{"f01002", "f01003"}, msig.Signers)
+	assert.Empty(t, msig.Pending)
+
+	// the proposer is removed before the approval, so the proposal expires
+	outcomes, err = tracker.Apply(120, &types.MultisigEvents{
+		MultisigInfo: []*types.MultisigInfo{
+			{MultisigAddress: msigAddress, ActionType: parser.MethodRemoveSigner, Value: `{"Signer":"f01002","Decrease":false}`, TxIndex: 1},
+		},
+		Proposals: []*types.MultisigProposal{
+			{MultisigAddress: msigAddress, ProposalID: 1, Signer: "f01002", ActionType: parser.MethodPropose, TxTypeToExecute: parser.MethodSend, TxIndex: 0},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, outcomes, 1)
+	assert.Equal(t, int64(1), outcomes[0].ProposalID)
+	assert.Equal(t, multisig.ProposalOutcomeExpired, outcomes[0].Outcome)
+}
@@ -0,0 +1,220 @@
+package multisig
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/types"
+)
+
+const (
+	metadataUnlockDuration = "UnlockDuration"
+	metadataStartEpoch     = "StartEpoch"
+	metadataAmount         = "Amount"
+)
+
+// LockSchedule is the balance a multisig locks and how it unlocks: linearly over UnlockDuration epochs from StartEpoch.
+type LockSchedule struct {
+	MultisigAddress string   `json:"multisig_address"`
+	InitialBalance  *big.Int `json:"initial_balance"`
+	StartEpoch      int64    `json:"start_epoch"`
+	UnlockDuration  int64    `json:"unlock_duration"`
+}
+
+// VestingCalculator reconstructs the lock schedule of the multisigs from their constructor and LockBalance messages,
+// and computes their locked balance at any epoch.
+type VestingCalculator struct {
+	network   string
+	schedules map[string]*LockSchedule
+}
+
+func NewVestingCalculator(network string) *VestingCalculator {
+	return &VestingCalculator{
+		network:   network,
+		schedules: make(map[string]*LockSchedule),
+	}
+}
+
+// SeedGenesis adds the schedules of the genesis multisigs, as returned by ParseGenesisMultisig.
+// Genesis multisigs lock their whole genesis balance.
+func (c *VestingCalculator) SeedGenesis(infos []*types.MultisigInfo, genesis *types.GenesisBalances) error {
+	balances := make(map[string]string)
+	if genesis != nil {
+		for _, actor := range genesis.Actors.All {
+			balances[actor.Key] = actor.Value.Balance
+		}
+	}
+
+	for _, info := range infos {
+		genesisInfo := *info
+		if balance, ok := balances[info.MultisigAddress]; ok {
+			initialBalance, ok := new(big.Int).SetString(balance, 10)
+			if !ok {
+				return fmt.Errorf("invalid genesis balance %s for multisig %s", balance, info.MultisigAddress)
+			}
+			genesisInfo.Amount = initialBalance
+		}
+		if err := c.add(&genesisInfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Add updates the schedules with the constructor and LockBalance multisig infos. Other infos are ignored.
+func (c *VestingCalculator) Add(infos []*types.MultisigInfo) error {
+	for _, info := range infos {
+		if err := c.add(info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *VestingCalculator) add(info *types.MultisigInfo) error {
+	switch info.ActionType {
+	case parser.MultisigConstructorMethod, parser.MethodLockBalance, parser.MethodLockBalanceExported:
+	default:
+		return nil
+	}
+
+	var params map[string]interface{}
+	if err := json.Unmarshal([]byte(info.Value), &params); err != nil {
+		return fmt.Errorf("error unmarshalling multisig %s %s params: %w", info.MultisigAddress, info.ActionType, err)
+	}
+	unlockDuration, _ := params[metadataUnlockDuration].(float64)
+	if unlockDuration <= 0 {
+		// nothing is locked
+		return nil
+	}
+
+	// the constructor of the first actors version has no start epoch, it starts unlocking at creation
+	// #nosec G115
+	startEpoch := int64(info.Height)
+	if epoch, ok := params[metadataStartEpoch].(float64); ok {
+		startEpoch = int64(epoch)
+	}
+	schedule := &LockSchedule{
+		MultisigAddress: info.MultisigAddress,
+		StartEpoch:      startEpoch,
+		UnlockDuration:  int64(unlockDuration),
+	}
+
+	if info.ActionType == parser.MultisigConstructorMethod {
+		// constructors lock the value received
+		if info.Amount == nil {
+			return fmt.Errorf("unknown initial balance of multisig %s", info.MultisigAddress)
+		}
+		schedule.InitialBalance = info.Amount
+	} else {
+		amount, ok := params[metadataAmount].(string)
+		if !ok {
+			return fmt.Errorf("missing amount in multisig %s %s params", info.MultisigAddress, info.ActionType)
+		}
+		initialBalance, ok := new(big.Int).SetString(amount, 10)
+		if !ok {
+			return fmt.Errorf("invalid amount %s in multisig %s %s params", amount, info.MultisigAddress, info.ActionType)
+		}
+		schedule.InitialBalance = initialBalance
+	}
+	c.schedules[info.MultisigAddress] = schedule
+	return nil
+}
+
+// Schedule returns the lock schedule of a multisig, false if it never locked any balance.
+func (c *VestingCalculator) Schedule(addr string) (*LockSchedule, bool) {
+	schedule, ok := c.schedules[addr]
+	return schedule, ok
+}
+
+// Schedules returns every lock schedule, sorted by multisig address.
+func (c *VestingCalculator) Schedules() []*LockSchedule {
+	schedules := make([]*LockSchedule, 0, len(c.schedules))
+	for _, schedule := range c.schedules {
+		schedules = append(schedules, schedule)
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].MultisigAddress < schedules[j].MultisigAddress
+	})
+	return schedules
+}
+
+// Locked returns the balance of the multisig locked at epoch, following the rounding of the actors version in use.
+func (c *VestingCalculator) Locked(addr string, epoch int64) *big.Int {
+	schedule, ok := c.schedules[addr]
+	if !ok {
+		return big.NewInt(0)
+	}
+
+	elapsed := epoch - schedule.StartEpoch
+	if elapsed >= schedule.UnlockDuration {
+		return big.NewInt(0)
+	}
+	if elapsed < 0 {
+		return new(big.Int).Set(schedule.InitialBalance)
+	}
+
+	unlockDuration := big.NewInt(schedule.UnlockDuration)
+	remaining := big.NewInt(schedule.UnlockDuration - elapsed)
+	if tools.VersionFromHeight(c.network, epoch).NodeVersion() < tools.V4.NodeVersion() {
+		// the first actors version truncates the amount unlocked per epoch
+		unitLocked := new(big.Int).Quo(schedule.InitialBalance, unlockDuration)
+		return unitLocked.Mul(unitLocked, remaining)
+	}
+
+	// locked = ceil(InitialBalance * remaining / UnlockDuration)
+	numerator := new(big.Int).Mul(schedule.InitialBalance, remaining)
+	locked, rem := new(big.Int).QuoRem(numerator, unlockDuration, new(big.Int))
+	if rem.Sign() != 0 {
+		locked.Add(locked, big.NewInt(1))
+	}
+	return locked
+}
+
+// Available returns the part of balance the multisig can spend at epoch, that is, what is not locked.
+func (c *VestingCalculator) Available(addr string, epoch int64, balance *big.Int) *big.Int {
+	available := new(big.Int).Sub(balance, c.Locked(addr, epoch))
+	if available.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	return available
+}
+
+// VestingRanges splits [from, to) into ranges of step epochs and returns the balance the multisig unlocks in each.
+// Ranges where nothing unlocks are skipped.
+func (c *VestingCalculator) VestingRanges(addr string, from, to, step int64) ([]*types.MultisigVesting, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("invalid step %d", step)
+	}
+	if _, ok := c.schedules[addr]; !ok {
+		return nil, nil
+	}
+
+	var ranges []*types.MultisigVesting
+	for start := from; start < to; start += step {
+		end := start + step
+		if end > to {
+			end = to
+		}
+		lockedFrom := c.Locked(addr, start)
+		lockedTo := c.Locked(addr, end)
+		vested := new(big.Int).Sub(lockedFrom, lockedTo)
+		if vested.Sign() == 0 {
+			continue
+		}
+		ranges = append(ranges, &types.MultisigVesting{
+			ID:              tools.BuildId(addr, fmt.Sprint(start), fmt.Sprint(end)),
+			MultisigAddress: addr,
+			FromEpoch:       start,
+			ToEpoch:         end,
+			LockedFrom:      lockedFrom,
+			LockedTo:        lockedTo,
+			Vested:          vested,
+		})
+	}
+	return ranges, nil
+}
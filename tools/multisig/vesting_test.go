@@ -0,0 +1,70 @@
+package multisig_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/multisig"
+	"github.com/zondax/fil-parser/types"
+)
+
+func TestVestingCalculator(t *testing.T) {
+	calculator := multisig.NewVestingCalculator(tools.MainnetNetwork)
+
+	var genesis types.GenesisBalances
+	require.NoError(t, json.Unmarshal([]byte(`{"Actors":{"All":[{"Key":"f02001","Value":{"Balance":"1000"}}]}}`), &genesis))
+	require.NoError(t, calculator.SeedGenesis([]*types.MultisigInfo{{
+		MultisigAddress: "f02001",
+		ActionType:      parser.MultisigConstructorMethod,
+		Value:           `{"Signers":["f01001"],"NumApprovalsThreshold":1,"UnlockDuration":3,"StartEpoch":0}`,
+	}}, &genesis))
+
+	t.Run("the first actors version truncates", func(t *testing.T) {
+		assert.Equal(t, big.NewInt(1000), calculator.Locked("f02001", -1))
+		assert.Equal(t, big.NewInt(999), calculator.Locked("f02001", 0))
+		assert.Equal(t, big.NewInt(666), calculator.Locked("f02001", 1))
+		assert.Equal(t, big.NewInt(0), calculator.Locked("f02001", 3))
+	})
+
+	start := tools.V24.Height()
+	require.NoError(t, calculator.Add([]*types.MultisigInfo{
+		{MultisigAddress: "f02002", ActionType: parser.MultisigConstructorMethod, Amount: big.NewInt(1000),
+			Value: `{"Signers":["f01001"],"NumApprovalsThreshold":1,"UnlockDuration":3,"StartEpoch":` + big.NewInt(start).String() + `}`},
+		{MultisigAddress: "f02003", ActionType: parser.MultisigConstructorMethod, Amount: big.NewInt(1000),
+			Value: `{"Signers":["f01001"],"NumApprovalsThreshold":1,"UnlockDuration":0,"StartEpoch":0}`},
+		{MultisigAddress: "f02003", ActionType: parser.MethodLockBalance,
+			Value: `{"StartEpoch":` + big.NewInt(start).String() + `,"UnlockDuration":4,"Amount":"400"}`},
+	}))
+
+	t.Run("later versions round up", func(t *testing.T) {
+		assert.Equal(t, big.NewInt(667), calculator.Locked("f02002", start+1))
+		assert.Equal(t, big.NewInt(1500), calculator.Available("f02002", start+1, big.NewInt(2167)))
+		assert.Equal(t, big.NewInt(0), calculator.Available("f02002", start, big.NewInt(10)))
+	})
+
+	t.Run("lock balance", func(t *testing.T) {
+		schedule, ok := calculator.Schedule("f02003")
+		require.True(t, ok)
+		assert.Equal(t, big.NewInt(400), schedule.InitialBalance)
+		assert.Equal(t, big.NewInt(300), calculator.Locked("f02003", start+1))
+		assert.Equal(t, big.NewInt(0), calculator.Locked("f01999", start))
+	})
+
+	t.Run("vesting ranges", func(t *testing.T) {
+		ranges, err := calculator.VestingRanges("f02003", start-2, start+10, 2)
+		require.NoError(t, err)
+		require.Len(t, ranges, 2)
+		assert.Equal(t, start, ranges[0].FromEpoch)
+		assert.Equal(t, big.NewInt(200), ranges[0].Vested)
+		assert.Equal(t, big.NewInt(200), ranges[1].LockedFrom)
+		assert.Equal(t, big.NewInt(0), ranges[1].LockedTo)
+
+		_, err = calculator.VestingRanges("f02003", start, start+10, 0)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,140 @@
+package paymentchannel
+
+import (
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+// laneSnapshotVersion is bumped whenever the snapshot layout changes.
+const laneSnapshotVersion = 1
+
+// Lane is the amount redeemed so far on a lane of a payment channel.
+type Lane struct {
+	ChannelAddress string   `json:"channel_address"`
+	Lane           uint64   `json:"lane"`
+	Redeemed       *big.Int `json:"redeemed"`
+}
+
+type laneKey struct {
+	channel string
+	lane    uint64
+}
+
+// laneState is the state of the tracker kept in its snapshots.
+type laneState struct {
+	Channels []string `json:"channels"`
+	Lanes    []*Lane  `json:"lanes"`
+}
+
+// LaneTracker follows the amount redeemed on every payment channel lane, to derive the increment of each voucher.
+// It takes the payment channel events of one tipset per Apply call, and is not safe for concurrent use.
+type LaneTracker struct {
+	height uint64
+	// channels holds the channels created since the tracker started, whose lanes all start empty
+	channels map[string]struct{}
+	lanes    map[laneKey]*big.Int
+}
+
+func NewLaneTracker() *LaneTracker {
+	return &LaneTracker{
+		channels: make(map[string]struct{}),
+		lanes:    make(map[laneKey]*big.Int),
+	}
+}
+
+// ImportLaneTracker restores a tracker from a snapshot written by Export.
+func ImportLaneTracker(r io.Reader) (*LaneTracker, error) {
+	state, height, err := common.ImportSnapshot[laneState](r, "payment channel lane", laneSnapshotVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := NewLaneTracker()
+	tracker.height = height
+	for _, channel := range state.Channels {
+		tracker.channels[channel] = struct{}{}
+	}
+	for _, lane := range state.Lanes {
+		tracker.lanes[laneKey{channel: lane.ChannelAddress, lane: lane.Lane}] = lane.Redeemed
+	}
+	return tracker, nil
+}
+
+// Export writes a snapshot of the tracker, with the channels and lanes sorted by channel address and lane.
+func (t *LaneTracker) Export(w io.Writer) error {
+	channels := make([]string, 0, len(t.channels))
+	for channel := range t.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return common.ExportSnapshot(w, "payment channel lane", laneSnapshotVersion, t.height, laneState{Channels: channels, Lanes: t.Lanes()})
+}
+
+// Height returns the height of the last applied tipset.
+func (t *LaneTracker) Height() uint64 {
+	return t.height
+}
+
+// Lanes returns every tracked lane, sorted by channel address and lane.
+func (t *LaneTracker) Lanes() []*Lane {
+	lanes := make([]*Lane, 0, len(t.lanes))
+	for key, redeemed := range t.lanes {
+		lanes = append(lanes, &Lane{ChannelAddress: key.channel, Lane: key.lane, Redeemed: redeemed})
+	}
+	sort.Slice(lanes, func(i, j int) bool {
+		if lanes[i].ChannelAddress != lanes[j].ChannelAddress {
+			return lanes[i].ChannelAddress < lanes[j].ChannelAddress
+		}
+		return lanes[i].Lane < lanes[j].Lane
+	})
+	return lanes
+}
+
+// Apply updates the tracker with the payment channel events of the tipset at height, setting the Increment of
+// every voucher in events. Like the actor, a voucher sets its lane to its Amount and the increment is that amount
+// minus what the lane and the lanes it merges redeemed before.
+// The increment is left nil when one of those lanes was last redeemed before the tracker started, as its redeemed
+// amount is unknown. Vouchers of channels created after the tracker started always get one.
+// Apply can only fail on the height check, which runs before any change to the tracker.
+func (t *LaneTracker) Apply(height uint64, events *types.PaymentChannelEvents) error {
+	if err := common.CheckTrackerHeight(t.height, height); err != nil {
+		return err
+	}
+	t.height = height
+	if events == nil {
+		return nil
+	}
+
+	for _, channel := range events.Channels {
+		t.channels[channel.ChannelAddress] = struct{}{}
+	}
+	for _, voucher := range events.Vouchers {
+		if voucher.Amount == nil {
+			continue
+		}
+		previous, known := t.redeemed(voucher.ChannelAddress, voucher.Lane)
+		for _, mergedLane := range voucher.MergedLanes {
+			redeemed, ok := t.redeemed(voucher.ChannelAddress, mergedLane)
+			known = known && ok
+			previous = new(big.Int).Add(previous, redeemed)
+		}
+		if known {
+			voucher.Increment = new(big.Int).Sub(voucher.Amount, previous)
+		}
+		t.lanes[laneKey{channel: voucher.ChannelAddress, lane: voucher.Lane}] = voucher.Amount
+	}
+	return nil
+}
+
+// redeemed returns the amount redeemed on a lane, false if it is unknown.
+func (t *LaneTracker) redeemed(channel string, lane uint64) (*big.Int, bool) {
+	if redeemed, ok := t.lanes[laneKey{channel: channel, lane: lane}]; ok {
+		return redeemed, true
+	}
+	_, created := t.channels[channel]
+	return big.NewInt(0), created
+}
@@ -0,0 +1,71 @@
+package paymentchannel_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/tools/paymentchannel"
+	"github.com/zondax/fil-parser/types"
+)
+
+func voucher(channelAddress string, lane uint64, amount int64, mergedLanes ...uint64) *types.PaymentChannelVoucher {
+	return &types.PaymentChannelVoucher{ChannelAddress: channelAddress, Lane: lane, Amount: big.NewInt(amount), MergedLanes: mergedLanes}
+}
+
+func TestLaneTracker(t *testing.T) {
+	const oldChannel = "f01600"
+	tracker := paymentchannel.NewLaneTracker()
+
+	t.Run("vouchers of a created channel redeem the increment over the lane", func(t *testing.T) {
+		events := &types.PaymentChannelEvents{
+			Channels: []*types.PaymentChannelCreation{{ChannelAddress: channel}},
+			Vouchers: []*types.PaymentChannelVoucher{
+				voucher(channel, 0, 100),
+				voucher(channel, 0, 250),
+				voucher(channel, 1, 40),
+			},
+		}
+		require.NoError(t, tracker.Apply(100, events))
+		assert.Equal(t, big.NewInt(100), events.Vouchers[0].Increment)
+		assert.Equal(t, big.NewInt(150), events.Vouchers[1].Increment)
+		assert.Equal(t, big.NewInt(40), events.Vouchers[2].Increment)
+	})
+
+	t.Run("merged lanes count as redeemed", func(t *testing.T) {
+		events := &types.PaymentChannelEvents{Vouchers: []*types.PaymentChannelVoucher{voucher(channel, 2, 300, 0, 1)}}
+		require.NoError(t, tracker.Apply(110, events))
+		assert.Equal(t, big.NewInt(10), events.Vouchers[0].Increment)
+	})
+
+	t.Run("lanes redeemed before the tracker started have no increment", func(t *testing.T) {
+		events := &types.PaymentChannelEvents{Vouchers: []*types.PaymentChannelVoucher{
+			voucher(oldChannel, 0, 500),
+			voucher(oldChannel, 0, 520),
+		}}
+		require.NoError(t, tracker.Apply(120, events))
+		assert.Nil(t, events.Vouchers[0].Increment)
+		assert.Equal(t, big.NewInt(20), events.Vouchers[1].Increment)
+	})
+
+	t.Run("events must be applied in height order", func(t *testing.T) {
+		assert.ErrorIs(t, tracker.Apply(120, nil), common.ErrHeightOutOfOrder)
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		var snapshot bytes.Buffer
+		require.NoError(t, tracker.Export(&snapshot))
+		restored, err := paymentchannel.ImportLaneTracker(bytes.NewReader(snapshot.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, tracker.Height(), restored.Height())
+		assert.Equal(t, tracker.Lanes(), restored.Lanes())
+
+		// lanes of created channels still start empty after the restore
+		events := &types.PaymentChannelEvents{Vouchers: []*types.PaymentChannelVoucher{voucher(channel, 5, 30)}}
+		require.NoError(t, restored.Apply(130, events))
+		assert.Equal(t, big.NewInt(30), events.Vouchers[0].Increment)
+	})
+}
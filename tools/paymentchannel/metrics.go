@@ -0,0 +1,66 @@
+package paymentchannel
+
+import (
+	"github.com/zondax/fil-parser/metrics"
+	metrics2 "github.com/zondax/golem/pkg/metrics"
+	"github.com/zondax/golem/pkg/metrics/collectors"
+)
+
+var (
+	_ metrics.MetricsClient = &paymentChannelMetricsClient{}
+	_ metrics2.TaskMetrics  = &paymentChannelMetricsClient{}
+)
+
+const parserModule = "parser_module"
+
+type paymentChannelMetricsClient struct {
+	metrics.MetricsClient
+	name string
+}
+
+func newClient(metricsClient metrics.MetricsClient, name string) *paymentChannelMetricsClient {
+	s := &paymentChannelMetricsClient{
+		MetricsClient: metricsClient,
+		name:          name,
+	}
+
+	s.registerModuleMetrics(actorNameFromAddressMetric)
+
+	return s
+}
+
+const (
+	actorNameFromAddress = "fil-parser_paymentchannel_actor_name_from_address"
+)
+
+var (
+	actorNameFromAddressMetric = metrics.Metric{
+		Name:    actorNameFromAddress,
+		Help:    "get actor name from address",
+		Labels:  []string{},
+		Handler: &collectors.Gauge{},
+	}
+)
+
+func (c *paymentChannelMetricsClient) registerModuleMetrics(metrics ...metrics.Metric) {
+	commonLabels := []string{parserModule}
+	for i := range metrics {
+		metrics[i].Labels = append(metrics[i].Labels, commonLabels...)
+	}
+
+	c.RegisterCustomMetrics(metrics...)
+}
+
+func (c *paymentChannelMetricsClient) IncrementMetric(name string, labels ...string) error {
+	labels = append(labels, c.name)
+	return c.MetricsClient.IncrementMetric(name, labels...)
+}
+
+func (c *paymentChannelMetricsClient) UpdateMetric(name string, value float64, labels ...string) error {
+	labels = append(labels, c.name)
+	return c.MetricsClient.UpdateMetric(name, value, labels...)
+}
+
+func (c *paymentChannelMetricsClient) UpdateActorNameFromAddressMetric() error {
+	return c.IncrementMetric(actorNameFromAddress)
+}
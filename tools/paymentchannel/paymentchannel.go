@@ -0,0 +1,284 @@
+package paymentchannel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/zondax/golem/pkg/logger"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/builtin/v17/paych"
+	"github.com/filecoin-project/go-state-types/manifest"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/zondax/fil-parser/metrics"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/parser/helper"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+const (
+	KeyParams          = "Params"
+	KeyFrom            = "From"
+	KeyTo              = "To"
+	KeySignedVoucher   = "Sv"
+	KeySecret          = "Secret"
+	KeySecretHash      = "SecretHash"
+	KeyLane            = "Lane"
+	KeyNonce           = "Nonce"
+	KeyAmount          = "Amount"
+	KeyMinSettleHeight = "MinSettleHeight"
+	KeyMerges          = "Merges"
+)
+
+type EventGenerator interface {
+	GeneratePaymentChannelEvents(ctx context.Context, transactions []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.PaymentChannelEvents, error)
+}
+
+var _ EventGenerator = &eventGenerator{}
+
+type eventGenerator struct {
+	helper  *helper.Helper
+	logger  *logger.Logger
+	metrics *paymentChannelMetricsClient
+	config  parser.Config
+}
+
+func NewEventGenerator(helper *helper.Helper, logger *logger.Logger, metrics metrics.MetricsClient, config parser.Config) EventGenerator {
+	return &eventGenerator{
+		helper:  helper,
+		logger:  logger,
+		metrics: newClient(metrics, "paymentChannel"),
+		config:  config,
+	}
+}
+
+// GeneratePaymentChannelEvents expects every transaction of the tipset, subcalls included, as channels are
+// created by a subcall of InitActor.Exec and collected channels pay out through subcalls of Collect.
+func (eg *eventGenerator) GeneratePaymentChannelEvents(ctx context.Context, transactions []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.PaymentChannelEvents, error) {
+	events := &types.PaymentChannelEvents{
+		Channels:    []*types.PaymentChannelCreation{},
+		Vouchers:    []*types.PaymentChannelVoucher{},
+		Settlements: []*types.PaymentChannelSettlement{},
+		Payouts:     []*types.PaymentChannelPayout{},
+	}
+
+	txsById := make(map[string]*types.Transaction, len(transactions))
+	children := make(map[string][]*types.Transaction)
+	for _, tx := range transactions {
+		txsById[tx.Id] = tx
+		children[tx.ParentId] = append(children[tx.ParentId], tx)
+	}
+
+	for _, tx := range transactions {
+		if !common.IsTxSuccess(tx) {
+			eg.logger.Debug("failed tx found, skipping it")
+			continue
+		}
+		// filter by method first, so actor names are only resolved for candidate messages
+		if !isPaymentChannelMethod(tx.TxType) {
+			continue
+		}
+
+		addr, err := address.NewFromString(tx.TxTo)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse address. err: %w", err)
+		}
+
+		// #nosec G115
+		actorName, err := common.ResolveActorName(ctx, eg.helper, addr, int64(tx.Height), tipsetKey, true)
+		if err != nil {
+			_ = eg.metrics.UpdateActorNameFromAddressMetric()
+			return nil, err
+		}
+
+		if !strings.Contains(actorName, manifest.PaychKey) {
+			continue
+		}
+
+		switch tx.TxType {
+		case parser.MethodConstructor:
+			channel, err := eg.createChannel(tx, tipsetCid, txsById[tx.ParentId])
+			if err != nil {
+				return nil, fmt.Errorf("could not create payment channel. err: %w", err)
+			}
+			events.Channels = append(events.Channels, channel)
+		case parser.MethodUpdateChannelState:
+			voucher, err := eg.createVoucher(tx, tipsetCid)
+			if err != nil {
+				return nil, fmt.Errorf("could not create payment channel voucher. err: %w", err)
+			}
+			events.Vouchers = append(events.Vouchers, voucher)
+		case parser.MethodSettle:
+			events.Settlements = append(events.Settlements, eg.createSettlement(tx, tipsetCid))
+		case parser.MethodCollect:
+			events.Payouts = append(events.Payouts, eg.createPayouts(tx, tipsetCid, children[tx.Id])...)
+		}
+	}
+
+	return events, nil
+}
+
+func isPaymentChannelMethod(txType string) bool {
+	switch txType {
+	case parser.MethodConstructor, parser.MethodUpdateChannelState, parser.MethodSettle, parser.MethodCollect:
+		return true
+	}
+	return false
+}
+
+// createChannel builds the channel from the Constructor subcall made by InitActor.Exec, which is the parent of tx.
+func (eg *eventGenerator) createChannel(tx *types.Transaction, tipsetCid string, exec *types.Transaction) (*types.PaymentChannelCreation, error) {
+	params, err := getParams(tx)
+	if err != nil {
+		return nil, err
+	}
+	from, err := common.GetItem[string](params, KeyFrom, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing from: %w", err)
+	}
+	to, err := common.GetItem[string](params, KeyTo, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing to: %w", err)
+	}
+
+	var creator string
+	if exec != nil && exec.TxType == parser.MethodExec {
+		creator = eg.consolidateAddress(exec.TxFrom)
+	}
+
+	return &types.PaymentChannelCreation{
+		ID:             tools.BuildId(tipsetCid, tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType),
+		ChannelAddress: eg.consolidateAddress(tx.TxTo),
+		Creator:        creator,
+		From:           eg.consolidateAddress(from),
+		To:             eg.consolidateAddress(to),
+		Height:         tx.Height,
+		TxCid:          tx.TxCid,
+		TxTimestamp:    tx.TxTimestamp,
+	}, nil
+}
+
+func (eg *eventGenerator) createVoucher(tx *types.Transaction, tipsetCid string) (*types.PaymentChannelVoucher, error) {
+	params, err := getParams(tx)
+	if err != nil {
+		return nil, err
+	}
+	voucher, err := common.GetItem[map[string]interface{}](params, KeySignedVoucher, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing signed voucher: %w", err)
+	}
+	lane, err := common.GetInteger[uint64](voucher, KeyLane, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing lane: %w", err)
+	}
+	nonce, err := common.GetInteger[uint64](voucher, KeyNonce, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing nonce: %w", err)
+	}
+	amount, err := common.GetBigInt(voucher, KeyAmount, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing amount: %w", err)
+	}
+	minSettleHeight, err := common.GetInteger[int64](voucher, KeyMinSettleHeight, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing min settle height: %w", err)
+	}
+	secretHash, err := common.GetItem[string](voucher, KeySecretHash, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing secret hash: %w", err)
+	}
+	secret, err := common.GetItem[string](params, KeySecret, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing secret: %w", err)
+	}
+	merges, err := common.GetSlice[map[string]interface{}](voucher, KeyMerges, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing merges: %w", err)
+	}
+	mergedLanes := make([]uint64, 0, len(merges))
+	for _, merge := range merges {
+		mergedLane, err := common.GetInteger[uint64](merge, KeyLane, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing merged lane: %w", err)
+		}
+		mergedLanes = append(mergedLanes, mergedLane)
+	}
+
+	return &types.PaymentChannelVoucher{
+		ID:              tools.BuildId(tipsetCid, tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType, fmt.Sprint(lane), fmt.Sprint(nonce)),
+		ChannelAddress:  eg.consolidateAddress(tx.TxTo),
+		Redeemer:        eg.consolidateAddress(tx.TxFrom),
+		Lane:            lane,
+		Nonce:           nonce,
+		Amount:          amount,
+		MergedLanes:     mergedLanes,
+		SecretHash:      secretHash,
+		SecretPreimage:  secret,
+		MinSettleHeight: minSettleHeight,
+		Height:          tx.Height,
+		TxCid:           tx.TxCid,
+		TxTimestamp:     tx.TxTimestamp,
+	}, nil
+}
+
+// createSettlement builds the start of the settling period. The settle delay has not changed across actor versions.
+func (eg *eventGenerator) createSettlement(tx *types.Transaction, tipsetCid string) *types.PaymentChannelSettlement {
+	return &types.PaymentChannelSettlement{
+		ID:             tools.BuildId(tipsetCid, tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType),
+		ChannelAddress: eg.consolidateAddress(tx.TxTo),
+		Caller:         eg.consolidateAddress(tx.TxFrom),
+		// #nosec G115
+		SettlingAt:  int64(tx.Height) + int64(paych.SettleDelay),
+		Height:      tx.Height,
+		TxCid:       tx.TxCid,
+		TxTimestamp: tx.TxTimestamp,
+	}
+}
+
+// createPayouts returns the funds sent by the channel in the subcalls of Collect.
+// The balance left to the payer is not always a send, as newer actor versions return it when deleting the channel.
+func (eg *eventGenerator) createPayouts(tx *types.Transaction, tipsetCid string, subcalls []*types.Transaction) []*types.PaymentChannelPayout {
+	var payouts []*types.PaymentChannelPayout
+	for _, subcall := range subcalls {
+		if subcall.TxFrom != tx.TxTo || subcall.TxType != parser.MethodSend || !common.IsTxSuccess(subcall) {
+			continue
+		}
+		if subcall.Amount == nil || subcall.Amount.Sign() <= 0 {
+			continue
+		}
+		payouts = append(payouts, &types.PaymentChannelPayout{
+			ID:             tools.BuildId(tipsetCid, tx.TxCid, subcall.TxFrom, subcall.TxTo, fmt.Sprint(tx.Height), tx.TxType, fmt.Sprint(len(payouts))),
+			ChannelAddress: eg.consolidateAddress(tx.TxTo),
+			Recipient:      eg.consolidateAddress(subcall.TxTo),
+			Amount:         subcall.Amount,
+			Height:         tx.Height,
+			TxCid:          tx.TxCid,
+			TxTimestamp:    tx.TxTimestamp,
+		})
+	}
+	return payouts
+}
+
+func (eg *eventGenerator) consolidateAddress(addr string) string {
+	consolidated, err := common.ConsolidateAddress(addr, eg.helper, eg.logger, eg.config, true)
+	if err != nil {
+		eg.logger.Errorf("error consolidating address %s: %s", addr, err)
+	}
+	return consolidated
+}
+
+func getParams(tx *types.Transaction) (map[string]interface{}, error) {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(tx.TxMetadata), &metadata); err != nil {
+		return nil, fmt.Errorf("error unmarshalling tx metadata: %w", err)
+	}
+	params, err := common.GetItem[map[string]interface{}](metadata, KeyParams, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing params: %w", err)
+	}
+	return params, nil
+}
@@ -0,0 +1,144 @@
+package paymentchannel_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/manifest"
+	filApiTypes "github.com/filecoin-project/lotus/api/types"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	filMetrics "github.com/zondax/fil-parser/metrics"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/parser/helper"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/mocks"
+	"github.com/zondax/fil-parser/tools/paymentchannel"
+	"github.com/zondax/fil-parser/types"
+	"github.com/zondax/golem/pkg/logger"
+	metrics2 "github.com/zondax/golem/pkg/metrics"
+	rosettaFilecoinLib "github.com/zondax/rosetta-filecoin-lib"
+)
+
+const (
+	tipsetCid   = "bafy2bzaceczpzd5k7u6hwaim7fdpwx2ujg7uhrdbpijf7q5ryvh7ogmawxupk"
+	txCid       = "bafy2bzacebbpdegvr3i4cosewthysg5xkxpqfn2wfcz6mv2hmoktwbdxkax4s"
+	actorCidStr = "bafk2bzacea6rabflc7kpwr6y4lzcqsnuahr4zblyq3rhzrrsfceeiw2lufrb4"
+	channel     = "f01500"
+	payer       = "f01100"
+	payee       = "f01200"
+	height      = 2_000_000
+)
+
+func setupTest(t *testing.T) paymentchannel.EventGenerator {
+	actorCid, err := cid.Parse(actorCidStr)
+	require.NoError(t, err)
+
+	logger := logger.NewDevelopmentLogger()
+	metrics := filMetrics.NewMetricsClient(metrics2.NewNoopMetrics())
+
+	node := &mocks.FullNode{}
+	node.On("StateNetworkName", mock.Anything).Return(dtypes.NetworkName("calibrationnet"), nil)
+	node.On("StateNetworkVersion", mock.Anything, mock.Anything).Return(filApiTypes.NetworkVersion(16), nil)
+	node.On("StateActorCodeCIDs", mock.Anything, mock.Anything).Return(map[string]cid.Cid{
+		manifest.PaychKey: actorCid,
+	}, nil)
+
+	cache := &mocks.IActorsCache{}
+	cache.On("StoreAddressInfo", mock.Anything).Return(nil)
+	cache.On("GetActorCode", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(actorCidStr, nil)
+	cache.On("GetActorNameFromAddress", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(manifest.PaychKey, nil)
+
+	lib := rosettaFilecoinLib.NewRosettaConstructionFilecoin(node)
+	helper := helper.NewHelper(lib, cache, node, logger, metrics)
+
+	return paymentchannel.NewEventGenerator(helper, logger, metrics, parser.Config{})
+}
+
+func newTx(id, parentId, txType, from, to, metadata string, amount int64) *types.Transaction {
+	tx := &types.Transaction{
+		Id:            id,
+		ParentId:      parentId,
+		TxCid:         txCid,
+		TxType:        txType,
+		TxFrom:        from,
+		TxTo:          to,
+		TxMetadata:    metadata,
+		Amount:        big.NewInt(amount),
+		Status:        tools.GetExitCodeStatus(exitcode.Ok),
+		SubcallStatus: tools.GetExitCodeStatus(exitcode.Ok),
+	}
+	tx.Height = height
+	return tx
+}
+
+func TestGeneratePaymentChannelEvents_Creation(t *testing.T) {
+	eg := setupTest(t)
+
+	exec := newTx("exec", "", parser.MethodExec, payer, "f01",
+		`{"Params":{"CodeCid":{"/":"`+actorCidStr+`"},"ConstructorParams":"gkMARExDAOwH"}}`, 100)
+	constructor := newTx("constructor", "exec", parser.MethodConstructor, "f01", channel,
+		`{"Params":{"From":"`+payer+`","To":"`+payee+`"}}`, 100)
+	constructor.Level = 1
+
+	events, err := eg.GeneratePaymentChannelEvents(context.Background(), []*types.Transaction{exec, constructor}, tipsetCid, filTypes.EmptyTSK)
+	require.NoError(t, err)
+
+	require.Len(t, events.Channels, 1)
+	created := events.Channels[0]
+	assert.Equal(t, channel, created.ChannelAddress)
+	assert.Equal(t, payer, created.Creator)
+	assert.Equal(t, payer, created.From)
+	assert.Equal(t, payee, created.To)
+	assert.Equal(t, txCid, created.TxCid)
+	assert.NotEmpty(t, created.ID)
+	assert.Empty(t, events.Vouchers)
+}
+
+func TestGeneratePaymentChannelEvents_Lifecycle(t *testing.T) {
+	eg := setupTest(t)
+
+	voucher := newTx("voucher", "", parser.MethodUpdateChannelState, payee, channel,
+		`{"Params":{"Sv":{"ChannelAddr":"`+channel+`","TimeLockMin":0,"TimeLockMax":0,"SecretHash":"aGFzaA==","Extra":null,"Lane":2,"Nonce":7,"Amount":"1500","MinSettleHeight":2000100,"Merges":[{"Lane":1,"Nonce":3}],"Signature":{"Type":1,"Data":"c2ln"}},"Secret":"c2VjcmV0"}}`, 0)
+	settle := newTx("settle", "", parser.MethodSettle, payer, channel, `{"Params":{}}`, 0)
+	collect := newTx("collect", "", parser.MethodCollect, payee, channel, `{"Params":{}}`, 0)
+	payout := newTx("payout", "collect", parser.MethodSend, channel, payee, `{}`, 1500)
+	payout.Level = 1
+	// sends that move no funds are not payouts
+	emptySend := newTx("empty", "collect", parser.MethodSend, channel, payer, `{}`, 0)
+	emptySend.Level = 1
+	failed := newTx("failed", "", parser.MethodUpdateChannelState, payee, channel, `{}`, 0)
+	failed.Status = tools.GetExitCodeStatus(exitcode.ErrIllegalArgument)
+
+	events, err := eg.GeneratePaymentChannelEvents(context.Background(), []*types.Transaction{voucher, settle, collect, payout, emptySend, failed}, tipsetCid, filTypes.EmptyTSK)
+	require.NoError(t, err)
+
+	require.Len(t, events.Vouchers, 1)
+	redeemed := events.Vouchers[0]
+	assert.Equal(t, channel, redeemed.ChannelAddress)
+	assert.Equal(t, payee, redeemed.Redeemer)
+	assert.Equal(t, uint64(2), redeemed.Lane)
+	assert.Equal(t, uint64(7), redeemed.Nonce)
+	assert.Equal(t, big.NewInt(1500), redeemed.Amount)
+	assert.Equal(t, []uint64{1}, redeemed.MergedLanes)
+	assert.Nil(t, redeemed.Increment, "increments are set by the lane tracker")
+	assert.Equal(t, "aGFzaA==", redeemed.SecretHash)
+	assert.Equal(t, "c2VjcmV0", redeemed.SecretPreimage)
+	assert.Equal(t, int64(2_000_100), redeemed.MinSettleHeight)
+
+	require.Len(t, events.Settlements, 1)
+	assert.Equal(t, payer, events.Settlements[0].Caller)
+	assert.Equal(t, int64(height+1440), events.Settlements[0].SettlingAt)
+
+	require.Len(t, events.Payouts, 1)
+	assert.Equal(t, channel, events.Payouts[0].ChannelAddress)
+	assert.Equal(t, payee, events.Payouts[0].Recipient)
+	assert.Equal(t, big.NewInt(1500), events.Payouts[0].Amount)
+	assert.Empty(t, events.Channels)
+}
@@ -0,0 +1,66 @@
+package power
+
+import (
+	"github.com/zondax/fil-parser/metrics"
+	metrics2 "github.com/zondax/golem/pkg/metrics"
+	"github.com/zondax/golem/pkg/metrics/collectors"
+)
+
+var (
+	_ metrics.MetricsClient = &powerMetricsClient{}
+	_ metrics2.TaskMetrics  = &powerMetricsClient{}
+)
+
+const parserModule = "parser_module"
+
+type powerMetricsClient struct {
+	metrics.MetricsClient
+	name string
+}
+
+func newClient(metricsClient metrics.MetricsClient, name string) *powerMetricsClient {
+	s := &powerMetricsClient{
+		MetricsClient: metricsClient,
+		name:          name,
+	}
+
+	s.registerModuleMetrics(actorNameFromAddressMetric)
+
+	return s
+}
+
+const (
+	actorNameFromAddress = "fil-parser_power_actor_name_from_address"
+)
+
+var (
+	actorNameFromAddressMetric = metrics.Metric{
+		Name:    actorNameFromAddress,
+		Help:    "get actor name from address",
+		Labels:  []string{},
+		Handler: &collectors.Gauge{},
+	}
+)
+
+func (c *powerMetricsClient) registerModuleMetrics(metrics ...metrics.Metric) {
+	commonLabels := []string{parserModule}
+	for i := range metrics {
+		metrics[i].Labels = append(metrics[i].Labels, commonLabels...)
+	}
+
+	c.RegisterCustomMetrics(metrics...)
+}
+
+func (c *powerMetricsClient) IncrementMetric(name string, labels ...string) error {
+	labels = append(labels, c.name)
+	return c.MetricsClient.IncrementMetric(name, labels...)
+}
+
+func (c *powerMetricsClient) UpdateMetric(name string, value float64, labels ...string) error {
+	labels = append(labels, c.name)
+	return c.MetricsClient.UpdateMetric(name, value, labels...)
+}
+
+func (c *powerMetricsClient) UpdateActorNameFromAddressMetric() error {
+	return c.IncrementMetric(actorNameFromAddress)
+}
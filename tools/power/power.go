@@ -0,0 +1,325 @@
+package power
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/zondax/golem/pkg/logger"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/manifest"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/zondax/fil-parser/metrics"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/parser/helper"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/tools/metadata"
+	"github.com/zondax/fil-parser/types"
+)
+
+const (
+	KeyParams               = "Params"
+	KeyRawByteDelta         = "RawByteDelta"
+	KeyQualityAdjustedDelta = "QualityAdjustedDelta"
+)
+
+type EventGenerator interface {
+	GeneratePowerEvents(ctx context.Context, transactions []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.PowerEvents, error)
+}
+
+var _ EventGenerator = &eventGenerator{}
+
+type eventGenerator struct {
+	helper  *helper.Helper
+	logger  *logger.Logger
+	metrics *powerMetricsClient
+	decoder *metadata.Decoder
+	config  parser.Config
+}
+
+func NewEventGenerator(helper *helper.Helper, logger *logger.Logger, metrics metrics.MetricsClient, network string, config parser.Config) EventGenerator {
+	return &eventGenerator{
+		helper:  helper,
+		logger:  logger,
+		metrics: newClient(metrics, "power"),
+		decoder: metadata.NewDecoder(network),
+		config:  config,
+	}
+}
+
+// GeneratePowerEvents expects every transaction of the tipset, subcalls included, as claimed power, pledge
+// and consensus fault updates are sent by the miner actors while handling their own messages.
+func (eg *eventGenerator) GeneratePowerEvents(ctx context.Context, transactions []*types.Transaction, tipsetCid string, tipsetKey filTypes.TipSetKey) (*types.PowerEvents, error) {
+	events := &types.PowerEvents{
+		MinerCreations:  []*types.PowerMinerCreation{},
+		ClaimedPower:    []*types.PowerClaimedPowerUpdate{},
+		PledgeTotals:    []*types.PowerPledgeTotalUpdate{},
+		ConsensusFaults: []*types.PowerConsensusFault{},
+	}
+
+	txsById := make(map[string]*types.Transaction, len(transactions))
+	for _, tx := range transactions {
+		txsById[tx.Id] = tx
+	}
+
+	for _, tx := range transactions {
+		if !common.IsTxSuccess(tx) {
+			eg.logger.Debug("failed tx found, skipping it")
+			continue
+		}
+		// filter by method first, so actor names are only resolved for candidate messages
+		if !isPowerStateMethod(tx.TxType) {
+			continue
+		}
+
+		addr, err := address.NewFromString(tx.TxTo)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse address. err: %w", err)
+		}
+
+		// #nosec G115
+		actorName, err := common.ResolveActorName(ctx, eg.helper, addr, int64(tx.Height), tipsetKey, true)
+		if err != nil {
+			_ = eg.metrics.UpdateActorNameFromAddressMetric()
+			return nil, err
+		}
+
+		if !strings.Contains(actorName, manifest.PowerKey) {
+			continue
+		}
+
+		switch tx.TxType {
+		case parser.MethodCreateMiner, parser.MethodCreateMinerExported:
+			creation, err := eg.createMinerCreation(tx, tipsetCid)
+			if err != nil {
+				return nil, fmt.Errorf("could not create miner creation. err: %w", err)
+			}
+			events.MinerCreations = append(events.MinerCreations, creation)
+		case parser.MethodUpdateClaimedPower:
+			update, err := eg.createClaimedPowerUpdate(tx, tipsetCid)
+			if err != nil {
+				return nil, fmt.Errorf("could not create claimed power update. err: %w", err)
+			}
+			events.ClaimedPower = append(events.ClaimedPower, update)
+		case parser.MethodUpdatePledgeTotal:
+			update, err := eg.createPledgeTotalUpdate(tx, tipsetCid)
+			if err != nil {
+				return nil, fmt.Errorf("could not create pledge total update. err: %w", err)
+			}
+			events.PledgeTotals = append(events.PledgeTotals, update)
+		case parser.MethodOnConsensusFault:
+			fault, err := eg.createConsensusFault(tx, tipsetCid, txsById[tx.ParentId])
+			if err != nil {
+				return nil, fmt.Errorf("could not create consensus fault. err: %w", err)
+			}
+			events.ConsensusFaults = append(events.ConsensusFaults, fault)
+		}
+	}
+
+	return events, nil
+}
+
+func isPowerStateMethod(txType string) bool {
+	switch txType {
+	case parser.MethodCreateMiner, parser.MethodCreateMinerExported, parser.MethodUpdateClaimedPower,
+		parser.MethodUpdatePledgeTotal, parser.MethodOnConsensusFault:
+		return true
+	}
+	return false
+}
+
+func (eg *eventGenerator) createMinerCreation(tx *types.Transaction, tipsetCid string) (*types.PowerMinerCreation, error) {
+	// both methods share the same params and return
+	createMiner, err := metadata.DecodeAs[*metadata.CreateMiner](eg.decoder, manifest.PowerKey, &types.Transaction{
+		TxBasicBlockData: tx.TxBasicBlockData,
+		TxType:           parser.MethodCreateMiner,
+		TxMetadata:       tx.TxMetadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if createMiner.Return == nil {
+		return nil, fmt.Errorf("missing return of %s", tx.TxType)
+	}
+
+	// schema version 1 holds the seal proof type, replaced by the window post proof type in actors v3
+	windowPoStProofType := createMiner.Params.ProofType
+	if createMiner.Version == 1 {
+		proofType, err := abi.RegisteredSealProof(createMiner.Params.ProofType).RegisteredWindowPoStProof()
+		if err != nil {
+			return nil, fmt.Errorf("error getting window post proof type: %w", err)
+		}
+		windowPoStProofType = int64(proofType)
+	}
+
+	multiaddrs := make([]string, 0, len(createMiner.Params.Multiaddrs))
+	for _, encoded := range createMiner.Params.Multiaddrs {
+		multiaddrs = append(multiaddrs, eg.decodeMultiaddr(encoded))
+	}
+
+	return &types.PowerMinerCreation{
+		ID:                  tools.BuildId(tipsetCid, tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType),
+		Creator:             eg.consolidateAddress(tx.TxFrom),
+		Owner:               eg.consolidateAddress(createMiner.Params.Owner),
+		Worker:              eg.consolidateAddress(createMiner.Params.Worker),
+		PeerID:              eg.decodePeerID(createMiner.Params.Peer),
+		Multiaddrs:          multiaddrs,
+		WindowPoStProofType: windowPoStProofType,
+		IDAddress:           createMiner.Return.IDAddress,
+		RobustAddress:       createMiner.Return.RobustAddress,
+		Height:              tx.Height,
+		TxCid:               tx.TxCid,
+		TxTimestamp:         tx.TxTimestamp,
+	}, nil
+}
+
+// createClaimedPowerUpdate builds the power delta reported by the miner that sent tx.
+func (eg *eventGenerator) createClaimedPowerUpdate(tx *types.Transaction, tipsetCid string) (*types.PowerClaimedPowerUpdate, error) {
+	params, err := getParams(tx)
+	if err != nil {
+		return nil, err
+	}
+	paramsMap, ok := params.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("params not of type object, of type %T", params)
+	}
+	rawByteDelta, err := common.GetBigInt(paramsMap, KeyRawByteDelta, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing raw byte delta: %w", err)
+	}
+	qualityAdjustedDelta, err := common.GetBigInt(paramsMap, KeyQualityAdjustedDelta, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing quality adjusted delta: %w", err)
+	}
+
+	return &types.PowerClaimedPowerUpdate{
+		ID:                   tools.BuildId(tipsetCid, tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType, tx.Id),
+		MinerAddress:         eg.consolidateAddress(tx.TxFrom),
+		RawBytePowerDelta:    rawByteDelta,
+		QualityAdjPowerDelta: qualityAdjustedDelta,
+		Height:               tx.Height,
+		TxCid:                tx.TxCid,
+		TxTimestamp:          tx.TxTimestamp,
+	}, nil
+}
+
+// createPledgeTotalUpdate builds the pledge delta reported by the miner that sent tx.
+func (eg *eventGenerator) createPledgeTotalUpdate(tx *types.Transaction, tipsetCid string) (*types.PowerPledgeTotalUpdate, error) {
+	pledgeDelta, err := getAmountParams(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.PowerPledgeTotalUpdate{
+		ID:           tools.BuildId(tipsetCid, tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType, tx.Id),
+		MinerAddress: eg.consolidateAddress(tx.TxFrom),
+		PledgeDelta:  pledgeDelta,
+		Height:       tx.Height,
+		TxCid:        tx.TxCid,
+		TxTimestamp:  tx.TxTimestamp,
+	}, nil
+}
+
+// createConsensusFault builds the removal of the faulty miner that sent tx, reported through the parent message.
+// The params are the pledge the miner held, which is removed from the network total.
+func (eg *eventGenerator) createConsensusFault(tx *types.Transaction, tipsetCid string, report *types.Transaction) (*types.PowerConsensusFault, error) {
+	pledge, err := getAmountParams(tx)
+	if err != nil {
+		return nil, err
+	}
+	var reporter string
+	if report != nil && report.TxType == parser.MethodReportConsensusFault {
+		reporter = eg.consolidateAddress(report.TxFrom)
+	}
+
+	return &types.PowerConsensusFault{
+		ID:           tools.BuildId(tipsetCid, tx.TxCid, tx.TxFrom, tx.TxTo, fmt.Sprint(tx.Height), tx.TxType),
+		MinerAddress: eg.consolidateAddress(tx.TxFrom),
+		Reporter:     reporter,
+		Pledge:       pledge,
+		Height:       tx.Height,
+		TxCid:        tx.TxCid,
+		TxTimestamp:  tx.TxTimestamp,
+	}, nil
+}
+
+// decodePeerID returns the text form of the base64 encoded peer id, or the encoded value if it is not a valid peer id.
+func (eg *eventGenerator) decodePeerID(encoded string) string {
+	if encoded == "" {
+		return ""
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		eg.logger.Errorf("error decoding peer id %s: %s", encoded, err)
+		return encoded
+	}
+	if len(raw) == 0 {
+		return ""
+	}
+	peerID, err := peer.IDFromBytes(raw)
+	if err != nil {
+		eg.logger.Debugf("invalid peer id %s: %s", encoded, err)
+		return encoded
+	}
+	return peerID.String()
+}
+
+// decodeMultiaddr returns the text form of the base64 encoded multiaddr, or the encoded value if it is not a valid multiaddr.
+func (eg *eventGenerator) decodeMultiaddr(encoded string) string {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		eg.logger.Errorf("error decoding multiaddr %s: %s", encoded, err)
+		return encoded
+	}
+	addr, err := multiaddr.NewMultiaddrBytes(raw)
+	if err != nil {
+		eg.logger.Debugf("invalid multiaddr %s: %s", encoded, err)
+		return encoded
+	}
+	return addr.String()
+}
+
+func (eg *eventGenerator) consolidateAddress(addr string) string {
+	consolidated, err := common.ConsolidateAddress(addr, eg.helper, eg.logger, eg.config, true)
+	if err != nil {
+		eg.logger.Errorf("error consolidating address %s: %s", addr, err)
+	}
+	return consolidated
+}
+
+// getAmountParams returns the params of methods that take a bare token amount, which the parser encodes as a string.
+func getAmountParams(tx *types.Transaction) (*big.Int, error) {
+	params, err := getParams(tx)
+	if err != nil {
+		return nil, err
+	}
+	encoded, ok := params.(string)
+	if !ok {
+		return nil, fmt.Errorf("params not of type string, of type %T", params)
+	}
+	amount, ok := new(big.Int).SetString(encoded, 10)
+	if !ok {
+		return nil, fmt.Errorf("failed to convert string %s to big.Int", encoded)
+	}
+	return amount, nil
+}
+
+func getParams(tx *types.Transaction) (interface{}, error) {
+	var value map[string]interface{}
+	if err := json.Unmarshal([]byte(tx.TxMetadata), &value); err != nil {
+		return nil, fmt.Errorf("error unmarshalling tx metadata: %w", err)
+	}
+	params, ok := value[KeyParams]
+	if !ok || params == nil {
+		return nil, fmt.Errorf("key %s not found", KeyParams)
+	}
+	return params, nil
+}
@@ -0,0 +1,183 @@
+package power_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	power16 "github.com/filecoin-project/go-state-types/builtin/v16/power"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/manifest"
+	filApiTypes "github.com/filecoin-project/lotus/api/types"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+	power0 "github.com/filecoin-project/specs-actors/actors/builtin/power"
+	"github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	filMetrics "github.com/zondax/fil-parser/metrics"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/parser/helper"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/mocks"
+	"github.com/zondax/fil-parser/tools/power"
+	"github.com/zondax/fil-parser/types"
+	"github.com/zondax/golem/pkg/logger"
+	metrics2 "github.com/zondax/golem/pkg/metrics"
+	rosettaFilecoinLib "github.com/zondax/rosetta-filecoin-lib"
+)
+
+const (
+	tipsetCid    = "bafy2bzaceczpzd5k7u6hwaim7fdpwx2ujg7uhrdbpijf7q5ryvh7ogmawxupk"
+	txCid        = "bafy2bzacebbpdegvr3i4cosewthysg5xkxpqfn2wfcz6mv2hmoktwbdxkax4s"
+	actorCidStr  = "bafk2bzacea6rabflc7kpwr6y4lzcqsnuahr4zblyq3rhzrrsfceeiw2lufrb4"
+	powerActor   = "f04"
+	minerAddress = "f01000"
+	robustMiner  = "f2ddsjma6hfwcqhdp4vv6z4t5fighlhrjrqyxcekq"
+	peerIDStr    = "12D3KooWGzxzKZYveHXtpG6AsrUJBcWxHBFS2HsEoGTxrMLvKXtf"
+	multiaddrStr = "/ip4/10.0.0.1/tcp/24001"
+)
+
+func setupTest(t *testing.T) power.EventGenerator {
+	actorCid, err := cid.Parse(actorCidStr)
+	require.NoError(t, err)
+
+	logger := logger.NewDevelopmentLogger()
+	metrics := filMetrics.NewMetricsClient(metrics2.NewNoopMetrics())
+
+	node := &mocks.FullNode{}
+	node.On("StateNetworkName", mock.Anything).Return(dtypes.NetworkName(tools.MainnetNetwork), nil)
+	node.On("StateNetworkVersion", mock.Anything, mock.Anything).Return(filApiTypes.NetworkVersion(16), nil)
+	node.On("StateActorCodeCIDs", mock.Anything, mock.Anything).Return(map[string]cid.Cid{
+		manifest.PowerKey: actorCid,
+	}, nil)
+
+	cache := &mocks.IActorsCache{}
+	cache.On("StoreAddressInfo", mock.Anything).Return(nil)
+	cache.On("GetActorCode", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(actorCidStr, nil)
+	cache.On("GetActorNameFromAddress", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(manifest.PowerKey, nil)
+
+	lib := rosettaFilecoinLib.NewRosettaConstructionFilecoin(node)
+	helper := helper.NewHelper(lib, cache, node, logger, metrics)
+
+	return power.NewEventGenerator(helper, logger, metrics, tools.MainnetNetwork, parser.Config{})
+}
+
+func newTx(id, parentId, txType, from, to string, height int64, metadata string) *types.Transaction {
+	tx := &types.Transaction{
+		Id:            id,
+		ParentId:      parentId,
+		TxCid:         txCid,
+		TxType:        txType,
+		TxFrom:        from,
+		TxTo:          to,
+		TxMetadata:    metadata,
+		Amount:        big.NewInt(0),
+		Status:        tools.GetExitCodeStatus(exitcode.Ok),
+		SubcallStatus: tools.GetExitCodeStatus(exitcode.Ok),
+	}
+	// #nosec G115
+	tx.Height = uint64(height)
+	return tx
+}
+
+// createMinerMetadata encodes params the way the power actor parser does, with the created miner as return.
+func createMinerMetadata(t *testing.T, params interface{}) string {
+	encoded, err := json.Marshal(map[string]interface{}{
+		parser.ParamsKey: params,
+		parser.ReturnKey: &types.AddressInfo{Short: minerAddress, Robust: robustMiner, ActorType: manifest.MinerKey},
+	})
+	require.NoError(t, err)
+	return string(encoded)
+}
+
+func TestGeneratePowerEvents_CreateMiner(t *testing.T) {
+	eg := setupTest(t)
+
+	owner, err := address.NewIDAddress(1100)
+	require.NoError(t, err)
+	worker, err := address.NewIDAddress(1200)
+	require.NoError(t, err)
+	peerID, err := peer.Decode(peerIDStr)
+	require.NoError(t, err)
+	maddr, err := multiaddr.NewMultiaddr(multiaddrStr)
+	require.NoError(t, err)
+
+	current := &power16.CreateMinerParams{
+		Owner:               owner,
+		Worker:              worker,
+		WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1_1,
+		Peer:                []byte(peerID),
+		Multiaddrs:          [][]byte{maddr.Bytes()},
+	}
+	legacy := &power0.CreateMinerParams{
+		Owner:         owner,
+		Worker:        worker,
+		SealProofType: abi.RegisteredSealProof_StackedDrg32GiBV1,
+	}
+
+	events, err := eg.GeneratePowerEvents(context.Background(), []*types.Transaction{
+		newTx("current", "", parser.MethodCreateMinerExported, owner.String(), powerActor, tools.V24.Height(), createMinerMetadata(t, current)),
+		newTx("legacy", "", parser.MethodCreateMiner, owner.String(), powerActor, tools.V4.Height(), createMinerMetadata(t, legacy)),
+	}, tipsetCid, filTypes.EmptyTSK)
+	require.NoError(t, err)
+	require.Len(t, events.MinerCreations, 2)
+
+	created := events.MinerCreations[0]
+	assert.Equal(t, owner.String(), created.Creator)
+	assert.Equal(t, owner.String(), created.Owner)
+	assert.Equal(t, worker.String(), created.Worker)
+	assert.Equal(t, peerIDStr, created.PeerID)
+	assert.Equal(t, []string{multiaddrStr}, created.Multiaddrs)
+	assert.Equal(t, int64(abi.RegisteredPoStProof_StackedDrgWindow32GiBV1_1), created.WindowPoStProofType)
+	assert.Equal(t, minerAddress, created.IDAddress)
+	assert.Equal(t, robustMiner, created.RobustAddress)
+
+	// legacy miners are created with a seal proof type
+	created = events.MinerCreations[1]
+	assert.Empty(t, created.PeerID)
+	assert.Empty(t, created.Multiaddrs)
+	assert.Equal(t, int64(abi.RegisteredPoStProof_StackedDrgWindow32GiBV1), created.WindowPoStProofType)
+}
+
+func TestGeneratePowerEvents_Updates(t *testing.T) {
+	eg := setupTest(t)
+	height := tools.V24.Height()
+
+	report := newTx("report", "", parser.MethodReportConsensusFault, "f01300", minerAddress, height, `{}`)
+	fault := newTx("fault", "report", parser.MethodOnConsensusFault, minerAddress, powerActor, height, `{"Params":"7000"}`)
+	fault.Level = 1
+	failed := newTx("failed", "", parser.MethodUpdatePledgeTotal, minerAddress, powerActor, height, `{"Params":"10"}`)
+	failed.SubcallStatus = tools.GetExitCodeStatus(exitcode.ErrForbidden)
+
+	events, err := eg.GeneratePowerEvents(context.Background(), []*types.Transaction{
+		newTx("power", "", parser.MethodUpdateClaimedPower, minerAddress, powerActor, height,
+			`{"Params":{"RawByteDelta":"34359738368","QualityAdjustedDelta":"-343597383680"}}`),
+		newTx("pledge", "", parser.MethodUpdatePledgeTotal, minerAddress, powerActor, height, `{"Params":"-2500"}`),
+		report,
+		fault,
+		failed,
+	}, tipsetCid, filTypes.EmptyTSK)
+	require.NoError(t, err)
+
+	require.Len(t, events.ClaimedPower, 1)
+	assert.Equal(t, minerAddress, events.ClaimedPower[0].MinerAddress)
+	assert.Equal(t, big.NewInt(34359738368), events.ClaimedPower[0].RawBytePowerDelta)
+	assert.Equal(t, big.NewInt(-343597383680), events.ClaimedPower[0].QualityAdjPowerDelta)
+
+	require.Len(t, events.PledgeTotals, 1)
+	assert.Equal(t, minerAddress, events.PledgeTotals[0].MinerAddress)
+	assert.Equal(t, big.NewInt(-2500), events.PledgeTotals[0].PledgeDelta)
+
+	require.Len(t, events.ConsensusFaults, 1)
+	assert.Equal(t, minerAddress, events.ConsensusFaults[0].MinerAddress)
+	assert.Equal(t, "f01300", events.ConsensusFaults[0].Reporter)
+	assert.Equal(t, big.NewInt(7000), events.ConsensusFaults[0].Pledge)
+	assert.Empty(t, events.MinerCreations)
+}
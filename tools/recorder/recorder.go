@@ -0,0 +1,256 @@
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/filecoin-project/lotus/api"
+)
+
+var (
+	ErrCallNotRecorded  = errors.New("rpc call not recorded")
+	ErrCallNotSupported = errors.New("rpc call not supported")
+)
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// Call is a single rpc call made to the node along with its response.
+// Params holds every argument but the context, encoded as a json array.
+// EncodingError is set when the params or the result could not be encoded, in which case the call can't be replayed.
+type Call struct {
+	Method        string          `json:"method"`
+	Params        json.RawMessage `json:"params"`
+	Result        json.RawMessage `json:"result,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	EncodingError string          `json:"encoding_error,omitempty"`
+}
+
+// Fixture is the list of rpc calls recorded while parsing, in the order they were made.
+type Fixture struct {
+	Calls []Call `json:"calls"`
+}
+
+// LoadFixture decodes a fixture previously written with Fixture.Write.
+func LoadFixture(r io.Reader) (*Fixture, error) {
+	var fixture Fixture
+	if err := json.NewDecoder(r).Decode(&fixture); err != nil {
+		return nil, fmt.Errorf("could not decode fixture: %w", err)
+	}
+	return &fixture, nil
+}
+
+func (f *Fixture) Write(w io.Writer) error {
+	if err := json.NewEncoder(w).Encode(f); err != nil {
+		return fmt.Errorf("could not encode fixture: %w", err)
+	}
+	return nil
+}
+
+// EncodingErrors returns the errors of the recorded calls whose params or result could not be encoded.
+func (f *Fixture) EncodingErrors() error {
+	var errs []error
+	for _, call := range f.Calls {
+		if call.EncodingError != "" {
+			errs = append(errs, fmt.Errorf("%s: %s", call.Method, call.EncodingError))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Recorder wraps an api.FullNode and records every call made through it.
+type Recorder struct {
+	api.FullNodeStruct
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewRecorder returns an api.FullNode that forwards every call to node and records it.
+// Methods returning channels are forwarded but not recorded, as their output can't be replayed.
+func NewRecorder(node api.FullNode) *Recorder {
+	r := &Recorder{}
+	proxy(&r.FullNodeStruct, func(method string, fnType reflect.Type) reflect.Value {
+		fn := reflect.ValueOf(node).MethodByName(method)
+		if !isReplayable(fnType) {
+			return fn
+		}
+		return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+			results := fn.Call(args)
+			r.record(method, args, results)
+			return results
+		})
+	})
+	return r
+}
+
+// Fixture returns a copy of the calls recorded so far.
+func (r *Recorder) Fixture() *Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	calls := make([]Call, len(r.calls))
+	copy(calls, r.calls)
+	return &Fixture{Calls: calls}
+}
+
+func (r *Recorder) record(method string, args, results []reflect.Value) {
+	call := Call{Method: method}
+	params, err := encodeParams(args)
+	if err != nil {
+		call.EncodingError = err.Error()
+	}
+	call.Params = params
+
+	if errValue := results[len(results)-1]; !errValue.IsNil() {
+		call.Error = errValue.Interface().(error).Error()
+	} else if len(results) == 2 && call.EncodingError == "" {
+		result, err := json.Marshal(results[0].Interface())
+		if err != nil {
+			call.EncodingError = fmt.Sprintf("could not encode result: %s", err)
+		}
+		call.Result = result
+	}
+
+	r.mu.Lock()
+	r.calls = append(r.calls, call)
+	r.mu.Unlock()
+}
+
+// Replayer is an api.FullNode serving the responses of a Fixture.
+// Calls with the same method and params are served in the order they were recorded,
+// repeating the last response once the recorded ones are exhausted.
+type Replayer struct {
+	api.FullNodeStruct
+
+	mu      sync.Mutex
+	pending map[string][]Call
+}
+
+func NewReplayer(fixture *Fixture) *Replayer {
+	r := &Replayer{pending: make(map[string][]Call)}
+	for _, call := range fixture.Calls {
+		key := callKey(call.Method, call.Params)
+		r.pending[key] = append(r.pending[key], call)
+	}
+
+	proxy(&r.FullNodeStruct, func(method string, fnType reflect.Type) reflect.Value {
+		return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+			return r.replay(method, fnType, args)
+		})
+	})
+	return r
+}
+
+func (r *Replayer) replay(method string, fnType reflect.Type, args []reflect.Value) []reflect.Value {
+	if !isReplayable(fnType) {
+		return failure(fnType, fmt.Errorf("%w: %s", ErrCallNotSupported, method))
+	}
+
+	params, err := encodeParams(args)
+	if err != nil {
+		return failure(fnType, err)
+	}
+
+	call, ok := r.next(callKey(method, params))
+	if !ok {
+		return failure(fnType, fmt.Errorf("%w: %s(%s)", ErrCallNotRecorded, method, params))
+	}
+	if call.EncodingError != "" {
+		return failure(fnType, fmt.Errorf("%w: %s could not be encoded: %s", ErrCallNotRecorded, method, call.EncodingError))
+	}
+	if call.Error != "" {
+		return failure(fnType, errors.New(call.Error))
+	}
+
+	results := zeroResults(fnType)
+	if fnType.NumOut() == 2 && len(call.Result) > 0 {
+		result := reflect.New(fnType.Out(0))
+		if err := json.Unmarshal(call.Result, result.Interface()); err != nil {
+			return failure(fnType, fmt.Errorf("could not decode recorded result of %s: %w", method, err))
+		}
+		results[0] = result.Elem()
+	}
+	return results
+}
+
+func (r *Replayer) next(key string) (Call, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	calls := r.pending[key]
+	if len(calls) == 0 {
+		return Call{}, false
+	}
+	if len(calls) > 1 {
+		r.pending[key] = calls[1:]
+	}
+	return calls[0], true
+}
+
+// proxy sets every method of out using build, following the lotus api proxies.
+func proxy(out *api.FullNodeStruct, build func(method string, fnType reflect.Type) reflect.Value) {
+	for _, internal := range api.GetInternalStructs(out) {
+		rint := reflect.ValueOf(internal).Elem()
+		for f := 0; f < rint.NumField(); f++ {
+			field := rint.Type().Field(f)
+			rint.Field(f).Set(build(field.Name, field.Type))
+		}
+	}
+}
+
+// isReplayable returns true for methods taking a context first and returning either an error or a single value and an error.
+func isReplayable(fnType reflect.Type) bool {
+	if fnType.NumIn() == 0 || fnType.In(0) != contextType {
+		return false
+	}
+	if fnType.NumOut() == 0 || fnType.NumOut() > 2 || fnType.Out(fnType.NumOut()-1) != errorType {
+		return false
+	}
+	for i := 0; i < fnType.NumOut(); i++ {
+		if fnType.Out(i).Kind() == reflect.Chan || fnType.Out(i).Kind() == reflect.Func {
+			return false
+		}
+	}
+	return true
+}
+
+func encodeParams(args []reflect.Value) (json.RawMessage, error) {
+	params := make([]interface{}, 0, len(args))
+	// the first argument is always the context
+	for _, arg := range args[1:] {
+		params = append(params, arg.Interface())
+	}
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode params: %w", err)
+	}
+	return encoded, nil
+}
+
+func callKey(method string, params json.RawMessage) string {
+	return fmt.Sprintf("%s-%s", method, params)
+}
+
+func zeroResults(fnType reflect.Type) []reflect.Value {
+	results := make([]reflect.Value, fnType.NumOut())
+	for i := range results {
+		results[i] = reflect.Zero(fnType.Out(i))
+	}
+	return results
+}
+
+func failure(fnType reflect.Type, err error) []reflect.Value {
+	results := zeroResults(fnType)
+	if fnType.NumOut() > 0 && fnType.Out(fnType.NumOut()-1) == errorType {
+		results[len(results)-1] = reflect.ValueOf(&err).Elem()
+	}
+	return results
+}
@@ -0,0 +1,96 @@
+package recorder_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/filecoin-project/lotus/node/modules/dtypes"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/tools/mocks"
+	"github.com/zondax/fil-parser/tools/recorder"
+)
+
+func TestRecordReplay(t *testing.T) {
+	ctx := context.Background()
+	actorCode, err := cid.Parse("bafk2bzacea6rabflc7kpwr6y4lzcqsnuahr4zblyq3rhzrrsfceeiw2lufrb4")
+	require.NoError(t, err)
+	actorAddr, err := address.NewIDAddress(1234)
+	require.NoError(t, err)
+	unknownAddr, err := address.NewIDAddress(5678)
+	require.NoError(t, err)
+	actor := &filTypes.Actor{Code: actorCode, Nonce: 7, Balance: abi.NewTokenAmount(100)}
+
+	node := &mocks.FullNode{}
+	node.On("StateNetworkName", mock.Anything).Return(dtypes.NetworkName("calibrationnet"), nil)
+	node.On("StateGetActor", mock.Anything, actorAddr, filTypes.EmptyTSK).Return(actor, nil)
+	node.On("StateLookupID", mock.Anything, unknownAddr, filTypes.EmptyTSK).Return(address.Undef, errors.New("actor not found"))
+
+	rec := recorder.NewRecorder(node)
+	_, err = rec.StateNetworkName(ctx)
+	require.NoError(t, err)
+	_, err = rec.StateGetActor(ctx, actorAddr, filTypes.EmptyTSK)
+	require.NoError(t, err)
+	_, err = rec.StateLookupID(ctx, unknownAddr, filTypes.EmptyTSK)
+	require.Error(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, rec.Fixture().Write(&buf))
+	fixture, err := recorder.LoadFixture(&buf)
+	require.NoError(t, err)
+	require.Len(t, fixture.Calls, 3)
+	require.NoError(t, fixture.EncodingErrors())
+
+	replay := recorder.NewReplayer(fixture)
+
+	network, err := replay.StateNetworkName(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, dtypes.NetworkName("calibrationnet"), network)
+
+	// repeated calls keep serving the last recorded response
+	for i := 0; i < 2; i++ {
+		got, err := replay.StateGetActor(ctx, actorAddr, filTypes.EmptyTSK)
+		require.NoError(t, err)
+		assert.Equal(t, actor, got)
+	}
+
+	_, err = replay.StateLookupID(ctx, unknownAddr, filTypes.EmptyTSK)
+	require.EqualError(t, err, "actor not found")
+
+	_, err = replay.StateGetActor(ctx, unknownAddr, filTypes.EmptyTSK)
+	assert.True(t, errors.Is(err, recorder.ErrCallNotRecorded))
+
+	_, err = replay.ChainNotify(ctx)
+	assert.True(t, errors.Is(err, recorder.ErrCallNotSupported))
+}
+
+func TestRecordEncodingError(t *testing.T) {
+	ctx := context.Background()
+	to, err := address.NewIDAddress(1234)
+	require.NoError(t, err)
+
+	node := &mocks.FullNode{}
+	// channels can't be encoded as json
+	node.On("StateDecodeParams", mock.Anything, to, abi.MethodNum(2), []byte{0x80}, filTypes.EmptyTSK).Return(make(chan int), nil)
+
+	rec := recorder.NewRecorder(node)
+	_, err = rec.StateDecodeParams(ctx, to, 2, []byte{0x80}, filTypes.EmptyTSK)
+	require.NoError(t, err)
+
+	fixture := rec.Fixture()
+	require.Len(t, fixture.Calls, 1)
+	assert.Equal(t, "StateDecodeParams", fixture.Calls[0].Method)
+	assert.NotEmpty(t, fixture.Calls[0].EncodingError)
+	assert.Error(t, fixture.EncodingErrors())
+
+	// the call is kept in the fixture, but replaying it fails instead of serving an empty result
+	_, err = recorder.NewReplayer(fixture).StateDecodeParams(ctx, to, 2, []byte{0x80}, filTypes.EmptyTSK)
+	assert.True(t, errors.Is(err, recorder.ErrCallNotRecorded))
+}
@@ -0,0 +1,211 @@
+package verifreg
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+// allocationSnapshotVersion is bumped whenever the snapshot layout changes.
+const allocationSnapshotVersion = 2
+
+// Allocation is the current state of a verified allocation, and of the claim it becomes once sealed.
+type Allocation struct {
+	AllocationID uint64 `json:"allocation_id"`
+	Client       string `json:"client"`
+	Provider     string `json:"provider"`
+	Size         uint64 `json:"size"`
+	State        string `json:"state"`
+	TermMin      int64  `json:"term_min"`
+	TermMax      int64  `json:"term_max"`
+	// Expiration is the epoch by which the allocation must be claimed.
+	Expiration   int64  `json:"expiration"`
+	SectorNumber uint64 `json:"sector_number"`
+	// ClaimedAt is the height the allocation was claimed at, zero if it wasn't.
+	ClaimedAt uint64 `json:"claimed_at"`
+	// UpdatedAt is the height of the last change to the allocation.
+	UpdatedAt uint64 `json:"updated_at"`
+}
+
+// AllocationTracker keeps the current state of every verified allocation seen in the verifreg events.
+// It expects the events of one tipset per Apply, and is not safe for concurrent use.
+type AllocationTracker struct {
+	height      uint64
+	allocations map[uint64]*Allocation
+	// expirations indexes the allocations not claimed yet by the epoch they can't be claimed after.
+	expirations *common.ExpirationIndex[uint64]
+}
+
+func NewAllocationTracker() *AllocationTracker {
+	return &AllocationTracker{
+		allocations: make(map[uint64]*Allocation),
+		expirations: common.NewExpirationIndex[uint64](),
+	}
+}
+
+// ImportAllocationTracker restores a tracker from a snapshot written by Export.
+func ImportAllocationTracker(r io.Reader) (*AllocationTracker, error) {
+	allocations, height, err := common.ImportSnapshot[[]*Allocation](r, "allocation", allocationSnapshotVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := NewAllocationTracker()
+	tracker.height = height
+	for _, allocation := range allocations {
+		tracker.allocations[allocation.AllocationID] = allocation
+		tracker.indexExpiration(allocation)
+	}
+	return tracker, nil
+}
+
+// Export writes a snapshot of the tracker, listing the allocations by id.
+func (t *AllocationTracker) Export(w io.Writer) error {
+	return common.ExportSnapshot(w, "allocation", allocationSnapshotVersion, t.height, t.Allocations())
+}
+
+// Height returns the height of the last applied tipset.
+func (t *AllocationTracker) Height() uint64 {
+	return t.height
+}
+
+// Allocation returns the current state of an allocation.
+func (t *AllocationTracker) Allocation(allocationID uint64) (*Allocation, bool) {
+	allocation, ok := t.allocations[allocationID]
+	return allocation, ok
+}
+
+// Allocations returns every tracked allocation, sorted by allocation id.
+func (t *AllocationTracker) Allocations() []*Allocation {
+	allocations := make([]*Allocation, 0, len(t.allocations))
+	for _, allocation := range t.allocations {
+		allocations = append(allocations, allocation)
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		return allocations[i].AllocationID < allocations[j].AllocationID
+	})
+	return allocations
+}
+
+// SealedDataCap returns the datacap of client sealed into sectors, that is the size of its claims not removed yet.
+func (t *AllocationTracker) SealedDataCap(client string) uint64 {
+	var sealed uint64
+	for _, allocation := range t.allocations {
+		if allocation.Client == client && allocation.State == AllocationStateClaimed {
+			sealed += allocation.Size
+		}
+	}
+	return sealed
+}
+
+// Apply updates the tracker with the verifreg events of the tipset at height and returns the resulting state transitions.
+// Allocations not claimed before their expiration expire first, then the allocation events are applied in order.
+// Claims stay claimed until they are removed, as the chain keeps them past their maximum term until then.
+// Apply can only fail on the height check, which runs before any change to the tracker.
+func (t *AllocationTracker) Apply(height uint64, events *types.VerifregEvents) ([]*types.VerifregAllocationTransition, error) {
+	if err := common.CheckTrackerHeight(t.height, height); err != nil {
+		return nil, err
+	}
+
+	t.height = height
+	transitions := t.expire(height)
+	if events == nil {
+		return transitions, nil
+	}
+
+	for _, event := range events.Allocations {
+		allocation, ok := t.allocations[event.AllocationID]
+		if !ok {
+			allocation = &Allocation{AllocationID: event.AllocationID}
+			t.allocations[event.AllocationID] = allocation
+		}
+		if isFinalAllocationState(allocation.State) {
+			continue
+		}
+		// removals and extensions don't carry every field, keep the known ones
+		t.unindexExpiration(allocation)
+		mergeAllocationEvent(allocation, event)
+		if event.State == AllocationStateClaimed && allocation.State != AllocationStateClaimed {
+			allocation.ClaimedAt = height
+		}
+		transitions = t.appendTransition(transitions, allocation, event.State, event.TxCid, event.ActionType)
+	}
+	return transitions, nil
+}
+
+// expire moves the allocations not claimed before their expiration to the expired state.
+func (t *AllocationTracker) expire(height uint64) []*types.VerifregAllocationTransition {
+	var transitions []*types.VerifregAllocationTransition
+	for _, allocationID := range t.expirations.PopExpired(height, func(a, b uint64) bool { return a < b }) {
+		transitions = t.appendTransition(transitions, t.allocations[allocationID], AllocationStateExpired, "", "")
+	}
+	return transitions
+}
+
+// appendTransition moves allocation to state, appending the transition to transitions if the state changed.
+// The allocation must not be indexed by expiration when called.
+func (t *AllocationTracker) appendTransition(transitions []*types.VerifregAllocationTransition, allocation *Allocation, state, txCid, actionType string) []*types.VerifregAllocationTransition {
+	allocation.UpdatedAt = t.height
+	from := allocation.State
+	allocation.State = state
+	t.indexExpiration(allocation)
+	if from == state {
+		return transitions
+	}
+
+	return append(transitions, &types.VerifregAllocationTransition{
+		ID:           tools.BuildId(fmt.Sprint(allocation.AllocationID), fmt.Sprint(t.height), state),
+		AllocationID: allocation.AllocationID,
+		Client:       allocation.Client,
+		Provider:     allocation.Provider,
+		Size:         allocation.Size,
+		FromState:    from,
+		ToState:      state,
+		Height:       t.height,
+		TxCid:        txCid,
+		ActionType:   actionType,
+	})
+}
+
+func mergeAllocationEvent(allocation *Allocation, event *types.VerifregAllocationEvent) {
+	if event.Client != "" {
+		allocation.Client = event.Client
+	}
+	if event.Provider != "" {
+		allocation.Provider = event.Provider
+	}
+	if event.Size != 0 {
+		allocation.Size = event.Size
+	}
+	if event.TermMin != 0 {
+		allocation.TermMin = event.TermMin
+	}
+	if event.TermMax != 0 {
+		allocation.TermMax = event.TermMax
+	}
+	if event.Expiration != 0 {
+		allocation.Expiration = event.Expiration
+	}
+	if event.SectorNumber != 0 {
+		allocation.SectorNumber = event.SectorNumber
+	}
+}
+
+func (t *AllocationTracker) indexExpiration(allocation *Allocation) {
+	if allocation.State != AllocationStateAllocated || allocation.Expiration <= 0 {
+		return
+	}
+	t.expirations.Add(allocation.Expiration, allocation.AllocationID)
+}
+
+func (t *AllocationTracker) unindexExpiration(allocation *Allocation) {
+	t.expirations.Remove(allocation.Expiration, allocation.AllocationID)
+}
+
+func isFinalAllocationState(state string) bool {
+	return state == AllocationStateExpired || state == ClaimStateExpired
+}
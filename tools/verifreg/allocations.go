@@ -0,0 +1,257 @@
+package verifreg
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/types"
+)
+
+// States of a verified allocation. Allocations are claimed when sealed into a sector, and both allocations and
+// claims are removed once expired.
+const (
+	AllocationStateAllocated = "allocated"
+	AllocationStateClaimed   = "claimed"
+	AllocationStateExpired   = "allocation_expired"
+	ClaimStateExpired        = "claim_expired"
+)
+
+const (
+	KeySectors       = "Sectors"
+	KeySector        = "Sector"
+	KeySectorExpiry  = "SectorExpiry"
+	KeyClaims        = "Claims"
+	KeyClient        = "Client"
+	KeyProvider      = "Provider"
+	KeyAllocationId  = "AllocationId"
+	KeyAllocationIds = "AllocationIds"
+	KeyClaimId       = "ClaimId"
+	KeyClaimIds      = "ClaimIds"
+	KeySize          = "Size"
+	KeyTerms         = "Terms"
+	KeyTermMax       = "TermMax"
+	KeyBatchInfo     = "BatchInfo"
+	KeyResults       = "Results"
+	KeyConsidered    = "Considered"
+)
+
+func (eg *eventGenerator) isAllocationMessage(txType string) bool {
+	switch txType {
+	case parser.MethodClaimAllocations,
+		parser.MethodExtendClaimTerms, parser.MethodExtendClaimTermsExported,
+		parser.MethodRemoveExpiredAllocations, parser.MethodRemoveExpiredAllocationsExported,
+		parser.MethodRemoveExpiredClaims, parser.MethodRemoveExpiredClaimsExported:
+		return true
+	}
+	return false
+}
+
+// createAllocationEvents returns a row per allocation or claim changed by tx.
+func (eg *eventGenerator) createAllocationEvents(tx *types.Transaction, metadata map[string]interface{}, tipsetCid string) ([]*types.VerifregAllocationEvent, error) {
+	params, err := common.GetItem[map[string]interface{}](metadata, parser.ParamsKey, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing params: %w", err)
+	}
+	ret, err := common.GetItem[map[string]interface{}](metadata, parser.ReturnKey, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing return: %w", err)
+	}
+
+	switch tx.TxType {
+	case parser.MethodClaimAllocations:
+		return eg.parseClaimAllocations(tx, tipsetCid, params, ret)
+	case parser.MethodExtendClaimTerms, parser.MethodExtendClaimTermsExported:
+		return eg.parseExtendClaimTerms(tx, tipsetCid, params, ret)
+	case parser.MethodRemoveExpiredAllocations, parser.MethodRemoveExpiredAllocationsExported:
+		return eg.parseRemoveExpired(tx, tipsetCid, params, ret, KeyClient, AllocationStateExpired)
+	case parser.MethodRemoveExpiredClaims, parser.MethodRemoveExpiredClaimsExported:
+		return eg.parseRemoveExpired(tx, tipsetCid, params, ret, KeyProvider, ClaimStateExpired)
+	}
+	return nil, fmt.Errorf("unexpected method: %s", tx.TxType)
+}
+
+// parseClaimAllocations handles both layouts of the params: claims grouped by sector (NV22 onwards),
+// where the batch return has a result per sector, and the earlier flat list with a result per claim.
+// The claiming miner is the sender of the message.
+func (eg *eventGenerator) parseClaimAllocations(tx *types.Transaction, tipsetCid string, params, ret map[string]interface{}) ([]*types.VerifregAllocationEvent, error) {
+	sectors, err := common.GetSlice[map[string]interface{}](params, KeySectors, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing sectors: %w", err)
+	}
+	batchInfo, err := common.GetItem[map[string]interface{}](ret, KeyBatchInfo, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing batch info: %w", err)
+	}
+	failed, err := common.FailedBatchIndexes(batchInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*types.VerifregAllocationEvent
+	for i, sector := range sectors {
+		if failed[i] {
+			continue
+		}
+		claims := []map[string]interface{}{sector}
+		if _, grouped := sector[KeyClaims]; grouped {
+			claims, err = common.GetSlice[map[string]interface{}](sector, KeyClaims, true)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing claims: %w", err)
+			}
+		}
+		sectorNumber, err := common.GetInteger[uint64](sector, KeySector, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing sector: %w", err)
+		}
+
+		for _, claim := range claims {
+			allocationID, err := common.GetInteger[uint64](claim, KeyAllocationId, false)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing allocation id: %w", err)
+			}
+			clientID, err := common.GetInteger[uint64](claim, KeyClient, false)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing client: %w", err)
+			}
+			size, err := common.GetInteger[uint64](claim, KeySize, false)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing size: %w", err)
+			}
+			event := eg.newAllocationEvent(tx, tipsetCid, allocationID, AllocationStateClaimed)
+			event.ClaimID = allocationID
+			event.Client = eg.consolidateIDAddress(clientID)
+			event.Provider = tx.TxFrom
+			event.Size = size
+			event.SectorNumber = sectorNumber
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (eg *eventGenerator) parseExtendClaimTerms(tx *types.Transaction, tipsetCid string, params, ret map[string]interface{}) ([]*types.VerifregAllocationEvent, error) {
+	terms, err := common.GetSlice[map[string]interface{}](params, KeyTerms, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing terms: %w", err)
+	}
+	failed, err := common.FailedBatchIndexes(ret)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*types.VerifregAllocationEvent
+	for i, term := range terms {
+		if failed[i] {
+			continue
+		}
+		claimID, err := common.GetInteger[uint64](term, KeyClaimId, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing claim id: %w", err)
+		}
+		providerID, err := common.GetInteger[uint64](term, KeyProvider, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing provider: %w", err)
+		}
+		termMax, err := common.GetInteger[int64](term, KeyTermMax, false)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing term max: %w", err)
+		}
+		// the claim stays claimed, only its maximum term changes
+		event := eg.newAllocationEvent(tx, tipsetCid, claimID, AllocationStateClaimed)
+		event.ClaimID = claimID
+		event.Provider = eg.consolidateIDAddress(providerID)
+		event.TermMax = termMax
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// parseRemoveExpired handles the removal of expired allocations and claims. The return lists the ids considered
+// for removal, with a batch result per considered id.
+func (eg *eventGenerator) parseRemoveExpired(tx *types.Transaction, tipsetCid string, params, ret map[string]interface{}, ownerKey, state string) ([]*types.VerifregAllocationEvent, error) {
+	ownerID, err := common.GetInteger[uint64](params, ownerKey, false)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", ownerKey, err)
+	}
+	owner := eg.consolidateIDAddress(ownerID)
+
+	considered, err := common.GetIntegerSlice[uint64](ret, KeyConsidered, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing considered ids: %w", err)
+	}
+	results, err := common.GetItem[map[string]interface{}](ret, KeyResults, true)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing results: %w", err)
+	}
+	failed, err := common.FailedBatchIndexes(results)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*types.VerifregAllocationEvent
+	for i, id := range considered {
+		if failed[i] {
+			continue
+		}
+		event := eg.newAllocationEvent(tx, tipsetCid, id, state)
+		if state == ClaimStateExpired {
+			event.ClaimID = id
+			event.Provider = owner
+		} else {
+			event.Client = owner
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// allocationEventsFromDeals returns the allocated rows of the allocations created by a datacap transfer.
+func allocationEventsFromDeals(tx *types.Transaction, tipsetCid string, deals []*types.VerifregDeal) ([]*types.VerifregAllocationEvent, error) {
+	events := make([]*types.VerifregAllocationEvent, 0, len(deals))
+	for _, deal := range deals {
+		allocationID, err := strconv.ParseUint(deal.DealID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing allocation id %s: %w", deal.DealID, err)
+		}
+		events = append(events, &types.VerifregAllocationEvent{
+			ID:           tools.BuildId(tipsetCid, tx.TxCid, fmt.Sprint(tx.Height), tx.TxType, deal.DealID),
+			AllocationID: allocationID,
+			Client:       deal.ActorAddress,
+			Provider:     deal.ProviderAddress,
+			// #nosec G115
+			Size:        uint64(deal.Size),
+			TermMin:     deal.TermMin,
+			TermMax:     deal.TermMax,
+			Expiration:  deal.Expiration,
+			State:       AllocationStateAllocated,
+			TxCid:       tx.TxCid,
+			Height:      tx.Height,
+			ActionType:  tx.TxType,
+			TxTimestamp: tx.TxTimestamp,
+		})
+	}
+	return events, nil
+}
+
+func (eg *eventGenerator) newAllocationEvent(tx *types.Transaction, tipsetCid string, allocationID uint64, state string) *types.VerifregAllocationEvent {
+	return &types.VerifregAllocationEvent{
+		ID:           tools.BuildId(tipsetCid, tx.TxCid, fmt.Sprint(tx.Height), tx.TxType, fmt.Sprint(allocationID)),
+		AllocationID: allocationID,
+		State:        state,
+		TxCid:        tx.TxCid,
+		Height:       tx.Height,
+		ActionType:   tx.TxType,
+		TxTimestamp:  tx.TxTimestamp,
+	}
+}
+
+func (eg *eventGenerator) consolidateIDAddress(id uint64) string {
+	addr, err := common.ConsolidateIDAddress(id, eg.helper, eg.logger, eg.config, true)
+	if err != nil {
+		eg.logger.Errorf("error consolidating id address: %s", err)
+	}
+	return addr
+}
@@ -0,0 +1,181 @@
+package verifreg_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/exitcode"
+	filTypes "github.com/filecoin-project/lotus/chain/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zondax/fil-parser/parser"
+	"github.com/zondax/fil-parser/tools"
+	"github.com/zondax/fil-parser/tools/common"
+	"github.com/zondax/fil-parser/tools/verifreg"
+	"github.com/zondax/fil-parser/types"
+)
+
+func TestAllocationEvents(t *testing.T) {
+	eg := setupTest(t, "mainnet")
+
+	tests := []struct {
+		name     string
+		txType   string
+		txFrom   string
+		metadata string
+		want     []*types.VerifregAllocationEvent
+	}{
+		{
+			name:     "ClaimAllocations grouped by sector",
+			txType:   parser.MethodClaimAllocations,
+			txFrom:   "f01000",
+			metadata: `{"Params":{"Sectors":[{"Sector":10,"SectorExpiry":5000,"Claims":[{"Client":1234,"AllocationId":1,"Size":2048},{"Client":1234,"AllocationId":2,"Size":4096}]},{"Sector":11,"SectorExpiry":5000,"Claims":[{"Client":1234,"AllocationId":3,"Size":2048}]}],"AllOrNothing":false},"Return":{"BatchInfo":{"SuccessCount":1,"FailCodes":[{"Idx":1,"Code":16}]},"ClaimedSpace":"6144"}}`,
+			want: []*types.VerifregAllocationEvent{
+				{AllocationID: 1, ClaimID: 1, Client: "f01234", Provider: "f01000", Size: 2048, SectorNumber: 10, State: verifreg.AllocationStateClaimed},
+				{AllocationID: 2, ClaimID: 2, Client: "f01234", Provider: "f01000", Size: 4096, SectorNumber: 10, State: verifreg.AllocationStateClaimed},
+			},
+		},
+		{
+			name:     "ClaimAllocations flat",
+			txType:   parser.MethodClaimAllocations,
+			txFrom:   "f01000",
+			metadata: `{"Params":{"Sectors":[{"Client":1234,"AllocationId":4,"Size":2048,"Sector":12,"SectorExpiry":5000}],"AllOrNothing":false},"Return":{"BatchInfo":{"SuccessCount":1,"FailCodes":null},"ClaimedSpace":"2048"}}`,
+			want: []*types.VerifregAllocationEvent{
+				{AllocationID: 4, ClaimID: 4, Client: "f01234", Provider: "f01000", Size: 2048, SectorNumber: 12, State: verifreg.AllocationStateClaimed},
+			},
+		},
+		{
+			name:     "ExtendClaimTerms",
+			txType:   parser.MethodExtendClaimTermsExported,
+			txFrom:   "f01234",
+			metadata: `{"Params":{"Terms":[{"Provider":1000,"ClaimId":1,"TermMax":3000000},{"Provider":1000,"ClaimId":9,"TermMax":3000000}]},"Return":{"SuccessCount":1,"FailCodes":[{"Idx":1,"Code":16}]}}`,
+			want: []*types.VerifregAllocationEvent{
+				{AllocationID: 1, ClaimID: 1, Provider: "f01000", TermMax: 3000000, State: verifreg.AllocationStateClaimed},
+			},
+		},
+		{
+			name:     "RemoveExpiredAllocations",
+			txType:   parser.MethodRemoveExpiredAllocationsExported,
+			txFrom:   "f01234",
+			metadata: `{"Params":{"Client":1234,"AllocationIds":[]},"Return":{"Considered":[5,6],"Results":{"SuccessCount":2,"FailCodes":null},"DataCapRecovered":"4096"}}`,
+			want: []*types.VerifregAllocationEvent{
+				{AllocationID: 5, Client: "f01234", State: verifreg.AllocationStateExpired},
+				{AllocationID: 6, Client: "f01234", State: verifreg.AllocationStateExpired},
+			},
+		},
+		{
+			name:     "RemoveExpiredClaims",
+			txType:   parser.MethodRemoveExpiredClaims,
+			txFrom:   "f01000",
+			metadata: `{"Params":{"Provider":1000,"ClaimIds":[1,2]},"Return":{"Considered":[1,2],"Results":{"SuccessCount":1,"FailCodes":[{"Idx":0,"Code":16}]}}}`,
+			want: []*types.VerifregAllocationEvent{
+				{AllocationID: 2, ClaimID: 2, Provider: "f01000", State: verifreg.ClaimStateExpired},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tx := &types.Transaction{
+				TxCid:         txCid,
+				TxType:        test.txType,
+				TxFrom:        test.txFrom,
+				TxTo:          txTo,
+				TxMetadata:    test.metadata,
+				Status:        tools.GetExitCodeStatus(exitcode.Ok),
+				SubcallStatus: tools.GetExitCodeStatus(exitcode.Ok),
+			}
+			tx.Height = 4878840
+			events, err := eg.GenerateVerifregEvents(context.Background(), []*types.Transaction{tx}, tipsetCid, filTypes.EmptyTSK)
+			require.NoError(t, err)
+			require.Len(t, events.Allocations, len(test.want))
+			for i, want := range test.want {
+				got := events.Allocations[i]
+				assert.NotEmpty(t, got.ID)
+				assert.Equal(t, test.txType, got.ActionType)
+				assert.Equal(t, txCid, got.TxCid)
+				assert.Equal(t, tx.Height, got.Height)
+				want.ID, want.ActionType, want.TxCid, want.Height = got.ID, got.ActionType, got.TxCid, got.Height
+				assert.Equal(t, want, got)
+			}
+		})
+	}
+}
+
+func allocationStates(transitions []*types.VerifregAllocationTransition) []string {
+	var result []string
+	for _, transition := range transitions {
+		result = append(result, transition.FromState+">"+transition.ToState)
+	}
+	return result
+}
+
+func TestAllocationTracker(t *testing.T) {
+	tracker := verifreg.NewAllocationTracker()
+	allocated := func(allocationID uint64, expiration int64) *types.VerifregAllocationEvent {
+		return &types.VerifregAllocationEvent{AllocationID: allocationID, Client: "f01234", Provider: "f01000", Size: 2048,
+			Expiration: expiration, State: verifreg.AllocationStateAllocated, ActionType: parser.MethodUniversalReceiverHook}
+	}
+
+	transitions, err := tracker.Apply(100, &types.VerifregEvents{Allocations: []*types.VerifregAllocationEvent{
+		allocated(1, 500), allocated(2, 500), allocated(3, 200),
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{">allocated", ">allocated", ">allocated"}, allocationStates(transitions))
+
+	transitions, err = tracker.Apply(150, &types.VerifregEvents{Allocations: []*types.VerifregAllocationEvent{
+		{AllocationID: 1, ClaimID: 1, Client: "f01234", Provider: "f01000", Size: 2048, SectorNumber: 10, State: verifreg.AllocationStateClaimed},
+		{AllocationID: 2, ClaimID: 2, Client: "f01234", Provider: "f01000", Size: 2048, SectorNumber: 10, State: verifreg.AllocationStateClaimed},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"allocated>claimed", "allocated>claimed"}, allocationStates(transitions))
+	assert.Equal(t, uint64(4096), tracker.SealedDataCap("f01234"))
+
+	t.Run("allocations not claimed before their expiration expire", func(t *testing.T) {
+		transitions, err := tracker.Apply(201, nil)
+		require.NoError(t, err)
+		require.Len(t, transitions, 1)
+		assert.Equal(t, uint64(3), transitions[0].AllocationID)
+		assert.Equal(t, "allocated>allocation_expired", allocationStates(transitions)[0])
+	})
+
+	t.Run("extensions keep the claim", func(t *testing.T) {
+		transitions, err := tracker.Apply(300, &types.VerifregEvents{Allocations: []*types.VerifregAllocationEvent{
+			{AllocationID: 1, ClaimID: 1, Provider: "f01000", TermMax: 3000000, State: verifreg.AllocationStateClaimed},
+		}})
+		require.NoError(t, err)
+		assert.Empty(t, transitions)
+		allocation, ok := tracker.Allocation(1)
+		require.True(t, ok)
+		assert.Equal(t, int64(3000000), allocation.TermMax)
+		assert.Equal(t, "f01234", allocation.Client)
+		assert.Equal(t, uint64(150), allocation.ClaimedAt)
+	})
+
+	t.Run("removed claims are no longer sealed", func(t *testing.T) {
+		transitions, err := tracker.Apply(400, &types.VerifregEvents{Allocations: []*types.VerifregAllocationEvent{
+			{AllocationID: 2, ClaimID: 2, Provider: "f01000", State: verifreg.ClaimStateExpired},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"claimed>claim_expired"}, allocationStates(transitions))
+		assert.Equal(t, "f01234", transitions[0].Client)
+		assert.Equal(t, uint64(2048), tracker.SealedDataCap("f01234"))
+	})
+
+	t.Run("events must be applied in height order", func(t *testing.T) {
+		_, err := tracker.Apply(400, nil)
+		assert.ErrorIs(t, err, common.ErrHeightOutOfOrder)
+		assert.Equal(t, uint64(400), tracker.Height())
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		var snapshot bytes.Buffer
+		require.NoError(t, tracker.Export(&snapshot))
+		restored, err := verifreg.ImportAllocationTracker(bytes.NewReader(snapshot.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, tracker.Height(), restored.Height())
+		assert.Equal(t, tracker.Allocations(), restored.Allocations())
+		assert.Equal(t, tracker.SealedDataCap("f01234"), restored.SealedDataCap("f01234"))
+	})
+}
@@ -53,6 +53,7 @@ func (eg *eventGenerator) GenerateVerifregEvents(ctx context.Context, transactio
 		VerifierInfo: make([]*types.VerifregEvent, 0),
 		ClientInfo:   make([]*types.VerifregClientInfo, 0),
 		Deals:        make([]*types.VerifregDeal, 0),
+		Allocations:  make([]*types.VerifregAllocationEvent, 0),
 	}
 
 	for _, tx := range transactions {
@@ -67,7 +68,7 @@ func (eg *eventGenerator) GenerateVerifregEvents(ctx context.Context, transactio
 		}
 
 		// #nosec G115
-		actorName, err := common.GetActorNameFromAddress(eg.helper, addr, int64(tx.Height), tipsetKey, true)
+		actorName, err := common.ResolveActorName(ctx, eg.helper, addr, int64(tx.Height), tipsetKey, true)
 		if err != nil {
 			_ = eg.metrics.UpdateActorNameFromAddressMetric()
 			return nil, err
@@ -134,6 +135,19 @@ func (eg *eventGenerator) createVerifregInfo(tx *types.Transaction, tipsetCid st
 		}
 		events.VerifierInfo = append(events.VerifierInfo, clientInfo)
 		events.Deals = append(events.Deals, dealInfo...)
+		allocations, err := allocationEventsFromDeals(tx, tipsetCid, dealInfo)
+		if err != nil {
+			return nil, err
+		}
+		events.Allocations = append(events.Allocations, allocations...)
+	default:
+		if eg.isAllocationMessage(tx.TxType) {
+			allocations, err := eg.createAllocationEvents(tx, metadata, tipsetCid)
+			if err != nil {
+				return nil, err
+			}
+			events.Allocations = append(events.Allocations, allocations...)
+		}
 	}
 
 	return events, nil
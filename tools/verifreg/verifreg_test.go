@@ -152,7 +152,7 @@ func TestParseUniversalReceiverHook(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			_, err := eg.GenerateVerifregEvents(context.Background(), []*types.Transaction{
+			events, err := eg.GenerateVerifregEvents(context.Background(), []*types.Transaction{
 				{
 					TxBasicBlockData: types.TxBasicBlockData{
 						BasicBlockData: types.BasicBlockData{
@@ -169,7 +169,11 @@ func TestParseUniversalReceiverHook(t *testing.T) {
 				},
 			}, tipsetCid, filTypes.EmptyTSK)
 			require.NoError(t, err)
-
+			require.NotEmpty(t, events.Deals)
+			require.Len(t, events.Allocations, len(events.Deals))
+			for _, allocation := range events.Allocations {
+				require.Equal(t, verifreg.AllocationStateAllocated, allocation.State)
+			}
 		})
 	}
 }
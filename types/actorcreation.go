@@ -0,0 +1,27 @@
+package types
+
+import "time"
+
+type ActorCreationEvents struct {
+	Creations []*ActorCreation
+}
+
+// ActorCreation is an actor created through the init actor Exec methods or the EAM create methods.
+type ActorCreation struct {
+	ID string `json:"id"`
+	// Creator is the address that requested the creation. For actors created through the EAM it is the caller of the EAM.
+	Creator       string `json:"creator"`
+	ActorCid      string `json:"actor_cid"`
+	ActorType     string `json:"actor_type"`
+	IDAddress     string `json:"id_address"`
+	RobustAddress string `json:"robust_address"`
+	// F410Address and EthAddress are only set for actors created with a delegated address.
+	F410Address string `json:"f410_address"`
+	EthAddress  string `json:"eth_address"`
+	// ConstructorParams are the constructor params decoded by the created actor parser, empty when they could not be decoded.
+	ConstructorParams    string    `json:"constructor_params"`
+	RawConstructorParams string    `json:"raw_constructor_params"`
+	Height               uint64    `json:"height"`
+	TxCid                string    `json:"tx_cid"`
+	TxTimestamp          time.Time `json:"tx_timestamp"`
+}
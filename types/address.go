@@ -18,6 +18,9 @@ type AddressInfo struct {
 
 	IsSystemActor bool `json:"-" gorm:"-"`
 	IsCanonical   bool `json:"-" gorm:"-"`
+	// Degraded is true when the node was not available to look up the address, e.g. when parsing offline,
+	// so the actor and the other address format may be missing.
+	Degraded bool `json:"-" gorm:"-"`
 }
 
 type AddressInfoMap struct {
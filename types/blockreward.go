@@ -0,0 +1,44 @@
+package types
+
+import (
+	"math/big"
+	"time"
+)
+
+type BlockRewardEvents struct {
+	Rewards      []*BlockReward
+	EpochRewards []*EpochReward
+}
+
+// BlockReward is the reward paid by the reward actor to a miner that won blocks in the tipset.
+type BlockReward struct {
+	ID           string `json:"id"`
+	MinerAddress string `json:"miner_address"`
+	// BlockCids are the blocks of the tipset mined by the miner.
+	BlockCids []string `json:"block_cids" gorm:"type:Array(String)"`
+	WinCount  int64    `json:"win_count"`
+	Penalty   *big.Int `json:"penalty" gorm:"column:penalty;type:UInt256"`
+	GasReward *big.Int `json:"gas_reward" gorm:"column:gas_reward;type:UInt256"`
+	// Reward is the amount received by the miner. It is zero when the reward could not be sent and was burnt instead.
+	Reward          *big.Int  `json:"reward" gorm:"column:reward;type:UInt256"`
+	ImmediateReward *big.Int  `json:"immediate_reward" gorm:"column:immediate_reward;type:UInt256"`
+	VestingReward   *big.Int  `json:"vesting_reward" gorm:"column:vesting_reward;type:UInt256"`
+	Height          uint64    `json:"height"`
+	TxCid           string    `json:"tx_cid"`
+	TxTimestamp     time.Time `json:"tx_timestamp"`
+}
+
+// EpochReward is the state of the reward actor at an epoch.
+type EpochReward struct {
+	ID string `json:"id"`
+	// RewardEstimate is the smoothed estimate of the reward minted at the epoch.
+	// It and BaselinePower are nil when parsing offline an epoch where no actor calls ThisEpochReward.
+	RewardEstimate *big.Int `json:"reward_estimate" gorm:"column:reward_estimate;type:UInt256"`
+	BaselinePower  *big.Int `json:"baseline_power" gorm:"column:baseline_power;type:UInt256"`
+	// RealizedPower is the network raw byte power reported by the power actor at the end of the epoch.
+	// It is nil when the tipset has no network KPI update.
+	RealizedPower *big.Int  `json:"realized_power" gorm:"column:realized_power;type:UInt256"`
+	Height        uint64    `json:"height"`
+	TxCid         string    `json:"tx_cid"`
+	TxTimestamp   time.Time `json:"tx_timestamp"`
+}
@@ -22,15 +22,17 @@ type DataCapInfo struct {
 }
 
 type DataCapTokenEvent struct {
-	ID           string    `json:"id"`
-	ActorAddress string    `json:"actor_address"`
-	Height       uint64    `json:"height"`
-	TxCid        string    `json:"tx_cid"`
-	ActionType   string    `json:"action_type"`
-	Balance      *big.Int  `json:"balance" gorm:"column:balance;type:Int256"`
-	Supply       *big.Int  `json:"supply" gorm:"column:supply;type:Int256"`
-	Data         string    `json:"data"`
-	TxTimestamp  time.Time `json:"tx_timestamp"`
+	ID           string `json:"id"`
+	ActorAddress string `json:"actor_address"`
+	Height       uint64 `json:"height"`
+	TxCid        string `json:"tx_cid"`
+	ActionType   string `json:"action_type"`
+	// Amount is the signed change of the balance of ActorAddress caused by the message.
+	Amount      *big.Int  `json:"amount" gorm:"column:amount;type:Int256"`
+	Balance     *big.Int  `json:"balance" gorm:"column:balance;type:Int256"`
+	Supply      *big.Int  `json:"supply" gorm:"column:supply;type:Int256"`
+	Data        string    `json:"data"`
+	TxTimestamp time.Time `json:"tx_timestamp"`
 }
 
 type DataCapAllowanceEvent struct {
@@ -44,3 +46,35 @@ type DataCapAllowanceEvent struct {
 	Data             string    `json:"data"`
 	TxTimestamp      time.Time `json:"tx_timestamp"`
 }
+
+// DataCapBalanceDelta is the net change of the datacap balance of an address in a tipset, as tracked by the datacap ledger.
+type DataCapBalanceDelta struct {
+	ID      string `json:"id"`
+	Height  uint64 `json:"height"`
+	Address string `json:"address"`
+	// Delta is the net balance change, in datacap token units
+	Delta *big.Int `json:"delta" gorm:"column:delta;type:Int256"`
+	// Balance is the balance after the tipset
+	Balance *big.Int `json:"balance" gorm:"column:balance;type:Int256"`
+}
+
+// DataCapAllowanceDelta is the net change of the allowance an owner granted to an operator in a tipset.
+type DataCapAllowanceDelta struct {
+	ID              string   `json:"id"`
+	Height          uint64   `json:"height"`
+	OwnerAddress    string   `json:"owner_address"`
+	OperatorAddress string   `json:"operator_address"`
+	Delta           *big.Int `json:"delta" gorm:"column:delta;type:Int256"`
+	Allowance       *big.Int `json:"allowance" gorm:"column:allowance;type:Int256"`
+}
+
+// DataCapBalanceMismatch reports a balance returned on chain that differs from the one computed by the datacap ledger.
+type DataCapBalanceMismatch struct {
+	ID         string   `json:"id"`
+	Height     uint64   `json:"height"`
+	TxCid      string   `json:"tx_cid"`
+	ActionType string   `json:"action_type"`
+	Address    string   `json:"address"`
+	Computed   *big.Int `json:"computed" gorm:"column:computed;type:Int256"`
+	OnChain    *big.Int `json:"on_chain" gorm:"column:on_chain;type:Int256"`
+}
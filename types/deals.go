@@ -6,10 +6,12 @@ import (
 )
 
 type DealsEvents struct {
-	DealsMessages    []*DealsMessages
-	DealsProposals   []*DealsProposals
-	DealsActivations []*DealsActivations
-	DealsSpaceInfo   []*DealsSpaceInfo
+	DealsMessages     []*DealsMessages
+	DealsProposals    []*DealsProposals
+	DealsActivations  []*DealsActivations
+	DealsSpaceInfo    []*DealsSpaceInfo
+	DealsTerminations []*DealsTerminations
+	DealsSettlements  []*DealsSettlements
 }
 
 type DealsMessages struct {
@@ -63,6 +65,8 @@ type DealsActivations struct {
 	SectorExpiry int64     `json:"sector_expiry"`
 	ActionType   string    `json:"action_type"`
 	TxTimestamp  time.Time `json:"tx_timestamp"`
+	// SectorNumber is the sector the deal was activated in, nil before NV22 when the params don't carry it
+	SectorNumber *uint64 `json:"sector_number"`
 }
 
 type DealsSpaceInfo struct {
@@ -83,3 +87,48 @@ type DealsSpaceInfo struct {
 	ActionType    string    `json:"action_type"`
 	TxTimestamp   time.Time `json:"tx_timestamp"`
 }
+
+// DealsTerminations is a deal terminated early because its sector was terminated.
+// From NV22 on the market is only told the terminated sectors, so the rows hold a SectorNumber instead of a DealID.
+type DealsTerminations struct {
+	ID     string `json:"id"`
+	Height uint64 `json:"height"`
+	// ActorAddress is the miner terminating the sector
+	ActorAddress string `json:"actor_address"`
+	TxCid        string `json:"tx_cid"`
+	// DealID is zero when SectorNumber is set
+	DealID uint64 `json:"deal_id"`
+	// TerminationEpoch is the epoch the sector was terminated at
+	TerminationEpoch int64     `json:"termination_epoch"`
+	ActionType       string    `json:"action_type"`
+	TxTimestamp      time.Time `json:"tx_timestamp"`
+	// SectorNumber is the terminated sector, nil before NV22 when the params list the deals instead
+	SectorNumber *uint64 `json:"sector_number"`
+}
+
+// DealsSettlements is a payment from the client to the provider of a deal, settled through SettleDealPaymentsExported.
+type DealsSettlements struct {
+	ID           string   `json:"id"`
+	Height       uint64   `json:"height"`
+	ActorAddress string   `json:"actor_address"`
+	TxCid        string   `json:"tx_cid"`
+	DealID       uint64   `json:"deal_id"`
+	Payment      *big.Int `json:"payment" gorm:"column:payment;type:UInt256"`
+	// Completed is true if the deal has settled for the final time
+	Completed   bool      `json:"completed"`
+	ActionType  string    `json:"action_type"`
+	TxTimestamp time.Time `json:"tx_timestamp"`
+}
+
+// DealStateTransition is a change in the lifecycle state of a deal, as tracked across tipsets.
+type DealStateTransition struct {
+	ID     string `json:"id"`
+	DealID uint64 `json:"deal_id"`
+	// FromState is empty the first time a deal is seen.
+	FromState string `json:"from_state"`
+	ToState   string `json:"to_state"`
+	Height    uint64 `json:"height"`
+	// TxCid and ActionType are the message causing the transition, both empty for expirations.
+	TxCid      string `json:"tx_cid"`
+	ActionType string `json:"action_type"`
+}
@@ -0,0 +1,22 @@
+package types
+
+import (
+	"math/big"
+)
+
+// BalanceChange is the net balance change of an address within a tipset.
+// Delta is always Credit minus Debit, so rows can be reconciled against the actor balances in chain state.
+type BalanceChange struct {
+	ID        string `json:"id"`
+	Height    uint64 `json:"height"`
+	TipsetCid string `json:"tipset_cid"`
+	Address   string `json:"address"`
+	// Delta is the net balance change in attoFil
+	Delta *big.Int `json:"delta" gorm:"column:delta;type:Int256"`
+	// Credit is the total amount received in attoFil, including miner tips, burnt fees and genesis balances
+	Credit *big.Int `json:"credit" gorm:"column:credit;type:Int256"`
+	// Debit is the total amount sent in attoFil, including the fees paid
+	Debit *big.Int `json:"debit" gorm:"column:debit;type:Int256"`
+	// FeesPaid is the part of Debit spent in gas fees
+	FeesPaid *big.Int `json:"fees_paid" gorm:"column:fees_paid;type:Int256"`
+}
@@ -1,10 +1,18 @@
 package types
 
-import "time"
+import (
+	"math/big"
+	"time"
+)
 
 type MinerEvents struct {
 	MinerInfo    []*MinerInfo
 	MinerSectors []*MinerSectorEvent
+	MinerProving []*MinerProvingEvent
+	// ConsensusFaults holds a slashing record per ReportConsensusFault message.
+	ConsensusFaults []*MinerConsensusFault
+	// DDOPieces holds the pieces onboarded through direct data onboarding, without a market deal.
+	DDOPieces []*MinerDDOPiece
 }
 type MinerInfo struct {
 	ID           string    `json:"id"`
@@ -26,3 +34,83 @@ type MinerSectorEvent struct {
 	Data         string    `json:"data"`
 	TxTimestamp  time.Time `json:"tx_timestamp"`
 }
+
+// MinerProvingEvent is a Window PoSt submitted by a miner, or a dispute of one of its Window PoSts.
+type MinerProvingEvent struct {
+	ID           string `json:"id"`
+	MinerAddress string `json:"miner_address"`
+	Height       uint64 `json:"height"`
+	TxCid        string `json:"tx_cid"`
+	ActionType   string `json:"action_type"`
+	Deadline     uint64 `json:"deadline"`
+	// Partitions holds the indexes of the proven partitions, empty for disputes.
+	Partitions []uint64 `json:"partitions" gorm:"type:Array(UInt64)"`
+	// SkippedSectors holds the sectors skipped while proving that weren't already declared faulty.
+	SkippedSectors   []uint64 `json:"skipped_sectors" gorm:"type:Array(UInt64)"`
+	ProofType        int64    `json:"proof_type"`
+	ChainCommitEpoch int64    `json:"chain_commit_epoch"`
+	// Disputer, PoStIndex and DisputeOutcome are only set for disputes.
+	Disputer       string    `json:"disputer"`
+	PoStIndex      uint64    `json:"post_index"`
+	DisputeOutcome string    `json:"dispute_outcome"`
+	TxTimestamp    time.Time `json:"tx_timestamp"`
+}
+
+// MinerDDOPiece is a piece activated in a sector from a PieceActivationManifest (direct data onboarding).
+type MinerDDOPiece struct {
+	ID           string `json:"id"`
+	MinerAddress string `json:"miner_address"`
+	SectorNumber uint64 `json:"sector_number"`
+	Height       uint64 `json:"height"`
+	TxCid        string `json:"tx_cid"`
+	ActionType   string `json:"action_type"`
+	PieceCid     string `json:"piece_cid"`
+	PieceSize    uint64 `json:"piece_size"`
+	// Verified is true if the piece fulfils a verified allocation, identified by AllocationClient and AllocationID.
+	Verified         bool   `json:"verified"`
+	AllocationClient string `json:"allocation_client"`
+	AllocationID     uint64 `json:"allocation_id"`
+	// Claimed is true if the verifreg ClaimAllocations receipt shows the allocation was claimed.
+	// The claim keeps the allocation id, which links the piece with the verifreg claims.
+	Claimed bool `json:"claimed"`
+	// NotifyAddresses are the actors notified of the piece activation.
+	NotifyAddresses []string  `json:"notify_addresses" gorm:"type:Array(String)"`
+	TxTimestamp     time.Time `json:"tx_timestamp"`
+}
+
+// MinerConsensusFault links a consensus fault report with the penalty paid by the offending miner.
+type MinerConsensusFault struct {
+	ID string `json:"id"`
+	// MinerAddress is the offending miner.
+	MinerAddress string `json:"miner_address"`
+	Reporter     string `json:"reporter"`
+	Height       uint64 `json:"height"`
+	TxCid        string `json:"tx_cid"`
+	// BlockHeader1Cid, BlockHeader2Cid and BlockHeaderExtraCid are the cids of the block headers proving the fault.
+	// BlockHeaderExtraCid is only set for parent grinding faults.
+	BlockHeader1Cid     string `json:"block_header1_cid"`
+	BlockHeader2Cid     string `json:"block_header2_cid"`
+	BlockHeaderExtraCid string `json:"block_header_extra_cid"`
+	FaultEpoch          int64  `json:"fault_epoch"`
+	FaultType           string `json:"fault_type"`
+	// PenaltyBurned is the amount sent to the burnt funds actor while handling the report.
+	PenaltyBurned *big.Int `json:"penalty_burned" gorm:"column:penalty_burned;type:UInt256"`
+	// ReporterReward is the amount sent to the reporter while handling the report.
+	ReporterReward *big.Int  `json:"reporter_reward" gorm:"column:reporter_reward;type:UInt256"`
+	TxTimestamp    time.Time `json:"tx_timestamp"`
+}
+
+// SectorStateTransition is a change in the lifecycle state of a sector, as tracked across tipsets.
+type SectorStateTransition struct {
+	ID           string `json:"id"`
+	MinerAddress string `json:"miner_address"`
+	SectorNumber uint64 `json:"sector_number"`
+	// FromState is empty the first time a sector is seen.
+	FromState  string `json:"from_state"`
+	ToState    string `json:"to_state"`
+	Height     uint64 `json:"height"`
+	Expiration int64  `json:"expiration"`
+	// TxCid and ActionType are the message causing the transition, both empty for expirations.
+	TxCid      string `json:"tx_cid"`
+	ActionType string `json:"action_type"`
+}
@@ -1,5 +1,7 @@
 package types
 
+import "math/big"
+
 type MultisigInfo struct {
 	ID              string `json:"id"`
 	MultisigAddress string `json:"multisig_address"`
@@ -8,6 +10,11 @@ type MultisigInfo struct {
 	ActionType      string `json:"action_type"`
 	Value           string `json:"value"`
 	Signer          string `json:"signer"`
+	// Amount is the value sent with the message, which a constructor locks when it sets an unlock duration.
+	// It is nil for the genesis multisigs.
+	Amount *big.Int `json:"amount" gorm:"column:amount;type:Int256"`
+	// TxIndex is the position of the transaction in the tipset transactions the event was generated from.
+	TxIndex uint64 `json:"tx_index"`
 }
 
 type MultisigProposal struct {
@@ -20,9 +27,48 @@ type MultisigProposal struct {
 	ActionType      string `json:"action_type"`
 	TxTypeToExecute string `json:"tx_type_to_execute"`
 	Value           string `json:"value"`
+	// Applied is true when the proposed call was executed by this message, once the approvals threshold was met.
+	Applied bool `json:"applied"`
+	// ExitCode is the exit code of the proposed call, only set when applied.
+	ExitCode int64 `json:"exit_code"`
+	// TxIndex is the position of the transaction in the tipset transactions the event was generated from.
+	TxIndex uint64 `json:"tx_index"`
 }
 
 type MultisigEvents struct {
 	Proposals    []*MultisigProposal
 	MultisigInfo []*MultisigInfo
 }
+
+// MultisigProposalOutcome is the resolution of a pending multisig proposal, as tracked across tipsets.
+type MultisigProposalOutcome struct {
+	ID              string `json:"id"`
+	MultisigAddress string `json:"multisig_address"`
+	ProposalID      int64  `json:"proposal_id"`
+	// Proposer is empty when the proposal was made before the tracker started.
+	Proposer        string   `json:"proposer"`
+	TxTypeToExecute string   `json:"tx_type_to_execute"`
+	Value           string   `json:"value"`
+	Approvals       []string `json:"approvals" gorm:"type:Array(String)"`
+	// Outcome is one of executed, cancelled or expired.
+	Outcome string `json:"outcome"`
+	// ExitCode is the exit code of the proposed call, only set when executed.
+	ExitCode   int64  `json:"exit_code"`
+	Height     uint64 `json:"height"`
+	TxCid      string `json:"tx_cid"`
+	ActionType string `json:"action_type"`
+}
+
+// MultisigVesting is the balance a multisig unlocks in a range of epochs, following its lock schedule.
+type MultisigVesting struct {
+	ID              string `json:"id"`
+	MultisigAddress string `json:"multisig_address"`
+	// FromEpoch is inclusive and ToEpoch exclusive.
+	FromEpoch int64 `json:"from_epoch"`
+	ToEpoch   int64 `json:"to_epoch"`
+	// LockedFrom and LockedTo are the locked balances at FromEpoch and ToEpoch.
+	LockedFrom *big.Int `json:"locked_from" gorm:"column:locked_from;type:Int256"`
+	LockedTo   *big.Int `json:"locked_to" gorm:"column:locked_to;type:Int256"`
+	// Vested is the balance unlocked in the range.
+	Vested *big.Int `json:"vested" gorm:"column:vested;type:Int256"`
+}
@@ -1,23 +1,47 @@
 package types
 
 import (
+	"io"
+
 	filTypes "github.com/filecoin-project/lotus/chain/types"
 )
 
 type TxsData struct {
-	Traces    []byte
-	Tipset    *ExtendedTipSet
-	EthLogs   []EthLog
-	Metadata  BlockMetadata
-	Canonical bool
+	Traces []byte
+	// TracesReader is an optional source for the traces used when streaming transactions.
+	// When set, it takes precedence over Traces.
+	TracesReader io.Reader
+	Tipset       *ExtendedTipSet
+	EthLogs      []EthLog
+	Metadata     BlockMetadata
+	Canonical    bool
 }
 
 type TxsParsedResult struct {
 	Txs       []*Transaction
 	Addresses *AddressInfoMap
 	TxCids    []TxCidTranslation
+	// VersionFallback is set when no registered parser supports the node version of the traces
+	// and they were parsed by the parser picked by the version fallback policy.
+	VersionFallback bool
 }
 
+// TxsParsedChunk holds everything produced while parsing a single top-level trace:
+// the main transaction, its subcalls, the fee transaction and the addresses seen along the way.
+type TxsParsedChunk struct {
+	TxCid     string
+	Txs       []*Transaction
+	Addresses *AddressInfoMap
+	TxCids    []TxCidTranslation
+	// VersionFallback is set when no registered parser supports the node version of the traces
+	// and they were parsed by the parser picked by the version fallback policy.
+	VersionFallback bool
+}
+
+// TxsChunkHandler is called once per finished top-level trace when streaming transactions.
+// Returning an error stops the stream.
+type TxsChunkHandler func(chunk *TxsParsedChunk) error
+
 type EventsData struct {
 	Tipset    *ExtendedTipSet
 	NativeLog []*filTypes.ActorEvent
@@ -30,4 +54,88 @@ type EventsParsedResult struct {
 	EVMEvents    int
 	NativeEvents int
 	ParsedEvents []*Event
+	// VersionFallback is set when no registered parser supports the node version of the traces
+	// and they were parsed by the parser picked by the version fallback policy.
+	VersionFallback bool
+}
+
+// HeightData contains every input required to parse a single height.
+type HeightData struct {
+	Height    uint64
+	Traces    []byte
+	Tipset    *ExtendedTipSet
+	NativeLog []*filTypes.ActorEvent
+	EthLogs   []EthLog
+	Metadata  BlockMetadata
+	Canonical bool
+}
+
+// HeightParsedResult contains the output of parsing a single height.
+// Err is set when any of the datasets could not be parsed, in which case the rest of the fields may be partially filled.
+type HeightParsedResult struct {
+	Height          uint64
+	Txs             *TxsParsedResult
+	NativeEvents    *EventsParsedResult
+	EthLogs         *EventsParsedResult
+	BlocksTimestamp *BlocksTimestamp
+	BlockAddresses  *AddressInfoMap
+	Err             error
+}
+
+// Dataset identifies one of the outputs that can be produced when parsing a tipset.
+type Dataset string
+
+const (
+	DatasetTransactions   Dataset = "transactions"
+	DatasetNativeEvents   Dataset = "native_events"
+	DatasetEthLogs        Dataset = "eth_logs"
+	DatasetBlocksInfo     Dataset = "blocks_info"
+	DatasetMultisig       Dataset = "multisig"
+	DatasetMiner          Dataset = "miner"
+	DatasetVerifreg       Dataset = "verifreg"
+	DatasetDataCap        Dataset = "datacap"
+	DatasetDeals          Dataset = "deals"
+	DatasetPaymentChannel Dataset = "payment_channel"
+	DatasetPower          Dataset = "power"
+	DatasetBlockReward    Dataset = "block_reward"
+	DatasetActorCreations Dataset = "actor_creations"
+)
+
+// AllDatasets returns every dataset that can be produced when parsing a tipset.
+func AllDatasets() []Dataset {
+	return []Dataset{
+		DatasetTransactions,
+		DatasetNativeEvents,
+		DatasetEthLogs,
+		DatasetBlocksInfo,
+		DatasetMultisig,
+		DatasetMiner,
+		DatasetVerifreg,
+		DatasetDataCap,
+		DatasetDeals,
+		DatasetPaymentChannel,
+		DatasetPower,
+		DatasetBlockReward,
+		DatasetActorCreations,
+	}
+}
+
+// TipsetBundle contains every dataset produced while parsing a single tipset.
+// Datasets that were not requested are left nil.
+type TipsetBundle struct {
+	Height               uint64
+	Txs                  *TxsParsedResult
+	NativeEvents         *EventsParsedResult
+	EthLogs              *EventsParsedResult
+	BlocksTimestamp      *BlocksTimestamp
+	BlockAddresses       *AddressInfoMap
+	MultisigEvents       *MultisigEvents
+	MinerEvents          *MinerEvents
+	VerifregEvents       *VerifregEvents
+	DataCapEvents        *DataCapEvents
+	DealsEvents          *DealsEvents
+	PaymentChannelEvents *PaymentChannelEvents
+	PowerEvents          *PowerEvents
+	BlockRewardEvents    *BlockRewardEvents
+	ActorCreationEvents  *ActorCreationEvents
 }
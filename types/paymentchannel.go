@@ -0,0 +1,76 @@
+package types
+
+import (
+	"math/big"
+	"time"
+)
+
+type PaymentChannelEvents struct {
+	Channels    []*PaymentChannelCreation
+	Vouchers    []*PaymentChannelVoucher
+	Settlements []*PaymentChannelSettlement
+	Payouts     []*PaymentChannelPayout
+}
+
+// PaymentChannelCreation is a payment channel created through InitActor.Exec.
+type PaymentChannelCreation struct {
+	ID             string `json:"id"`
+	ChannelAddress string `json:"channel_address"`
+	// Creator is the address that called InitActor.Exec.
+	Creator string `json:"creator"`
+	// From is the payer and To the payee of the channel.
+	From        string    `json:"from"`
+	To          string    `json:"to"`
+	Height      uint64    `json:"height"`
+	TxCid       string    `json:"tx_cid"`
+	TxTimestamp time.Time `json:"tx_timestamp"`
+}
+
+// PaymentChannelVoucher is a voucher redeemed through UpdateChannelState.
+type PaymentChannelVoucher struct {
+	ID             string `json:"id"`
+	ChannelAddress string `json:"channel_address"`
+	// Redeemer is the address that submitted the voucher.
+	Redeemer string `json:"redeemer"`
+	Lane     uint64 `json:"lane"`
+	Nonce    uint64 `json:"nonce"`
+	// Amount is the total amount the lane can be redeemed for, not the increment over the previous voucher,
+	// so vouchers can't be summed. Increment is the amount the voucher adds to what the channel pays out:
+	// Amount minus what the lane and the MergedLanes redeemed before. It depends on the earlier vouchers of the channel,
+	// so the event generator leaves it nil and paymentchannel.LaneTracker sets it.
+	Amount      *big.Int `json:"amount" gorm:"column:amount;type:UInt256"`
+	Increment   *big.Int `json:"increment" gorm:"column:increment;type:Int256"`
+	MergedLanes []uint64 `json:"merged_lanes" gorm:"type:Array(UInt64)"`
+	// SecretHash is the hash the voucher is locked with, and SecretPreimage the secret revealed to redeem it.
+	// Both are base64 encoded and empty when the voucher is not locked.
+	SecretHash      string    `json:"secret_hash"`
+	SecretPreimage  string    `json:"secret_preimage"`
+	MinSettleHeight int64     `json:"min_settle_height"`
+	Height          uint64    `json:"height"`
+	TxCid           string    `json:"tx_cid"`
+	TxTimestamp     time.Time `json:"tx_timestamp"`
+}
+
+// PaymentChannelSettlement is the start of the settling period of a channel.
+type PaymentChannelSettlement struct {
+	ID             string `json:"id"`
+	ChannelAddress string `json:"channel_address"`
+	Caller         string `json:"caller"`
+	// SettlingAt is the earliest epoch the channel can be collected at.
+	// A redeemed voucher may push it further through its MinSettleHeight.
+	SettlingAt  int64     `json:"settling_at"`
+	Height      uint64    `json:"height"`
+	TxCid       string    `json:"tx_cid"`
+	TxTimestamp time.Time `json:"tx_timestamp"`
+}
+
+// PaymentChannelPayout is a transfer made by a channel when it is collected.
+type PaymentChannelPayout struct {
+	ID             string    `json:"id"`
+	ChannelAddress string    `json:"channel_address"`
+	Recipient      string    `json:"recipient"`
+	Amount         *big.Int  `json:"amount" gorm:"column:amount;type:UInt256"`
+	Height         uint64    `json:"height"`
+	TxCid          string    `json:"tx_cid"`
+	TxTimestamp    time.Time `json:"tx_timestamp"`
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"math/big"
+	"time"
+)
+
+type PowerEvents struct {
+	MinerCreations  []*PowerMinerCreation
+	ClaimedPower    []*PowerClaimedPowerUpdate
+	PledgeTotals    []*PowerPledgeTotalUpdate
+	ConsensusFaults []*PowerConsensusFault
+}
+
+// PowerMinerCreation is a miner actor created through the power actor CreateMiner method.
+type PowerMinerCreation struct {
+	ID      string `json:"id"`
+	Creator string `json:"creator"`
+	Owner   string `json:"owner"`
+	Worker  string `json:"worker"`
+	// PeerID is empty when the miner was created without a peer id.
+	PeerID     string   `json:"peer_id"`
+	Multiaddrs []string `json:"multiaddrs" gorm:"type:Array(String)"`
+	// WindowPoStProofType is derived from the seal proof type for miners created before actors v3.
+	WindowPoStProofType int64     `json:"window_post_proof_type"`
+	IDAddress           string    `json:"id_address"`
+	RobustAddress       string    `json:"robust_address"`
+	Height              uint64    `json:"height"`
+	TxCid               string    `json:"tx_cid"`
+	TxTimestamp         time.Time `json:"tx_timestamp"`
+}
+
+// PowerClaimedPowerUpdate is the change of the power claimed by a miner, reported when its sectors change.
+type PowerClaimedPowerUpdate struct {
+	ID                   string    `json:"id"`
+	MinerAddress         string    `json:"miner_address"`
+	RawBytePowerDelta    *big.Int  `json:"raw_byte_power_delta" gorm:"column:raw_byte_power_delta;type:Int256"`
+	QualityAdjPowerDelta *big.Int  `json:"quality_adj_power_delta" gorm:"column:quality_adj_power_delta;type:Int256"`
+	Height               uint64    `json:"height"`
+	TxCid                string    `json:"tx_cid"`
+	TxTimestamp          time.Time `json:"tx_timestamp"`
+}
+
+// PowerPledgeTotalUpdate is the change of the network total pledge collateral reported by a miner.
+type PowerPledgeTotalUpdate struct {
+	ID           string    `json:"id"`
+	MinerAddress string    `json:"miner_address"`
+	PledgeDelta  *big.Int  `json:"pledge_delta" gorm:"column:pledge_delta;type:Int256"`
+	Height       uint64    `json:"height"`
+	TxCid        string    `json:"tx_cid"`
+	TxTimestamp  time.Time `json:"tx_timestamp"`
+}
+
+// PowerConsensusFault is a miner removed from the power table after a reported consensus fault.
+type PowerConsensusFault struct {
+	ID           string `json:"id"`
+	MinerAddress string `json:"miner_address"`
+	// Reporter is the address that reported the fault to the miner, empty if the report is not part of the tipset.
+	Reporter string `json:"reporter"`
+	// Pledge is the pledge the miner held, removed from the network pledge total.
+	Pledge      *big.Int  `json:"pledge" gorm:"column:pledge;type:UInt256"`
+	Height      uint64    `json:"height"`
+	TxCid       string    `json:"tx_cid"`
+	TxTimestamp time.Time `json:"tx_timestamp"`
+}
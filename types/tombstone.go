@@ -0,0 +1,59 @@
+package types
+
+// Row kinds referenced by a Tombstone.
+const (
+	RowKindTransaction              = "transaction"
+	RowKindNativeEvent              = "native_event"
+	RowKindEthLog                   = "eth_log"
+	RowKindBlocksTimestamp          = "blocks_timestamp"
+	RowKindMultisigInfo             = "multisig_info"
+	RowKindMultisigProposal         = "multisig_proposal"
+	RowKindMinerInfo                = "miner_info"
+	RowKindMinerSector              = "miner_sector"
+	RowKindMinerProving             = "miner_proving"
+	RowKindMinerConsensusFault      = "miner_consensus_fault"
+	RowKindMinerDDOPiece            = "miner_ddo_piece"
+	RowKindDealsMessage             = "deals_message"
+	RowKindDealsProposal            = "deals_proposal"
+	RowKindDealsActivation          = "deals_activation"
+	RowKindDealsSpaceInfo           = "deals_space_info"
+	RowKindDealsTermination         = "deals_termination"
+	RowKindDealsSettlement          = "deals_settlement"
+	RowKindDataCapInfo              = "datacap_info"
+	RowKindDataCapTokenEvent        = "datacap_token_event"
+	RowKindDataCapAllowanceEvent    = "datacap_allowance_event"
+	RowKindVerifregVerifier         = "verifreg_verifier"
+	RowKindVerifregClient           = "verifreg_client"
+	RowKindVerifregDeal             = "verifreg_deal"
+	RowKindVerifregAllocation       = "verifreg_allocation"
+	RowKindPaymentChannelCreation   = "payment_channel_creation"
+	RowKindPaymentChannelVoucher    = "payment_channel_voucher"
+	RowKindPaymentChannelSettlement = "payment_channel_settlement"
+	RowKindPaymentChannelPayout     = "payment_channel_payout"
+	RowKindPowerMinerCreation       = "power_miner_creation"
+	RowKindPowerClaimedPower        = "power_claimed_power"
+	RowKindPowerPledgeTotal         = "power_pledge_total"
+	RowKindPowerConsensusFault      = "power_consensus_fault"
+	RowKindBlockReward              = "block_reward"
+	RowKindEpochReward              = "epoch_reward"
+	RowKindActorCreation            = "actor_creation"
+)
+
+// Tombstone marks a row produced by a tipset that is no longer part of the chain.
+type Tombstone struct {
+	// ID is the id of the reverted row
+	ID string `json:"id"`
+	// Kind is the type of the reverted row, one of the RowKind constants
+	Kind    string  `json:"kind"`
+	Dataset Dataset `json:"dataset"`
+}
+
+// TipsetTombstones contains a tombstone for every row produced by a reverted tipset.
+// Tombstones are sorted by kind and id, so reverting the same tipset always yields the same output.
+type TipsetTombstones struct {
+	Height     uint64      `json:"height"`
+	TipsetCid  string      `json:"tipset_cid"`
+	Tombstones []Tombstone `json:"tombstones"`
+	// PurgedAddresses are the address cache entries of the actors created by the tipset, which were purged
+	PurgedAddresses []*AddressInfo `json:"purged_addresses"`
+}
@@ -3,4 +3,6 @@ package types
 type TxCidTranslation struct {
 	TxCid  string `json:"tx_cid"`
 	TxHash string `json:"tx_hash"`
+	// Offline is set when the parser runs without a node and the tx hash could not be derived from the tx cid alone.
+	Offline bool `json:"offline,omitempty"`
 }
@@ -9,6 +9,8 @@ type VerifregEvents struct {
 	VerifierInfo []*VerifregEvent
 	ClientInfo   []*VerifregClientInfo
 	Deals        []*VerifregDeal
+	// Allocations holds a row per change of a verified allocation or of the claim it becomes once sealed.
+	Allocations []*VerifregAllocationEvent
 }
 
 type VerifregEvent struct {
@@ -46,3 +48,41 @@ type VerifregDeal struct {
 	Data            string    `json:"data"`
 	TxTimestamp     time.Time `json:"tx_timestamp"`
 }
+
+// VerifregAllocationEvent is a change of a verified allocation or of its claim.
+// A claim keeps the id of the allocation it was created from, so ClaimID equals AllocationID once claimed.
+type VerifregAllocationEvent struct {
+	ID           string `json:"id"`
+	AllocationID uint64 `json:"allocation_id"`
+	ClaimID      uint64 `json:"claim_id"`
+	// Client, Provider, Size and the terms are empty when the message doesn't carry them, e.g. removals.
+	Client   string `json:"client"`
+	Provider string `json:"provider"`
+	Size     uint64 `json:"size"`
+	TermMin  int64  `json:"term_min"`
+	TermMax  int64  `json:"term_max"`
+	// Expiration is the epoch by which the allocation must be claimed.
+	Expiration   int64  `json:"expiration"`
+	SectorNumber uint64 `json:"sector_number"`
+	// State is the state of the allocation after the message.
+	State       string    `json:"state"`
+	TxCid       string    `json:"tx_cid"`
+	Height      uint64    `json:"height"`
+	ActionType  string    `json:"action_type"`
+	TxTimestamp time.Time `json:"tx_timestamp"`
+}
+
+// VerifregAllocationTransition is a change in the lifecycle state of a verified allocation, as tracked across tipsets.
+type VerifregAllocationTransition struct {
+	ID           string `json:"id"`
+	AllocationID uint64 `json:"allocation_id"`
+	Client       string `json:"client"`
+	Provider     string `json:"provider"`
+	Size         uint64 `json:"size"`
+	// FromState is empty the first time an allocation is seen.
+	FromState  string `json:"from_state"`
+	ToState    string `json:"to_state"`
+	Height     uint64 `json:"height"`
+	TxCid      string `json:"tx_cid"`
+	ActionType string `json:"action_type"`
+}